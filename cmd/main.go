@@ -2,15 +2,29 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"net"
 	"net/http"
+	"net/mail"
+	"net/smtp"
 	"os"
 	"os/signal"
+	"project/internal/ats"
 	"project/internal/auth"
 	"project/internal/database"
+	"project/internal/email"
 	handler "project/internal/handlers"
+	"project/internal/inbox"
+	"project/internal/jobstream"
+	"project/internal/models"
+	"project/internal/outbox"
 	"project/internal/repository"
+	"project/internal/rpc"
 	service "project/internal/service"
+	"project/internal/webhook"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -73,28 +87,114 @@ func StartApp() error {
 		return err
 	}
 
-	sc, err := service.NewService(repo, a)
+	// the broker hands job events to the clients following /jobs/stream
+	jobEvents, err := jobstream.NewBroker(jobstream.DefaultReplay)
 	if err != nil {
 		return err
 	}
 
+	// the hub pushes notifications to the sockets of /me/notifications/ws
+	notifications := inbox.NewHub()
+
+	// the unsubscribe links of emails are signed with a key derived from the auth
+	// key, so they stay valid across restarts without another secret to keep
+	unsubscribeKey := sha256.Sum256(append([]byte("email unsubscribe "), privatePEM...))
+	tokens, err := email.NewTokens(unsubscribeKey[:])
+	if err != nil {
+		return err
+	}
+	admins, err := adminIDs()
+	if err != nil {
+		return err
+	}
+
+	sc, err := service.NewService(repo, a, service.WithJobStream(jobEvents), service.WithInbox(notifications),
+		service.WithEmail(tokens, envOr("PUBLIC_URL", "http://localhost:8099")), service.WithAdmins(admins...))
+	if err != nil {
+		return err
+	}
+
+	// the dispatcher posts the queued webhook deliveries until the app stops, one
+	// cut short is claimed again once its lease ends
+	dispatcher, err := webhook.NewDispatcher(repo)
+	if err != nil {
+		return err
+	}
+	workers, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go dispatcher.Run(workers)
+
+	// the outbox dispatcher hands the domain events the service records to the
+	// subscribers registered on it
+	domainEvents, err := outbox.NewDispatcher(repo)
+	if err != nil {
+		return err
+	}
+	domainEvents.Subscribe(models.EventUserSignedUp, "email", sc.EmailEvent)
+	domainEvents.Subscribe(models.EventCompanyCreated, "email", sc.EmailEvent)
+	domainEvents.Subscribe(models.EventJobCreated, "email", sc.EmailEvent)
+	for _, event := range []string{models.EventJobCreated, models.EventJobUpdated, models.EventJobClosed} {
+		domainEvents.Subscribe(event, "webhooks", sc.WebhookEvent)
+	}
+	go domainEvents.Run(workers)
+
+	// the sender sends the emails the service queues
+	from, err := mail.ParseAddress(envOr("MAIL_FROM", "Job Portal <no-reply@localhost>"))
+	if err != nil {
+		return fmt.Errorf("invalid MAIL_FROM : %w", err)
+	}
+	emails, err := email.NewSender(repo, mailTransport(), *from)
+	if err != nil {
+		return err
+	}
+	go emails.Run(workers)
+
+	// the scheduler pulls the job boards of the ats connectors of the companies
+	// as they come due and syncs their jobs
+	boards, err := ats.NewScheduler(repo, sc)
+	if err != nil {
+		return err
+	}
+	go boards.Run(workers)
+
 	// initializing the http server
 	api := http.Server{
 		Addr:         ":8099",
 		ReadTimeout:  8000 * time.Second,
 		WriteTimeout: 800 * time.Second,
 		IdleTimeout:  800 * time.Second,
-		Handler:      handler.API(a, sc),
+		Handler:      handler.API(a, sc, handler.WithValidation()),
+	}
+	// open event streams never go idle, ending them lets Shutdown finish
+	api.RegisterOnShutdown(jobEvents.Close)
+	// Shutdown does not wait for hijacked websockets, closing the hub ends them
+	api.RegisterOnShutdown(notifications.Close)
+
+	// initializing the grpc server, it takes the same tokens as the http api
+	rpcServer, err := rpc.NewServer(a, sc)
+	if err != nil {
+		return err
 	}
+	rpcAddr := ":8098"
 
 	// channel to store any errors while setting up the service
-	serverErrors := make(chan error, 1)
+	serverErrors := make(chan error, 2)
 
 	go func() {
 		log.Info().Str("Port", api.Addr).Msg("main started : api is listening")
 		serverErrors <- api.ListenAndServe()
 	}()
 
+	go func() {
+		lis, err := net.Listen("tcp", rpcAddr)
+		if err != nil {
+			serverErrors <- err
+			return
+		}
+		log.Info().Str("Port", rpcAddr).Msg("main started : grpc is listening")
+		serverErrors <- rpcServer.Serve(lis)
+	}()
+
 	//shutdown channel intercepts ctrl+c signals
 	shutdown := make(chan os.Signal, 1)
 
@@ -102,6 +202,8 @@ func StartApp() error {
 
 	select {
 	case err := <-serverErrors:
+		rpcServer.Stop()
+		api.Close()
 		return fmt.Errorf("server error : %w", err)
 
 	case sig := <-shutdown:
@@ -109,12 +211,69 @@ func StartApp() error {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
+		// the grpc server gets the same time to finish its calls
+		stopped := make(chan struct{})
+		go func() {
+			rpcServer.GracefulStop()
+			close(stopped)
+		}()
+
 		err := api.Shutdown(ctx)
 		if err != nil {
+			rpcServer.Stop()
 			err := api.Close()
 			return fmt.Errorf("could not stop server gracefully : %w", err)
 		}
+
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			rpcServer.Stop()
+			return fmt.Errorf("could not stop grpc server gracefully : %w", ctx.Err())
+		}
 	}
 	return nil
 
 }
+
+// mailTransport sends emails through the SMTP server of SMTP_ADDR, signing in
+// with SMTP_USERNAME and SMTP_PASSWORD when set. Without a server emails are
+// appended to the mailbox file of MBOX_PATH, mail.mbox by default
+func mailTransport() email.Transport {
+	addr := os.Getenv("SMTP_ADDR")
+	if addr == "" {
+		path := envOr("MBOX_PATH", "mail.mbox")
+		log.Info().Str("path", path).Msg("no SMTP_ADDR, emails are written to a mailbox file")
+		return email.NewMbox(path)
+	}
+	transport := email.SMTP{Addr: addr}
+	if user := os.Getenv("SMTP_USERNAME"); user != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		transport.Auth = smtp.PlainAuth("", user, os.Getenv("SMTP_PASSWORD"), host)
+	}
+	return transport
+}
+
+// adminIDs are the users of the comma separated ADMIN_USER_IDS
+func adminIDs() ([]uint, error) {
+	var ids []uint
+	for _, field := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ADMIN_USER_IDS : %w", err)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,101 @@
+// Package aggregator writes the published jobs as the XML feeds job aggregators
+// ingest. Each feed format is an adapter, the Writer streams a feed in any of them
+package aggregator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"project/internal/models"
+	"time"
+)
+
+// MediaType is the media type of the feeds
+const MediaType = "application/xml"
+
+// Source is the portal a feed comes from. Link is the url of a job
+type Source struct {
+	Name      string
+	URL       string
+	Generated time.Time
+	Link      func(models.Jobs) string
+}
+
+// Format is a feed format an aggregator ingests
+type Format interface {
+	// Name selects the format
+	Name() string
+	// Missing names the fields the format requires that the job lacks
+	Missing(jobData models.Jobs) []string
+	// Open writes the feed up to its first job
+	Open(enc *xml.Encoder, src Source) error
+	// Job writes one job, its company is loaded
+	Job(enc *xml.Encoder, src Source, jobData models.Jobs) error
+	// Close writes the end of the feed
+	Close(enc *xml.Encoder) error
+}
+
+// Writer streams a feed, each job is written as it is added. Jobs the format
+// cannot take are left out and listed in the report
+type Writer struct {
+	enc    *xml.Encoder
+	format Format
+	src    Source
+	report models.AggregatorReport
+}
+
+// NewWriter starts a feed in format f on w
+func NewWriter(w io.Writer, f Format, src Source) (*Writer, error) {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return nil, err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = f.Open(enc, src)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{
+		enc:    enc,
+		format: f,
+		src:    src,
+		report: models.AggregatorReport{Format: f.Name(), Excluded: []models.AggregatorExclusion{}},
+	}, nil
+}
+
+// Write adds a job to the feed, or to the excluded jobs of the report when it is
+// not published or lacks a field the format requires
+func (w *Writer) Write(jobData models.Jobs) error {
+	var reasons []string
+	if jobData.Status != "" && jobData.Status != models.JobPublished {
+		reasons = append(reasons, "not published")
+	}
+	for _, field := range w.format.Missing(jobData) {
+		reasons = append(reasons, "missing "+field)
+	}
+	if len(reasons) > 0 {
+		w.report.Excluded = append(w.report.Excluded, models.AggregatorExclusion{JobID: jobData.ID, Name: jobData.Name, Reasons: reasons})
+		return nil
+	}
+	err := w.format.Job(w.enc, w.src, jobData)
+	if err != nil {
+		return fmt.Errorf("writing job %d: %w", jobData.ID, err)
+	}
+	w.report.Included++
+	return nil
+}
+
+// Close ends the feed and returns what it listed and left out
+func (w *Writer) Close() (models.AggregatorReport, error) {
+	err := w.format.Close(w.enc)
+	if err != nil {
+		return w.report, err
+	}
+	return w.report, w.enc.Flush()
+}
+
+// text writes <name>value</name>, element by element while a feed is open
+func text(enc *xml.Encoder, name string, value string) error {
+	return enc.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: name}})
+}
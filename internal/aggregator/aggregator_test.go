@@ -0,0 +1,176 @@
+package aggregator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"project/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var posted = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func testSource() Source {
+	return Source{
+		Name:      "Job Portal",
+		URL:       "https://portal.example",
+		Generated: posted.Add(time.Hour),
+		Link: func(jobData models.Jobs) string {
+			return fmt.Sprintf("https://portal.example/api/v1/jobs/%d", jobData.ID)
+		},
+	}
+}
+
+func testJobs() []models.Jobs {
+	tek := models.Company{Model: gorm.Model{ID: 7}, Name: "tek & co", Field: "software"}
+	return []models.Jobs{
+		{
+			Model:          gorm.Model{ID: 11, CreatedAt: posted, UpdatedAt: posted},
+			Company:        tek,
+			Cid:            7,
+			Name:           "developer",
+			Location:       "pune",
+			EmploymentType: models.EmploymentFullTime,
+			RemotePolicy:   models.RemoteHybrid,
+			MinSalary:      800000,
+			MaxSalary:      1200000,
+			Skills:         []string{"go", "sql"},
+		},
+		{Model: gorm.Model{ID: 12}, Company: tek, Cid: 7, Name: "tester", RemotePolicy: models.RemoteFull},
+		{Model: gorm.Model{ID: 13}, Cid: 8, Location: "pune"},
+		{Model: gorm.Model{ID: 14}, Company: tek, Cid: 7, Name: "draft", Location: "pune", Status: models.JobDraft},
+	}
+}
+
+func writeFeed(t *testing.T, f Format) (string, models.AggregatorReport) {
+	t.Helper()
+	var b bytes.Buffer
+	w, err := NewWriter(&b, f, testSource())
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for _, jobData := range testJobs() {
+		err = w.Write(jobData)
+		if err != nil {
+			t.Fatalf("Writer.Write() error = %v", err)
+		}
+	}
+	report, err := w.Close()
+	if err != nil {
+		t.Fatalf("Writer.Close() error = %v", err)
+	}
+	return b.String(), report
+}
+
+func TestGeneric(t *testing.T) {
+	out, report := writeFeed(t, Generic{})
+	var got struct {
+		XMLName   xml.Name `xml:"jobs"`
+		Publisher string   `xml:"publisher"`
+		Jobs      []struct {
+			ID      uint   `xml:"id,attr"`
+			Title   string `xml:"title"`
+			URL     string `xml:"url"`
+			Company string `xml:"company"`
+			Salary  struct {
+				Min      int    `xml:"min,attr"`
+				Currency string `xml:"currency,attr"`
+			} `xml:"salary"`
+			Skills []string `xml:"skills>skill"`
+		} `xml:"job"`
+	}
+	err := xml.Unmarshal([]byte(out), &got)
+	if err != nil {
+		t.Fatalf("the feed is not well formed: %v\n%s", err, out)
+	}
+	if got.Publisher != "Job Portal" || len(got.Jobs) != 2 {
+		t.Fatalf("Generic wrote\n%s", out)
+	}
+	job := got.Jobs[0]
+	if job.ID != 11 || job.Company != "tek & co" || job.URL != "https://portal.example/api/v1/jobs/11" ||
+		job.Salary.Min != 800000 || job.Salary.Currency != "INR" || len(job.Skills) != 2 {
+		t.Errorf("Generic wrote the job as %+v", job)
+	}
+	// a remote job needs no location
+	if got.Jobs[1].Title != "tester" {
+		t.Errorf("Generic left out the remote job\n%s", out)
+	}
+	if strings.Contains(out, "<skills></skills>") {
+		t.Errorf("Generic writes empty skills\n%s", out)
+	}
+	want := models.AggregatorReport{
+		Format:   "generic",
+		Included: 2,
+		Excluded: []models.AggregatorExclusion{
+			{JobID: 13, Reasons: []string{"missing title", "missing company"}},
+			{JobID: 14, Name: "draft", Reasons: []string{"not published"}},
+		},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("Generic report = %+v, want %+v", report, want)
+	}
+}
+
+func TestIndeed(t *testing.T) {
+	out, report := writeFeed(t, Indeed{Country: "IN"})
+	var got struct {
+		XMLName       xml.Name `xml:"source"`
+		LastBuildDate string   `xml:"lastBuildDate"`
+		Jobs          []struct {
+			Title           string `xml:"title"`
+			Date            string `xml:"date"`
+			ReferenceNumber string `xml:"referencenumber"`
+			Company         string `xml:"company"`
+			City            string `xml:"city"`
+			Country         string `xml:"country"`
+			Salary          string `xml:"salary"`
+			JobType         string `xml:"jobtype"`
+			RemoteType      string `xml:"remotetype"`
+		} `xml:"job"`
+	}
+	err := xml.Unmarshal([]byte(out), &got)
+	if err != nil {
+		t.Fatalf("the feed is not well formed: %v\n%s", err, out)
+	}
+	if got.LastBuildDate != "Tue, 02 Jan 2024 04:04:05 GMT" || len(got.Jobs) != 1 {
+		t.Fatalf("Indeed wrote\n%s", out)
+	}
+	job := got.Jobs[0]
+	if job.Title != "developer" || job.Date != "Tue, 02 Jan 2024 03:04:05 GMT" || job.ReferenceNumber != "11" ||
+		job.Company != "tek & co" || job.City != "pune" || job.Country != "IN" ||
+		job.Salary != "INR 800000 - 1200000 per year" || job.JobType != "fulltime" || job.RemoteType != "Hybrid remote" {
+		t.Errorf("Indeed wrote the job as %+v", job)
+	}
+	if !strings.Contains(out, "<company><![CDATA[tek & co]]></company>") {
+		t.Errorf("Indeed does not write CDATA\n%s", out)
+	}
+	if report.Included != 1 || len(report.Excluded) != 3 ||
+		!reflect.DeepEqual(report.Excluded[0], models.AggregatorExclusion{JobID: 12, Name: "tester", Reasons: []string{"missing city"}}) {
+		t.Errorf("Indeed report = %+v", report)
+	}
+
+	_, report = writeFeed(t, Indeed{})
+	if report.Included != 0 {
+		t.Errorf("Indeed without a country included %d jobs", report.Included)
+	}
+}
+
+func TestWriter_streams(t *testing.T) {
+	var w bytes.Buffer
+	feed, err := NewWriter(&w, Generic{}, testSource())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = feed.Write(testJobs()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(w.String(), "<title>developer</title>") {
+		t.Errorf("the job is held back until the feed is closed:\n%s", w.String())
+	}
+}
@@ -0,0 +1,112 @@
+package aggregator
+
+import (
+	"encoding/xml"
+	"project/internal/feed"
+	"project/internal/models"
+	"time"
+)
+
+// Generic is a plain XML job feed, a <jobs> element of <job> elements whose
+// fields are named like the json of the api
+type Generic struct{}
+
+type genericJob struct {
+	XMLName        xml.Name       `xml:"job"`
+	ID             uint           `xml:"id,attr"`
+	Title          string         `xml:"title"`
+	URL            string         `xml:"url"`
+	Company        string         `xml:"company"`
+	CompanyID      uint           `xml:"company_id"`
+	Location       string         `xml:"location,omitempty"`
+	EmploymentType string         `xml:"employment_type,omitempty"`
+	RemotePolicy   string         `xml:"remote_policy,omitempty"`
+	Seniority      string         `xml:"seniority,omitempty"`
+	Salary         *genericSalary `xml:"salary,omitempty"`
+	NoticePeriod   string         `xml:"notice_period,omitempty"`
+	Skills         *genericSkills `xml:"skills,omitempty"`
+	Description    string         `xml:"description"`
+	PostedAt       string         `xml:"posted_at"`
+	UpdatedAt      string         `xml:"updated_at"`
+}
+
+type genericSkills struct {
+	Skill []string `xml:"skill"`
+}
+
+// genericSalary is a range in its attributes, or the salary as it was posted
+type genericSalary struct {
+	Min      int    `xml:"min,attr,omitempty"`
+	Max      int    `xml:"max,attr,omitempty"`
+	Currency string `xml:"currency,attr,omitempty"`
+	Text     string `xml:",chardata"`
+}
+
+func (Generic) Name() string {
+	return "generic"
+}
+
+// Missing requires a title, a company and a location, remote jobs may go without one
+func (Generic) Missing(jobData models.Jobs) []string {
+	var missing []string
+	if jobData.Name == "" {
+		missing = append(missing, "title")
+	}
+	if jobData.Company.Name == "" {
+		missing = append(missing, "company")
+	}
+	if jobData.Location == "" && jobData.RemotePolicy != models.RemoteFull {
+		missing = append(missing, "location")
+	}
+	return missing
+}
+
+func (Generic) Open(enc *xml.Encoder, src Source) error {
+	err := enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "jobs"}})
+	if err != nil {
+		return err
+	}
+	for _, field := range [][2]string{
+		{"publisher", src.Name},
+		{"publisher_url", src.URL},
+		{"generated_at", src.Generated.UTC().Format(time.RFC3339)},
+	} {
+		err = text(enc, field[0], field[1])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (Generic) Job(enc *xml.Encoder, src Source, jobData models.Jobs) error {
+	job := genericJob{
+		ID:             jobData.ID,
+		Title:          jobData.Name,
+		URL:            src.Link(jobData),
+		Company:        jobData.Company.Name,
+		CompanyID:      jobData.Cid,
+		Location:       jobData.Location,
+		EmploymentType: jobData.EmploymentType,
+		RemotePolicy:   jobData.RemotePolicy,
+		Seniority:      jobData.Seniority,
+		NoticePeriod:   jobData.NoticePeriod,
+		Description:    feed.Summary(jobData),
+		PostedAt:       jobData.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:      jobData.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if len(jobData.Skills) > 0 {
+		job.Skills = &genericSkills{Skill: jobData.Skills}
+	}
+	switch {
+	case jobData.MinSalary > 0 || jobData.MaxSalary > 0:
+		job.Salary = &genericSalary{Min: jobData.MinSalary, Max: jobData.MaxSalary, Currency: models.SalaryCurrency}
+	case jobData.Salary != "":
+		job.Salary = &genericSalary{Text: jobData.Salary}
+	}
+	return enc.Encode(job)
+}
+
+func (Generic) Close(enc *xml.Encoder) error {
+	return enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "jobs"}})
+}
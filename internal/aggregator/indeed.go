@@ -0,0 +1,134 @@
+package aggregator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"project/internal/feed"
+	"project/internal/models"
+)
+
+// Indeed is the <source> of <job> elements Indeed and the aggregators copying it
+// ingest, the values are written as CDATA. Country is the country of every job,
+// the portal does not record one per job
+type Indeed struct {
+	Country string
+}
+
+// indeedDate is how Indeed dates feeds and jobs
+const indeedDate = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+type indeedJob struct {
+	XMLName         xml.Name `xml:"job"`
+	Title           cdata    `xml:"title"`
+	Date            cdata    `xml:"date"`
+	ReferenceNumber cdata    `xml:"referencenumber"`
+	URL             cdata    `xml:"url"`
+	Company         cdata    `xml:"company"`
+	City            cdata    `xml:"city"`
+	Country         cdata    `xml:"country"`
+	Description     cdata    `xml:"description"`
+	Salary          *cdata   `xml:"salary,omitempty"`
+	JobType         *cdata   `xml:"jobtype,omitempty"`
+	Category        *cdata   `xml:"category,omitempty"`
+	Experience      *cdata   `xml:"experience,omitempty"`
+	RemoteType      *cdata   `xml:"remotetype,omitempty"`
+}
+
+// indeedJobTypes and indeedRemoteTypes map the values of a job to Indeed's
+var (
+	indeedJobTypes = map[string]string{
+		models.EmploymentFullTime:   "fulltime",
+		models.EmploymentPartTime:   "parttime",
+		models.EmploymentContract:   "contract",
+		models.EmploymentInternship: "internship",
+	}
+	indeedRemoteTypes = map[string]string{
+		models.RemoteFull:   "Fully remote",
+		models.RemoteHybrid: "Hybrid remote",
+	}
+)
+
+func (Indeed) Name() string {
+	return "indeed"
+}
+
+// Missing requires a title, a company, a city, a country and a description
+func (f Indeed) Missing(jobData models.Jobs) []string {
+	var missing []string
+	if jobData.Name == "" {
+		missing = append(missing, "title")
+	}
+	if jobData.Company.Name == "" {
+		missing = append(missing, "company")
+	}
+	if jobData.Location == "" {
+		missing = append(missing, "city")
+	}
+	if f.Country == "" {
+		missing = append(missing, "country")
+	}
+	if feed.Summary(jobData) == "" {
+		missing = append(missing, "description")
+	}
+	return missing
+}
+
+func (Indeed) Open(enc *xml.Encoder, src Source) error {
+	err := enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "source"}})
+	if err != nil {
+		return err
+	}
+	for _, field := range [][2]string{
+		{"publisher", src.Name},
+		{"publisherurl", src.URL},
+		{"lastBuildDate", src.Generated.UTC().Format(indeedDate)},
+	} {
+		err = text(enc, field[0], field[1])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f Indeed) Job(enc *xml.Encoder, src Source, jobData models.Jobs) error {
+	job := indeedJob{
+		Title:           cdata{jobData.Name},
+		Date:            cdata{jobData.CreatedAt.UTC().Format(indeedDate)},
+		ReferenceNumber: cdata{fmt.Sprint(jobData.ID)},
+		URL:             cdata{src.Link(jobData)},
+		Company:         cdata{jobData.Company.Name},
+		City:            cdata{jobData.Location},
+		Country:         cdata{f.Country},
+		Description:     cdata{feed.Summary(jobData)},
+		JobType:         optional(indeedJobTypes[jobData.EmploymentType]),
+		Category:        optional(jobData.Company.Field),
+		Experience:      optional(jobData.Seniority),
+		RemoteType:      optional(indeedRemoteTypes[jobData.RemotePolicy]),
+	}
+	switch {
+	case jobData.MinSalary > 0 && jobData.MaxSalary > 0:
+		job.Salary = optional(fmt.Sprintf("%s %d - %d per year", models.SalaryCurrency, jobData.MinSalary, jobData.MaxSalary))
+	case jobData.MinSalary > 0:
+		job.Salary = optional(fmt.Sprintf("%s %d per year", models.SalaryCurrency, jobData.MinSalary))
+	default:
+		job.Salary = optional(jobData.Salary)
+	}
+	return enc.Encode(job)
+}
+
+func (Indeed) Close(enc *xml.Encoder) error {
+	return enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "source"}})
+}
+
+// optional leaves an empty value out of the job
+func optional(v string) *cdata {
+	if v == "" {
+		return nil
+	}
+	return &cdata{v}
+}
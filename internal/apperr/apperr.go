@@ -0,0 +1,140 @@
+// Package apperr holds the typed errors passed from the repository through the
+// service to the handlers, where the kind decides the status of the response
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind is the class of an error, the zero value is Internal so errors nobody
+// classified never leak details to clients
+type Kind int
+
+const (
+	Internal Kind = iota
+	NotFound
+	Conflict
+	Validation
+	Unauthorized
+	Forbidden
+	PreconditionFailed
+	PreconditionRequired
+	Unprocessable
+	UnsupportedMediaType
+	Unavailable
+	TooLarge
+)
+
+var kindNames = map[Kind]string{
+	Internal:     "internal",
+	NotFound:     "not found",
+	Conflict:     "conflict",
+	Validation:   "validation",
+	Unauthorized: "unauthorized",
+	Forbidden:    "forbidden",
+
+	PreconditionFailed:   "precondition failed",
+	PreconditionRequired: "precondition required",
+	Unprocessable:        "unprocessable",
+	UnsupportedMediaType: "unsupported media type",
+	Unavailable:          "unavailable",
+	TooLarge:             "too large",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Status is the http status a response for the kind carries
+func (k Kind) Status() int {
+	switch k {
+	case NotFound:
+		return http.StatusNotFound
+	case Conflict:
+		return http.StatusConflict
+	case Validation:
+		return http.StatusBadRequest
+	case Unauthorized:
+		return http.StatusUnauthorized
+	case Forbidden:
+		return http.StatusForbidden
+	case PreconditionFailed:
+		return http.StatusPreconditionFailed
+	case PreconditionRequired:
+		return http.StatusPreconditionRequired
+	case Unprocessable:
+		return http.StatusUnprocessableEntity
+	case UnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case TooLarge:
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusInternalServerError
+}
+
+// Error is an error of a known kind. Msg is written for clients, Err is the cause
+// and only ends up in the logs
+type Error struct {
+	Kind Kind
+	Msg  string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = e.Kind.String()
+	}
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is makes every error match the sentinel of its kind, errors.Is(err, ErrNotFound)
+// holds for any not found error
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Msg == "" && t.Err == nil && t.Kind == e.Kind
+}
+
+// sentinels to test the kind of an error with errors.Is
+var (
+	ErrInternal     = &Error{Kind: Internal}
+	ErrNotFound     = &Error{Kind: NotFound}
+	ErrConflict     = &Error{Kind: Conflict}
+	ErrValidation   = &Error{Kind: Validation}
+	ErrUnauthorized = &Error{Kind: Unauthorized}
+	ErrForbidden    = &Error{Kind: Forbidden}
+
+	ErrPreconditionFailed   = &Error{Kind: PreconditionFailed}
+	ErrPreconditionRequired = &Error{Kind: PreconditionRequired}
+	ErrUnprocessable        = &Error{Kind: Unprocessable}
+	ErrUnsupportedMediaType = &Error{Kind: UnsupportedMediaType}
+	ErrUnavailable          = &Error{Kind: Unavailable}
+)
+
+func New(kind Kind, msg string) error {
+	return &Error{Kind: kind, Msg: msg}
+}
+
+// Wrap classifies err, msg replaces its text in responses
+func Wrap(kind Kind, err error, msg string) error {
+	return &Error{Kind: kind, Msg: msg, Err: err}
+}
+
+// KindOf returns the kind of the first Error in the chain of err, Internal if there is none
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return Internal
+}
@@ -0,0 +1,66 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestError_Is(t *testing.T) {
+	cause := errors.New("record not found")
+	err := fmt.Errorf("service: %w", Wrap(NotFound, cause, "could not find the company"))
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("a wrapped not found error does not match ErrNotFound")
+	}
+	if errors.Is(err, ErrConflict) {
+		t.Errorf("a not found error matches ErrConflict")
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("the cause is lost")
+	}
+	forbidden := New(Forbidden, "only owners")
+	if errors.Is(New(Forbidden, "only recruiters"), forbidden) {
+		t.Errorf("two different forbidden errors match each other")
+	}
+	if KindOf(err) != NotFound || KindOf(cause) != Internal {
+		t.Errorf("KindOf() = %v, %v", KindOf(err), KindOf(cause))
+	}
+}
+
+func TestProblemOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Problem
+	}{
+		{
+			name: "typed error shows its message",
+			err:  Wrap(Conflict, errors.New("duplicate key value violates unique constraint"), "could not create the company, it already exists"),
+			want: Problem{Type: "about:blank", Title: "Conflict", Status: http.StatusConflict, Detail: "could not create the company, it already exists"},
+		},
+		{
+			name: "sentinel has no detail",
+			err:  ErrUnauthorized,
+			want: Problem{Type: "about:blank", Title: "Unauthorized", Status: http.StatusUnauthorized},
+		},
+		{
+			name: "untyped error is hidden",
+			err:  errors.New("dial tcp 10.0.0.1:5432: connection refused"),
+			want: Problem{Type: "about:blank", Title: "Internal Server Error", Status: http.StatusInternalServerError},
+		},
+		{
+			name: "validation",
+			err:  New(Validation, "invalid id"),
+			want: Problem{Type: "about:blank", Title: "Bad Request", Status: http.StatusBadRequest, Detail: "invalid id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProblemOf(tt.err); got != tt.want {
+				t.Errorf("ProblemOf() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
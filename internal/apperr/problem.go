@@ -0,0 +1,53 @@
+package apperr
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// ContentType is the media type of problem responses, RFC 7807
+const ContentType = "application/problem+json"
+
+// Problem is the body of every error response
+type Problem struct {
+	Type     string `json:"type" validate:"required"`
+	Title    string `json:"title" validate:"required"`
+	Status   int    `json:"status" validate:"required"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	TraceID  string `json:"trace_id,omitempty"`
+}
+
+// ProblemOf describes err for a client, only the message of a typed error is
+// shown, everything else stays in the logs
+func ProblemOf(err error) Problem {
+	kind := KindOf(err)
+	p := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(kind.Status()),
+		Status: kind.Status(),
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		p.Detail = e.Msg
+	}
+	return p
+}
+
+// Abort logs err and answers the request with its problem
+func Abort(c *gin.Context, traceID string, err error) {
+	p := ProblemOf(err)
+	p.Instance = c.Request.URL.Path
+	p.TraceID = traceID
+
+	if p.Status >= http.StatusInternalServerError {
+		log.Error().Err(err).Str("trace id", traceID).Send()
+	} else {
+		log.Info().Err(err).Str("trace id", traceID).Send()
+	}
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
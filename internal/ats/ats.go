@@ -0,0 +1,104 @@
+// Package ats pulls the public job boards of external applicant tracking
+// systems. A Source reads one board into jobs, the Scheduler pulls the board of
+// every connector as it comes due and hands the jobs over to be synced
+package ats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"project/internal/models"
+	"strings"
+)
+
+// maxBoardSize is the most a board may send, a larger one is refused
+const maxBoardSize = 8 << 20
+
+// Source is the job board of one company. Jobs returns every job the board
+// lists, each with ExternalID set and the company left to the caller
+type Source interface {
+	Jobs(ctx context.Context) ([]models.Jobs, error)
+}
+
+// adapters build the source of each provider
+var adapters = map[string]func(client *http.Client, connector models.ATSConnector) Source{
+	models.ATSGreenhouse: func(client *http.Client, connector models.ATSConnector) Source {
+		return greenhouse{client: client, connector: connector}
+	},
+	models.ATSLever: func(client *http.Client, connector models.ATSConnector) Source {
+		return lever{client: client, connector: connector}
+	},
+}
+
+// NewSource returns the source of the board the connector points at
+func NewSource(client *http.Client, connector models.ATSConnector) (Source, error) {
+	adapter, ok := adapters[connector.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown ats provider %q", connector.Provider)
+	}
+	return adapter(client, connector), nil
+}
+
+// fetch gets url and decodes its json body into v
+func fetch(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		return fmt.Errorf("the board answered %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBoardSize+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > maxBoardSize {
+		return fmt.Errorf("the board is larger than %d bytes", maxBoardSize)
+	}
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		return fmt.Errorf("the board is not valid json: %w", err)
+	}
+	return nil
+}
+
+// employmentType maps the way boards name employment types to the ones of a
+// job, unknown ones are left out
+func employmentType(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case strings.Contains(s, "intern"):
+		return models.EmploymentInternship
+	case strings.Contains(s, "part"):
+		return models.EmploymentPartTime
+	case strings.Contains(s, "contract"), strings.Contains(s, "temporary"), strings.Contains(s, "freelance"):
+		return models.EmploymentContract
+	case strings.Contains(s, "full"), s == "permanent", s == "regular":
+		return models.EmploymentFullTime
+	}
+	return ""
+}
+
+// remotePolicy reads the remote policy from the way boards describe where a
+// job is done, unknown ones are left out
+func remotePolicy(s string) string {
+	s = strings.ToLower(s)
+	switch {
+	case strings.Contains(s, "hybrid"):
+		return models.RemoteHybrid
+	case strings.Contains(s, "remote"):
+		return models.RemoteFull
+	case strings.Contains(s, "on-site"), strings.Contains(s, "onsite"), strings.Contains(s, "office"):
+		return models.RemoteOnsite
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package ats
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// boards serves the fixtures of testdata as job boards, any other path fails
+func boards(t *testing.T) *httptest.Server {
+	t.Helper()
+	files := http.FileServer(http.Dir("testdata"))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/greenhouse.json", "/lever.json":
+			files.ServeHTTP(w, r)
+		case "/huge.json":
+			w.Write([]byte("["))
+			w.Write([]byte(strings.Repeat(" ", maxBoardSize)))
+			w.Write([]byte("]"))
+		default:
+			http.Error(w, "down", http.StatusBadGateway)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSource_Jobs(t *testing.T) {
+	srv := boards(t)
+	tests := []struct {
+		name      string
+		connector models.ATSConnector
+		want      []models.Jobs
+		wantErr   bool
+	}{
+		{
+			name:      "greenhouse",
+			connector: models.ATSConnector{Provider: models.ATSGreenhouse, URL: srv.URL + "/greenhouse.json"},
+			want: []models.Jobs{
+				{
+					Name:           "Backend Engineer",
+					Location:       "Pune",
+					EmploymentType: models.EmploymentFullTime,
+					MinSalary:      800000,
+					MaxSalary:      1200000,
+					ExternalID:     "greenhouse:4012",
+				},
+				{
+					Name:           "QA Intern",
+					Location:       "Remote - India",
+					EmploymentType: models.EmploymentInternship,
+					RemotePolicy:   models.RemoteFull,
+					ExternalID:     "greenhouse:4013",
+				},
+			},
+		},
+		{
+			name:      "lever",
+			connector: models.ATSConnector{Provider: models.ATSLever, URL: srv.URL + "/lever.json"},
+			want: []models.Jobs{
+				{
+					Name:           "Data Analyst",
+					Location:       "Bengaluru",
+					EmploymentType: models.EmploymentContract,
+					RemotePolicy:   models.RemoteHybrid,
+					MinSalary:      600000,
+					MaxSalary:      900000,
+					ExternalID:     "lever:5ac21346-8e0c-4494-8e7a-3eb92ff77902",
+				},
+				{
+					Name:           "Designer",
+					Location:       "Remote",
+					EmploymentType: models.EmploymentPartTime,
+					RemotePolicy:   models.RemoteFull,
+					ExternalID:     "lever:0c4cbd52-38a4-4b1a-9f0a-c8d9d3c3e2d1",
+				},
+			},
+		},
+		{
+			name:      "board down",
+			connector: models.ATSConnector{Provider: models.ATSLever, URL: srv.URL + "/down"},
+			wantErr:   true,
+		},
+		{
+			name:      "board of the other provider",
+			connector: models.ATSConnector{Provider: models.ATSGreenhouse, URL: srv.URL + "/lever.json"},
+			wantErr:   true,
+		},
+		{
+			name:      "board too large",
+			connector: models.ATSConnector{Provider: models.ATSLever, URL: srv.URL + "/huge.json"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := NewSource(srv.Client(), tt.connector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := source.Jobs(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Jobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Jobs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSource_unknownProvider(t *testing.T) {
+	_, err := NewSource(http.DefaultClient, models.ATSConnector{Provider: "workday"})
+	if err == nil {
+		t.Error("NewSource() accepts an unknown provider")
+	}
+}
@@ -0,0 +1,72 @@
+package ats
+
+import (
+	"context"
+	"net/http"
+	"project/internal/models"
+	"strconv"
+	"strings"
+)
+
+// greenhouse reads a Greenhouse job board, as served at
+// boards-api.greenhouse.io/v1/boards/{board}/jobs?content=true
+type greenhouse struct {
+	client    *http.Client
+	connector models.ATSConnector
+}
+
+type greenhouseBoard struct {
+	Jobs []greenhouseJob `json:"jobs"`
+}
+
+type greenhouseJob struct {
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	Location struct {
+		Name string `json:"name"`
+	} `json:"location"`
+	Metadata []struct {
+		Name  string      `json:"name"`
+		Value interface{} `json:"value"`
+	} `json:"metadata"`
+	PayInputRanges []struct {
+		MinCents     int64  `json:"min_cents"`
+		MaxCents     int64  `json:"max_cents"`
+		CurrencyType string `json:"currency_type"`
+	} `json:"pay_input_ranges"`
+}
+
+func (g greenhouse) Jobs(ctx context.Context) ([]models.Jobs, error) {
+	var board greenhouseBoard
+	err := fetch(ctx, g.client, g.connector.URL, &board)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]models.Jobs, 0, len(board.Jobs))
+	for _, posting := range board.Jobs {
+		if posting.ID == 0 {
+			continue
+		}
+		jobData := models.Jobs{
+			Name:         strings.TrimSpace(posting.Title),
+			Location:     strings.TrimSpace(posting.Location.Name),
+			RemotePolicy: remotePolicy(posting.Location.Name),
+			ExternalID:   g.connector.ExternalID(strconv.FormatInt(posting.ID, 10)),
+		}
+		for _, m := range posting.Metadata {
+			if value, ok := m.Value.(string); ok && strings.EqualFold(m.Name, "Employment Type") {
+				jobData.EmploymentType = employmentType(value)
+			}
+		}
+		// only salaries posted in the currency of the portal are taken over
+		for _, pay := range posting.PayInputRanges {
+			if pay.CurrencyType == models.SalaryCurrency && pay.MinCents <= pay.MaxCents {
+				jobData.MinSalary = int(pay.MinCents / 100)
+				jobData.MaxSalary = int(pay.MaxCents / 100)
+				break
+			}
+		}
+		jobs = append(jobs, jobData)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,63 @@
+package ats
+
+import (
+	"context"
+	"net/http"
+	"project/internal/models"
+	"strings"
+)
+
+// lever reads a Lever job board, as served at
+// api.lever.co/v0/postings/{company}?mode=json
+type lever struct {
+	client    *http.Client
+	connector models.ATSConnector
+}
+
+type leverPosting struct {
+	ID         string `json:"id"`
+	Text       string `json:"text"`
+	Categories struct {
+		Commitment string `json:"commitment"`
+		Location   string `json:"location"`
+	} `json:"categories"`
+	WorkplaceType string `json:"workplaceType"`
+	SalaryRange   *struct {
+		Currency string `json:"currency"`
+		Interval string `json:"interval"`
+		Min      int    `json:"min"`
+		Max      int    `json:"max"`
+	} `json:"salaryRange"`
+}
+
+func (l lever) Jobs(ctx context.Context) ([]models.Jobs, error) {
+	var postings []leverPosting
+	err := fetch(ctx, l.client, l.connector.URL, &postings)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]models.Jobs, 0, len(postings))
+	for _, posting := range postings {
+		if posting.ID == "" {
+			continue
+		}
+		jobData := models.Jobs{
+			Name:           strings.TrimSpace(posting.Text),
+			Location:       strings.TrimSpace(posting.Categories.Location),
+			EmploymentType: employmentType(posting.Categories.Commitment),
+			RemotePolicy:   remotePolicy(posting.WorkplaceType),
+			ExternalID:     l.connector.ExternalID(posting.ID),
+		}
+		if jobData.RemotePolicy == "" {
+			jobData.RemotePolicy = remotePolicy(posting.Categories.Location)
+		}
+		// salaries are yearly, others are not taken over
+		salary := posting.SalaryRange
+		if salary != nil && salary.Currency == models.SalaryCurrency && salary.Interval == "per-year-salary" && salary.Min <= salary.Max {
+			jobData.MinSalary = salary.Min
+			jobData.MaxSalary = salary.Max
+		}
+		jobs = append(jobs, jobData)
+	}
+	return jobs, nil
+}
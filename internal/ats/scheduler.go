@@ -0,0 +1,160 @@
+package ats
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project/internal/egress"
+	"project/internal/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Queue is where connectors wait for their next pull, the repository keeps them
+// in the database so several api instances can share them
+type Queue interface {
+	ClaimConnectors(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.ATSConnector, error)
+	SaveSync(ctx context.Context, connector models.ATSConnector) error
+}
+
+// Syncer brings the jobs of the company of the connector in line with the jobs
+// its board lists
+type Syncer interface {
+	SyncATSJobs(ctx context.Context, connector models.ATSConnector, postings []models.Jobs) (models.ATSSync, error)
+}
+
+// Scheduler pulls the board of every connector as it comes due. A board that
+// cannot be read is tried again at the next pull and closes none of the jobs
+type Scheduler struct {
+	queue    Queue
+	syncer   Syncer
+	client   *http.Client
+	every    time.Duration
+	batch    int
+	interval time.Duration
+	now      func() time.Time
+}
+
+// Option changes the default configuration of the scheduler
+type Option func(*Scheduler)
+
+// WithClient reads boards with c, the default client gives up after 30s and
+// only reaches public addresses, see egress.NewClient
+func WithClient(c *http.Client) Option {
+	return func(s *Scheduler) {
+		s.client = c
+	}
+}
+
+// WithEvery sets how often the board of a connector is pulled, hourly by default
+func WithEvery(every time.Duration) Option {
+	return func(s *Scheduler) {
+		s.every = every
+	}
+}
+
+// WithInterval sets how often Run looks for due connectors, every minute by default
+func WithInterval(interval time.Duration) Option {
+	return func(s *Scheduler) {
+		s.interval = interval
+	}
+}
+
+func NewScheduler(q Queue, syncer Syncer, opts ...Option) (*Scheduler, error) {
+	if q == nil || syncer == nil {
+		return nil, errors.New("queue and syncer cannot be null")
+	}
+	s := &Scheduler{
+		queue:    q,
+		syncer:   syncer,
+		client:   egress.NewClient(30 * time.Second),
+		every:    time.Hour,
+		batch:    5,
+		interval: time.Minute,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.every <= 0 || s.interval <= 0 {
+		return nil, errors.New("ats pull and interval must be positive")
+	}
+	return s, nil
+}
+
+// Run pulls due boards until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := s.SyncDue(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("ats pulls stopped")
+			}
+			// a full batch means more may be waiting
+			if err != nil || n < s.batch {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncDue pulls one batch of due boards and returns how many it tried
+func (s *Scheduler) SyncDue(ctx context.Context) (int, error) {
+	now := s.now()
+	// the lease outlasts the pulls of the batch, a scheduler that dies midway
+	// leaves the rest to be claimed again once it ends
+	lease := now.Add(time.Duration(s.batch+1) * s.client.Timeout)
+	if s.client.Timeout == 0 {
+		lease = now.Add(time.Hour)
+	}
+	due, err := s.queue.ClaimConnectors(ctx, now, lease, s.batch)
+	if err != nil {
+		return 0, err
+	}
+	for _, connector := range due {
+		connector = s.sync(ctx, connector)
+		err = s.queue.SaveSync(ctx, connector)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(due), nil
+}
+
+// sync pulls the board of the connector and returns it updated with the outcome
+func (s *Scheduler) sync(ctx context.Context, connector models.ATSConnector) models.ATSConnector {
+	connector.NextSyncAt = s.now().Add(s.every)
+	result, err := s.pull(ctx, connector)
+	if err != nil {
+		log.Warn().Err(err).Uint("connector", connector.ID).Uint("cid", connector.Cid).Msg("ats pull failed")
+		connector.LastError = err.Error()
+		return connector
+	}
+	now := s.now()
+	connector.LastSyncedAt = &now
+	connector.LastSync = result
+	connector.LastError = ""
+	log.Info().Uint("connector", connector.ID).Uint("cid", connector.Cid).
+		Int("created", result.Created).Int("updated", result.Updated).Int("closed", result.Closed).Msg("ats pulled")
+	return connector
+}
+
+func (s *Scheduler) pull(ctx context.Context, connector models.ATSConnector) (models.ATSSync, error) {
+	source, err := NewSource(s.client, connector)
+	if err != nil {
+		return models.ATSSync{}, err
+	}
+	postings, err := source.Jobs(ctx)
+	if err != nil {
+		return models.ATSSync{}, err
+	}
+	return s.syncer.SyncATSJobs(ctx, connector, postings)
+}
@@ -0,0 +1,106 @@
+package ats
+
+import (
+	"context"
+	"errors"
+	"project/internal/models"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeQueue hands out its connectors once and keeps the syncs saved
+type fakeQueue struct {
+	due   []models.ATSConnector
+	saved []models.ATSConnector
+}
+
+func (q *fakeQueue) ClaimConnectors(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.ATSConnector, error) {
+	due := q.due
+	q.due = nil
+	return due, nil
+}
+
+func (q *fakeQueue) SaveSync(ctx context.Context, connector models.ATSConnector) error {
+	q.saved = append(q.saved, connector)
+	return nil
+}
+
+// fakeSyncer keeps the postings it was handed
+type fakeSyncer struct {
+	postings map[uint][]models.Jobs
+	err      error
+}
+
+func (s *fakeSyncer) SyncATSJobs(ctx context.Context, connector models.ATSConnector, postings []models.Jobs) (models.ATSSync, error) {
+	if s.err != nil {
+		return models.ATSSync{}, s.err
+	}
+	s.postings[connector.ID] = postings
+	return models.ATSSync{Created: len(postings)}, nil
+}
+
+func TestScheduler_SyncDue(t *testing.T) {
+	srv := boards(t)
+	now := time.Now().Truncate(time.Second)
+	tests := []struct {
+		name      string
+		connector models.ATSConnector
+		syncErr   error
+		wantJobs  int
+		wantError bool
+	}{
+		{name: "pulled", connector: models.ATSConnector{Provider: models.ATSGreenhouse, URL: srv.URL + "/greenhouse.json", LastError: "earlier"}, wantJobs: 2},
+		{name: "board down", connector: models.ATSConnector{Provider: models.ATSLever, URL: srv.URL + "/down"}, wantError: true},
+		{name: "sync failed", connector: models.ATSConnector{Provider: models.ATSLever, URL: srv.URL + "/lever.json"}, syncErr: errors.New("db down"), wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.connector.Model = gorm.Model{ID: 4}
+			tt.connector.Cid = 7
+			q := &fakeQueue{due: []models.ATSConnector{tt.connector}}
+			syncer := &fakeSyncer{postings: map[uint][]models.Jobs{}, err: tt.syncErr}
+			s, err := NewScheduler(q, syncer, WithClient(srv.Client()), WithEvery(30*time.Minute))
+			if err != nil {
+				t.Fatalf("NewScheduler() error = %v", err)
+			}
+			s.now = func() time.Time { return now }
+
+			n, err := s.SyncDue(context.Background())
+			if err != nil || n != 1 {
+				t.Fatalf("SyncDue() = %d, %v, want 1 connector", n, err)
+			}
+			if len(q.saved) != 1 {
+				t.Fatalf("%d syncs saved, want 1", len(q.saved))
+			}
+			saved := q.saved[0]
+			if !saved.NextSyncAt.Equal(now.Add(30 * time.Minute)) {
+				t.Errorf("next pull at %v, want in 30m", saved.NextSyncAt)
+			}
+			if (saved.LastError != "") != tt.wantError {
+				t.Errorf("last error %q, want an error %v", saved.LastError, tt.wantError)
+			}
+			if tt.wantError {
+				if saved.LastSyncedAt != nil || len(syncer.postings) != 0 {
+					t.Errorf("a failed pull is recorded as synced at %v with %v", saved.LastSyncedAt, syncer.postings)
+				}
+				return
+			}
+			if len(syncer.postings[4]) != tt.wantJobs || saved.LastSync.Created != tt.wantJobs || saved.LastSyncedAt == nil || !saved.LastSyncedAt.Equal(now) {
+				t.Errorf("synced %d jobs, saved %+v", len(syncer.postings[4]), saved)
+			}
+		})
+	}
+}
+
+func TestNewScheduler(t *testing.T) {
+	_, err := NewScheduler(&fakeQueue{}, nil)
+	if err == nil {
+		t.Error("NewScheduler() accepts no syncer")
+	}
+	_, err = NewScheduler(&fakeQueue{}, &fakeSyncer{}, WithEvery(0))
+	if err == nil {
+		t.Error("NewScheduler() accepts pulls that never come due")
+	}
+}
@@ -33,6 +33,44 @@ func DbConnection() (*gorm.DB, error) {
 		// If there is an error while migrating, log the error message and stop the program
 		return nil, err
 	}
+	err = db.Migrator().AutoMigrate(&models.Profile{}, &models.Membership{}, &models.ProfileView{}, &models.CompanyVerification{})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Migrator().AutoMigrate(&models.Webhook{}, &models.WebhookDelivery{})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Migrator().AutoMigrate(&models.Notification{})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Migrator().AutoMigrate(&models.OutboxEvent{})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Migrator().AutoMigrate(&models.ATSConnector{})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Migrator().AutoMigrate(&models.Email{})
+	if err != nil {
+		return nil, err
+	}
+
+	// trigram indexes back the typo tolerant job search
+	err = db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error
+	if err != nil {
+		return nil, err
+	}
+	err = db.Exec("CREATE INDEX IF NOT EXISTS idx_jobs_name_trgm ON jobs USING gin (name gin_trgm_ops)").Error
+	if err != nil {
+		return nil, err
+	}
+	err = db.Exec("CREATE INDEX IF NOT EXISTS idx_companies_name_trgm ON companies USING gin (name gin_trgm_ops)").Error
+	if err != nil {
+		return nil, err
+	}
 	return db, nil
 }
 
@@ -0,0 +1,88 @@
+// Package egress guards the requests the api sends to urls its users give it,
+// webhook receivers and job boards, from reaching the network the api runs in
+package egress
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for a url or connection that points into a
+// private, loopback or link-local network
+var ErrForbiddenAddress = errors.New("the address is not on the public internet")
+
+// ErrRedirect is returned for a redirect, the client does not follow them so a
+// public url cannot send it somewhere it would refuse to go
+var ErrRedirect = errors.New("redirects are not followed")
+
+// Public reports whether the address is one the api may connect to
+func Public(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() && addr.IsGlobalUnicast() && !addr.IsPrivate() &&
+		!sharedSpace.Contains(addr) && !uniqueLocal.Contains(addr)
+}
+
+var (
+	// carrier-grade NAT, not private by RFC 1918 but never public either
+	sharedSpace = netip.MustParsePrefix("100.64.0.0/10")
+	uniqueLocal = netip.MustParsePrefix("fc00::/7")
+)
+
+// CheckURL refuses an http url that names a host which is never public, a
+// loopback or private address or localhost. Names are resolved on every
+// connection, where Control checks what they resolve to
+func CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: %s is not an http url", ErrForbiddenAddress, raw)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err == nil && !Public(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// Control is a net.Dialer control refusing connections to addresses that are
+// not public, it runs after the name is resolved so it sees the actual peer
+func Control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !Public(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// NewClient is an http client that only connects to public addresses, goes
+// through no proxy and answers a redirect with ErrRedirect
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second, Control: Control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return ErrRedirect
+		},
+	}
+}
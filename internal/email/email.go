@@ -0,0 +1,170 @@
+// Package email writes and sends the email notifications of the portal. The
+// service renders an email from the templates of the locale of its user and
+// queues it, the Sender works through the queue with a Transport
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"project/internal/models"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// the emails of the portal
+const (
+	TemplateWelcome        = "welcome"
+	TemplateCompanyCreated = "company_created"
+	TemplateJobCreated     = "job_created"
+)
+
+// DefaultLocale is the locale of users who have none, or one without templates
+const DefaultLocale = "en"
+
+// Names are the templates every locale has
+var Names = []string{TemplateWelcome, TemplateCompanyCreated, TemplateJobCreated}
+
+//go:embed templates
+var embedded embed.FS
+
+// Data is what a template is rendered from, the fields an email has nothing
+// for are left zero
+type Data struct {
+	User    models.User
+	Company models.Company
+	Job     models.Jobs
+	// Link is where the email points the user to
+	Link string
+	// Unsubscribe stops the email notifications of the user, the footer offers
+	// it when set
+	Unsubscribe string
+}
+
+// page is what the html layout is rendered from, the subject titles it
+type page struct {
+	Data
+	Subject string
+}
+
+// Templates are the emails of every locale, a directory per locale holds a
+// name.txt defining the "subject" and "text" templates and a name.html defining
+// "body" for every name, next to the "footer" of layout.txt and the "layout" of
+// layout.html
+type Templates struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// DefaultTemplates are the templates this package embeds
+func DefaultTemplates() (*Templates, error) {
+	sub, err := fs.Sub(embedded, "templates")
+	if err != nil {
+		return nil, err
+	}
+	return ParseTemplates(sub)
+}
+
+// ParseTemplates reads the templates of every locale of fsys, a locale missing
+// one of Names or failing to render Sample is an error
+func ParseTemplates(fsys fs.FS) (*Templates, error) {
+	locales, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	t := &Templates{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+	for _, locale := range locales {
+		if !locale.IsDir() {
+			continue
+		}
+		for _, name := range Names {
+			key := locale.Name() + "/" + name
+			t.text[key], err = texttemplate.ParseFS(fsys, locale.Name()+"/layout.txt", key+".txt")
+			if err != nil {
+				return nil, fmt.Errorf("email template %s: %w", key, err)
+			}
+			t.html[key], err = htmltemplate.ParseFS(fsys, locale.Name()+"/layout.html", key+".html")
+			if err != nil {
+				return nil, fmt.Errorf("email template %s: %w", key, err)
+			}
+			_, err = t.render(key, Sample())
+			if err != nil {
+				return nil, fmt.Errorf("email template %s: %w", key, err)
+			}
+		}
+	}
+	if !slices.Contains(t.Locales(), DefaultLocale) {
+		return nil, fmt.Errorf("there are no email templates of the default locale %s", DefaultLocale)
+	}
+	return t, nil
+}
+
+// Locales are the locales there are templates of, sorted
+func (t *Templates) Locales() []string {
+	var locales []string
+	for key := range t.text {
+		locale, _, _ := strings.Cut(key, "/")
+		if !slices.Contains(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+// Render writes the email name in locale, DefaultLocale when there are no
+// templates of locale
+func (t *Templates) Render(name, locale string, data Data) (models.RenderedEmail, error) {
+	if !slices.Contains(Names, name) {
+		return models.RenderedEmail{}, fmt.Errorf("unknown email template %q", name)
+	}
+	key := locale + "/" + name
+	if _, ok := t.text[key]; !ok {
+		key = DefaultLocale + "/" + name
+	}
+	return t.render(key, data)
+}
+
+func (t *Templates) render(key string, data Data) (models.RenderedEmail, error) {
+	var subject, text, html bytes.Buffer
+	err := t.text[key].ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		return models.RenderedEmail{}, err
+	}
+	err = t.text[key].ExecuteTemplate(&text, "text", data)
+	if err != nil {
+		return models.RenderedEmail{}, err
+	}
+	rendered := models.RenderedEmail{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+	}
+	err = t.html[key].ExecuteTemplate(&html, "layout", page{Data: data, Subject: rendered.Subject})
+	if err != nil {
+		return models.RenderedEmail{}, err
+	}
+	rendered.HTML = html.String()
+	return rendered, nil
+}
+
+// Sample is made up data every template renders, the admin preview shows it
+func Sample() Data {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return Data{
+		User:    models.User{Model: gorm.Model{ID: 1, CreatedAt: created}, Username: "asha", Email: "asha@example.com", Locale: DefaultLocale},
+		Company: models.Company{Model: gorm.Model{ID: 7, CreatedAt: created}, Name: "Tek Solutions", Location: "Bengaluru", Field: "software"},
+		Job: models.Jobs{Model: gorm.Model{ID: 11, CreatedAt: created}, Cid: 7, Name: "Backend developer", Location: "Pune",
+			EmploymentType: models.EmploymentFullTime, Status: models.JobPublished},
+		Link:        "https://portal.example/api/v1/jobs/11",
+		Unsubscribe: "https://portal.example/api/v1/emails/unsubscribe?token=sample",
+	}
+}
@@ -0,0 +1,157 @@
+package email
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTemplates_Render(t *testing.T) {
+	templates, err := DefaultTemplates()
+	if err != nil {
+		t.Fatalf("DefaultTemplates() error = %v", err)
+	}
+	if got := strings.Join(templates.Locales(), ","); got != "en,hi" {
+		t.Errorf("Locales() = %s, want en,hi", got)
+	}
+	data := Sample()
+	data.Job.Name = `<script>alert("hi")</script>`
+	tests := []struct {
+		name        string
+		template    string
+		locale      string
+		wantSubject string
+		wantText    []string
+		wantHTML    []string
+	}{
+		{
+			name:        "english",
+			template:    TemplateWelcome,
+			locale:      "en",
+			wantSubject: "Welcome to the job portal, asha",
+			wantText:    []string{"Hi asha,", "asha@example.com", data.Link, "Stop these emails: " + data.Unsubscribe},
+			wantHTML:    []string{`<html lang="en">`, "<title>Welcome to the job portal, asha</title>", `href="https://portal.example/api/v1/emails/unsubscribe?token=sample"`},
+		},
+		{
+			name:        "hindi",
+			template:    TemplateCompanyCreated,
+			locale:      "hi",
+			wantSubject: "Tek Solutions अब जॉब पोर्टल पर है",
+			wantText:    []string{"नमस्ते asha,", "ये ईमेल बंद करें: " + data.Unsubscribe},
+			wantHTML:    []string{`<html lang="hi">`, "<strong>Tek Solutions</strong>"},
+		},
+		{
+			name:        "unknown locale is english",
+			template:    TemplateJobCreated,
+			locale:      "fr",
+			wantSubject: `New job at Tek Solutions: <script>alert("hi")</script>`,
+			wantText:    []string{`<script>alert("hi")</script> is posted at Tek Solutions in Pune.`},
+			// the html escapes what users wrote
+			wantHTML: []string{"<strong>&lt;script&gt;alert(&#34;hi&#34;)&lt;/script&gt;</strong>", "<title>New job at Tek Solutions: &lt;script&gt;"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := templates.Render(tt.template, tt.locale, data)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got.Subject != tt.wantSubject {
+				t.Errorf("Render() subject = %q, want %q", got.Subject, tt.wantSubject)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(got.Text, want) {
+					t.Errorf("Render() text does not contain %q\n%s", want, got.Text)
+				}
+			}
+			for _, want := range tt.wantHTML {
+				if !strings.Contains(got.HTML, want) {
+					t.Errorf("Render() html does not contain %q\n%s", want, got.HTML)
+				}
+			}
+		})
+	}
+
+	noLink := Sample()
+	noLink.Unsubscribe = ""
+	got, err := templates.Render(TemplateWelcome, "en", noLink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got.Text, "Stop these emails") || strings.Contains(got.HTML, "Stop these emails") {
+		t.Errorf("Render() offers to unsubscribe without a link\n%s", got.Text)
+	}
+	_, err = templates.Render("farewell", "en", Sample())
+	if err == nil {
+		t.Error("Render() renders an unknown template")
+	}
+}
+
+func TestParseTemplates(t *testing.T) {
+	complete := func() fstest.MapFS {
+		fsys := fstest.MapFS{
+			"en/layout.txt":  {Data: []byte(`{{define "footer"}}--{{end}}`)},
+			"en/layout.html": {Data: []byte(`{{define "layout"}}<title>{{.Subject}}</title>{{template "body" .}}{{end}}`)},
+		}
+		for _, name := range Names {
+			fsys["en/"+name+".txt"] = &fstest.MapFile{Data: []byte(`{{define "subject"}}hi {{.User.Username}}{{end}}{{define "text"}}hi{{template "footer" .}}{{end}}`)}
+			fsys["en/"+name+".html"] = &fstest.MapFile{Data: []byte(`{{define "body"}}<p>hi</p>{{end}}`)}
+		}
+		return fsys
+	}
+	_, err := ParseTemplates(complete())
+	if err != nil {
+		t.Fatalf("ParseTemplates() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(fsys fstest.MapFS)
+	}{
+		{name: "missing template", change: func(fsys fstest.MapFS) { delete(fsys, "en/welcome.html") }},
+		{name: "unknown field", change: func(fsys fstest.MapFS) {
+			fsys["en/welcome.txt"].Data = []byte(`{{define "subject"}}{{.User.Nickname}}{{end}}{{define "text"}}{{end}}`)
+		}},
+		{name: "no default locale", change: func(fsys fstest.MapFS) {
+			for path, f := range fsys {
+				fsys["hi/"+strings.TrimPrefix(path, "en/")] = f
+				delete(fsys, path)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := complete()
+			tt.change(fsys)
+			_, err := ParseTemplates(fsys)
+			if err == nil {
+				t.Error("ParseTemplates() error = nil")
+			}
+		})
+	}
+}
+
+func TestTokens(t *testing.T) {
+	_, err := NewTokens([]byte("short"))
+	if err == nil {
+		t.Error("NewTokens() accepts a short key")
+	}
+	tokens, _ := NewTokens([]byte("0123456789abcdef"))
+	other, _ := NewTokens([]byte("fedcba9876543210"))
+
+	token := tokens.Sign(42)
+	got, err := tokens.Verify(token)
+	if err != nil || got != 42 {
+		t.Errorf("Verify(Sign(42)) = %d, %v", got, err)
+	}
+	for _, bad := range []string{"", "42", "43" + token[2:], other.Sign(42), token + "x"} {
+		_, err := tokens.Verify(bad)
+		if err == nil {
+			t.Errorf("Verify(%q) accepts the token", bad)
+		}
+	}
+	_, err = (&Tokens{}).Verify((&Tokens{}).Sign(42))
+	if err == nil {
+		t.Error("tokens without a key verify")
+	}
+}
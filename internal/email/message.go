@@ -0,0 +1,83 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"project/internal/models"
+	"strings"
+	"time"
+)
+
+// Build writes the email as a message from from, a multipart/alternative of its
+// text and html. An email with an unsubscribe url offers one click unsubscribing
+// to mail clients, as of RFC 8058
+func Build(e models.Email, from mail.Address, now time.Time) ([]byte, error) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+
+	to := mail.Address{Name: e.Name, Address: e.To}
+	header := []struct{ key, value string }{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", e.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(e, from)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + w.Boundary()},
+	}
+	if e.Unsubscribe != "" {
+		header = append(header,
+			struct{ key, value string }{"List-Unsubscribe", "<" + e.Unsubscribe + ">"},
+			struct{ key, value string }{"List-Unsubscribe-Post", "List-Unsubscribe=One-Click"})
+	}
+	var head bytes.Buffer
+	for _, h := range header {
+		fmt.Fprintf(&head, "%s: %s\r\n", h.key, h.value)
+	}
+	head.WriteString("\r\n")
+
+	for _, part := range []struct{ mediaType, body string }{
+		{"text/plain", e.Text},
+		{"text/html", e.HTML},
+	} {
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.mediaType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		_, err = qp.Write([]byte(strings.ReplaceAll(part.body, "\n", "\r\n")))
+		if err != nil {
+			return nil, err
+		}
+		err = qp.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := w.Close()
+	if err != nil {
+		return nil, err
+	}
+	return append(head.Bytes(), b.Bytes()...), nil
+}
+
+// messageID is unique to the email, at the domain of the sender
+func messageID(e models.Email, from mail.Address) string {
+	_, domain, ok := strings.Cut(from.Address, "@")
+	if !ok || domain == "" {
+		domain = "localhost"
+	}
+	salt := make([]byte, 4)
+	_, _ = rand.Read(salt)
+	return fmt.Sprintf("<email.%d.%s@%s>", e.ID, hex.EncodeToString(salt), domain)
+}
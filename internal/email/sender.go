@@ -0,0 +1,163 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"project/internal/models"
+	"project/internal/webhook"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Queue is where emails wait to be sent, the repository keeps them in the
+// database so none are lost when the api restarts
+type Queue interface {
+	ClaimEmails(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.Email, error)
+	SaveEmail(ctx context.Context, e models.Email) error
+}
+
+// Sender sends the emails that come due. Several can work the same queue, a
+// claimed email is left alone by the others until its lease ends
+type Sender struct {
+	queue       Queue
+	transport   Transport
+	from        mail.Address
+	backoff     webhook.Backoff
+	maxAttempts int
+	batch       int
+	interval    time.Duration
+	timeout     time.Duration
+	now         func() time.Time
+}
+
+// Option changes the default configuration of the sender
+type Option func(*Sender)
+
+// WithBackoff replaces the default backoff, a minute after the first failure
+// doubling up to six hours
+func WithBackoff(b webhook.Backoff) Option {
+	return func(s *Sender) {
+		s.backoff = b
+	}
+}
+
+// WithMaxAttempts sets how many failed attempts mark an email failed, 8 by default
+func WithMaxAttempts(n int) Option {
+	return func(s *Sender) {
+		s.maxAttempts = n
+	}
+}
+
+// WithInterval sets how often Run looks for due emails, every 5s by default
+func WithInterval(interval time.Duration) Option {
+	return func(s *Sender) {
+		s.interval = interval
+	}
+}
+
+// WithTimeout sets how long the transport gets to send one email, 30s by default
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Sender) {
+		s.timeout = timeout
+	}
+}
+
+// NewSender sends the queued emails with t, from the address from
+func NewSender(q Queue, t Transport, from mail.Address, opts ...Option) (*Sender, error) {
+	if q == nil || t == nil {
+		return nil, errors.New("queue and transport cannot be null")
+	}
+	if from.Address == "" {
+		return nil, errors.New("emails need a from address")
+	}
+	s := &Sender{
+		queue:       q,
+		transport:   t,
+		from:        from,
+		backoff:     webhook.Backoff{Base: time.Minute, Max: 6 * time.Hour},
+		maxAttempts: 8,
+		batch:       20,
+		interval:    5 * time.Second,
+		timeout:     30 * time.Second,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.maxAttempts < 1 || s.interval <= 0 || s.timeout <= 0 || s.backoff.Base <= 0 {
+		return nil, errors.New("email attempts, interval, timeout and backoff must be positive")
+	}
+	return s, nil
+}
+
+// Run sends due emails until ctx is done
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := s.SendDue(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("email sending stopped")
+			}
+			// a full batch means more may be waiting
+			if err != nil || n < s.batch {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends one batch of due emails and returns how many it tried
+func (s *Sender) SendDue(ctx context.Context) (int, error) {
+	now := s.now()
+	// the lease outlasts every attempt of the batch, a sender that dies midway
+	// leaves the rest to be claimed again once it ends
+	lease := now.Add(time.Duration(s.batch+1) * s.timeout)
+	due, err := s.queue.ClaimEmails(ctx, now, lease, s.batch)
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range due {
+		err = s.queue.SaveEmail(ctx, s.send(ctx, e))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(due), nil
+}
+
+// send hands the email to the transport and returns it updated with the outcome
+func (s *Sender) send(ctx context.Context, e models.Email) models.Email {
+	e.Attempts++
+	msg, err := Build(e, s.from, s.now())
+	if err == nil {
+		sendCtx, cancel := context.WithTimeout(ctx, s.timeout)
+		err = s.transport.Send(sendCtx, s.from.Address, []string{e.To}, msg)
+		cancel()
+	}
+	if err == nil {
+		now := s.now()
+		e.Status = models.EmailSent
+		e.SentAt = &now
+		e.LastError = ""
+		return e
+	}
+
+	log.Warn().Err(err).Uint("email", e.ID).Str("template", e.Template).Int("attempts", e.Attempts).Msg("email not sent")
+	e.LastError = err.Error()
+	if e.Attempts >= s.maxAttempts {
+		e.Status = models.EmailFailed
+		return e
+	}
+	e.Status = models.EmailPending
+	e.NextAttemptAt = s.now().Add(s.backoff.Delay(e.Attempts))
+	return e
+}
@@ -0,0 +1,106 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"project/internal/models"
+	"project/internal/webhook"
+	"testing"
+	"time"
+)
+
+// fakeQueue hands out its emails once and keeps the attempts saved
+type fakeQueue struct {
+	due   []models.Email
+	saved []models.Email
+}
+
+func (q *fakeQueue) ClaimEmails(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.Email, error) {
+	due := q.due
+	q.due = nil
+	return due, nil
+}
+
+func (q *fakeQueue) SaveEmail(ctx context.Context, e models.Email) error {
+	q.saved = append(q.saved, e)
+	return nil
+}
+
+// fakeTransport keeps the recipients of what it sent, or fails with err
+type fakeTransport struct {
+	to  []string
+	err error
+}
+
+func (f *fakeTransport) Send(ctx context.Context, from string, to []string, msg []byte) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.to = append(f.to, to...)
+	return nil
+}
+
+func TestSender_SendDue(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	down := errors.New("the mail server is down")
+	tests := []struct {
+		name         string
+		attempts     int
+		sendErr      error
+		wantStatus   string
+		wantAttempts int
+		wantNext     time.Time
+	}{
+		{name: "sent", wantStatus: models.EmailSent, wantAttempts: 1},
+		{name: "server down", attempts: 2, sendErr: down, wantStatus: models.EmailPending, wantAttempts: 3, wantNext: now.Add(4 * time.Second)},
+		{name: "server down for the last time", attempts: 4, sendErr: down, wantStatus: models.EmailFailed, wantAttempts: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := sampleEmail()
+			e.Status = models.EmailPending
+			e.Attempts = tt.attempts
+			q := &fakeQueue{due: []models.Email{e}}
+			transport := &fakeTransport{err: tt.sendErr}
+			s, err := NewSender(q, transport, mail.Address{Address: "no-reply@portal.example"},
+				WithBackoff(webhook.Backoff{Base: time.Second, Max: time.Minute}), WithMaxAttempts(5))
+			if err != nil {
+				t.Fatalf("NewSender() error = %v", err)
+			}
+			s.now = func() time.Time { return now }
+
+			n, err := s.SendDue(context.Background())
+			if err != nil || n != 1 {
+				t.Fatalf("SendDue() = %d, %v, want 1 email", n, err)
+			}
+			if len(q.saved) != 1 {
+				t.Fatalf("%d attempts saved, want 1", len(q.saved))
+			}
+			saved := q.saved[0]
+			if saved.Status != tt.wantStatus || saved.Attempts != tt.wantAttempts {
+				t.Errorf("saved %s after %d attempts, want %s after %d", saved.Status, saved.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+			if !tt.wantNext.IsZero() && !saved.NextAttemptAt.Equal(tt.wantNext) {
+				t.Errorf("next attempt at %v, want %v", saved.NextAttemptAt, tt.wantNext)
+			}
+			if (saved.SentAt != nil) != (tt.wantStatus == models.EmailSent) || (saved.LastError != "") == (tt.sendErr == nil) {
+				t.Errorf("sent at %v with error %q", saved.SentAt, saved.LastError)
+			}
+			if tt.sendErr == nil && (len(transport.to) != 1 || transport.to[0] != "asha@example.com") {
+				t.Errorf("sent to %v", transport.to)
+			}
+		})
+	}
+}
+
+func TestNewSender(t *testing.T) {
+	_, err := NewSender(&fakeQueue{}, &fakeTransport{}, mail.Address{})
+	if err == nil {
+		t.Error("NewSender() accepts no from address")
+	}
+	_, err = NewSender(&fakeQueue{}, nil, mail.Address{Address: "no-reply@portal.example"})
+	if err == nil {
+		t.Error("NewSender() accepts no transport")
+	}
+}
@@ -0,0 +1,137 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"sync"
+	"time"
+)
+
+// Transport hands a built message over for delivery to its recipients
+type Transport interface {
+	Send(ctx context.Context, from string, to []string, msg []byte) error
+}
+
+// SMTP sends through a mail server, over TLS when the server offers STARTTLS
+type SMTP struct {
+	// Addr is the host:port of the server
+	Addr string
+	// Auth signs in to the server when set, the server has to offer AUTH
+	Auth smtp.Auth
+	// TLS configures STARTTLS, by default the certificate is checked against
+	// the host of Addr
+	TLS *tls.Config
+}
+
+// Send delivers msg in one SMTP session, given up when ctx is done
+func (s SMTP) Send(ctx context.Context, from string, to []string, msg []byte) error {
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		config := s.TLS
+		if config == nil {
+			config = &tls.Config{ServerName: host}
+		}
+		err = c.StartTLS(config)
+		if err != nil {
+			return err
+		}
+	}
+	if s.Auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("the mail server does not offer AUTH")
+		}
+		err = c.Auth(s.Auth)
+		if err != nil {
+			return err
+		}
+	}
+	err = c.Mail(from)
+	if err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		err = c.Rcpt(rcpt)
+		if err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(msg)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Mbox appends every message to a file in the mboxrd format, mail clients open
+// it as a mailbox. It sends nothing, it is meant for development
+type Mbox struct {
+	path string
+	now  func() time.Time
+	mu   sync.Mutex
+}
+
+func NewMbox(path string) *Mbox {
+	return &Mbox{path: path, now: time.Now}
+}
+
+func (m *Mbox) Send(ctx context.Context, from string, to []string, msg []byte) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From %s %s\n", from, m.now().UTC().Format(time.ANSIC))
+	msg = bytes.ReplaceAll(msg, []byte("\r\n"), []byte("\n"))
+	for _, line := range bytes.SplitAfter(msg, []byte("\n")) {
+		// a line starting with From, after any >, would start the next message
+		if bytes.HasPrefix(bytes.TrimLeft(line, ">"), []byte("From ")) {
+			b.WriteByte('>')
+		}
+		b.Write(line)
+	}
+	if !bytes.HasSuffix(msg, []byte("\n")) {
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b.Bytes())
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
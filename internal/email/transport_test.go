@@ -0,0 +1,165 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"project/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func sampleEmail() models.Email {
+	return models.Email{
+		Model:         gorm.Model{ID: 5},
+		To:            "asha@example.com",
+		Name:          "asha",
+		RenderedEmail: models.RenderedEmail{Subject: "नमस्ते asha", Text: "From the portal\nbye\n", HTML: "<p>bye</p>\n"},
+		Unsubscribe:   "https://portal.example/api/v1/emails/unsubscribe?token=1.abc",
+	}
+}
+
+func TestBuild(t *testing.T) {
+	from := mail.Address{Name: "Job Portal", Address: "no-reply@portal.example"}
+	b, err := Build(sampleEmail(), from, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(b)))
+	if err != nil {
+		t.Fatalf("the message cannot be read: %v\n%s", err, b)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "नमस्ते asha" {
+		t.Errorf("subject = %q, %v", subject, err)
+	}
+	if to := msg.Header.Get("To"); to != `"asha" <asha@example.com>` {
+		t.Errorf("to = %s", to)
+	}
+	if msg.Header.Get("List-Unsubscribe") != "<https://portal.example/api/v1/emails/unsubscribe?token=1.abc>" ||
+		msg.Header.Get("List-Unsubscribe-Post") != "List-Unsubscribe=One-Click" {
+		t.Errorf("one click unsubscribe is not offered: %v", msg.Header)
+	}
+	if id := msg.Header.Get("Message-ID"); !strings.HasPrefix(id, "<email.5.") || !strings.HasSuffix(id, "@portal.example>") {
+		t.Errorf("message id = %s", id)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %s, %v", mediaType, err)
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	for {
+		p, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(p)
+		bodies = append(bodies, p.Header.Get("Content-Type")+": "+string(body))
+	}
+	want := []string{"text/plain; charset=utf-8: From the portal\r\nbye\r\n", "text/html; charset=utf-8: <p>bye</p>\r\n"}
+	if strings.Join(bodies, "|") != strings.Join(want, "|") {
+		t.Errorf("parts = %q, want %q", bodies, want)
+	}
+}
+
+func TestMbox_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.mbox")
+	m := NewMbox(path)
+	m.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	for i := 0; i < 2; i++ {
+		err := m.Send(context.Background(), "no-reply@portal.example", []string{"asha@example.com"}, []byte("Subject: hi\r\n\r\nFrom the portal\r\n>From before\r\n"))
+		if err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	one := "From no-reply@portal.example Tue Jan  2 03:04:05 2024\nSubject: hi\n\n>From the portal\n>>From before\n\n"
+	if string(got) != one+one {
+		t.Errorf("the mailbox holds\n%q\nwant\n%q", got, one+one)
+	}
+}
+
+// fakeSMTP answers one session and sends what was sent in it on the channel
+func fakeSMTP(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	session := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var got strings.Builder
+		r := bufio.NewReader(conn)
+		reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+		reply("220 fake ready")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				session <- got.String()
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250-fake\r\n250 8BITMIME")
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				got.WriteString(strings.TrimSpace(line) + "\n")
+				reply("250 ok")
+			case cmd == "DATA":
+				reply("354 go on")
+				for {
+					data, _ := r.ReadString('\n')
+					if data == ".\r\n" {
+						break
+					}
+					got.WriteString(data)
+				}
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				session <- got.String()
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+	return l.Addr().String(), session
+}
+
+func TestSMTP_Send(t *testing.T) {
+	addr, session := fakeSMTP(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := SMTP{Addr: addr}.Send(ctx, "no-reply@portal.example", []string{"asha@example.com"}, []byte("Subject: hi\r\n\r\nbye\r\n"))
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	want := "MAIL FROM:<no-reply@portal.example> BODY=8BITMIME\nRCPT TO:<asha@example.com>\nSubject: hi\r\n\r\nbye\r\n"
+	if got := <-session; got != want {
+		t.Errorf("the server got %q, want %q", got, want)
+	}
+}
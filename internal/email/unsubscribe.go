@@ -0,0 +1,83 @@
+package email
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrBadToken is returned for an unsubscribe token that was not signed with the key
+var ErrBadToken = errors.New("invalid unsubscribe token")
+
+// Tokens signs the unsubscribe tokens of users. A token does not expire, it
+// only stops working when the key changes
+type Tokens struct {
+	key []byte
+}
+
+// NewTokens signs with key, at least 16 bytes of it
+func NewTokens(key []byte) (*Tokens, error) {
+	if len(key) < 16 {
+		return nil, errors.New("the unsubscribe key must be at least 16 bytes")
+	}
+	return &Tokens{key: key}, nil
+}
+
+// Sign returns the unsubscribe token of the user
+func (t *Tokens) Sign(userID uint) string {
+	id := strconv.FormatUint(uint64(userID), 10)
+	return id + "." + base64.RawURLEncoding.EncodeToString(t.mac(id))
+}
+
+// Verify returns the user the token unsubscribes
+func (t *Tokens) Verify(token string) (uint, error) {
+	if len(t.key) == 0 {
+		return 0, errors.New("the unsubscribe tokens have no key")
+	}
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, ErrBadToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, t.mac(id)) {
+		return 0, ErrBadToken
+	}
+	userID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrBadToken, err)
+	}
+	return uint(userID), nil
+}
+
+func (t *Tokens) mac(id string) []byte {
+	m := hmac.New(sha256.New, t.key)
+	m.Write([]byte("unsubscribe:" + id))
+	return m.Sum(nil)[:16]
+}
+
+// Links are the urls of the api emails point to
+type Links struct {
+	// BaseURL is the scheme and host the api is reached at
+	BaseURL string
+}
+
+func (l Links) Profile() string {
+	return l.BaseURL + "/api/v1/me/profile"
+}
+
+func (l Links) Company(cid uint) string {
+	return l.BaseURL + "/api/v1/companies/" + strconv.FormatUint(uint64(cid), 10)
+}
+
+func (l Links) Job(jid uint) string {
+	return l.BaseURL + "/api/v1/jobs/" + strconv.FormatUint(uint64(jid), 10)
+}
+
+func (l Links) Unsubscribe(token string) string {
+	return l.BaseURL + "/api/v1/emails/unsubscribe?token=" + url.QueryEscape(token)
+}
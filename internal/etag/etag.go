@@ -0,0 +1,54 @@
+// Package etag builds strong entity tags for stored records and compares them
+// with the If-Match and If-None-Match headers of a request
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Of is the tag of one record, it changes whenever the record is updated
+func Of(kind string, id uint, updatedAt time.Time) string {
+	return sum(kind + "/" + strconv.FormatUint(uint64(id), 10) + "/" + strconv.FormatInt(updatedAt.UnixNano(), 10))
+}
+
+// Combine is the tag of a list, it changes when a record is added, removed or updated
+func Combine(tags ...string) string {
+	return sum(strings.Join(tags, ","))
+}
+
+// Body is the tag of a representation that is not a plain record, such as one
+// with included resources
+func Body(b []byte) string {
+	return sum(string(b))
+}
+
+func sum(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return `"` + hex.EncodeToString(h[:16]) + `"`
+}
+
+// Match reports whether header, the value of If-Match or If-None-Match, lists
+// tag or is "*". If-None-Match compares weakly and ignores the W/ prefix, If-Match
+// compares strongly and never matches a weak tag
+func Match(header, tag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = candidate[len("W/"):]
+		}
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package etag
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOf(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	tag := Of("company", 1, at)
+	if tag != Of("company", 1, at.In(time.FixedZone("IST", 19800))) {
+		t.Errorf("Of() depends on the time zone")
+	}
+	for _, other := range []string{Of("job", 1, at), Of("company", 2, at), Of("company", 1, at.Add(time.Microsecond))} {
+		if other == tag {
+			t.Errorf("Of() = %s for a different record or version", other)
+		}
+	}
+	if Combine(tag) == Combine(tag, tag) || Combine() == Combine(tag) {
+		t.Errorf("Combine() ignores a record")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tag := Of("company", 1, time.Unix(0, 0))
+	tests := []struct {
+		name   string
+		header string
+		weak   bool
+		want   bool
+	}{
+		{name: "empty", header: "", want: false},
+		{name: "same", header: tag, want: true},
+		{name: "any", header: "*", want: true},
+		{name: "in a list", header: `"a", ` + tag + `,"b"`, want: true},
+		{name: "other", header: `"a"`, want: false},
+		{name: "unquoted", header: tag[1 : len(tag)-1], want: false},
+		{name: "weak in if-match", header: "W/" + tag, want: false},
+		{name: "weak in if-none-match", header: "W/" + tag, weak: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.header, tag, tt.weak); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
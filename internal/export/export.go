@@ -0,0 +1,186 @@
+// Package export writes records one at a time as CSV with a header row or as
+// newline delimited json, the formats the imports package reads
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"project/internal/imports"
+	"reflect"
+	"strings"
+)
+
+// Writer writes records of type T. Only the members named in fields are kept,
+// along with ID, and no fields keeps all of them
+type Writer[T any] struct {
+	csv     *csv.Writer
+	json    *json.Encoder
+	columns []column
+	fields  map[string]bool
+	header  bool
+}
+
+type column struct {
+	name  string
+	index []int
+}
+
+// NewWriter writes to w in mediaType, either imports.CSVType or imports.NDJSONType
+func NewWriter[T any](mediaType string, w io.Writer, fields []string) (*Writer[T], error) {
+	ew := &Writer[T]{}
+	if len(fields) > 0 {
+		ew.fields = map[string]bool{"ID": true}
+		for _, name := range fields {
+			ew.fields[name] = true
+		}
+	}
+	switch mediaType {
+	case imports.CSVType:
+		ew.csv = csv.NewWriter(w)
+		for _, c := range columns(reflect.TypeOf(new(T)).Elem(), nil) {
+			if ew.fields == nil || ew.fields[c.name] {
+				ew.columns = append(ew.columns, c)
+			}
+		}
+	case imports.NDJSONType:
+		ew.json = json.NewEncoder(w)
+	default:
+		return nil, fmt.Errorf("cannot export as %s", mediaType)
+	}
+	return ew, nil
+}
+
+// Write writes one record, CSV output is buffered until Flush or until enough is
+// waiting to be sent
+func (w *Writer[T]) Write(v T) error {
+	if w.json != nil {
+		if w.fields == nil {
+			return w.json.Encode(v)
+		}
+		obj, err := w.object(v)
+		if err != nil {
+			return err
+		}
+		return w.json.Encode(obj)
+	}
+
+	err := w.writeHeader()
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v)
+	record := make([]string, 0, len(w.columns))
+	for _, c := range w.columns {
+		s, err := cell(rv.FieldByIndex(c.index))
+		if err != nil {
+			return err
+		}
+		record = append(record, s)
+	}
+	return w.csv.Write(record)
+}
+
+// Flush sends what is buffered, an export without records still gets its header row
+func (w *Writer[T]) Flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	err := w.writeHeader()
+	if err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *Writer[T]) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	names := make([]string, 0, len(w.columns))
+	for _, c := range w.columns {
+		names = append(names, c.name)
+	}
+	return w.csv.Write(names)
+}
+
+// object is the json object of v trimmed to the requested fields
+func (w *Writer[T]) object(v T) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]json.RawMessage
+	err = json.Unmarshal(b, &obj)
+	if err != nil {
+		return nil, err
+	}
+	for key := range obj {
+		if !w.fields[key] {
+			delete(obj, key)
+		}
+	}
+	return obj, nil
+}
+
+// columns lists the members of t under their json names in the order json writes
+// them, embedded structs without a json name contribute their own members
+func columns(t reflect.Type, index []int) []column {
+	var cols []column
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		at := append(append([]int(nil), index...), i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			cols = append(cols, columns(f.Type, at)...)
+			continue
+		}
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		cols = append(cols, column{name: name, index: at})
+	}
+	return cols
+}
+
+// cell formats v as it appears in json without quotes, lists are separated by
+// semicolons and null is empty. Text starting like a formula is prefixed with a
+// quote so spreadsheets show it instead of running it
+func cell(v reflect.Value) (string, error) {
+	switch {
+	case v.Kind() == reflect.String:
+		return text(v.String()), nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, v.Index(i).String())
+		}
+		return text(strings.Join(items, ";")), nil
+	}
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(b, []byte("null")) {
+		return "", nil
+	}
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		return text(s), nil
+	}
+	return string(b), nil
+}
+
+func text(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
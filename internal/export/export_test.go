@@ -0,0 +1,115 @@
+package export
+
+import (
+	"bytes"
+	"io"
+	"project/internal/imports"
+	"project/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestWriter(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	jobs := []models.Jobs{
+		{Model: gorm.Model{ID: 1, CreatedAt: created, UpdatedAt: created}, Cid: 7, Name: "developer", Skills: []string{"go", "sql"}, Latitude: -12.5},
+		{Model: gorm.Model{ID: 2, CreatedAt: created, UpdatedAt: created}, Cid: 7, Name: "=HYPERLINK(\"x\")", Salary: "10, negotiable"},
+	}
+	tests := []struct {
+		name      string
+		mediaType string
+		fields    []string
+		jobs      []models.Jobs
+		want      string
+	}{
+		{
+			name:      "csv of some fields",
+			mediaType: imports.CSVType,
+			fields:    []string{"skills", "name", "salary", "latitude"},
+			jobs:      jobs,
+			want: "ID,name,salary,skills,latitude\n" +
+				"1,developer,,go;sql,-12.5\n" +
+				"2,\"'=HYPERLINK(\"\"x\"\")\",\"10, negotiable\",,0\n",
+		},
+		{
+			name:      "csv of all fields",
+			mediaType: imports.CSVType,
+			jobs:      jobs[:1],
+			want: "ID,CreatedAt,UpdatedAt,DeletedAt,cid,name,salary,notice_period,location,employment_type,remote_policy,min_salary,max_salary,skills,seniority,latitude,longitude,status,external_id\n" +
+				"1,2024-01-02T03:04:05Z,2024-01-02T03:04:05Z,,7,developer,,,,,,0,0,go;sql,,-12.5,0,,\n",
+		},
+		{
+			name:      "empty csv",
+			mediaType: imports.CSVType,
+			fields:    []string{"name"},
+			want:      "ID,name\n",
+		},
+		{
+			name:      "ndjson of some fields",
+			mediaType: imports.NDJSONType,
+			fields:    []string{"name", "skills"},
+			jobs:      jobs,
+			want: `{"ID":1,"name":"developer","skills":["go","sql"]}` + "\n" +
+				`{"ID":2,"name":"=HYPERLINK(\"x\")"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter[models.Jobs](tt.mediaType, &buf, tt.fields)
+			if err != nil {
+				t.Fatalf("NewWriter() error = %v", err)
+			}
+			for _, job := range tt.jobs {
+				err = w.Write(job)
+				if err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			err = w.Flush()
+			if err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("wrote\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+// the csv written is buffered and reaches w in pieces, not all at the end
+func TestWriter_streams(t *testing.T) {
+	w := &countingWriter{}
+	ew, err := NewWriter[models.Company](imports.CSVType, w, nil)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for i := 0; i < 1000; i++ {
+		err = ew.Write(models.Company{Name: strings.Repeat("a", 100)})
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if w.writes == 0 {
+		t.Errorf("nothing was written before Flush")
+	}
+}
+
+type countingWriter struct {
+	writes int
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.writes++
+	return io.Discard.Write(b)
+}
+
+func TestNewWriter_unknownType(t *testing.T) {
+	_, err := NewWriter[models.Jobs]("application/json", io.Discard, nil)
+	if err == nil {
+		t.Errorf("NewWriter() error = %v, want an error", err)
+	}
+}
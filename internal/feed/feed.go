@@ -0,0 +1,252 @@
+// Package feed writes lists of jobs as RSS 2.0 and Atom feeds
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"project/internal/models"
+	"strings"
+	"time"
+)
+
+// media types of the feeds
+const (
+	RSSType  = "application/rss+xml"
+	AtomType = "application/atom+xml"
+)
+
+// Generator names the portal in the feeds it writes
+const Generator = "Job Portal"
+
+// Feed is what both formats are written from. Self is the url of the feed
+// itself and Link the url of what it lists
+type Feed struct {
+	Title       string
+	Description string
+	Self        string
+	Link        string
+	Updated     time.Time
+	Entries     []Entry
+}
+
+// Entry is one item of a feed, Link is its permanent url and doubles as its id
+type Entry struct {
+	Title      string
+	Link       string
+	Summary    string
+	Author     string
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+// JobEntry is the entry of a job whose company is loaded, link is the url of its
+// detail endpoint
+func JobEntry(jobData models.Jobs, link string) Entry {
+	company := jobData.Company.Name
+	if company == "" {
+		company = fmt.Sprintf("company %d", jobData.Cid)
+	}
+	return Entry{
+		Title:      jobData.Name + " at " + company,
+		Link:       link,
+		Summary:    Summary(jobData),
+		Author:     company,
+		Categories: jobData.Skills,
+		Published:  jobData.CreatedAt,
+		Updated:    jobData.UpdatedAt,
+	}
+}
+
+// Summary lists the details of the job a reader scans a feed for
+func Summary(jobData models.Jobs) string {
+	var parts []string
+	add := func(format string, v string) {
+		if v != "" {
+			parts = append(parts, fmt.Sprintf(format, v))
+		}
+	}
+	add("%s", jobData.Location)
+	add("%s", strings.ReplaceAll(jobData.EmploymentType, "_", " "))
+	add("%s", jobData.RemotePolicy)
+	add("%s level", jobData.Seniority)
+	switch {
+	case jobData.MinSalary > 0 && jobData.MaxSalary > 0:
+		add("salary %s", fmt.Sprintf("%d-%d", jobData.MinSalary, jobData.MaxSalary))
+	case jobData.MinSalary > 0:
+		add("salary from %s", fmt.Sprint(jobData.MinSalary))
+	default:
+		add("salary %s", jobData.Salary)
+	}
+	add("notice period %s", jobData.NoticePeriod)
+	if len(jobData.Skills) > 0 {
+		add("skills: %s", strings.Join(jobData.Skills, ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+// rssItem names its company in dc:creator, the author of an rss item has to be an email
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description,omitempty"`
+	Author      string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS writes f as RSS 2.0
+func WriteRSS(w io.Writer, f Feed) error {
+	doc := rss{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			Self:        atomLink{Href: f.Self, Rel: "self", Type: RSSType},
+			Generator:   Generator,
+			Items:       make([]rssItem, 0, len(f.Entries)),
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, e := range f.Entries {
+		item := rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			GUID:        rssGUID{IsPermaLink: true, Value: e.Link},
+			Description: e.Summary,
+			Author:      e.Author,
+			Categories:  e.Categories,
+		}
+		if !e.Published.IsZero() {
+			item.PubDate = e.Published.UTC().Format(time.RFC1123Z)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+	return write(w, doc)
+}
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Author    atomPerson  `xml:"author"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Summary    string         `xml:"summary,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// WriteAtom writes f as an Atom feed. Atom needs an updated time everywhere, a
+// feed that never had entries is dated at the unix epoch
+func WriteAtom(w io.Writer, f Feed) error {
+	doc := atomFeed{
+		ID:       f.Self,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  atomTime(f.Updated),
+		Links: []atomLink{
+			{Href: f.Self, Rel: "self", Type: AtomType},
+			{Href: f.Link, Rel: "alternate"},
+		},
+		Author:    atomPerson{Name: Generator},
+		Generator: Generator,
+		Entries:   make([]atomEntry, 0, len(f.Entries)),
+	}
+	for _, e := range f.Entries {
+		entry := atomEntry{
+			ID:      e.Link,
+			Title:   e.Title,
+			Link:    atomLink{Href: e.Link, Rel: "alternate"},
+			Updated: atomTime(e.Updated),
+			Summary: e.Summary,
+		}
+		if !e.Published.IsZero() {
+			entry.Published = atomTime(e.Published)
+		}
+		if e.Author != "" {
+			entry.Author = &atomPerson{Name: e.Author}
+		}
+		for _, c := range e.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: c})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return write(w, doc)
+}
+
+func atomTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func write(w io.Writer, doc interface{}) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+	return enc.Close()
+}
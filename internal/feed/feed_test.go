@@ -0,0 +1,127 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"project/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func testFeed() Feed {
+	posted := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	job := models.Jobs{
+		Model:          gorm.Model{ID: 11, CreatedAt: posted, UpdatedAt: posted.Add(time.Hour)},
+		Company:        models.Company{Model: gorm.Model{ID: 7}, Name: "tek & co"},
+		Cid:            7,
+		Name:           "developer",
+		NoticePeriod:   "3 weeks",
+		Location:       "pune",
+		EmploymentType: models.EmploymentFullTime,
+		MinSalary:      800000,
+		MaxSalary:      1200000,
+		Skills:         []string{"go", "sql"},
+	}
+	return Feed{
+		Title:   "Jobs",
+		Self:    "https://portal.example/api/v1/jobs.atom?location=pune",
+		Link:    "https://portal.example/api/v1/jobs",
+		Updated: posted.Add(2 * time.Hour),
+		Entries: []Entry{JobEntry(job, "https://portal.example/api/v1/jobs/11")},
+	}
+}
+
+func TestJobEntry(t *testing.T) {
+	e := testFeed().Entries[0]
+	if e.Title != "developer at tek & co" || e.Author != "tek & co" {
+		t.Errorf("JobEntry() title %q by %q", e.Title, e.Author)
+	}
+	want := "pune · full time · salary 800000-1200000 · notice period 3 weeks · skills: go, sql"
+	if e.Summary != want {
+		t.Errorf("JobEntry() summary = %q, want %q", e.Summary, want)
+	}
+	if got := JobEntry(models.Jobs{Cid: 8, Name: "tester"}, "").Title; got != "tester at company 8" {
+		t.Errorf("JobEntry() of a job without its company = %q", got)
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteRSS(&b, testFeed())
+	if err != nil {
+		t.Fatalf("WriteRSS() error = %v", err)
+	}
+	var got struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title      string   `xml:"title"`
+				Link       string   `xml:"link"`
+				GUID       string   `xml:"guid"`
+				Creator    string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				Categories []string `xml:"category"`
+				PubDate    string   `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	err = xml.Unmarshal(b.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("the rss is not well formed: %v\n%s", err, b.String())
+	}
+	if got.Version != "2.0" || got.Channel.LastBuildDate != "Tue, 02 Jan 2024 05:04:05 +0000" || len(got.Channel.Items) != 1 {
+		t.Fatalf("WriteRSS() wrote\n%s", b.String())
+	}
+	item := got.Channel.Items[0]
+	if item.Title != "developer at tek & co" || item.GUID != "https://portal.example/api/v1/jobs/11" ||
+		item.Creator != "tek & co" || item.PubDate != "Tue, 02 Jan 2024 03:04:05 +0000" || len(item.Categories) != 2 {
+		t.Errorf("WriteRSS() item = %+v", item)
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteAtom(&b, testFeed())
+	if err != nil {
+		t.Fatalf("WriteAtom() error = %v", err)
+	}
+	var got struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Updated string   `xml:"updated"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Published string `xml:"published"`
+			Updated   string `xml:"updated"`
+			Author    string `xml:"author>name"`
+		} `xml:"entry"`
+	}
+	err = xml.Unmarshal(b.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("the atom feed is not well formed: %v\n%s", err, b.String())
+	}
+	if got.ID != "https://portal.example/api/v1/jobs.atom?location=pune" || got.Updated != "2024-01-02T05:04:05Z" ||
+		len(got.Links) != 2 || got.Links[0].Rel != "self" || len(got.Entries) != 1 {
+		t.Fatalf("WriteAtom() wrote\n%s", b.String())
+	}
+	entry := got.Entries[0]
+	if entry.ID != "https://portal.example/api/v1/jobs/11" || entry.Published != "2024-01-02T03:04:05Z" ||
+		entry.Updated != "2024-01-02T04:04:05Z" || entry.Author != "tek & co" {
+		t.Errorf("WriteAtom() entry = %+v", entry)
+	}
+
+	// atom has to date even an empty feed
+	b.Reset()
+	err = WriteAtom(&b, Feed{Title: "Jobs"})
+	if err != nil || !strings.Contains(b.String(), "<updated>1970-01-01T00:00:00Z</updated>") {
+		t.Errorf("WriteAtom() of an empty feed = %s, %v", b.String(), err)
+	}
+}
@@ -0,0 +1,83 @@
+// Package fuzzy scores how closely two pieces of text match using edit distance,
+// it backs the typo tolerant search on stores that have no trigram support
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Levenshtein returns the number of single rune insertions, deletions and
+// substitutions needed to turn a into b
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Similarity normalizes the edit distance of a and b into the range 0 to 1,
+// where 1 means equal after case folding and whitespace normalization
+func Similarity(a, b string) float64 {
+	a, b = Normalize(a), Normalize(b)
+	if a == b {
+		return 1
+	}
+	longest := max(len([]rune(a)), len([]rune(b)))
+	return 1 - float64(Levenshtein(a, b))/float64(longest)
+}
+
+// WordSimilarity is the best Similarity between query and any run of consecutive
+// words in text that has as many words as the query, so "sofware enginer" scores
+// high against "Senior Software Engineer"
+func WordSimilarity(query, text string) float64 {
+	qw := strings.Fields(Normalize(query))
+	tw := strings.Fields(Normalize(text))
+	if len(qw) == 0 || len(tw) == 0 {
+		return Similarity(query, text)
+	}
+	if len(tw) <= len(qw) {
+		return Similarity(query, text)
+	}
+
+	q := strings.Join(qw, " ")
+	best := 0.0
+	for i := 0; i+len(qw) <= len(tw); i++ {
+		if s := Similarity(q, strings.Join(tw[i:i+len(qw)], " ")); s > best {
+			best = s
+		}
+	}
+	return best
+}
+
+// Normalize lower cases s, turns punctuation into spaces and collapses whitespace
+func Normalize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
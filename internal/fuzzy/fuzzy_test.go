@@ -0,0 +1,56 @@
+package fuzzy
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"sofware", "software", 1},
+		{"enginer", "engineer", 1},
+		{"bengaluru", "bengaluru", 0},
+		{"héllo", "hello", 1},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if got := Similarity("Software  Engineer", "software engineer"); got != 1 {
+		t.Errorf("Similarity() of equal text = %v, want 1", got)
+	}
+	if got := Similarity("sofware enginer", "software engineer"); got < 0.85 {
+		t.Errorf("Similarity() of a typo = %v, want at least 0.85", got)
+	}
+	if got := Similarity("accountant", "software engineer"); got > 0.3 {
+		t.Errorf("Similarity() of unrelated text = %v, want at most 0.3", got)
+	}
+}
+
+func TestWordSimilarity(t *testing.T) {
+	tests := []struct {
+		query, text string
+		atLeast     float64
+		atMost      float64
+	}{
+		{"sofware enginer", "Senior Software Engineer", 0.85, 1},
+		{"tcs", "TCS", 1, 1},
+		{"infosis", "Infosys Ltd.", 0.85, 1},
+		{"golang developer", "Accountant", 0, 0.3},
+		{"data", "", 0, 0},
+	}
+	for _, tt := range tests {
+		got := WordSimilarity(tt.query, tt.text)
+		if got < tt.atLeast || got > tt.atMost {
+			t.Errorf("WordSimilarity(%q, %q) = %v, want between %v and %v", tt.query, tt.text, got, tt.atLeast, tt.atMost)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package graph serves companies, their jobs and the signed in user over
+// GraphQL, so a client can fetch what it needs of them in one request
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/models"
+	service "project/internal/service"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/rs/zerolog/log"
+)
+
+// Request is the body of a GraphQL request sent over http
+type Request struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response carries what the query resolved to and the errors met on the way,
+// data is null when the query was refused before it ran
+type Response struct {
+	Data   interface{}                `json:"data"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+type Server struct {
+	svc    service.UserService
+	limits Limits
+	schema graphql.Schema
+}
+
+// Option changes the default configuration of the server
+type Option func(*Server)
+
+// WithLimits replaces DefaultLimits
+func WithLimits(l Limits) Option {
+	return func(s *Server) {
+		s.limits = l
+	}
+}
+
+func NewServer(svc service.UserService, opts ...Option) (*Server, error) {
+	if svc == nil {
+		return nil, errors.New("service cannot be null")
+	}
+	s := &Server{svc: svc, limits: DefaultLimits()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.limits.Depth < 1 || s.limits.Complexity < 1 || s.limits.ListSize < 1 {
+		return nil, errors.New("graphql limits must be positive")
+	}
+	schema, err := s.build()
+	if err != nil {
+		return nil, err
+	}
+	s.schema = schema
+	return s, nil
+}
+
+// Execute runs the query on behalf of userID. Queries that do not parse, do not
+// fit the schema or go over the limits are answered with errors only
+func (s *Server) Execute(ctx context.Context, traceID string, userID uint, req Request) Response {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return Response{Errors: gqlerrors.FormatErrors(err)}
+	}
+	vr := graphql.ValidateDocument(&s.schema, doc, nil)
+	if !vr.IsValid {
+		return Response{Errors: vr.Errors}
+	}
+	err = s.limits.measure(s.schema, doc, req.OperationName)
+	if err != nil {
+		return Response{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	r := &request{traceID: traceID, userID: userID}
+	r.companies = newLoader(func(ids []uint) (map[uint]models.Company, error) {
+		return s.svc.CompaniesByIds(ctx, ids)
+	})
+	r.jobs = newLoader(func(cids []uint) (map[uint][]models.Jobs, error) {
+		return s.svc.JobsByCompanyIds(ctx, cids)
+	})
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       context.WithValue(ctx, requestKey{}, r),
+	})
+	return Response{Data: result.Data, Errors: result.Errors}
+}
+
+// request is what the resolvers of one query share
+type request struct {
+	traceID   string
+	userID    uint
+	companies *loader[uint, models.Company]
+	jobs      *loader[uint, []models.Jobs]
+}
+
+type requestKey struct{}
+
+func requestOf(ctx context.Context) *request {
+	r, _ := ctx.Value(requestKey{}).(*request)
+	return r
+}
+
+// safe keeps the causes of failures out of responses. Clients get what a problem
+// response would have told them and the rest is logged, as apperr.Abort does
+func safe(resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		v, err := resolve(p)
+		if err != nil {
+			return nil, clientError(p.Context, err)
+		}
+		if thunk, ok := v.(func() (interface{}, error)); ok {
+			return func() (interface{}, error) {
+				v, err := thunk()
+				if err != nil {
+					return nil, clientError(p.Context, err)
+				}
+				return v, nil
+			}, nil
+		}
+		return v, nil
+	}
+}
+
+func clientError(ctx context.Context, err error) error {
+	var traceID string
+	if r := requestOf(ctx); r != nil {
+		traceID = r.traceID
+	}
+	p := apperr.ProblemOf(err)
+	if p.Status >= http.StatusInternalServerError {
+		log.Error().Err(err).Str("trace id", traceID).Send()
+	} else {
+		log.Info().Err(err).Str("trace id", traceID).Send()
+	}
+	if p.Detail != "" {
+		return errors.New(p.Detail)
+	}
+	return errors.New(p.Title)
+}
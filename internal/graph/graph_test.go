@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"project/internal/apperr"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"strings"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestServer_Execute(t *testing.T) {
+	companies := []models.Company{
+		{Model: gorm.Model{ID: 1}, Name: "tek", Location: "pune", Field: "it"},
+		{Model: gorm.Model{ID: 2}, Name: "acme", Location: "goa", Field: "retail"},
+	}
+	jobs := map[uint][]models.Jobs{
+		1: {{Model: gorm.Model{ID: 10}, Cid: 1, Name: "developer", Skills: []string{"go"}}},
+	}
+	tests := []struct {
+		name      string
+		query     string
+		setup     func(ms *mock_files.MockUserService)
+		want      string
+		wantError string
+	}{
+		{
+			name:  "companies with their jobs in one batch",
+			query: `{ companies { id name jobs { name skills company { name } } } }`,
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewAllCompanies(gomock.Any()).Return(companies, nil)
+				ms.EXPECT().JobsByCompanyIds(gomock.Any(), []uint{1, 2}).Return(jobs, nil).Times(1)
+				ms.EXPECT().CompaniesByIds(gomock.Any(), []uint{1}).
+					Return(map[uint]models.Company{1: companies[0]}, nil).Times(1)
+			},
+			want: `{"companies":[` +
+				`{"id":"1","jobs":[{"company":{"name":"tek"},"name":"developer","skills":["go"]}],"name":"tek"},` +
+				`{"id":"2","jobs":[],"name":"acme"}]}`,
+		},
+		{
+			name:  "the signed in user without a profile",
+			query: `query Me { me { username profile { headline } } }`,
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewUser(gomock.Any(), uint(4)).Return(models.User{Model: gorm.Model{ID: 4}, Username: "bhoomi"}, nil)
+				ms.EXPECT().ViewProfile(gomock.Any(), uint(4)).Return(models.Profile{}, apperr.New(apperr.NotFound, "profile not found"))
+			},
+			want: `{"me":{"profile":null,"username":"bhoomi"}}`,
+		},
+		{
+			name:  "missing company",
+			query: `{ company(id: "9") { name } }`,
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewCompanyDetails(gomock.Any(), uint64(9)).Return(models.Company{}, apperr.New(apperr.NotFound, "company not found"))
+			},
+			want: `{"company":null}`,
+		},
+		{
+			name:  "database failures stay in the logs",
+			query: `{ jobs { name } }`,
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewAllJobs(gomock.Any()).Return(nil, apperr.Wrap(apperr.Internal, errors.New("connection refused"), ""))
+			},
+			want:      `null`,
+			wantError: "Internal Server Error",
+		},
+		{
+			name:      "too deep",
+			query:     `{ companies { jobs { company { jobs { company { jobs { name } } } } } } }`,
+			want:      `null`,
+			wantError: "query is nested too deeply, at most 6 levels are allowed",
+		},
+		{
+			name:      "too deep through fragments",
+			query:     `{ job(id: "1") { ...a } } fragment a on Job { company { jobs { company { jobs { company { name } } } } } }`,
+			want:      `null`,
+			wantError: "query is nested too deeply, at most 6 levels are allowed",
+		},
+		{
+			name:      "too complex",
+			query:     `{ companies { jobs { name company { name location field jobs { name } } } } }`,
+			want:      `null`,
+			wantError: "query is too complex, it is estimated to resolve over 1000 fields",
+		},
+		{
+			name:      "not in the schema",
+			query:     `{ applications { id } }`,
+			want:      `null`,
+			wantError: `Cannot query field "applications" on type "Query".`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			s, err := NewServer(ms)
+			if err != nil {
+				t.Fatalf("NewServer() error = %v", err)
+			}
+
+			resp := s.Execute(context.Background(), "trace", 4, Request{Query: tt.query})
+
+			data, err := json.Marshal(resp.Data)
+			if err != nil {
+				t.Fatalf("data is not json: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("data = %s, want %s", data, tt.want)
+			}
+			var messages []string
+			for _, e := range resp.Errors {
+				messages = append(messages, e.Message)
+			}
+			if got := strings.Join(messages, "; "); got != tt.wantError {
+				t.Errorf("errors = %q, want %q", got, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestNewServer_limits(t *testing.T) {
+	ms := mock_files.NewMockUserService(gomock.NewController(t))
+	_, err := NewServer(ms, WithLimits(Limits{Depth: 3}))
+	if err == nil {
+		t.Errorf("NewServer() error = %v, want an error", err)
+	}
+}
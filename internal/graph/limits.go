@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bound the work a single query can ask for, queries over either are
+// refused before anything is resolved
+type Limits struct {
+	// Depth is how deeply fields can be nested, the fields of the query root are
+	// at depth 1
+	Depth int
+	// Complexity is the estimated number of fields resolved. Every field costs 1
+	// and the selections under a list count ListSize times
+	Complexity int
+	// ListSize is how many items a list is assumed to hold
+	ListSize int
+}
+
+// DefaultLimits allow a company with its jobs and their companies in one query,
+// but not a list of every company with the jobs of each nested below it again
+func DefaultLimits() Limits {
+	return Limits{Depth: 6, Complexity: 1000, ListSize: 10}
+}
+
+// measure checks the operation to be run against the limits. The document must
+// already be valid, fragment cycles in particular are not looked for
+func (l Limits) measure(schema graphql.Schema, doc *ast.Document, operationName string) error {
+	var op *ast.OperationDefinition
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				op = def
+			}
+		case *ast.FragmentDefinition:
+			fragments[def.Name.Value] = def
+		}
+	}
+	if op == nil {
+		return nil
+	}
+	root := schema.QueryType()
+	if op.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+	if root == nil {
+		return nil
+	}
+	m := meter{limits: l, schema: schema, fragments: fragments}
+	_, err := m.walk(root, op.SelectionSet, 1)
+	return err
+}
+
+type meter struct {
+	limits    Limits
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+// walk returns the cost of the selections made on parent at depth, it stops as
+// soon as a limit is passed so a small document cannot keep it busy for long
+func (m meter) walk(parent *graphql.Object, set *ast.SelectionSet, depth int) (int, error) {
+	if set == nil {
+		return 0, nil
+	}
+	cost := 0
+	for _, sel := range set.Selections {
+		var c int
+		var err error
+		switch sel := sel.(type) {
+		case *ast.Field:
+			c, err = m.field(parent, sel, depth)
+		case *ast.InlineFragment:
+			c, err = m.walk(m.on(parent, sel.TypeCondition), sel.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			frag, ok := m.fragments[sel.Name.Value]
+			if ok {
+				c, err = m.walk(m.on(parent, frag.TypeCondition), frag.SelectionSet, depth)
+			}
+		}
+		if err != nil {
+			return 0, err
+		}
+		cost += c
+		if cost > m.limits.Complexity {
+			return 0, fmt.Errorf("query is too complex, it is estimated to resolve over %d fields", m.limits.Complexity)
+		}
+	}
+	return cost, nil
+}
+
+func (m meter) field(parent *graphql.Object, f *ast.Field, depth int) (int, error) {
+	// introspection is bounded by the size of the schema
+	if strings.HasPrefix(f.Name.Value, "__") {
+		return 0, nil
+	}
+	if depth > m.limits.Depth {
+		return 0, fmt.Errorf("query is nested too deeply, at most %d levels are allowed", m.limits.Depth)
+	}
+	def, ok := parent.Fields()[f.Name.Value]
+	if !ok {
+		return 1, nil
+	}
+	t, list := def.Type, false
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.NonNull:
+			t = wrapped.OfType
+			continue
+		case *graphql.List:
+			t, list = wrapped.OfType, true
+			continue
+		}
+		break
+	}
+	obj, ok := t.(*graphql.Object)
+	if !ok {
+		return 1, nil
+	}
+	c, err := m.walk(obj, f.SelectionSet, depth+1)
+	if err != nil {
+		return 0, err
+	}
+	if list {
+		c *= m.limits.ListSize
+	}
+	return 1 + c, nil
+}
+
+// on is the type a fragment selects on, the schema has no interfaces or unions
+// so it is parent unless the condition names another object
+func (m meter) on(parent *graphql.Object, cond *ast.Named) *graphql.Object {
+	if cond == nil {
+		return parent
+	}
+	if obj, ok := m.schema.Type(cond.Name.Value).(*graphql.Object); ok {
+		return obj
+	}
+	return parent
+}
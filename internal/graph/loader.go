@@ -0,0 +1,51 @@
+package graph
+
+// loader batches the lookups made while one level of a query resolves. Resolvers
+// ask for a key and get back a thunk, graphql runs the thunks once every field
+// of the level is resolved, so the first of them fetches all the keys collected
+// so far in one call. A loader belongs to one request, which graphql resolves on
+// a single goroutine
+type loader[K comparable, V any] struct {
+	fetch   func(keys []K) (map[K]V, error)
+	pending []K
+	results map[K]loaded[V]
+}
+
+type loaded[V any] struct {
+	value V
+	found bool
+	err   error
+}
+
+func newLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{fetch: fetch, results: map[K]loaded[V]{}}
+}
+
+// load queues key and returns what it resolves to, found is false when fetch
+// did not return it
+func (l *loader[K, V]) load(key K) func() (value V, found bool, err error) {
+	if _, ok := l.results[key]; !ok {
+		l.pending = append(l.pending, key)
+	}
+	return func() (V, bool, error) {
+		if len(l.pending) > 0 {
+			l.dispatch()
+		}
+		r := l.results[key]
+		return r.value, r.found, r.err
+	}
+}
+
+func (l *loader[K, V]) dispatch() {
+	keys := l.pending
+	l.pending = nil
+	values, err := l.fetch(keys)
+	for _, key := range keys {
+		if err != nil {
+			l.results[key] = loaded[V]{err: err}
+			continue
+		}
+		v, ok := values[key]
+		l.results[key] = loaded[V]{value: v, found: ok}
+	}
+}
@@ -0,0 +1,243 @@
+package graph
+
+import (
+	"errors"
+	"project/internal/apperr"
+	"project/internal/models"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"gorm.io/gorm"
+)
+
+// build makes the types over the service. Field names are the json names the
+// rest of the api uses
+func (s *Server) build() (graphql.Schema, error) {
+	profile := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Profile",
+		Fields: withModel(graphql.Fields{
+			"headline":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"skills":           &graphql.Field{Type: stringList},
+			"location":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"latitude":         &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"longitude":        &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"expected_salary":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"seniority":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"current_employer": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"experience_years": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"availability":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"discoverable":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		}),
+	})
+
+	user := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: withModel(graphql.Fields{
+			"username": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"email":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"profile": &graphql.Field{
+				Type:        profile,
+				Description: "null until the user saves a profile",
+				Resolve:     safe(s.userProfile),
+			},
+		}),
+	})
+
+	company := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Company",
+		Fields: withModel(graphql.Fields{
+			"name":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"location": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"field":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		}),
+	})
+
+	job := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Job",
+		Fields: withModel(graphql.Fields{
+			"cid":             &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: jobCid},
+			"name":            &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"salary":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"notice_period":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"location":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"employment_type": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"remote_policy":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"min_salary":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"max_salary":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"skills":          &graphql.Field{Type: stringList},
+			"seniority":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"latitude":        &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"longitude":       &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"status":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"company":         &graphql.Field{Type: company, Resolve: safe(s.jobCompany)},
+		}),
+	})
+
+	// the two types refer to each other, so one of the fields is added afterwards
+	company.AddFieldConfig("jobs", &graphql.Field{
+		Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(job))),
+		Resolve: safe(s.companyJobs),
+	})
+
+	idArg := graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}}
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"me": &graphql.Field{
+				Type:        graphql.NewNonNull(user),
+				Description: "the signed in user",
+				Resolve:     safe(s.me),
+			},
+			"company": &graphql.Field{
+				Type:        company,
+				Description: "null when there is no company with the id",
+				Args:        idArg,
+				Resolve:     safe(s.company),
+			},
+			"companies": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(company))),
+				Resolve: safe(s.companies),
+			},
+			"job": &graphql.Field{
+				Type:        job,
+				Description: "null when there is no job with the id",
+				Args:        idArg,
+				Resolve:     safe(s.job),
+			},
+			"jobs": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(job))),
+				Resolve: safe(s.jobs),
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+var stringList = graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))
+
+// withModel adds the members of gorm.Model, which the default resolver does not
+// find inside the embedded struct
+func withModel(fields graphql.Fields) graphql.Fields {
+	fields["id"] = &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		return formatID(modelOf(p.Source).ID), nil
+	}}
+	fields["created_at"] = &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		return modelOf(p.Source).CreatedAt, nil
+	}}
+	fields["updated_at"] = &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		return modelOf(p.Source).UpdatedAt, nil
+	}}
+	return fields
+}
+
+func modelOf(source interface{}) gorm.Model {
+	switch v := source.(type) {
+	case models.Company:
+		return v.Model
+	case models.Jobs:
+		return v.Model
+	case models.User:
+		return v.Model
+	case models.Profile:
+		return v.Model
+	}
+	return gorm.Model{}
+}
+
+func formatID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func parseID(p graphql.ResolveParams) (uint64, error) {
+	s, _ := p.Args["id"].(string)
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || id == 0 {
+		return 0, apperr.New(apperr.Validation, "id must be a positive integer")
+	}
+	return id, nil
+}
+
+func jobCid(p graphql.ResolveParams) (interface{}, error) {
+	return formatID(p.Source.(models.Jobs).Cid), nil
+}
+
+func (s *Server) me(p graphql.ResolveParams) (interface{}, error) {
+	return s.svc.ViewUser(p.Context, requestOf(p.Context).userID)
+}
+
+func (s *Server) userProfile(p graphql.ResolveParams) (interface{}, error) {
+	profile, err := s.svc.ViewProfile(p.Context, p.Source.(models.User).ID)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *Server) company(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p)
+	if err != nil {
+		return nil, err
+	}
+	companyData, err := s.svc.ViewCompanyDetails(p.Context, id)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return companyData, nil
+}
+
+func (s *Server) companies(p graphql.ResolveParams) (interface{}, error) {
+	return s.svc.ViewAllCompanies(p.Context)
+}
+
+func (s *Server) job(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p)
+	if err != nil {
+		return nil, err
+	}
+	jobData, err := s.svc.ViewJobById(p.Context, requestOf(p.Context).userID, id)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return jobData, nil
+}
+
+func (s *Server) jobs(p graphql.ResolveParams) (interface{}, error) {
+	return s.svc.ViewAllJobs(p.Context)
+}
+
+// companyJobs and jobCompany go through the loaders of the request, so a list
+// of companies costs one query for all of their jobs
+func (s *Server) companyJobs(p graphql.ResolveParams) (interface{}, error) {
+	thunk := requestOf(p.Context).jobs.load(p.Source.(models.Company).ID)
+	return func() (interface{}, error) {
+		jobDatas, _, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+		if jobDatas == nil {
+			jobDatas = []models.Jobs{}
+		}
+		return jobDatas, nil
+	}, nil
+}
+
+func (s *Server) jobCompany(p graphql.ResolveParams) (interface{}, error) {
+	thunk := requestOf(p.Context).companies.load(p.Source.(models.Jobs).Cid)
+	return func() (interface{}, error) {
+		companyData, found, err := thunk()
+		if err != nil || !found {
+			return nil, err
+		}
+		return companyData, nil
+	}, nil
+}
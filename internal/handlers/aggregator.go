@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"project/internal/aggregator"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/feed"
+	"project/internal/middleware"
+	"project/internal/models"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// aggregatorFormats are the formats of the aggregator feed by name. The portal
+// posts jobs in India, as it posts salaries in rupees
+var aggregatorFormats = map[string]aggregator.Format{
+	aggregator.Generic{}.Name(): aggregator.Generic{},
+	aggregator.Indeed{}.Name():  aggregator.Indeed{Country: "IN"},
+}
+
+// aggregatorFormatNames lists the formats in the order the spec documents them
+func aggregatorFormatNames() []string {
+	names := make([]string, 0, len(aggregatorFormats))
+	for name := range aggregatorFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// aggregatorFormat is the format named in ?format, generic by default
+func aggregatorFormat(c *gin.Context) (aggregator.Format, error) {
+	f, ok := aggregatorFormats[c.DefaultQuery("format", aggregator.Generic{}.Name())]
+	if !ok {
+		return nil, apperr.New(apperr.Validation, "unknown feed format")
+	}
+	return f, nil
+}
+
+func aggregatorSource(c *gin.Context) aggregator.Source {
+	return aggregator.Source{
+		Name:      feed.Generator,
+		URL:       baseURL(c),
+		Generated: time.Now(),
+		Link: func(jobData models.Jobs) string {
+			return jobURL(c, jobData.ID)
+		},
+	}
+}
+
+// AggregatorFeed streams every published job in the feed format of ?format. The
+// jobs a format cannot take are left out, AggregatorReport lists them
+func (h *handler) AggregatorFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+
+	format, err := aggregatorFormat(c)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	// the first few jobs are held back, a failure before they are sent is still
+	// answered with a problem. The buffer is hidden from the xml encoder, which
+	// would flush a *bufio.Writer after every job
+	resp := &exportResponse{c: c, mediaType: aggregator.MediaType}
+	buf := bufio.NewWriter(resp)
+	report, err := h.writeAggregatorFeed(c, struct{ io.Writer }{buf}, format)
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err == nil {
+		resp.start()
+		log.Info().Str("trace id", traceid).Str("format", report.Format).
+			Int("included", report.Included).Int("excluded", len(report.Excluded)).Msg("aggregator feed sent")
+		return
+	}
+	if !resp.started {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	// the status is already sent, the aggregator is left with a cut off feed
+	log.Error().Err(err).Str("trace id", traceid).Msg("aggregator feed stopped")
+	c.Abort()
+}
+
+// AggregatorReport lists which published jobs the feed in the format of ?format
+// includes and which it leaves out and why
+func (h *handler) AggregatorReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+
+	format, err := aggregatorFormat(c)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	report, err := h.writeAggregatorFeed(c, io.Discard, format)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *handler) writeAggregatorFeed(c *gin.Context, w io.Writer, format aggregator.Format) (models.AggregatorReport, error) {
+	jobsWriter, err := aggregator.NewWriter(w, format, aggregatorSource(c))
+	if err != nil {
+		return models.AggregatorReport{}, err
+	}
+	err = h.service.ExportPublishedJobs(c.Request.Context(), jobsWriter.Write)
+	if err != nil {
+		return models.AggregatorReport{}, err
+	}
+	return jobsWriter.Close()
+}
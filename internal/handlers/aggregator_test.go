@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/aggregator"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_API_aggregator(t *testing.T) {
+	tek := models.Company{Model: gorm.Model{ID: 7}, Name: "tek"}
+	jobs := []models.Jobs{
+		{Model: gorm.Model{ID: 11}, Company: tek, Cid: 7, Name: "developer", Location: "pune"},
+		{Model: gorm.Model{ID: 12}, Company: tek, Cid: 7, Name: "tester"},
+	}
+	exported := func(ms *mock_files.MockUserService) {
+		ms.EXPECT().ExportPublishedJobs(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(models.Jobs) error) error {
+				for _, jobData := range jobs {
+					err := fn(jobData)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+	}
+	tests := []struct {
+		name     string
+		path     string
+		token    bool
+		setup    func(ms *mock_files.MockUserService)
+		wantCode int
+		wantType string
+		want     []string
+		wantNot  []string
+	}{
+		{
+			name:     "generic by default",
+			path:     "/api/v1/aggregator/jobs.xml",
+			setup:    exported,
+			wantCode: http.StatusOK,
+			wantType: aggregator.MediaType,
+			want:     []string{"<jobs>", `<job id="11">`, "<url>http://example.com/api/v1/jobs/11/jsonld</url>"},
+			wantNot:  []string{`<job id="12">`},
+		},
+		{
+			name:     "indeed",
+			path:     "/api/v1/aggregator/jobs.xml?format=indeed",
+			setup:    exported,
+			wantCode: http.StatusOK,
+			wantType: aggregator.MediaType,
+			want:     []string{"<source>", "<referencenumber><![CDATA[11]]></referencenumber>", "<country><![CDATA[IN]]></country>"},
+		},
+		{
+			name:     "unknown format",
+			path:     "/api/v1/aggregator/jobs.xml?format=monster",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "failure before the first jobs",
+			path: "/api/v1/aggregator/jobs.xml",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ExportPublishedJobs(gomock.Any(), gomock.Any()).Return(errors.New("connection reset"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "report",
+			path:     "/api/v1/aggregator/report?format=generic",
+			token:    true,
+			setup:    exported,
+			wantCode: http.StatusOK,
+			wantType: "application/json",
+		},
+		{
+			name:     "report needs a token",
+			path:     "/api/v1/aggregator/report",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token {
+				req.Header.Set("Authorization", "Bearer token")
+			}
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.wantType != "" {
+				assert.Equal(t, tt.wantType+"; charset=utf-8", rr.Header().Get("Content-Type"))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(rr.Body.String(), want) {
+					t.Errorf("the feed lacks %s:\n%s", want, rr.Body.String())
+				}
+			}
+			for _, notWant := range tt.wantNot {
+				if strings.Contains(rr.Body.String(), notWant) {
+					t.Errorf("the feed has %s:\n%s", notWant, rr.Body.String())
+				}
+			}
+			if tt.name == "report" {
+				var report models.AggregatorReport
+				err := json.Unmarshal(rr.Body.Bytes(), &report)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := models.AggregatorReport{
+					Format:   "generic",
+					Included: 1,
+					Excluded: []models.AggregatorExclusion{{JobID: 12, Name: "tester", Reasons: []string{"missing location"}}},
+				}
+				if !reflect.DeepEqual(report, want) {
+					t.Errorf("the report is %+v, want %+v", report, want)
+				}
+			}
+		})
+	}
+}
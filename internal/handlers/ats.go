@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/middleware"
+	"project/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func (h *handler) CreateConnector(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	cid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	var connector models.ATSConnector
+	err = json.NewDecoder(c.Request.Body).Decode(&connector)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide a valid provider and url"))
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(connector)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide a valid provider and url"))
+		return
+	}
+
+	connector, err = h.service.CreateConnector(ctx, uid, cid, connector)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, connector)
+}
+
+func (h *handler) Connectors(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	cid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	connectors, err := h.service.Connectors(ctx, uid, cid)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, connectors)
+}
+
+func (h *handler) DeleteConnector(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	ids, err := uintParams(c, "id", "connector_id")
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	err = h.service.DeleteConnector(ctx, uid, ids[0], ids[1])
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
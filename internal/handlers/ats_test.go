@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"project/internal/apperr"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_API_connectors(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	connector := models.ATSConnector{Model: gorm.Model{ID: 3, CreatedAt: created, UpdatedAt: created}, Cid: 42,
+		Provider: models.ATSGreenhouse, URL: "https://boards-api.greenhouse.io/v1/boards/tek/jobs", NextSyncAt: created}
+	synced := connector
+	synced.LastSyncedAt = &created
+	synced.LastSync = models.ATSSync{Created: 2, Closed: 1}
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		setup    func(ms *mock_files.MockUserService)
+		wantCode int
+		want     string
+	}{
+		{
+			name:   "create",
+			method: http.MethodPost,
+			path:   "/api/v1/companies/42/connectors",
+			body:   `{"provider":"greenhouse","url":"https://boards-api.greenhouse.io/v1/boards/tek/jobs"}`,
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().CreateConnector(gomock.Any(), uint(4), uint64(42), models.ATSConnector{Provider: models.ATSGreenhouse, URL: "https://boards-api.greenhouse.io/v1/boards/tek/jobs"}).
+					Return(connector, nil)
+			},
+			wantCode: http.StatusOK,
+			want:     `"provider":"greenhouse"`,
+		},
+		{
+			name:     "create for an unknown provider",
+			method:   http.MethodPost,
+			path:     "/api/v1/companies/42/connectors",
+			body:     `{"provider":"workday","url":"https://tek.example/jobs"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:   "create a second one of the provider",
+			method: http.MethodPost,
+			path:   "/api/v1/companies/42/connectors",
+			body:   `{"provider":"greenhouse","url":"https://boards-api.greenhouse.io/v1/boards/tek/jobs"}`,
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().CreateConnector(gomock.Any(), uint(4), uint64(42), gomock.Any()).
+					Return(models.ATSConnector{}, apperr.New(apperr.Conflict, "the company already has a greenhouse connector"))
+			},
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/api/v1/companies/42/connectors",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().Connectors(gomock.Any(), uint(4), uint64(42)).Return([]models.ATSConnector{synced}, nil)
+			},
+			wantCode: http.StatusOK,
+			want:     `"last_sync":{"created":2,"updated":0,"closed":1,"unchanged":0}`,
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			path:   "/api/v1/companies/42/connectors/3",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().DeleteConnector(gomock.Any(), uint(4), uint64(42), uint64(3)).Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, true, strings.Contains(rr.Body.String(), tt.want))
+		})
+	}
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"project/internal/apperr"
 	"project/internal/auth"
 	"project/internal/middleware"
 	"project/internal/models"
@@ -11,23 +13,18 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/rs/zerolog/log"
 )
 
 func (h *handler) ViewCompany(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
-		log.Error().Msg("traceid missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
-		})
+		apperr.Abort(c, "", errTraceIDMissing)
 		return
 	}
 	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
 	if !ok {
-		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
 		return
 	}
 
@@ -35,49 +32,68 @@ func (h *handler) ViewCompany(c *gin.Context) {
 
 	cid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error":http.StatusText(http.StatusBadRequest)})
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	s, err := parseShape(c, resourceJobs)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
 		return
 	}
 
 	companyData, err := h.service.ViewCompanyDetails(ctx, cid)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		apperr.Abort(c, traceid, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, companyData)
+	body, err := h.shapeCompany(ctx, s, companyData)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	respondTagged(c, traceid, plainTag(s, companyData.ETag), body)
 }
 
 func (h *handler) ViewAllCompanies(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
-		log.Error().Msg("traceid missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
-		})
+		apperr.Abort(c, "", errTraceIDMissing)
 		return
 	}
 	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
 	if !ok {
-		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
 		return
 	}
 
-	companyDetails, err := h.service.ViewAllCompanies(ctx)
+	s, err := parseShape(c, resourceJobs)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	if t := exportType(c); t != "" {
+		streamExport(c, traceid, t, "companies", s, func(write func(models.Company) error) error {
+			return h.service.ExportCompanies(ctx, write)
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, companyDetails)
+	companyDetails, err := h.service.ViewAllCompanies(ctx)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	body, err := h.shapeCompanies(ctx, s, companyDetails)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	respondTagged(c, traceid, plainTag(s, companiesTag(companyDetails)), body)
 
 }
 
@@ -85,50 +101,178 @@ func (h *handler) AddCompany(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
-		log.Error().Msg("traceid missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
-		})
+		apperr.Abort(c, "", errTraceIDMissing)
 		return
 	}
 
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
 	if !ok {
-		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	s, err := parseShape(c, resourceJobs)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
 		return
 	}
 
 	var companyData models.Company
 
-	err := json.NewDecoder(c.Request.Body).Decode(&companyData)
+	err = json.NewDecoder(c.Request.Body).Decode(&companyData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide valid name, location and field",
-		})
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide valid name, location and field"))
 		return
 	}
 
 	validate := validator.New()
 	err = validate.Struct(companyData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide valid name, location and field",
-		})
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide valid name, location and field"))
 		return
 	}
 
-	companyData, err = h.service.AddCompanyDetails(ctx, companyData)
+	companyData, err = h.service.AddCompanyDetails(ctx, companyData, uid)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	body, err := h.shapeCompany(ctx, s, companyData)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	c.JSON(http.StatusOK, body)
+
+}
+
+func (h *handler) UpdateCompany(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	cid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	var companyData models.Company
+	err = json.NewDecoder(c.Request.Body).Decode(&companyData)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide valid name, location and field"))
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(companyData)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide valid name, location and field"))
 		return
 	}
 
+	companyData, err = h.service.UpdateCompany(ctx, uid, cid, companyData, c.GetHeader("If-Match"))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.Header("ETag", companyData.ETag())
 	c.JSON(http.StatusOK, companyData)
+}
+
+func (h *handler) DeleteCompany(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	cid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	err = h.service.DeleteCompany(ctx, uid, cid, c.GetHeader("If-Match"))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *handler) PatchCompany(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	cid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
 
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "could not read the patch"))
+		return
+	}
+
+	companyData, err := h.service.PatchCompany(ctx, uid, cid, func(current models.Company) (models.Company, error) {
+		var patched models.Company
+		err := applyPatch(c.GetHeader("Content-Type"), current, body, &patched, modelMembers...)
+		return patched, err
+	}, c.GetHeader("If-Match"))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.Header("ETag", companyData.ETag())
+	c.JSON(http.StatusOK, companyData)
 }
@@ -39,7 +39,7 @@ func Test_handler_ViewCompany(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"error":"Internal Server Error"}`,
+			expectedResponse:   problemBody(http.StatusInternalServerError, "", "", ""),
 		},
 		{
 			name: "jwtclamis not there",
@@ -54,7 +54,7 @@ func Test_handler_ViewCompany(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   problemBody(http.StatusUnauthorized, "", "", "456"),
 		},
 		{
 			name: "id not found",
@@ -75,7 +75,7 @@ func Test_handler_ViewCompany(t *testing.T) {
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse:   `{"error":"Bad Request"}`,
+			expectedResponse:   problemBody(http.StatusBadRequest, "invalid id", "", "456"),
 		},
 		{
 			name: "no companies to view",
@@ -96,8 +96,8 @@ func Test_handler_ViewCompany(t *testing.T) {
 
 				return c, rr, ms
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse:   `{"error":"error while fetching"}`,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   problemBody(http.StatusInternalServerError, "", "", "456"),
 		},
 	}
 	for _, tt := range tests {
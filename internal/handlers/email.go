@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PreviewEmail renders the email template of ?template in ?locale from made up
+// data, for admins to check the templates
+func (h *handler) PreviewEmail(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	preview, err := h.service.PreviewEmail(ctx, uid, c.Query("template"), c.Query("locale"))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// Unsubscribe stops the email notifications of the user ?token was signed for.
+// Mail clients post to it when the user unsubscribes from the email, as of RFC 8058
+func (h *handler) Unsubscribe(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+
+	unsubscribed, err := h.service.Unsubscribe(ctx, c.Query("token"))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, unsubscribed)
+}
+
+// unsubscribePage asks the user following an unsubscribe link to confirm, its
+// form posts the one click unsubscribe of RFC 8058 to the same url
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Unsubscribe</title></head>
+<body>
+<p>Stop the email notifications of the job portal?</p>
+<form method="post" action="{{.}}">
+<input type="hidden" name="List-Unsubscribe" value="One-Click">
+<button type="submit">Unsubscribe</button>
+</form>
+</body>
+</html>
+`))
+
+// UnsubscribeLink is the unsubscribe link of the email footers, followed by
+// the user. Mail scanners and link previews follow it too, so it only checks
+// the token and asks to confirm, the form posts to Unsubscribe
+func (h *handler) UnsubscribeLink(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+
+	err := h.service.VerifyUnsubscribe(ctx, c.Query("token"))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	var b bytes.Buffer
+	err = unsubscribePage.Execute(&b, c.Request.URL.RequestURI())
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", b.Bytes())
+}
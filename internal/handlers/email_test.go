@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"project/internal/apperr"
+	"project/internal/email"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	service "project/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_API_emails(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		signedIn    bool
+		setup       func(ms *mock_files.MockUserService)
+		wantCode    int
+		want        string
+	}{
+		{
+			name:     "preview",
+			method:   http.MethodGet,
+			path:     "/api/v1/emails/preview?template=welcome&locale=hi",
+			signedIn: true,
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().PreviewEmail(gomock.Any(), uint(4), email.TemplateWelcome, "hi").
+					Return(models.RenderedEmail{Subject: "स्वागत", Text: "नमस्ते", HTML: "<p>नमस्ते</p>"}, nil)
+			},
+			wantCode: http.StatusOK,
+			want:     `"subject":"स्वागत"`,
+		},
+		{
+			name:     "preview by a user",
+			method:   http.MethodGet,
+			path:     "/api/v1/emails/preview?template=welcome",
+			signedIn: true,
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().PreviewEmail(gomock.Any(), uint(4), email.TemplateWelcome, "").Return(models.RenderedEmail{}, service.ErrNotAdmin)
+			},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "preview signed out",
+			method:   http.MethodGet,
+			path:     "/api/v1/emails/preview?template=welcome",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "unsubscribe link",
+			method: http.MethodGet,
+			path:   "/api/v1/emails/unsubscribe?token=3.abc",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().VerifyUnsubscribe(gomock.Any(), "3.abc").Return(nil)
+			},
+			wantCode: http.StatusOK,
+			want:     `<form method="post" action="/api/v1/emails/unsubscribe?token=3.abc">`,
+		},
+		{
+			name:        "one click unsubscribe",
+			method:      http.MethodPost,
+			path:        "/api/v1/emails/unsubscribe?token=3.abc",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "List-Unsubscribe=One-Click",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().Unsubscribe(gomock.Any(), "3.abc").Return(models.Unsubscribed{Email: "asha@example.com", UnsubscribedAt: at}, nil)
+			},
+			wantCode: http.StatusOK,
+			want:     `"email":"asha@example.com"`,
+		},
+		{
+			name:   "forged unsubscribe link",
+			method: http.MethodGet,
+			path:   "/api/v1/emails/unsubscribe?token=3.forged",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().VerifyUnsubscribe(gomock.Any(), "3.forged").
+					Return(apperr.Wrap(apperr.Validation, email.ErrBadToken, "invalid unsubscribe link"))
+			},
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.signedIn {
+				req.Header.Set("Authorization", "Bearer token")
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, true, strings.Contains(rr.Body.String(), tt.want))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/etag"
+	"project/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondTagged sends body with tag as its ETag, or a bodyless 304 when the
+// client named that tag in If-None-Match. An empty tag is computed from the body,
+// shaped responses carry included records whose versions the tag has to cover
+func respondTagged(c *gin.Context, traceid string, tag string, body interface{}) {
+	respondTaggedAs(c, traceid, "application/json", tag, body)
+}
+
+// respondTaggedAs is respondTagged for json sent as mediaType, such as JSON-LD
+func respondTaggedAs(c *gin.Context, traceid string, mediaType string, tag string, body interface{}) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	if tag == "" {
+		tag = etag.Body(b)
+	}
+	c.Header("ETag", tag)
+	if etag.Match(c.GetHeader("If-None-Match"), tag, true) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, mediaType+"; charset=utf-8", b)
+}
+
+// plainTag is tag when the response is the record as stored, it then matches
+// the ETag writes compare If-Match against
+func plainTag(s shape, tag func() string) string {
+	if !s.plain() {
+		return ""
+	}
+	return tag()
+}
+
+func companiesTag(companyDatas []models.Company) func() string {
+	return func() string {
+		tags := make([]string, 0, len(companyDatas))
+		for _, companyData := range companyDatas {
+			tags = append(tags, companyData.ETag())
+		}
+		return etag.Combine(tags...)
+	}
+}
+
+func jobsTag(jobDatas []models.Jobs) func() string {
+	return func() string {
+		tags := make([]string, 0, len(jobDatas))
+		for _, jobData := range jobDatas {
+			tags = append(tags, jobData.ETag())
+		}
+		return etag.Combine(tags...)
+	}
+}
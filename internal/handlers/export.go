@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/export"
+	"project/internal/imports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// exportType is the format the client asked for in Accept, empty when it wants json
+func exportType(c *gin.Context) string {
+	c.Header("Vary", "Accept")
+	switch t := c.NegotiateFormat(gin.MIMEJSON, imports.CSVType, imports.NDJSONType); t {
+	case imports.CSVType, imports.NDJSONType:
+		return t
+	}
+	return ""
+}
+
+// streamExport writes the records each produces in mediaType as they come, name
+// is the file name CSV is saved under. ?fields picks the members written, ?include
+// is not supported since every record would need its own lookup
+func streamExport[T any](c *gin.Context, traceid string, mediaType string, name string, s shape, each func(func(T) error) error) {
+	if len(s.include) > 0 {
+		apperr.Abort(c, traceid, apperr.New(apperr.Validation, "include cannot be used with "+mediaType))
+		return
+	}
+	var fields []string
+	for field := range s.fields[""] {
+		fields = append(fields, field)
+	}
+	resp := &exportResponse{c: c, mediaType: mediaType, name: name}
+	w, err := export.NewWriter[T](mediaType, resp, fields)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	err = each(w.Write)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		resp.start()
+		return
+	}
+	if !resp.started {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	// the status is already sent, the client is left with a cut off export
+	log.Error().Err(err).Str("trace id", traceid).Msg("export stopped")
+	c.Abort()
+}
+
+// exportResponse sends the headers of an export with its first bytes, until then
+// a failure can still be answered with a problem
+type exportResponse struct {
+	c         *gin.Context
+	mediaType string
+	name      string
+	started   bool
+}
+
+func (r *exportResponse) Write(b []byte) (int, error) {
+	r.start()
+	return r.c.Writer.Write(b)
+}
+
+// start sends the headers, an empty export is started once it is done
+func (r *exportResponse) start() {
+	if r.started {
+		return
+	}
+	r.started = true
+	r.c.Header("Content-Type", r.mediaType+"; charset=utf-8")
+	if r.mediaType == imports.CSVType {
+		r.c.Header("Content-Disposition", `attachment; filename="`+r.name+`.csv"`)
+	}
+	r.c.Status(http.StatusOK)
+	r.c.Writer.WriteHeaderNow()
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/apperr"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_API_export(t *testing.T) {
+	jobs := []models.Jobs{
+		{Model: gorm.Model{ID: 1}, Cid: 7, Name: "developer", Skills: []string{"go", "sql"}},
+		{Model: gorm.Model{ID: 2}, Cid: 7, Name: "tester"},
+	}
+	tests := []struct {
+		name            string
+		path            string
+		accept          string
+		wantFilter      models.JobFilter
+		failAfter       int
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "csv of a search",
+			path:            "/api/v1/jobs/search?q=dev&location=pune&page=3&fields=name,skills",
+			accept:          "text/csv",
+			wantFilter:      models.JobFilter{Query: "dev", Location: []string{"pune"}, Page: 3},
+			failAfter:       -1,
+			wantCode:        http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody:        "ID,name,skills\n1,developer,go;sql\n2,tester,\n",
+		},
+		{
+			name:            "ndjson of a company's jobs",
+			path:            "/api/v1/companies/7/jobs?fields=name",
+			accept:          "application/x-ndjson, application/json;q=0.5",
+			wantFilter:      models.JobFilter{Cid: []uint{7}},
+			failAfter:       -1,
+			wantCode:        http.StatusOK,
+			wantContentType: "application/x-ndjson; charset=utf-8",
+			wantBody:        `{"ID":1,"name":"developer"}` + "\n" + `{"ID":2,"name":"tester"}` + "\n",
+		},
+		{
+			name:            "failing before anything is sent",
+			path:            "/api/v1/jobs",
+			accept:          "text/csv",
+			failAfter:       0,
+			wantCode:        http.StatusInternalServerError,
+			wantContentType: apperr.ContentType,
+		},
+		{
+			name:     "include",
+			path:     "/api/v1/jobs?include=company",
+			accept:   "text/csv",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			ms.EXPECT().ExportJobs(gomock.Any(), tt.wantFilter, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, filter models.JobFilter, fn func(models.Jobs) error) error {
+					for i, job := range jobs {
+						if i == tt.failAfter {
+							return apperr.New(apperr.Internal, "could not export the jobs")
+						}
+						err := fn(job)
+						if err != nil {
+							return err
+						}
+					}
+					return nil
+				}).MaxTimes(1)
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("Accept", tt.accept)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+			if tt.wantContentType != "" {
+				assert.Equal(t, tt.wantContentType, rr.Header().Get("Content-Type"))
+			}
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/etag"
+	"project/internal/feed"
+	"project/internal/middleware"
+	"project/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedMaxAge is how long clients and proxies may reuse a feed without asking again
+const feedMaxAge = 5 * time.Minute
+
+// feedWriter writes a feed in one of the formats
+type feedWriter struct {
+	mediaType string
+	write     func(io.Writer, feed.Feed) error
+}
+
+var (
+	rssFeed  = feedWriter{mediaType: feed.RSSType, write: feed.WriteRSS}
+	atomFeed = feedWriter{mediaType: feed.AtomType, write: feed.WriteAtom}
+)
+
+func (h *handler) JobsRSS(c *gin.Context) {
+	h.jobFeed(c, rssFeed)
+}
+
+func (h *handler) JobsAtom(c *gin.Context) {
+	h.jobFeed(c, atomFeed)
+}
+
+func (h *handler) CompanyJobsRSS(c *gin.Context) {
+	h.companyJobFeed(c, rssFeed)
+}
+
+func (h *handler) CompanyJobsAtom(c *gin.Context) {
+	h.companyJobFeed(c, atomFeed)
+}
+
+// jobFeed sends the newest published jobs matching the listing filters
+func (h *handler) jobFeed(c *gin.Context, w feedWriter) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+
+	filter, err := jobFilterFromQuery(c)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	jobFeed, err := h.service.JobFeed(ctx, filter)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	base := baseURL(c)
+	respondFeed(c, traceid, w, jobFeed, feed.Feed{
+		Title:       "Jobs",
+		Description: "The newest jobs posted on the portal",
+		Link:        base + APIPrefix + "/jobs",
+	})
+}
+
+// companyJobFeed sends the newest published jobs of one company matching the
+// listing filters
+func (h *handler) companyJobFeed(c *gin.Context, w feedWriter) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+
+	cid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+	filter, err := jobFilterFromQuery(c)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	jobFeed, err := h.service.CompanyJobFeed(ctx, cid, filter)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	base := baseURL(c)
+	respondFeed(c, traceid, w, jobFeed, feed.Feed{
+		Title:       "Jobs at " + jobFeed.Company.Name,
+		Description: "The newest jobs posted by " + jobFeed.Company.Name,
+		Link:        fmt.Sprintf("%s%s/companies/%d/jobs", base, APIPrefix, cid),
+	})
+}
+
+// respondFeed fills f from jobFeed and sends it with its ETag and Last-Modified,
+// or a bodyless 304 when the client already holds it
+func respondFeed(c *gin.Context, traceid string, w feedWriter, jobFeed models.JobFeed, f feed.Feed) {
+	base := baseURL(c)
+	f.Self = base + c.Request.URL.RequestURI()
+	f.Updated = jobFeed.Updated
+	for _, jobData := range jobFeed.Jobs {
+		f.Entries = append(f.Entries, feed.JobEntry(jobData, jobURL(c, jobData.ID)))
+	}
+
+	if cached(c, jobFeed.ETag(), f.Updated, feedMaxAge) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	var b bytes.Buffer
+	err := w.write(&b, f)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	c.Data(http.StatusOK, w.mediaType+"; charset=utf-8", b.Bytes())
+}
+
+// cached sets the ETag, Last-Modified and Cache-Control of a public document and
+// reports whether the client already holds it
+func cached(c *gin.Context, tag string, updated time.Time, maxAge time.Duration) bool {
+	c.Header("ETag", tag)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+	if !updated.IsZero() {
+		c.Header("Last-Modified", updated.UTC().Format(http.TimeFormat))
+	}
+	return notModified(c, tag, updated)
+}
+
+// notModified reports whether the client holds the current representation. An
+// If-None-Match is decisive when sent, If-Modified-Since is only read without it
+func notModified(c *gin.Context, tag string, updated time.Time) bool {
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		return etag.Match(header, tag, true)
+	}
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || updated.IsZero() {
+		return false
+	}
+	// http dates have no fraction of a second
+	return !updated.Truncate(time.Second).After(since)
+}
+
+// baseURL is the scheme and host the client reached the api at, a proxy in front
+// names the scheme in X-Forwarded-Proto
+func baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
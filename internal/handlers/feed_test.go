@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"project/internal/apperr"
+	"project/internal/feed"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_API_feeds(t *testing.T) {
+	posted := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tek := models.Company{Model: gorm.Model{ID: 7, UpdatedAt: posted}, Name: "tek"}
+	jobFeed := models.JobFeed{
+		Jobs:    []models.Jobs{{Model: gorm.Model{ID: 11, CreatedAt: posted, UpdatedAt: posted}, Company: tek, Cid: 7, Name: "developer"}},
+		Updated: posted.Add(time.Hour),
+	}
+	companyFeed := jobFeed
+	companyFeed.Company = &tek
+	tests := []struct {
+		name     string
+		path     string
+		header   map[string]string
+		setup    func(ms *mock_files.MockUserService)
+		wantCode int
+		wantType string
+		want     []string
+	}{
+		{
+			name:   "atom",
+			path:   "/api/v1/jobs.atom?location=pune&remote_policy=remote",
+			header: map[string]string{"X-Forwarded-Proto": "https"},
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().JobFeed(gomock.Any(), models.JobFilter{Location: []string{"pune"}, RemotePolicy: []string{models.RemoteFull}}).Return(jobFeed, nil)
+			},
+			wantCode: http.StatusOK,
+			wantType: feed.AtomType,
+			want: []string{
+				`<id>https://example.com/api/v1/jobs.atom?location=pune&amp;remote_policy=remote</id>`,
+				`<link href="https://example.com/api/v1/jobs/11/jsonld" rel="alternate"></link>`,
+				`<title>developer at tek</title>`,
+			},
+		},
+		{
+			name: "rss",
+			path: "/api/v1/jobs.rss",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().JobFeed(gomock.Any(), models.JobFilter{}).Return(jobFeed, nil)
+			},
+			wantCode: http.StatusOK,
+			wantType: feed.RSSType,
+			want:     []string{`<guid isPermaLink="true">http://example.com/api/v1/jobs/11/jsonld</guid>`},
+		},
+		{
+			name:   "etag still current",
+			path:   "/api/v1/jobs.rss",
+			header: map[string]string{"If-None-Match": jobFeed.ETag()},
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().JobFeed(gomock.Any(), gomock.Any()).Return(jobFeed, nil)
+			},
+			wantCode: http.StatusNotModified,
+		},
+		{
+			name:   "not modified since",
+			path:   "/api/v1/jobs.atom",
+			header: map[string]string{"If-Modified-Since": "Tue, 02 Jan 2024 04:04:05 GMT"},
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().JobFeed(gomock.Any(), gomock.Any()).Return(jobFeed, nil)
+			},
+			wantCode: http.StatusNotModified,
+		},
+		{
+			name:   "modified since",
+			path:   "/api/v1/jobs.atom",
+			header: map[string]string{"If-Modified-Since": "Tue, 02 Jan 2024 04:04:04 GMT"},
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().JobFeed(gomock.Any(), gomock.Any()).Return(jobFeed, nil)
+			},
+			wantCode: http.StatusOK,
+			wantType: feed.AtomType,
+		},
+		{
+			name:   "a stale etag wins over the date",
+			path:   "/api/v1/jobs.atom",
+			header: map[string]string{"If-None-Match": `"stale"`, "If-Modified-Since": "Tue, 02 Jan 2024 04:04:05 GMT"},
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().JobFeed(gomock.Any(), gomock.Any()).Return(jobFeed, nil)
+			},
+			wantCode: http.StatusOK,
+			wantType: feed.AtomType,
+		},
+		{
+			name:     "unknown filter value",
+			path:     "/api/v1/jobs.atom?employment_type=forever",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "company",
+			path: "/api/v1/companies/7/jobs.atom",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().CompanyJobFeed(gomock.Any(), uint64(7), models.JobFilter{}).Return(companyFeed, nil)
+			},
+			wantCode: http.StatusOK,
+			wantType: feed.AtomType,
+			want: []string{
+				`<title>Jobs at tek</title>`,
+				`<link href="http://example.com/api/v1/companies/7/jobs" rel="alternate"></link>`,
+			},
+		},
+		{
+			name: "company rss",
+			path: "/api/v1/companies/7/jobs.rss?salary=600000-1200000",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().CompanyJobFeed(gomock.Any(), uint64(7), models.JobFilter{SalaryBucket: []string{"600000-1200000"}}).Return(companyFeed, nil)
+			},
+			wantCode: http.StatusOK,
+			wantType: feed.RSSType,
+			want:     []string{`<title>Jobs at tek</title>`},
+		},
+		{
+			name: "unknown company",
+			path: "/api/v1/companies/9/jobs.rss",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().CompanyJobFeed(gomock.Any(), uint64(9), gomock.Any()).Return(models.JobFeed{}, apperr.New(apperr.NotFound, "could not find the company"))
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			// feeds are public, no token is sent
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.wantType != "" {
+				assert.Equal(t, tt.wantType+"; charset=utf-8", rr.Header().Get("Content-Type"))
+				assert.Equal(t, "Tue, 02 Jan 2024 04:04:05 GMT", rr.Header().Get("Last-Modified"))
+				assert.NotEqual(t, "", rr.Header().Get("ETag"))
+			}
+			if tt.wantCode == http.StatusNotModified {
+				assert.Equal(t, 0, rr.Body.Len())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(rr.Body.String(), want) {
+					t.Errorf("the feed lacks %s:\n%s", want, rr.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/graph"
+	"project/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// GraphQL runs a query over the companies, their jobs and the signed in user.
+// Mistakes in the query are reported in the errors of a 200 response as GraphQL
+// clients expect, only a body that is not a request at all gets a problem
+func (h *handler) GraphQL(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	var req graph.Request
+	err = json.NewDecoder(c.Request.Body).Decode(&req)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide a graphql query"))
+		return
+	}
+	err = validator.New().Struct(req)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide a graphql query"))
+		return
+	}
+
+	c.JSON(http.StatusOK, h.graph.Execute(ctx, traceid, uid, req))
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_API_graphql(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		auth      bool
+		wantCode  int
+		wantData  string
+		wantError bool
+	}{
+		{
+			name:     "query",
+			body:     `{"query":"query Me($id: ID!) { me { username } company(id: $id) { name } }","variables":{"id":"7"}}`,
+			auth:     true,
+			wantCode: http.StatusOK,
+			wantData: `{"company":{"name":"tek"},"me":{"username":"bhoomi"}}`,
+		},
+		{
+			name:      "invalid query",
+			body:      `{"query":"{ me { password } }"}`,
+			auth:      true,
+			wantCode:  http.StatusOK,
+			wantData:  `null`,
+			wantError: true,
+		},
+		{
+			name:     "no query",
+			body:     `{"variables":{}}`,
+			auth:     true,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "signed out",
+			body:     `{"query":"{ me { username } }"}`,
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			ms.EXPECT().ViewUser(gomock.Any(), uint(4)).Return(models.User{Model: gorm.Model{ID: 4}, Username: "bhoomi"}, nil).AnyTimes()
+			ms.EXPECT().ViewCompanyDetails(gomock.Any(), uint64(7)).Return(models.Company{Model: gorm.Model{ID: 7}, Name: "tek"}, nil).AnyTimes()
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.auth {
+				req.Header.Set("Authorization", "Bearer token")
+			}
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.wantData == "" {
+				return
+			}
+			var got struct {
+				Data   json.RawMessage   `json:"data"`
+				Errors []json.RawMessage `json:"errors"`
+			}
+			err := json.Unmarshal(rr.Body.Bytes(), &got)
+			if err != nil {
+				t.Fatalf("response %s is not json: %v", rr.Body, err)
+			}
+			assert.Equal(t, tt.wantData, string(got.Data))
+			assert.Equal(t, tt.wantError, len(got.Errors) > 0)
+		})
+	}
+}
@@ -1,23 +1,220 @@
 package handler
 
 import (
+	"errors"
 	"log"
+	"net/http"
+	"project/internal/aggregator"
 	"project/internal/auth"
+	"project/internal/feed"
+	"project/internal/graph"
+	"project/internal/idempotency"
 	"project/internal/middleware"
+	"project/internal/models"
+	"project/internal/openapi"
+	"project/internal/schemaorg"
 	service "project/internal/service"
+	"project/internal/sitemap"
 
 	"github.com/gin-gonic/gin"
 )
 
-func API(a auth.UserAuth, svc service.UserService) *gin.Engine {
+// errTraceIDMissing means the Log middleware did not run, it is answered as an internal error
+var errTraceIDMissing = errors.New("traceid missing from context")
+
+// APIPrefix is where the current version of the api is mounted
+const APIPrefix = "/api/v1"
+
+// route is one endpoint of the api. Path is relative to APIPrefix, legacy lists the
+// old unversioned paths that keep answering but send a Deprecation header. A legacy
+// path must use the same parameter names as path, except for the names in fromQuery
+// which the old path took from the query string instead. An idempotent POST creates
+// a resource and replays its response to a retry with the same Idempotency-Key.
+// params, request, response and status document the endpoint in the api spec,
+// request and response are values of the body types and status is the status of
+// a success when it is not 200
+type route struct {
+	method     string
+	path       string
+	legacy     []string
+	fromQuery  []string
+	public     bool
+	idempotent bool
+	handler    gin.HandlerFunc
+	params     []openapi.Parameter
+	request    interface{}
+	response   interface{}
+	status     int
+	export     interface{}
+}
+
+func routes(h UserHandler) []route {
+	return []route{
+		{method: http.MethodPost, path: "/users", legacy: []string{"/signup"}, public: true, handler: h.SignUp,
+			params: shapeParams(), request: models.NewUser{}, response: models.User{}},
+		{method: http.MethodPost, path: "/sessions", legacy: []string{"/signin"}, public: true, handler: h.Login,
+			request: loginRequest{}, response: tokenResponse{}},
+
+		{method: http.MethodGet, path: "/companies", legacy: []string{"/view/allcomp"}, handler: h.ViewAllCompanies,
+			params: append(shapeParams(resourceJobs), ifNoneMatch()), response: []models.Company{}, export: models.Company{}},
+		{method: http.MethodPost, path: "/companies", legacy: []string{"/add"}, idempotent: true, handler: h.AddCompany,
+			params: shapeParams(resourceJobs), request: models.Company{}, response: models.Company{}},
+		{method: http.MethodPost, path: "/companies/import", handler: h.ImportCompanies,
+			params: importParams(), request: openapi.StreamBody{Of: models.Company{}}, response: models.ImportReport{}},
+		{method: http.MethodGet, path: "/companies/:id", legacy: []string{"/viewcompany/:id"}, handler: h.ViewCompany,
+			params: append(shapeParams(resourceJobs), ifNoneMatch()), response: models.Company{}},
+		{method: http.MethodPut, path: "/companies/:id", handler: h.UpdateCompany,
+			params: []openapi.Parameter{ifMatch()}, request: models.Company{}, response: models.Company{}},
+		{method: http.MethodPatch, path: "/companies/:id", handler: h.PatchCompany,
+			params: []openapi.Parameter{ifMatch()}, request: openapi.PatchBody{Of: models.Company{}}, response: models.Company{}},
+		{method: http.MethodDelete, path: "/companies/:id", handler: h.DeleteCompany,
+			params: []openapi.Parameter{ifMatch()}, status: http.StatusNoContent},
+		{method: http.MethodGet, path: "/companies/:id/jobs", legacy: []string{"/job/view"}, fromQuery: []string{"id"}, handler: h.Jobs,
+			params: append(shapeParams(resourceCompany), ifNoneMatch()), response: []models.Jobs{}, export: models.Jobs{}},
+		{method: http.MethodGet, path: "/companies/:id/jobs.rss", public: true, handler: h.CompanyJobsRSS,
+			params: feedParams(), response: openapi.Text{MediaType: feed.RSSType, Description: "RSS 2.0 feed of the newest published jobs of the company"}},
+		{method: http.MethodGet, path: "/companies/:id/jobs.atom", public: true, handler: h.CompanyJobsAtom,
+			params: feedParams(), response: openapi.Text{MediaType: feed.AtomType, Description: "Atom feed of the newest published jobs of the company"}},
+		{method: http.MethodPost, path: "/companies/:id/jobs", legacy: []string{"/add/:id"}, idempotent: true, handler: h.CreateJobs,
+			params: shapeParams(resourceCompany), request: models.Jobs{}, response: models.Jobs{}},
+		{method: http.MethodPost, path: "/companies/:id/members", idempotent: true, handler: h.AddMember,
+			request: models.Membership{}, response: models.Membership{}},
+		{method: http.MethodPut, path: "/companies/:id/verification", handler: h.VerifyCompany,
+			response: models.CompanyVerification{}},
+		{method: http.MethodGet, path: "/companies/:id/webhooks", handler: h.Webhooks,
+			response: []models.Webhook{}},
+		{method: http.MethodPost, path: "/companies/:id/webhooks", idempotent: true, handler: h.CreateWebhook,
+			request: models.Webhook{}, response: models.Webhook{}},
+		{method: http.MethodDelete, path: "/companies/:id/webhooks/:webhook_id", handler: h.DeleteWebhook,
+			status: http.StatusNoContent},
+		{method: http.MethodGet, path: "/companies/:id/webhooks/:webhook_id/deliveries", handler: h.WebhookDeliveries,
+			response: []models.WebhookDelivery{}},
+		{method: http.MethodPost, path: "/companies/:id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", handler: h.Redeliver,
+			response: models.WebhookDelivery{}},
+		{method: http.MethodGet, path: "/companies/:id/connectors", handler: h.Connectors,
+			response: []models.ATSConnector{}},
+		{method: http.MethodPost, path: "/companies/:id/connectors", idempotent: true, handler: h.CreateConnector,
+			request: models.ATSConnector{}, response: models.ATSConnector{}},
+		{method: http.MethodDelete, path: "/companies/:id/connectors/:connector_id", handler: h.DeleteConnector,
+			status: http.StatusNoContent},
+
+		{method: http.MethodGet, path: "/jobs", legacy: []string{"/view/all"}, handler: h.AllJobs,
+			params: append(shapeParams(resourceCompany), ifNoneMatch()), response: []models.Jobs{}, export: models.Jobs{}},
+		{method: http.MethodGet, path: "/jobs/search", handler: h.SearchJobs,
+			params: append(searchParams(), shapeParams(resourceCompany)...), response: models.JobSearchResult{}, export: models.Jobs{}},
+		{method: http.MethodGet, path: "/jobs/stream", handler: h.StreamJobs,
+			params: streamParams(), response: openapi.EventStream{Of: models.Jobs{}}},
+		{method: http.MethodGet, path: "/jobs.rss", public: true, handler: h.JobsRSS,
+			params: feedParams(), response: openapi.Text{MediaType: feed.RSSType, Description: "RSS 2.0 feed of the newest published jobs"}},
+		{method: http.MethodGet, path: "/jobs.atom", public: true, handler: h.JobsAtom,
+			params: feedParams(), response: openapi.Text{MediaType: feed.AtomType, Description: "Atom feed of the newest published jobs"}},
+		{method: http.MethodPost, path: "/jobs/import", handler: h.ImportJobs,
+			params: importParams(), request: openapi.StreamBody{Of: models.JobRow{}}, response: models.ImportReport{}},
+		{method: http.MethodGet, path: "/jobs/:id", legacy: []string{"/viewjob/:id"}, handler: h.JobByID,
+			params: append(shapeParams(resourceCompany), ifNoneMatch()), response: models.Jobs{}},
+		{method: http.MethodGet, path: "/jobs/:id/jsonld", public: true, handler: h.JobPosting,
+			params: []openapi.Parameter{ifNoneMatch()}, response: openapi.Typed{MediaType: schemaorg.MediaType, Of: schemaorg.JobPosting{}}},
+		{method: http.MethodPut, path: "/jobs/:id", handler: h.UpdateJob,
+			params: []openapi.Parameter{ifMatch()}, request: models.Jobs{}, response: models.Jobs{}},
+		{method: http.MethodPatch, path: "/jobs/:id", handler: h.PatchJob,
+			params: []openapi.Parameter{ifMatch()}, request: openapi.PatchBody{Of: models.Jobs{}}, response: models.Jobs{}},
+		{method: http.MethodDelete, path: "/jobs/:id", handler: h.DeleteJob,
+			params: []openapi.Parameter{ifMatch()}, status: http.StatusNoContent},
+
+		{method: http.MethodGet, path: "/me/profile", handler: h.ViewProfile,
+			params: []openapi.Parameter{ifNoneMatch()}, response: models.Profile{}},
+		{method: http.MethodPut, path: "/me/profile", handler: h.SaveProfile,
+			params: []openapi.Parameter{ifMatch()}, request: models.Profile{}, response: models.Profile{}},
+		{method: http.MethodGet, path: "/me/profile/views", handler: h.ProfileViews,
+			response: []models.ProfileView{}},
+		{method: http.MethodGet, path: "/me/notifications", handler: h.Notifications,
+			params: []openapi.Parameter{openapi.Query("unread", "boolean")}, response: []models.Notification{}},
+		{method: http.MethodPost, path: "/me/notifications/read", handler: h.ReadNotifications,
+			request: models.NotificationAck{}, status: http.StatusNoContent},
+		{method: http.MethodPost, path: "/me/notifications/unread", handler: h.UnreadNotifications,
+			request: models.NotificationAck{}, status: http.StatusNoContent},
+		{method: http.MethodGet, path: "/me/notifications/ws", handler: h.NotificationSocket,
+			params: socketParams(), status: http.StatusSwitchingProtocols},
+		{method: http.MethodGet, path: "/me/recommendations", handler: h.Recommendations,
+			params: []openapi.Parameter{openapi.Query("limit", "integer")}, response: []models.Recommendation{}},
+
+		{method: http.MethodGet, path: "/candidates", handler: h.SearchTalent,
+			params: talentParams(), response: models.TalentSearchResult{}},
+		{method: http.MethodGet, path: "/candidates/:id", handler: h.ViewCandidate,
+			response: models.Candidate{}},
+
+		{method: http.MethodGet, path: "/emails/preview", handler: h.PreviewEmail,
+			params: emailPreviewParams(), response: models.RenderedEmail{}},
+		{method: http.MethodGet, path: "/emails/unsubscribe", public: true, handler: h.UnsubscribeLink,
+			params: unsubscribeParams(), response: openapi.Text{MediaType: "text/html", Description: "a page asking to confirm, its form posts the unsubscribe"}},
+		{method: http.MethodPost, path: "/emails/unsubscribe", public: true, handler: h.Unsubscribe,
+			params: unsubscribeParams(), response: models.Unsubscribed{}},
+
+		{method: http.MethodGet, path: "/aggregator/jobs.xml", public: true, handler: h.AggregatorFeed,
+			params: aggregatorParams(), response: openapi.Text{MediaType: aggregator.MediaType, Description: "the published jobs in the aggregator feed format of format"}},
+		{method: http.MethodGet, path: "/aggregator/report", handler: h.AggregatorReport,
+			params: aggregatorParams(), response: models.AggregatorReport{}},
+		{method: http.MethodGet, path: "/sitemap.xml", public: true, handler: h.Sitemap,
+			params: conditionalParams(), response: openapi.Text{MediaType: sitemap.MediaType, Description: "sitemap of the published jobs, or an index of its pages once there are more than 50000"}},
+		{method: http.MethodGet, path: "/sitemaps/:page", public: true, handler: h.SitemapPage,
+			params: conditionalParams(), response: openapi.Text{MediaType: sitemap.MediaType, Description: "one page of the sitemap of the published jobs"}},
+
+		{method: http.MethodPost, path: "/graphql", handler: h.GraphQL,
+			request: graph.Request{}, response: graph.Response{}},
+	}
+}
+
+// Option changes how API builds the engine
+type Option func(*options)
+
+type options struct {
+	validate    bool
+	idempotency idempotency.Store
+	graphql     []graph.Option
+}
+
+// WithValidation checks every request against the api spec. In gin's test mode
+// the responses are checked as well
+func WithValidation() Option {
+	return func(o *options) {
+		o.validate = true
+	}
+}
+
+// WithIdempotencyStore keeps the responses of the idempotent POST requests sent
+// with an Idempotency-Key in store, the default is a memory store keeping them for
+// idempotency.DefaultWindow
+func WithIdempotencyStore(store idempotency.Store) Option {
+	return func(o *options) {
+		o.idempotency = store
+	}
+}
+
+// WithGraphQLLimits bounds the depth and complexity of GraphQL queries, the
+// default is graph.DefaultLimits
+func WithGraphQLLimits(l graph.Limits) Option {
+	return func(o *options) {
+		o.graphql = append(o.graphql, graph.WithLimits(l))
+	}
+}
+
+func API(a auth.UserAuth, svc service.UserService, opts ...Option) *gin.Engine {
 	r := gin.New()
 
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.idempotency == nil {
+		o.idempotency = idempotency.NewMemoryStore(idempotency.DefaultWindow)
+	}
+
 	m, err := middleware.NewMiddleware(a)
 	if err != nil {
 		log.Panic("middlewares not setup")
 		return nil
 	}
-	h, err := Newhandler(svc)
+	h, err := Newhandler(svc, o.graphql...)
 	if err != nil {
 		log.Panic("middlewares not setup")
 		return nil
@@ -25,16 +222,30 @@ func API(a auth.UserAuth, svc service.UserService) *gin.Engine {
 
 	r.Use(m.Log(), gin.Recovery())
 
+	spec := Spec()
+	if o.validate {
+		r.Use(m.Validate(spec, gin.Mode() == gin.TestMode))
+	}
+
 	r.GET("/check", Check)
-	r.POST("/signup", h.SignUp)
-	r.POST("/signin", h.Login)
-	r.POST("/add", m.Authenticate(h.AddCompany))
-	r.GET("/view/allcomp", m.Authenticate(h.ViewAllCompanies))
-	r.GET("/viewcompany/:id", m.Authenticate(h.ViewCompany))
-	r.POST("/add/:cid", m.Authenticate(h.CreateJobs))
-	r.GET("/view/all", m.Authenticate(h.AllJobs))
-	r.GET("/job/view", m.Authenticate(h.Jobs))
-	r.GET("/viewjob/:cid", m.Authenticate(h.JobByID))
+	r.GET(SpecPath, func(c *gin.Context) {
+		c.JSON(http.StatusOK, spec)
+	})
+
+	v1 := r.Group(APIPrefix)
+	for _, rt := range routes(h) {
+		handler := rt.handler
+		if rt.idempotent {
+			handler = m.Idempotent(o.idempotency)(handler)
+		}
+		if !rt.public {
+			handler = m.Authenticate(handler)
+		}
+		v1.Handle(rt.method, rt.path, handler)
+		for _, path := range rt.legacy {
+			r.Handle(rt.method, path, m.Deprecated(APIPrefix+rt.path, rt.fromQuery...), handler)
+		}
+	}
 
 	return r
 
@@ -0,0 +1,523 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/apperr"
+	"project/internal/etag"
+	"project/internal/idempotency"
+	"project/internal/middleware"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"project/internal/service"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+// stubAuth accepts every token as user 4
+type stubAuth struct{}
+
+func (stubAuth) GenerateToken(claims jwt.RegisteredClaims) (string, error) {
+	return "token", nil
+}
+
+func (stubAuth) ValidateToken(token string) (jwt.RegisteredClaims, error) {
+	return jwt.RegisteredClaims{Subject: "4"}, nil
+}
+
+func pathParams(path string) []string {
+	var params []string
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			params = append(params, part[1:])
+		}
+	}
+	sort.Strings(params)
+	return params
+}
+
+// Test_routes_params guards against a legacy path naming its parameters
+// differently from the path the handler was written for
+func Test_routes_params(t *testing.T) {
+	h := &handler{}
+	seen := make(map[string]bool)
+	for _, rt := range routes(h) {
+		key := rt.method + " " + rt.path
+		if seen[key] {
+			t.Errorf("%s is registered twice", key)
+		}
+		seen[key] = true
+
+		want := pathParams(rt.path)
+		for _, legacy := range rt.legacy {
+			got := append(pathParams(legacy), rt.fromQuery...)
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("%s %s has parameters %v, %s has %v", rt.method, legacy, got, rt.path, want)
+			}
+		}
+	}
+}
+
+// Test_API_routes sends a request to every route that takes an id and checks the
+// id from the path reaches the service
+func Test_API_routes(t *testing.T) {
+	tests := []struct {
+		method     string
+		path       string
+		body       string
+		deprecated bool
+		expect     func(ms *mock_files.MockUserService)
+	}{
+		{
+			method: http.MethodGet,
+			path:   "/api/v1/companies/42",
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewCompanyDetails(gomock.Any(), uint64(42)).Return(models.Company{}, nil)
+			},
+		},
+		{
+			method:     http.MethodGet,
+			path:       "/viewcompany/42",
+			deprecated: true,
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewCompanyDetails(gomock.Any(), uint64(42)).Return(models.Company{}, nil)
+			},
+		},
+		{
+			method: http.MethodGet,
+			path:   "/api/v1/companies/42/jobs",
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewJob(gomock.Any(), uint64(42)).Return([]models.Jobs{}, nil)
+			},
+		},
+		{
+			method:     http.MethodGet,
+			path:       "/job/view?id=42",
+			deprecated: true,
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewJob(gomock.Any(), uint64(42)).Return([]models.Jobs{}, nil)
+			},
+		},
+		{
+			method: http.MethodPost,
+			path:   "/api/v1/companies/42/jobs",
+			body:   `{"name":"developer"}`,
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().AddJobDetails(gomock.Any(), uint(4), gomock.Any(), uint64(42)).Return(models.Jobs{}, nil)
+			},
+		},
+		{
+			method:     http.MethodPost,
+			path:       "/add/42",
+			body:       `{"name":"developer"}`,
+			deprecated: true,
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().AddJobDetails(gomock.Any(), uint(4), gomock.Any(), uint64(42)).Return(models.Jobs{}, nil)
+			},
+		},
+		{
+			method: http.MethodGet,
+			path:   "/api/v1/jobs/42",
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewJobById(gomock.Any(), uint(4), uint64(42)).Return(models.Jobs{}, nil)
+			},
+		},
+		{
+			method:     http.MethodGet,
+			path:       "/viewjob/42",
+			deprecated: true,
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewJobById(gomock.Any(), uint(4), uint64(42)).Return(models.Jobs{}, nil)
+			},
+		},
+		{
+			method: http.MethodGet,
+			path:   "/api/v1/jobs/search",
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().SearchJobs(gomock.Any(), models.JobFilter{}).Return(models.JobSearchResult{}, nil)
+			},
+		},
+		{
+			method: http.MethodGet,
+			path:   "/api/v1/candidates/42",
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewCandidate(gomock.Any(), uint(4), uint(42)).Return(models.Candidate{}, nil)
+			},
+		},
+		{
+			method: http.MethodPost,
+			path:   "/api/v1/companies/42/members",
+			body:   `{"user_id":5,"role":"recruiter"}`,
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().AddMember(gomock.Any(), uint(4), models.Membership{CompanyID: 42, UserID: 5, Role: "recruiter"}).
+					Return(models.Membership{CompanyID: 42, UserID: 5, Role: "recruiter"}, nil)
+			},
+		},
+		{
+			method: http.MethodPut,
+			path:   "/api/v1/companies/42/verification",
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().VerifyCompany(gomock.Any(), uint(4), uint64(42)).Return(models.CompanyVerification{CompanyID: 42, VerifiedBy: 4}, nil)
+			},
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			tt.expect(ms)
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			if tt.deprecated {
+				assert.Equal(t, "true", rr.Header().Get("Deprecation"))
+				assert.Equal(t, true, strings.HasPrefix(rr.Header().Get("Link"), "</api/v1/"))
+				assert.Equal(t, true, strings.Contains(rr.Header().Get("Link"), "/42"))
+			} else {
+				assert.Equal(t, "", rr.Header().Get("Deprecation"))
+			}
+		})
+	}
+}
+
+// Test_API_noAliasOfNewRoutes checks that only the paths served before the api
+// was versioned answer unversioned
+func Test_API_noAliasOfNewRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := API(stubAuth{}, mock_files.NewMockUserService(gomock.NewController(t)))
+	for _, path := range []string{"/jobs/search", "/me/profile", "/me/profile/views", "/me/recommendations", "/talent/search", "/talent/42"} {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		r.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	}
+}
+
+// Test_Spec_routes fails when a route is registered on the engine without being in
+// the api spec, or the spec lists an operation the engine does not serve
+func Test_Spec_routes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := API(stubAuth{}, mock_files.NewMockUserService(gomock.NewController(t)))
+	spec := Spec()
+
+	registered := make(map[string]bool)
+	for _, ri := range r.Routes() {
+		registered[ri.Method+" "+ri.Path] = true
+		if _, ok := spec.Operation(ri.Method, ri.Path); !ok {
+			t.Errorf("%s %s is served but missing from the api spec", ri.Method, ri.Path)
+		}
+	}
+	for path, item := range spec.Paths {
+		for method := range *item {
+			route := strings.ToUpper(method) + " " + strings.NewReplacer("{", ":", "}", "").Replace(path)
+			if !registered[route] {
+				t.Errorf("%s is in the api spec but not served", route)
+			}
+		}
+	}
+}
+
+func Test_API_spec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := API(stubAuth{}, mock_files.NewMockUserService(gomock.NewController(t)))
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, SpecPath, nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var doc struct {
+		OpenAPI string                            `json:"openapi"`
+		Paths   map[string]map[string]interface{} `json:"paths"`
+	}
+	err := json.Unmarshal(rr.Body.Bytes(), &doc)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	_, ok := doc.Paths["/api/v1/companies/{id}/jobs"]["post"]
+	assert.Equal(t, true, ok)
+}
+
+// Test_API_validation checks requests that break the spec never reach the service
+// and responses that break it are caught in test mode
+func Test_API_validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		expect   func(ms *mock_files.MockUserService)
+		wantCode int
+		wantErr  string
+	}{
+		{
+			name:     "id is not a number",
+			method:   http.MethodGet,
+			path:     "/api/v1/jobs/abc",
+			expect:   func(ms *mock_files.MockUserService) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  "id must be of type integer",
+		},
+		{
+			name:     "role outside of the enum",
+			method:   http.MethodPost,
+			path:     "/api/v1/companies/42/members",
+			body:     `{"user_id":5,"role":"boss"}`,
+			expect:   func(ms *mock_files.MockUserService) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  "body.role must be one of [owner recruiter]",
+		},
+		{
+			name:     "required property missing",
+			method:   http.MethodPost,
+			path:     "/api/v1/companies",
+			body:     `{"name":"tek","location":"pune"}`,
+			expect:   func(ms *mock_files.MockUserService) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  "body.field is required",
+		},
+		{
+			name:     "query value outside of the enum",
+			method:   http.MethodGet,
+			path:     "/api/v1/jobs/search?remote_policy=remote,moon",
+			expect:   func(ms *mock_files.MockUserService) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  "remote_policy must be one of [onsite hybrid remote]",
+		},
+		{
+			name:   "response outside of the spec",
+			method: http.MethodGet,
+			path:   "/api/v1/jobs/42",
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewJobById(gomock.Any(), uint(4), uint64(42)).Return(models.Jobs{Status: "archived"}, nil)
+			},
+			wantCode: http.StatusInternalServerError,
+			wantErr:  "response does not match the api spec: response.status must be one of [ draft published closed]",
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			tt.expect(ms)
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, apperr.ContentType, rr.Header().Get("Content-Type"))
+			var body apperr.Problem
+			err := json.Unmarshal(rr.Body.Bytes(), &body)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tt.wantErr, body.Detail)
+			assert.Equal(t, tt.path[:strings.IndexAny(tt.path+"?", "?")], body.Instance)
+			assert.Equal(t, true, body.TraceID != "")
+		})
+	}
+}
+
+// problemBody is the body apperr.Abort writes
+func problemBody(status int, detail, instance, traceID string) string {
+	b, _ := json.Marshal(apperr.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		TraceID:  traceID,
+	})
+	return string(b)
+}
+
+// Test_API_conditional follows ETags through conditional reads and writes
+func Test_API_conditional(t *testing.T) {
+	company := models.Company{Model: gorm.Model{ID: 42, UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}, Name: "tek", Location: "pune", Field: "it"}
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		header   map[string]string
+		expect   func(ms *mock_files.MockUserService)
+		wantCode int
+		wantETag string
+		wantBody string
+	}{
+		{
+			name:   "read sends the etag",
+			method: http.MethodGet,
+			path:   "/api/v1/companies/42",
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewCompanyDetails(gomock.Any(), uint64(42)).Return(company, nil)
+			},
+			wantCode: http.StatusOK,
+			wantETag: company.ETag(),
+		},
+		{
+			name:   "read of a current etag",
+			method: http.MethodGet,
+			path:   "/api/v1/companies/42",
+			header: map[string]string{"If-None-Match": `"other", W/` + company.ETag()},
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewCompanyDetails(gomock.Any(), uint64(42)).Return(company, nil)
+			},
+			wantCode: http.StatusNotModified,
+			wantETag: company.ETag(),
+		},
+		{
+			name:   "read of a stale etag",
+			method: http.MethodGet,
+			path:   "/api/v1/companies",
+			header: map[string]string{"If-None-Match": company.ETag()},
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewAllCompanies(gomock.Any()).Return([]models.Company{company}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantETag: etag.Combine(company.ETag()),
+		},
+		{
+			name:   "update",
+			method: http.MethodPut,
+			path:   "/api/v1/companies/42",
+			body:   `{"name":"tek","location":"pune","field":"it"}`,
+			header: map[string]string{"If-Match": `"v1"`},
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().UpdateCompany(gomock.Any(), uint(4), uint64(42), models.Company{Name: "tek", Location: "pune", Field: "it"}, `"v1"`).
+					Return(company, nil)
+			},
+			wantCode: http.StatusOK,
+			wantETag: company.ETag(),
+		},
+		{
+			name:   "delete without an etag",
+			method: http.MethodDelete,
+			path:   "/api/v1/jobs/42",
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().DeleteJob(gomock.Any(), uint(4), uint64(42), "").Return(service.ErrPreconditionRequired)
+			},
+			wantCode: http.StatusPreconditionRequired,
+			wantBody: problemBody(http.StatusPreconditionRequired, "send the ETag of the record in If-Match", "/api/v1/jobs/42", ""),
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			path:   "/api/v1/jobs/42",
+			header: map[string]string{"If-Match": `"v1"`},
+			expect: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().DeleteJob(gomock.Any(), uint(4), uint64(42), `"v1"`).Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			tt.expect(ms)
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantETag, rr.Header().Get("ETag"))
+			if tt.wantCode == http.StatusNotModified || tt.wantCode == http.StatusNoContent {
+				assert.Equal(t, "", rr.Body.String())
+			}
+			if tt.wantBody != "" {
+				var got, want apperr.Problem
+				_ = json.Unmarshal(rr.Body.Bytes(), &got)
+				_ = json.Unmarshal([]byte(tt.wantBody), &want)
+				got.TraceID = ""
+				assert.Equal(t, want, got)
+			}
+		})
+	}
+}
+
+// Test_API_idempotency retries a POST with the same Idempotency-Key
+func Test_API_idempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ms := mock_files.NewMockUserService(gomock.NewController(t))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ms.EXPECT().AddJobDetails(gomock.Any(), uint(4), models.Jobs{Name: "developer"}, uint64(42)).
+		DoAndReturn(func(ctx context.Context, actorID uint, jobData models.Jobs, cid uint64) (models.Jobs, error) {
+			close(started)
+			<-release
+			jobData.ID = 7
+			jobData.Cid = uint(cid)
+			return jobData, nil
+		})
+	ms.EXPECT().AddJobDetails(gomock.Any(), uint(4), models.Jobs{Name: "tester"}, uint64(42)).
+		Return(models.Jobs{}, errors.New("database is down"))
+	r := API(stubAuth{}, ms, WithValidation(), WithIdempotencyStore(idempotency.NewMemoryStore(time.Hour)))
+
+	post := func(path, key, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer token")
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() {
+		first <- post("/api/v1/companies/42/jobs", "k1", `{"name":"developer"}`)
+	}()
+	<-started
+	rr := post("/api/v1/companies/42/jobs", "k1", `{"name":"developer"}`)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	close(release)
+	want := <-first
+	assert.Equal(t, http.StatusOK, want.Code)
+
+	rr = post("/api/v1/companies/42/jobs", "k1", `{"name":"developer"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want.Body.String(), rr.Body.String())
+	assert.Equal(t, "true", rr.Header().Get("Idempotent-Replayed"))
+
+	rr = post("/api/v1/companies/42/jobs", "k1", `{"name":"manager"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	// failures are not kept, the retry runs again
+	rr = post("/api/v1/companies/42/jobs", "k2", `{"name":"tester"}`)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	ms.EXPECT().AddJobDetails(gomock.Any(), uint(4), models.Jobs{Name: "tester"}, uint64(42)).Return(models.Jobs{Name: "tester"}, nil)
+	rr = post("/api/v1/companies/42/jobs", "k2", `{"name":"tester"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "", rr.Header().Get("Idempotent-Replayed"))
+
+	// only the POSTs creating a resource are replayed, the key is ignored elsewhere
+	ms.EXPECT().MarkNotifications(gomock.Any(), uint(4), []uint{1}, true).Return(nil).Times(2)
+	for i := 0; i < 2; i++ {
+		rr = post("/api/v1/me/notifications/read", "k3", `{"ids":[1]}`)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, "", rr.Header().Get("Idempotent-Replayed"))
+	}
+}
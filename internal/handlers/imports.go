@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/imports"
+	"project/internal/middleware"
+	"project/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// maxImportBytes caps the size of one upload
+const maxImportBytes = 32 << 20
+
+func (h *handler) ImportCompanies(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	opts, err := importOptions(c)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	rows, err := imports.NewSource[models.Company](c.GetHeader("Content-Type"), http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	report, err := h.service.ImportCompanies(ctx, uid, rows, opts)
+	importReport(c, traceid, report, err)
+}
+
+func (h *handler) ImportJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	opts, err := importOptions(c)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	rows, err := imports.NewSource[models.JobRow](c.GetHeader("Content-Type"), http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	report, err := h.service.ImportJobs(ctx, uid, rows, opts)
+	importReport(c, traceid, report, err)
+}
+
+// importReport answers with the report. An import that stopped after committing
+// some of its batches answers it too, the rows stored are in the report and its
+// error says why the rest was not
+func importReport(c *gin.Context, traceid string, report models.ImportReport, err error) {
+	if err != nil && report.CommittedLine == 0 {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Int("committed line", report.CommittedLine).Msg("import stopped")
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// importOptions reads ?dry_run and ?chunk_size, without them the import is
+// written in one transaction
+func importOptions(c *gin.Context) (models.ImportOptions, error) {
+	var opts models.ImportOptions
+	var err error
+	if v := c.Query("dry_run"); v != "" {
+		opts.DryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			return opts, apperr.Wrap(apperr.Validation, err, "dry_run must be true or false")
+		}
+	}
+	if v := c.Query("chunk_size"); v != "" {
+		opts.ChunkSize, err = strconv.Atoi(v)
+		if err != nil || opts.ChunkSize < 0 {
+			return opts, apperr.Wrap(apperr.Validation, err, "chunk_size must be a positive number of rows")
+		}
+	}
+	return opts, nil
+}
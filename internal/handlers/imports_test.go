@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/imports"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_API_import(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		wantCode    int
+		wantOpts    models.ImportOptions
+		wantRows    []models.ImportRow
+	}{
+		{
+			name:        "csv dry run",
+			query:       "?dry_run=true",
+			contentType: "text/csv",
+			body:        "name,location,field\ntek,pune,it\nacme,,retail\n",
+			wantCode:    http.StatusOK,
+			wantOpts:    models.ImportOptions{DryRun: true},
+			wantRows: []models.ImportRow{
+				{Line: 2, Status: models.ImportCreated},
+				{Line: 3, Status: models.ImportFailed, Reason: "location must satisfy required"},
+			},
+		},
+		{
+			name:        "ndjson in chunks",
+			query:       "?chunk_size=100",
+			contentType: "application/x-ndjson",
+			body:        `{"name":"tek","location":"pune","field":"it"}` + "\n",
+			wantCode:    http.StatusOK,
+			wantOpts:    models.ImportOptions{ChunkSize: 100},
+			wantRows:    []models.ImportRow{{Line: 1, Status: models.ImportCreated}},
+		},
+		{
+			name:        "json array",
+			contentType: "application/json",
+			body:        `[{"name":"tek","location":"pune","field":"it"}]`,
+			wantCode:    http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "negative chunk size",
+			query:       "?chunk_size=-1",
+			contentType: "text/csv",
+			body:        "name,location,field\n",
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "unknown column",
+			contentType: "text/csv",
+			body:        "name,city,field\n",
+			wantCode:    http.StatusBadRequest,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			ms.EXPECT().ImportCompanies(gomock.Any(), uint(4), gomock.Any(), tt.wantOpts).
+				DoAndReturn(func(ctx context.Context, actorID uint, rows imports.Source[models.Company], opts models.ImportOptions) (models.ImportReport, error) {
+					report := models.ImportReport{DryRun: opts.DryRun, Rows: []models.ImportRow{}}
+					for {
+						row, err := rows.Next()
+						if errors.Is(err, io.EOF) {
+							return report, nil
+						}
+						if err != nil {
+							return models.ImportReport{}, err
+						}
+						result := models.ImportRow{Line: row.Line, Status: models.ImportCreated}
+						if row.Err != nil {
+							result = models.ImportRow{Line: row.Line, Status: models.ImportFailed, Reason: row.Err.Error()}
+						}
+						report.Add(result)
+					}
+				}).MaxTimes(1)
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/companies/import"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("Content-Type", tt.contentType)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.wantRows != nil {
+				var got models.ImportReport
+				err := json.Unmarshal(rr.Body.Bytes(), &got)
+				if err != nil {
+					t.Fatalf("response %s is not a report: %v", rr.Body, err)
+				}
+				assert.Equal(t, tt.wantOpts.DryRun, got.DryRun)
+				assert.Equal(t, tt.wantRows, got.Rows)
+			}
+		})
+	}
+}
@@ -2,31 +2,34 @@ package handler
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"project/internal/apperr"
 	"project/internal/auth"
 	"project/internal/middleware"
 	"project/internal/models"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/rs/zerolog/log"
 )
 
 func (h *handler) JobByID(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
-		log.Error().Msg("traceid missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
-		})
+		apperr.Abort(c, "", errTraceIDMissing)
 		return
 	}
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
 	if !ok {
-		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
 		return
 	}
 
@@ -34,20 +37,28 @@ func (h *handler) JobByID(c *gin.Context) {
 
 	jid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
 		return
 	}
 
-	jobData, err := h.service.ViewJobById(ctx, jid)
+	s, err := parseShape(c, resourceCompany)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		apperr.Abort(c, traceid, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, jobData)
+	jobData, err := h.service.ViewJobById(ctx, uid, jid)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	body, err := h.shapeJob(ctx, s, jobData)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	respondTagged(c, traceid, plainTag(s, jobData.ETag), body)
 
 }
 
@@ -55,28 +66,39 @@ func (h *handler) AllJobs(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
-		log.Error().Msg("traceid missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
-		})
+		apperr.Abort(c, "", errTraceIDMissing)
 		return
 	}
 	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
 	if !ok {
-		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
 		return
 	}
-	jobDatas, err := h.service.ViewAllJobs(ctx)
+	s, err := parseShape(c, resourceCompany)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	if t := exportType(c); t != "" {
+		streamExport(c, traceid, t, "jobs", s, func(write func(models.Jobs) error) error {
+			return h.service.ExportJobs(ctx, models.JobFilter{}, write)
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, jobDatas)
+	jobDatas, err := h.service.ViewAllJobs(ctx)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	body, err := h.shapeJobs(ctx, s, jobDatas)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	respondTagged(c, traceid, plainTag(s, jobsTag(jobDatas)), body)
 
 }
 
@@ -84,16 +106,12 @@ func (h *handler) Jobs(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
-		log.Error().Msg("traceid missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
-		})
+		apperr.Abort(c, "", errTraceIDMissing)
 		return
 	}
 	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
 	if !ok {
-		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
 		return
 	}
 
@@ -101,20 +119,35 @@ func (h *handler) Jobs(c *gin.Context) {
 
 	cid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error":http.StatusText(http.StatusBadRequest)})
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
 		return
 	}
 
-	jobData, err := h.service.ViewJob(ctx, cid)
+	s, err := parseShape(c, resourceCompany)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	if t := exportType(c); t != "" {
+		streamExport(c, traceid, t, "jobs", s, func(write func(models.Jobs) error) error {
+			return h.service.ExportJobs(ctx, models.JobFilter{Cid: []uint{uint(cid)}}, write)
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, jobData)
+	jobData, err := h.service.ViewJob(ctx, cid)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	body, err := h.shapeJobs(ctx, s, jobData)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	respondTagged(c, traceid, plainTag(s, jobsTag(jobData)), body)
 
 }
 
@@ -122,46 +155,185 @@ func (h *handler) CreateJobs(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
-		log.Error().Msg("traceid missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
-		})
+		apperr.Abort(c, "", errTraceIDMissing)
 		return
 	}
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
 	if !ok {
-		log.Error().Str("Trace Id", traceid).Msg("login first")
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
 		return
 	}
 
-	id := c.Param("cid")
+	id := c.Param("id")
 
 	cid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	s, err := parseShape(c, resourceCompany)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
 	}
 
 	var jobData models.Jobs
 
 	err = json.NewDecoder(c.Request.Body).Decode(&jobData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide valid name, location and field",
-		})
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide valid name, location and field"))
 		return
 	}
 
-	jobData, err = h.service.AddJobDetails(ctx, jobData, cid)
+	validate := validator.New()
+	err = validate.Struct(jobData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide a valid job"))
+		return
+	}
+
+	jobData, err = h.service.AddJobDetails(ctx, uid, jobData, cid)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	body, err := h.shapeJob(ctx, s, jobData)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	c.JSON(http.StatusOK, body)
+
+}
+
+func (h *handler) UpdateJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	jid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	var jobData models.Jobs
+	err = json.NewDecoder(c.Request.Body).Decode(&jobData)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide a valid job"))
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(jobData)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide a valid job"))
+		return
+	}
+
+	jobData, err = h.service.UpdateJob(ctx, uid, jid, jobData, c.GetHeader("If-Match"))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
 		return
 	}
 
+	c.Header("ETag", jobData.ETag())
 	c.JSON(http.StatusOK, jobData)
+}
+
+func (h *handler) DeleteJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
 
+	jid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	err = h.service.DeleteJob(ctx, uid, jid, c.GetHeader("If-Match"))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *handler) PatchJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	jid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "could not read the patch"))
+		return
+	}
+
+	jobData, err := h.service.PatchJob(ctx, uid, jid, func(current models.Jobs) (models.Jobs, error) {
+		var patched models.Jobs
+		err := applyPatch(c.GetHeader("Content-Type"), current, body, &patched, append(modelMembers, "cid")...)
+		return patched, err
+	}, c.GetHeader("If-Match"))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.Header("ETag", jobData.ETag())
+	c.JSON(http.StatusOK, jobData)
 }
@@ -40,7 +40,7 @@ func Test_handler_JobByID(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"error":"Internal Server Error"}`,
+			expectedResponse:   problemBody(http.StatusInternalServerError, "", "", ""),
 		},
 		{
 			name: "missing jwt claims",
@@ -56,7 +56,7 @@ func Test_handler_JobByID(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   problemBody(http.StatusUnauthorized, "", "", "123"),
 		},
 		{
 			name: "invalid job id",
@@ -66,7 +66,7 @@ func Test_handler_JobByID(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: "4"})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Params = append(c.Params, gin.Param{Key: "id", Value: "abc"})
 
@@ -78,7 +78,7 @@ func Test_handler_JobByID(t *testing.T) {
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse:   `{"error":"Bad Request"}`,
+			expectedResponse:   problemBody(http.StatusBadRequest, "invalid id", "", "123"),
 		},
 
 		{
@@ -89,19 +89,19 @@ func Test_handler_JobByID(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: "4"})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "id", Value: "123"})
 				mc := gomock.NewController(t)
 				ms := mock_files.NewMockUserService(mc)
 
-				ms.EXPECT().ViewJobById(c.Request.Context(), gomock.Any()).Return(models.Jobs{}, errors.New("test service error")).AnyTimes()
+				ms.EXPECT().ViewJobById(c.Request.Context(), uint(4), gomock.Any()).Return(models.Jobs{}, errors.New("test service error")).AnyTimes()
 
 				return c, rr, ms
 			},
-			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse:   `{"error":"test service error"}`,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   problemBody(http.StatusInternalServerError, "", "", "123"),
 		},
 		{
 			name: "success",
@@ -111,14 +111,14 @@ func Test_handler_JobByID(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: "4"})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "id", Value: "123"})
 				mc := gomock.NewController(t)
 				ms := mock_files.NewMockUserService(mc)
 
-				ms.EXPECT().ViewJobById(c.Request.Context(), gomock.Any()).Return(models.Jobs{}, nil).AnyTimes()
+				ms.EXPECT().ViewJobById(c.Request.Context(), uint(4), gomock.Any()).Return(models.Jobs{}, nil).AnyTimes()
 
 				return c, rr, ms
 			},
@@ -162,7 +162,7 @@ func Test_handler_AllJobs(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"error":"Internal Server Error"}`,
+			expectedResponse:   problemBody(http.StatusInternalServerError, "", "", ""),
 		},
 		{
 			name: "missing jwt claims",
@@ -178,7 +178,7 @@ func Test_handler_AllJobs(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   problemBody(http.StatusUnauthorized, "", "", "123"),
 		},
 		{
 			name: "error while fetching jobs from service",
@@ -200,7 +200,7 @@ func Test_handler_AllJobs(t *testing.T) {
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   problemBody(http.StatusUnauthorized, "", "", "123"),
 		},
 
 		{
@@ -262,7 +262,7 @@ func Test_handler_Jobs(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"error":"Internal Server Error"}`,
+			expectedResponse:   problemBody(http.StatusInternalServerError, "", "", ""),
 		},
 		{
 			name: "missing jwt claims",
@@ -278,7 +278,7 @@ func Test_handler_Jobs(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   problemBody(http.StatusUnauthorized, "", "", "123"),
 		},
 		{
 			name: "invalid company id",
@@ -300,7 +300,7 @@ func Test_handler_Jobs(t *testing.T) {
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusBadRequest,
-			expectedResponse:   `{"error":"Bad Request"}`,
+			expectedResponse:   problemBody(http.StatusBadRequest, "invalid id", "", "123"),
 		},
 
 		{
@@ -362,7 +362,7 @@ func Test_handler_CreateJobs(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   `{"error":"Internal Server Error"}`,
+			expectedResponse:   problemBody(http.StatusInternalServerError, "", "", ""),
 		},
 		{
 			name: "missing jwt claims",
@@ -378,7 +378,7 @@ func Test_handler_CreateJobs(t *testing.T) {
 				return c, rr, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   `{"error":"Unauthorized"}`,
+			expectedResponse:   problemBody(http.StatusUnauthorized, "", "", "123"),
 		},
 		{
 			name: "Success",
@@ -388,18 +388,61 @@ func Test_handler_CreateJobs(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", strings.NewReader(`{"name":"Tek system","location":"mysore"}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: "4"})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "id", Value: "3"})
 
 				mc := gomock.NewController(t)
 				ms := mock_files.NewMockUserService(mc)
-				ms.EXPECT().AddJobDetails(gomock.Any(), gomock.Any(),gomock.Any()).Return(models.Jobs{}, nil).AnyTimes()
+				ms.EXPECT().AddJobDetails(gomock.Any(), uint(4), gomock.Any(), gomock.Any()).Return(models.Jobs{}, nil).AnyTimes()
 
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"cid":0,"job_role":"","salary":""}`,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"cid":0,"name":"","salary":"","notice_period":""}`,
+		},
+		{
+			name: "invalid company id",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", strings.NewReader(`{"name":"developer"}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: "4"})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "id", Value: "abc"})
+
+				mc := gomock.NewController(t)
+				ms := mock_files.NewMockUserService(mc)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   problemBody(http.StatusBadRequest, "invalid id", "", "123"),
+		},
+		{
+			name: "invalid job",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodPost, "http://test.com:8080", strings.NewReader(`{"name":"developer","remote_policy":"sometimes"}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: "4"})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				c.Params = append(c.Params, gin.Param{Key: "id", Value: "3"})
+
+				mc := gomock.NewController(t)
+				ms := mock_files.NewMockUserService(mc)
+
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   problemBody(http.StatusBadRequest, "please provide a valid job", "", "123"),
 		},
 	}
 	for _, tt := range tests {
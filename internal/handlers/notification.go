@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/inbox"
+	"project/internal/middleware"
+	"project/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/net/websocket"
+)
+
+// Notifications lists the notifications of the signed in user newest first,
+// ?unread=true leaves out the ones already read
+func (h *handler) Notifications(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	var unread bool
+	if v := c.Query("unread"); v != "" {
+		unread, err = strconv.ParseBool(v)
+		if err != nil {
+			apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid unread"))
+			return
+		}
+	}
+
+	notifications, err := h.service.Notifications(ctx, uid, unread)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+func (h *handler) ReadNotifications(c *gin.Context) {
+	h.markNotifications(c, true)
+}
+
+func (h *handler) UnreadNotifications(c *gin.Context) {
+	h.markNotifications(c, false)
+}
+
+func (h *handler) markNotifications(c *gin.Context, read bool) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	var ack models.NotificationAck
+	err = json.NewDecoder(c.Request.Body).Decode(&ack)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide the ids of up to 100 notifications"))
+		return
+	}
+	err = validator.New().Struct(ack)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide the ids of up to 100 notifications"))
+		return
+	}
+
+	err = h.service.MarkNotifications(ctx, uid, ack.IDs, read)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// NotificationSocket upgrades to a websocket that first sends the unread
+// notifications of the user and then every new notification and acknowledgement
+// of any of their sessions. The client acknowledges with read and unread messages
+func (h *handler) NotificationSocket(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	if !c.IsWebsocket() {
+		apperr.Abort(c, traceid, apperr.New(apperr.Validation, "expected a websocket upgrade"))
+		return
+	}
+
+	session, unread, err := h.service.JoinNotifications(ctx, uid)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	defer session.Close()
+
+	// the handshake is written on the hijacked connection where gin does not see
+	// it, record its status for the logs
+	c.Status(http.StatusSwitchingProtocols)
+	// the token is the credential, not a cookie, so any origin may connect
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		h.serveNotifications(ctx, traceid, uid, ws, session, unread)
+	}}.ServeHTTP(c.Writer, c.Request)
+}
+
+// serveNotifications runs the socket until the client leaves or the session ends
+func (h *handler) serveNotifications(ctx context.Context, traceid string, uid uint, ws *websocket.Conn,
+	session *inbox.Session, unread []models.Notification) {
+	// the timeouts of the server still apply to the hijacked connection
+	err := ws.SetDeadline(time.Time{})
+	if err != nil {
+		return
+	}
+	err = websocket.JSON.Send(ws, models.NotificationMessage{Type: models.MessageInbox, Notifications: unread})
+	if err != nil {
+		return
+	}
+
+	replies := make(chan models.NotificationMessage, 1)
+	go func() {
+		// a client that leaves ends the session and with it the loop below
+		defer session.Close()
+		for {
+			var data []byte
+			err := websocket.Message.Receive(ws, &data)
+			if err != nil {
+				return
+			}
+			err = h.acknowledge(ctx, uid, data)
+			if err == nil {
+				continue
+			}
+			p := apperr.ProblemOf(err)
+			log.Info().Err(err).Str("trace id", traceid).Send()
+			if p.Detail == "" {
+				p.Detail = p.Title
+			}
+			select {
+			case replies <- models.NotificationMessage{Type: models.MessageError, Error: p.Detail}:
+			default:
+			}
+		}
+	}()
+
+	for {
+		var msg models.NotificationMessage
+		select {
+		case m, ok := <-session.Messages():
+			if !ok {
+				// the server is stopping or the client fell too far behind,
+				// either way it reconnects and gets the unread ones again
+				return
+			}
+			msg = m
+		case msg = <-replies:
+		}
+		err = websocket.JSON.Send(ws, msg)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// acknowledge applies a read or unread message of the client, the change reaches
+// every session of the user through the hub
+func (h *handler) acknowledge(ctx context.Context, uid uint, data []byte) error {
+	var msg models.NotificationMessage
+	err := json.Unmarshal(data, &msg)
+	if err != nil {
+		return apperr.Wrap(apperr.Validation, err, "messages must be json")
+	}
+	if msg.Type != models.MessageRead && msg.Type != models.MessageUnread {
+		return apperr.New(apperr.Validation, "only read and unread messages can be sent")
+	}
+	err = validator.New().Struct(models.NotificationAck{IDs: msg.IDs})
+	if err != nil {
+		return apperr.Wrap(apperr.Validation, err, "please provide the ids of up to 100 notifications")
+	}
+	return h.service.MarkNotifications(ctx, uid, msg.IDs, msg.Type == models.MessageRead)
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/inbox"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/net/websocket"
+	"gorm.io/gorm"
+)
+
+func Test_API_notifications(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		setup    func(ms *mock_files.MockUserService)
+		wantCode int
+		want     string
+	}{
+		{
+			name:   "unread",
+			method: http.MethodGet,
+			path:   "/api/v1/me/notifications?unread=true",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().Notifications(gomock.Any(), uint(4), true).Return([]models.Notification{
+					{Model: gorm.Model{ID: 3, CreatedAt: created, UpdatedAt: created}, UserID: 4, Type: models.NotificationMemberAdded, Text: "You were added to tek as recruiter"},
+				}, nil)
+			},
+			wantCode: http.StatusOK,
+			want:     `"read_at":null`,
+		},
+		{
+			name:     "unread that is not a boolean",
+			method:   http.MethodGet,
+			path:     "/api/v1/me/notifications?unread=maybe",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:   "read",
+			method: http.MethodPost,
+			path:   "/api/v1/me/notifications/read",
+			body:   `{"ids":[3,5]}`,
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().MarkNotifications(gomock.Any(), uint(4), []uint{3, 5}, true).Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:   "unread again",
+			method: http.MethodPost,
+			path:   "/api/v1/me/notifications/unread",
+			body:   `{"ids":[3]}`,
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().MarkNotifications(gomock.Any(), uint(4), []uint{3}, false).Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "read without ids",
+			method:   http.MethodPost,
+			path:     "/api/v1/me/notifications/read",
+			body:     `{"ids":[]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "socket without an upgrade",
+			method:   http.MethodGet,
+			path:     "/api/v1/me/notifications/ws",
+			wantCode: http.StatusBadRequest,
+			want:     "expected a websocket upgrade",
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, true, strings.Contains(rr.Body.String(), tt.want))
+		})
+	}
+}
+
+// dialNotifications opens the notification socket of srv, token goes in the
+// query like a browser sends it
+func dialNotifications(t *testing.T, srv *httptest.Server, token string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/me/notifications/ws"
+	if token != "" {
+		url += "?access_token=" + token
+	}
+	ws, err := websocket.Dial(url, "", srv.URL)
+	if err != nil {
+		t.Fatalf("could not open the socket: %v", err)
+	}
+	return ws
+}
+
+func receive(t *testing.T, ws *websocket.Conn) models.NotificationMessage {
+	t.Helper()
+	var msg models.NotificationMessage
+	_ = ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	err := websocket.JSON.Receive(ws, &msg)
+	if err != nil {
+		t.Fatalf("could not receive a message: %v", err)
+	}
+	return msg
+}
+
+func Test_API_NotificationSocket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hub := inbox.NewHub()
+	unread := []models.Notification{{Model: gorm.Model{ID: 3}, UserID: 4, Type: models.NotificationProfileViewed, Text: "A recruiter of tek viewed your profile"}}
+	ms := mock_files.NewMockUserService(gomock.NewController(t))
+	ms.EXPECT().JoinNotifications(gomock.Any(), uint(4)).Times(2).
+		DoAndReturn(func(ctx context.Context, userID uint) (*inbox.Session, []models.Notification, error) {
+			s, err := hub.Join(userID)
+			return s, unread, err
+		})
+	ms.EXPECT().MarkNotifications(gomock.Any(), uint(4), []uint{3}, true).
+		DoAndReturn(func(ctx context.Context, userID uint, ids []uint, read bool) error {
+			hub.Publish(userID, models.NotificationMessage{Type: models.MessageRead, IDs: ids})
+			return nil
+		})
+	srv := httptest.NewServer(API(stubAuth{}, ms))
+	defer srv.Close()
+
+	phone := dialNotifications(t, srv, "token")
+	defer phone.Close()
+	laptop := dialNotifications(t, srv, "token")
+	defer laptop.Close()
+	for _, ws := range []*websocket.Conn{phone, laptop} {
+		msg := receive(t, ws)
+		assert.Equal(t, models.MessageInbox, msg.Type)
+		assert.Equal(t, 1, len(msg.Notifications))
+	}
+
+	// a new notification reaches every session
+	hub.Publish(4, models.NotificationMessage{Type: models.MessageNotification, Notifications: []models.Notification{{Model: gorm.Model{ID: 5}, UserID: 4}}})
+	for _, ws := range []*websocket.Conn{phone, laptop} {
+		msg := receive(t, ws)
+		assert.Equal(t, models.MessageNotification, msg.Type)
+		assert.Equal(t, uint(5), msg.Notifications[0].ID)
+	}
+
+	// reading on the phone marks it read on the laptop as well
+	err := websocket.JSON.Send(phone, models.NotificationMessage{Type: models.MessageRead, IDs: []uint{3}})
+	if err != nil {
+		t.Fatalf("could not send the acknowledgement: %v", err)
+	}
+	for _, ws := range []*websocket.Conn{phone, laptop} {
+		msg := receive(t, ws)
+		assert.Equal(t, models.MessageRead, msg.Type)
+		assert.Equal(t, []uint{3}, msg.IDs)
+	}
+
+	// a message that cannot be applied is answered on that session only
+	err = websocket.JSON.Send(laptop, models.NotificationMessage{Type: models.MessageNotification})
+	if err != nil {
+		t.Fatalf("could not send the message: %v", err)
+	}
+	msg := receive(t, laptop)
+	assert.Equal(t, models.MessageError, msg.Type)
+	assert.Equal(t, "only read and unread messages can be sent", msg.Error)
+
+	// closing the hub ends the sockets
+	hub.Close()
+	var rest models.NotificationMessage
+	_ = phone.SetReadDeadline(time.Now().Add(2 * time.Second))
+	err = websocket.JSON.Receive(phone, &rest)
+	assert.NotEqual(t, nil, err)
+}
+
+func Test_API_NotificationSocket_unauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ms := mock_files.NewMockUserService(gomock.NewController(t))
+	srv := httptest.NewServer(API(stubAuth{}, ms))
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/me/notifications/ws"
+	_, err := websocket.Dial(url, "", srv.URL)
+	assert.NotEqual(t, nil, err)
+}
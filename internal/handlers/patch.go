@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"project/internal/apperr"
+	"project/internal/patch"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// modelMembers are the json members of gorm.Model, no patch can change them
+var modelMembers = []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt"}
+
+// applyPatch patches the json of current with body into dst, contentType picks the
+// patch format. Patches changing one of the immutable members or leaving a record
+// the create rules reject are unprocessable, as RFC 5789 has it
+func applyPatch(contentType string, current interface{}, body []byte, dst interface{}, immutable ...string) error {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	patched, err := patch.Apply(contentType, doc, body)
+	if err != nil {
+		return err
+	}
+
+	var before, after map[string]interface{}
+	err = json.Unmarshal(doc, &before)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(patched, &after)
+	if err != nil {
+		return apperr.Wrap(apperr.Unprocessable, err, "the patched record must be an object")
+	}
+	for _, name := range immutable {
+		if changed(before, after, name) {
+			return apperr.New(apperr.Unprocessable, name+" cannot be changed")
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(dst)
+	if err != nil {
+		return apperr.Wrap(apperr.Unprocessable, err, "the patched record is not valid")
+	}
+	err = validator.New().Struct(dst)
+	if err != nil {
+		return apperr.Wrap(apperr.Unprocessable, err, "the patched record is not valid")
+	}
+	return nil
+}
+
+// changed compares every member matching name regardless of case, decoding into
+// a struct matches names that way so "id" would otherwise sneak past "ID"
+func changed(before, after map[string]interface{}, name string) bool {
+	for _, doc := range []map[string]interface{}{before, after} {
+		for member := range doc {
+			if strings.EqualFold(member, name) && !reflect.DeepEqual(before[member], after[member]) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"project/internal/patch"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_API_patch(t *testing.T) {
+	company := models.Company{Model: gorm.Model{ID: 42, CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}, Name: "tek", Location: "pune", Field: "it"}
+	job := models.Jobs{Model: gorm.Model{ID: 7}, Cid: 42, Name: "developer", Skills: []string{"go"}, Status: models.JobPublished}
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		wantCode    int
+		want        interface{}
+	}{
+		{
+			name:        "merge patch removing a required field",
+			path:        "/api/v1/companies/42",
+			contentType: patch.MergePatchType,
+			body:        `{"location":"mumbai","field":null}`,
+			wantCode:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "merge patch of one field",
+			path:        "/api/v1/companies/42",
+			contentType: patch.MergePatchType,
+			body:        `{"location":"mumbai"}`,
+			wantCode:    http.StatusOK,
+			want:        models.Company{Model: company.Model, Name: "tek", Location: "mumbai", Field: "it"},
+		},
+		{
+			name:        "json patch",
+			path:        "/api/v1/jobs/7",
+			contentType: patch.JSONPatchType,
+			body:        `[{"op":"test","path":"/name","value":"developer"},{"op":"add","path":"/skills/-","value":"sql"},{"op":"add","path":"/remote_policy","value":"remote"}]`,
+			wantCode:    http.StatusOK,
+			want:        models.Jobs{Model: job.Model, Cid: 42, Name: "developer", Skills: []string{"go", "sql"}, RemotePolicy: "remote", Status: models.JobPublished},
+		},
+		{
+			name:        "failed test",
+			path:        "/api/v1/jobs/7",
+			contentType: patch.JSONPatchType,
+			body:        `[{"op":"test","path":"/name","value":"tester"}]`,
+			wantCode:    http.StatusConflict,
+		},
+		{
+			name:        "change of the id",
+			path:        "/api/v1/companies/42",
+			contentType: patch.MergePatchType,
+			body:        `{"ID":43}`,
+			wantCode:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "change of the id in another case",
+			path:        "/api/v1/companies/42",
+			contentType: patch.MergePatchType,
+			body:        `{"id":43}`,
+			wantCode:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "move to another company",
+			path:        "/api/v1/jobs/7",
+			contentType: patch.JSONPatchType,
+			body:        `[{"op":"replace","path":"/cid","value":43}]`,
+			wantCode:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "change of the creation time",
+			path:        "/api/v1/jobs/7",
+			contentType: patch.JSONPatchType,
+			body:        `[{"op":"replace","path":"/CreatedAt","value":"2020-01-01T00:00:00Z"}]`,
+			wantCode:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "result breaks the create rules",
+			path:        "/api/v1/jobs/7",
+			contentType: patch.MergePatchType,
+			body:        `{"seniority":"wizard"}`,
+			wantCode:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "plain json",
+			path:        "/api/v1/companies/42",
+			contentType: "application/json",
+			body:        `{"location":"mumbai"}`,
+			wantCode:    http.StatusUnsupportedMediaType,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			ms.EXPECT().PatchCompany(gomock.Any(), uint(4), uint64(42), gomock.Any(), `"v1"`).
+				DoAndReturn(func(ctx context.Context, actorID uint, cid uint64, apply func(models.Company) (models.Company, error), ifMatch string) (models.Company, error) {
+					return apply(company)
+				}).AnyTimes()
+			ms.EXPECT().PatchJob(gomock.Any(), uint(4), uint64(7), gomock.Any(), `"v1"`).
+				DoAndReturn(func(ctx context.Context, actorID uint, jid uint64, apply func(models.Jobs) (models.Jobs, error), ifMatch string) (models.Jobs, error) {
+					return apply(job)
+				}).AnyTimes()
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set("If-Match", `"v1"`)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.want != nil {
+				want, _ := json.Marshal(tt.want)
+				assert.Equal(t, string(want), rr.Body.String())
+			}
+		})
+	}
+}
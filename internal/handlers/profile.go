@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/middleware"
+	"project/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// userID reads the id of the signed in user from the subject of the token claims
+func userID(claims jwt.RegisteredClaims) (uint, error) {
+	id, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil || id == 0 {
+		return 0, apperr.New(apperr.Unauthorized, "token subject is not a user id")
+	}
+	return uint(id), nil
+}
+
+func (h *handler) ViewProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	profile, err := h.service.ViewProfile(ctx, uid)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	respondTagged(c, traceid, profile.ETag(), profile)
+}
+
+func (h *handler) SaveProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	var profile models.Profile
+	err = json.NewDecoder(c.Request.Body).Decode(&profile)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide a valid profile"))
+		return
+	}
+
+	validate := validator.New()
+	err = validate.Struct(profile)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide a valid profile"))
+		return
+	}
+
+	profile, err = h.service.SaveProfile(ctx, uid, profile, c.GetHeader("If-Match"))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.Header("ETag", profile.ETag())
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *handler) Recommendations(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			apperr.Abort(c, traceid, apperr.New(apperr.Validation, "limit must be a positive number"))
+			return
+		}
+	}
+
+	recommendations, err := h.service.Recommendations(ctx, uid, limit)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendations)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/middleware"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"project/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func newUserContext(method, target, body, subject string) (*gin.Context, *httptest.ResponseRecorder) {
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	httpRequest, _ := http.NewRequest(method, target, strings.NewReader(body))
+	ctx := httpRequest.Context()
+	ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+	ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: subject})
+	c.Request = httpRequest.WithContext(ctx)
+	return c, rr
+}
+
+func Test_handler_Recommendations(t *testing.T) {
+	tests := []struct {
+		name               string
+		target             string
+		subject            string
+		setup              func(ms *mock_files.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "token without a user",
+			target:             "http://test.com/me/recommendations",
+			subject:            "",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   problemBody(http.StatusUnauthorized, "token subject is not a user id", "/me/recommendations", "123"),
+		},
+		{
+			name:               "invalid limit",
+			target:             "http://test.com/me/recommendations?limit=-2",
+			subject:            "4",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   problemBody(http.StatusBadRequest, "limit must be a positive number", "/me/recommendations", "123"),
+		},
+		{
+			name:    "service error",
+			target:  "http://test.com/me/recommendations",
+			subject: "4",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().Recommendations(gomock.Any(), uint(4), 0).Return(nil, apperr.New(apperr.NotFound, "profile not found"))
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   problemBody(http.StatusNotFound, "profile not found", "/me/recommendations", "123"),
+		},
+		{
+			name:    "success",
+			target:  "http://test.com/me/recommendations?limit=1",
+			subject: "4",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().Recommendations(gomock.Any(), uint(4), 1).Return([]models.Recommendation{{
+					Job:     models.Jobs{Cid: 2, Name: "developer"},
+					Score:   0.8,
+					Factors: []models.FactorScore{{Factor: "skills", Weight: 1, Score: 0.8, Reason: "matches 4 of 5 skills"}},
+				}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[{"job":{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"cid":2,"name":"developer","salary":"","notice_period":""},"score":0.8,"factors":[{"factor":"skills","weight":1,"score":0.8,"reason":"matches 4 of 5 skills"}]}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newUserContext(http.MethodGet, tt.target, "", tt.subject)
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			h := &handler{
+				service: ms,
+			}
+			h.Recommendations(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_SaveProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c, rr := newUserContext(http.MethodPut, "http://test.com/me/profile", `{"seniority":"wizard"}`, "4")
+	h := &handler{service: mock_files.NewMockUserService(gomock.NewController(t))}
+	h.SaveProfile(c)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, problemBody(http.StatusBadRequest, "please provide a valid profile", "/me/profile", "123"), rr.Body.String())
+
+	c, rr = newUserContext(http.MethodPut, "http://test.com/me/profile", `{"skills":["go"],"seniority":"senior"}`, "4")
+	c.Request.Header.Set("If-Match", `"v1"`)
+	saved := models.Profile{Model: gorm.Model{ID: 2}, UserID: 4, Skills: []string{"go"}, Seniority: "senior"}
+	ms := mock_files.NewMockUserService(gomock.NewController(t))
+	ms.EXPECT().SaveProfile(gomock.Any(), uint(4), models.Profile{Skills: []string{"go"}, Seniority: "senior"}, `"v1"`).
+		Return(saved, nil)
+	h = &handler{service: ms}
+	h.SaveProfile(c)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, saved.ETag(), rr.Header().Get("ETag"))
+
+	c, rr = newUserContext(http.MethodPut, "http://test.com/me/profile", `{"skills":["go"]}`, "4")
+	c.Request.Header.Set("If-Match", `"v0"`)
+	ms = mock_files.NewMockUserService(gomock.NewController(t))
+	ms.EXPECT().SaveProfile(gomock.Any(), uint(4), models.Profile{Skills: []string{"go"}}, `"v0"`).
+		Return(models.Profile{}, service.ErrPreconditionFailed)
+	h = &handler{service: ms}
+	h.SaveProfile(c)
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	assert.Equal(t, problemBody(http.StatusPreconditionFailed, "the record was changed since it was read, fetch it again", "/me/profile", "123"), rr.Body.String())
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/middleware"
+	"project/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func (h *handler) SearchJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+
+	filter, err := jobFilterFromQuery(c)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	s, err := parseShape(c, resourceCompany)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	if t := exportType(c); t != "" {
+		streamExport(c, traceid, t, "jobs", s, func(write func(models.Jobs) error) error {
+			return h.service.ExportJobs(ctx, filter, write)
+		})
+		return
+	}
+
+	result, err := h.service.SearchJobs(ctx, filter)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	if s.plain() {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	jobs, err := h.shapeJobs(ctx, s, result.Jobs)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	c.JSON(http.StatusOK, shapedSearchResult{JobSearchResult: result, Jobs: jobs})
+}
+
+// shapedSearchResult replaces the jobs of the envelope with their shaped form
+type shapedSearchResult struct {
+	models.JobSearchResult
+	Jobs interface{} `json:"jobs"`
+}
+
+// jobFilterFromQuery reads the listing filters from the query string, every filter
+// can be repeated (?location=a&location=b) or comma separated (?location=a,b)
+func jobFilterFromQuery(c *gin.Context) (models.JobFilter, error) {
+	filter := models.JobFilter{Query: c.Query("q")}
+
+	for _, v := range queryList(c, "company") {
+		cid, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return models.JobFilter{}, apperr.New(apperr.Validation, fmt.Sprintf("invalid company id %q", v))
+		}
+		filter.Cid = append(filter.Cid, uint(cid))
+	}
+	filter.Location = queryList(c, "location")
+
+	filter.EmploymentType = queryList(c, "employment_type")
+	for _, v := range filter.EmploymentType {
+		switch v {
+		case models.EmploymentFullTime, models.EmploymentPartTime, models.EmploymentContract, models.EmploymentInternship:
+		default:
+			return models.JobFilter{}, apperr.New(apperr.Validation, fmt.Sprintf("invalid employment type %q", v))
+		}
+	}
+
+	filter.RemotePolicy = queryList(c, "remote_policy")
+	for _, v := range filter.RemotePolicy {
+		switch v {
+		case models.RemoteOnsite, models.RemoteHybrid, models.RemoteFull:
+		default:
+			return models.JobFilter{}, apperr.New(apperr.Validation, fmt.Sprintf("invalid remote policy %q", v))
+		}
+	}
+
+	filter.SalaryBucket = queryList(c, "salary")
+	for _, v := range filter.SalaryBucket {
+		if _, ok := models.SalaryBucketByKey(v); !ok {
+			return models.JobFilter{}, apperr.New(apperr.Validation, fmt.Sprintf("invalid salary bucket %q", v))
+		}
+	}
+
+	var err error
+	if v := c.Query("page"); v != "" {
+		filter.Page, err = strconv.Atoi(v)
+		if err != nil || filter.Page < 1 {
+			return models.JobFilter{}, apperr.New(apperr.Validation, "page must be a positive number")
+		}
+	}
+	if v := c.Query("page_size"); v != "" {
+		filter.PageSize, err = strconv.Atoi(v)
+		if err != nil || filter.PageSize < 1 {
+			return models.JobFilter{}, apperr.New(apperr.Validation, "page_size must be a positive number")
+		}
+	}
+	return filter, nil
+}
+
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, v := range c.QueryArray(key) {
+		for _, part := range strings.Split(v, ",") {
+			part = strings.TrimSpace(part)
+			if part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+	return values
+}
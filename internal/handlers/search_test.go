@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/auth"
+	"project/internal/middleware"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	service "project/internal/service"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_SearchJobs(t *testing.T) {
+	newContext := func(target string) (*gin.Context, *httptest.ResponseRecorder) {
+		rr := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rr)
+		httpRequest, _ := http.NewRequest(http.MethodGet, target, nil)
+		ctx := httpRequest.Context()
+		ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+		ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+		c.Request = httpRequest.WithContext(ctx)
+		return c, rr
+	}
+	tests := []struct {
+		name               string
+		setup              func() (*gin.Context, *httptest.ResponseRecorder, service.UserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "missing jwt claims",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com/jobs/search", nil)
+				ctx := context.WithValue(httpRequest.Context(), middleware.TraceIDKey, "123")
+				c.Request = httpRequest.WithContext(ctx)
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   problemBody(http.StatusUnauthorized, "", "/jobs/search", "123"),
+		},
+		{
+			name: "invalid salary bucket",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				c, rr := newContext("http://test.com/jobs/search?salary=lots")
+				return c, rr, mock_files.NewMockUserService(gomock.NewController(t))
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   problemBody(http.StatusBadRequest, `invalid salary bucket "lots"`, "/jobs/search", "123"),
+		},
+		{
+			name: "invalid company id",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				c, rr := newContext("http://test.com/jobs/search?company=abc")
+				return c, rr, mock_files.NewMockUserService(gomock.NewController(t))
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   problemBody(http.StatusBadRequest, `invalid company id "abc"`, "/jobs/search", "123"),
+		},
+		{
+			name: "error from service",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				c, rr := newContext("http://test.com/jobs/search")
+				ms := mock_files.NewMockUserService(gomock.NewController(t))
+				ms.EXPECT().SearchJobs(gomock.Any(), models.JobFilter{}).Return(models.JobSearchResult{}, errors.New("could not find the jobs"))
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   problemBody(http.StatusInternalServerError, "", "/jobs/search", "123"),
+		},
+		{
+			name: "success",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, service.UserService) {
+				c, rr := newContext("http://test.com/jobs/search?q=sofware&company=2,3&location=bang&location=mysore&remote_policy=remote&employment_type=full_time&salary=2500000%2B&page=2&page_size=1")
+				ms := mock_files.NewMockUserService(gomock.NewController(t))
+				filter := models.JobFilter{
+					Query:          "sofware",
+					Cid:            []uint{2, 3},
+					Location:       []string{"bang", "mysore"},
+					EmploymentType: []string{"full_time"},
+					RemotePolicy:   []string{"remote"},
+					SalaryBucket:   []string{"2500000+"},
+					Page:           2,
+					PageSize:       1,
+				}
+				ms.EXPECT().SearchJobs(gomock.Any(), filter).Return(models.JobSearchResult{
+					Jobs:     []models.Jobs{},
+					Total:    1,
+					Page:     2,
+					PageSize: 1,
+					Facets: models.JobFacets{
+						Company: []models.FacetCount{{Value: "2", Label: "tcs", Count: 1}},
+					},
+					Suggestion: "software",
+				}, nil)
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"jobs":[],"total":1,"page":2,"page_size":1,"facets":{"company":[{"value":"2","label":"tcs","count":1}],"location":null,"employment_type":null,"salary_bucket":null,"remote_policy":null},"did_you_mean":"software"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr, ms := tt.setup()
+			h := &handler{
+				service: ms,
+			}
+			h.SearchJobs(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
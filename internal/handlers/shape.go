@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"project/internal/apperr"
+	"project/internal/models"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// resource names usable in ?include and ?fields[...]
+const (
+	resourceCompany = "company"
+	resourceJobs    = "jobs"
+)
+
+// shape is the requested form of a response: ?include=company embeds related
+// resources and ?fields=name,salary trims the primary resource down to the listed
+// fields, fields[company]=name trims an embedded resource. ID is always kept
+type shape struct {
+	include map[string]bool
+	fields  map[string]map[string]bool
+}
+
+// parseShape reads include and fields from the query string, includes lists the
+// related resources the endpoint can embed
+func parseShape(c *gin.Context, includes ...string) (shape, error) {
+	s := shape{
+		include: make(map[string]bool),
+		fields:  make(map[string]map[string]bool),
+	}
+	for _, name := range queryList(c, "include") {
+		allowed := false
+		for _, include := range includes {
+			allowed = allowed || include == name
+		}
+		if !allowed {
+			return shape{}, apperr.New(apperr.Validation, fmt.Sprintf("cannot include %q", name))
+		}
+		s.include[name] = true
+	}
+
+	for key := range c.Request.URL.Query() {
+		resource := ""
+		switch {
+		case key == "fields":
+		case strings.HasPrefix(key, "fields[") && strings.HasSuffix(key, "]"):
+			resource = key[len("fields[") : len(key)-1]
+			if !s.include[resource] {
+				return shape{}, apperr.New(apperr.Validation, fmt.Sprintf("%s needs include=%s", key, resource))
+			}
+		default:
+			continue
+		}
+		names := queryList(c, key)
+		if len(names) == 0 {
+			return shape{}, apperr.New(apperr.Validation, fmt.Sprintf("%s cannot be empty", key))
+		}
+		s.fields[resource] = make(map[string]bool, len(names))
+		for _, name := range names {
+			s.fields[resource][name] = true
+		}
+	}
+	return s, nil
+}
+
+// plain reports whether the response can be sent as is
+func (s shape) plain() bool {
+	return len(s.include) == 0 && len(s.fields) == 0
+}
+
+// object turns v into its json object, trimmed to the fields requested for resource
+func (s shape) object(resource string, v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	err = d.Decode(&obj)
+	if err != nil {
+		return nil, err
+	}
+	if fields, ok := s.fields[resource]; ok {
+		for key := range obj {
+			if key != "ID" && !fields[key] {
+				delete(obj, key)
+			}
+		}
+	}
+	return obj, nil
+}
+
+// jobs shapes a list of jobs, the companies are loaded with one query when included
+func (h *handler) shapeJobs(ctx context.Context, s shape, jobDatas []models.Jobs) (interface{}, error) {
+	if s.plain() {
+		return jobDatas, nil
+	}
+	var companies map[uint]models.Company
+	if s.include[resourceCompany] {
+		cids := make([]uint, 0, len(jobDatas))
+		for _, jobData := range jobDatas {
+			cids = append(cids, jobData.Cid)
+		}
+		var err error
+		companies, err = h.service.CompaniesByIds(ctx, cids)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objs := make([]map[string]interface{}, 0, len(jobDatas))
+	for _, jobData := range jobDatas {
+		obj, err := s.object("", jobData)
+		if err != nil {
+			return nil, err
+		}
+		if s.include[resourceCompany] {
+			obj[resourceCompany] = nil
+			if companyData, ok := companies[jobData.Cid]; ok {
+				obj[resourceCompany], err = s.object(resourceCompany, companyData)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+func (h *handler) shapeJob(ctx context.Context, s shape, jobData models.Jobs) (interface{}, error) {
+	if s.plain() {
+		return jobData, nil
+	}
+	objs, err := h.shapeJobs(ctx, s, []models.Jobs{jobData})
+	if err != nil {
+		return nil, err
+	}
+	return objs.([]map[string]interface{})[0], nil
+}
+
+// companies shapes a list of companies, the jobs are loaded with one query when included
+func (h *handler) shapeCompanies(ctx context.Context, s shape, companyDatas []models.Company) (interface{}, error) {
+	if s.plain() {
+		return companyDatas, nil
+	}
+	var jobs map[uint][]models.Jobs
+	if s.include[resourceJobs] {
+		ids := make([]uint, 0, len(companyDatas))
+		for _, companyData := range companyDatas {
+			ids = append(ids, companyData.ID)
+		}
+		var err error
+		jobs, err = h.service.JobsByCompanyIds(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objs := make([]map[string]interface{}, 0, len(companyDatas))
+	for _, companyData := range companyDatas {
+		obj, err := s.object("", companyData)
+		if err != nil {
+			return nil, err
+		}
+		if s.include[resourceJobs] {
+			jobObjs := make([]map[string]interface{}, 0, len(jobs[companyData.ID]))
+			for _, jobData := range jobs[companyData.ID] {
+				jobObj, err := s.object(resourceJobs, jobData)
+				if err != nil {
+					return nil, err
+				}
+				jobObjs = append(jobObjs, jobObj)
+			}
+			obj[resourceJobs] = jobObjs
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+func (h *handler) shapeCompany(ctx context.Context, s shape, companyData models.Company) (interface{}, error) {
+	if s.plain() {
+		return companyData, nil
+	}
+	objs, err := h.shapeCompanies(ctx, s, []models.Company{companyData})
+	if err != nil {
+		return nil, err
+	}
+	return objs.([]map[string]interface{})[0], nil
+}
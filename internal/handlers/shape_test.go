@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/auth"
+	"project/internal/middleware"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func newShapeContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	httpRequest, _ := http.NewRequest(http.MethodGet, target, nil)
+	ctx := httpRequest.Context()
+	ctx = context.WithValue(ctx, middleware.TraceIDKey, "123")
+	ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+	c.Request = httpRequest.WithContext(ctx)
+	return c, rr
+}
+
+func Test_handler_AllJobs_shape(t *testing.T) {
+	jobs := []models.Jobs{
+		{Model: gorm.Model{ID: 1}, Cid: 2, Name: "developer", Salary: "30000", NoticePeriod: "30 days"},
+		{Model: gorm.Model{ID: 2}, Cid: 2, Name: "tester", Salary: "20000"},
+		{Model: gorm.Model{ID: 3}, Cid: 7, Name: "designer", Salary: "25000"},
+	}
+	tests := []struct {
+		name               string
+		target             string
+		setup              func(ms *mock_files.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "unknown include",
+			target:             "http://test.com/view/all?include=owner",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   problemBody(http.StatusBadRequest, `cannot include "owner"`, "/view/all", "123"),
+		},
+		{
+			name:               "fields of a resource that is not included",
+			target:             "http://test.com/view/all?fields[company]=name",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   problemBody(http.StatusBadRequest, "fields[company] needs include=company", "/view/all", "123"),
+		},
+		{
+			name:   "sparse fields",
+			target: "http://test.com/view/all?fields=name,salary",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewAllJobs(gomock.Any()).Return(jobs[:1], nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[{"ID":1,"name":"developer","salary":"30000"}]`,
+		},
+		{
+			name:   "companies embedded with one batched call",
+			target: "http://test.com/view/all?include=company&fields=name&fields[company]=name",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewAllJobs(gomock.Any()).Return(jobs, nil)
+				ms.EXPECT().CompaniesByIds(gomock.Any(), []uint{2, 2, 7}).Return(map[uint]models.Company{
+					2: {Model: gorm.Model{ID: 2}, Name: "tcs", Location: "bang", Field: "software"},
+				}, nil).Times(1)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[{"ID":1,"company":{"ID":2,"name":"tcs"},"name":"developer"},{"ID":2,"company":{"ID":2,"name":"tcs"},"name":"tester"},{"ID":3,"company":null,"name":"designer"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newShapeContext(tt.target)
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			h := &handler{
+				service: ms,
+			}
+			h.AllJobs(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_ViewAllCompanies_shape(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, rr := newShapeContext("http://test.com/view/allcomp?include=jobs&fields=name&fields[jobs]=name")
+	ms := mock_files.NewMockUserService(gomock.NewController(t))
+	ms.EXPECT().ViewAllCompanies(gomock.Any()).Return([]models.Company{
+		{Model: gorm.Model{ID: 2}, Name: "tcs"},
+		{Model: gorm.Model{ID: 3}, Name: "ibm"},
+	}, nil)
+	ms.EXPECT().JobsByCompanyIds(gomock.Any(), []uint{2, 3}).Return(map[uint][]models.Jobs{
+		2: {{Model: gorm.Model{ID: 1}, Cid: 2, Name: "developer"}},
+	}, nil).Times(1)
+
+	h := &handler{
+		service: ms,
+	}
+	h.ViewAllCompanies(c)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `[{"ID":2,"jobs":[{"ID":1,"name":"developer"}],"name":"tcs"},{"ID":3,"jobs":[],"name":"ibm"}]`, rr.Body.String())
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/etag"
+	"project/internal/middleware"
+	"project/internal/models"
+	"project/internal/schemaorg"
+	"project/internal/sitemap"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sitemapMaxAge is how long crawlers and proxies may reuse a sitemap without asking again
+const sitemapMaxAge = 5 * time.Minute
+
+// JobPosting sends the schema.org JobPosting of a published or closed job as JSON-LD
+func (h *handler) JobPosting(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+
+	jid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+	jobData, err := h.service.PublicJob(ctx, jid)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	posting := schemaorg.NewJobPosting(jobData, jobURL(c, jobData.ID), time.Now())
+	respondTaggedAs(c, traceid, schemaorg.MediaType, "", posting)
+}
+
+// Sitemap sends the sitemap of the published jobs, or once there are more than
+// sitemap.MaxURLs of them an index of its pages
+func (h *handler) Sitemap(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+
+	state, err := h.service.Sitemap(ctx)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	if cached(c, state.ETag(), state.Updated, sitemapMaxAge) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	if state.Pages > 1 {
+		base := baseURL(c)
+		pages := make([]sitemap.URL, 0, state.Pages)
+		for page := 1; page <= state.Pages; page++ {
+			pages = append(pages, sitemap.URL{Loc: fmt.Sprintf("%s%s/sitemaps/%d", base, APIPrefix, page), LastMod: state.Updated})
+		}
+		var b bytes.Buffer
+		err = sitemap.WriteIndex(&b, pages)
+		if err != nil {
+			apperr.Abort(c, traceid, err)
+			return
+		}
+		c.Data(http.StatusOK, sitemap.MediaType+"; charset=utf-8", b.Bytes())
+		return
+	}
+
+	page, err := h.service.SitemapPage(ctx, 1)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	respondSitemapPage(c, traceid, page)
+}
+
+// SitemapPage sends one of the sitemaps listed by the index of Sitemap
+func (h *handler) SitemapPage(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+
+	n, err := strconv.Atoi(c.Param("page"))
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid page"))
+		return
+	}
+	page, err := h.service.SitemapPage(ctx, n)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	if cached(c, etag.Combine(page.Sitemap.ETag(), strconv.Itoa(page.Page)), page.Sitemap.Updated, sitemapMaxAge) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	respondSitemapPage(c, traceid, page)
+}
+
+func respondSitemapPage(c *gin.Context, traceid string, page models.SitemapPage) {
+	urls := make([]sitemap.URL, 0, len(page.Jobs))
+	for _, jobData := range page.Jobs {
+		urls = append(urls, sitemap.URL{Loc: jobURL(c, jobData.ID), LastMod: jobData.UpdatedAt})
+	}
+	var b bytes.Buffer
+	err := sitemap.WriteURLSet(&b, urls)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	c.Data(http.StatusOK, sitemap.MediaType+"; charset=utf-8", b.Bytes())
+}
+
+// jobURL is the public url of a job as the client reached the api, its JobPosting
+// which needs no token unlike the detail endpoint. Crawlers, feed readers and
+// aggregators follow it
+func jobURL(c *gin.Context, jid uint) string {
+	return fmt.Sprintf("%s%s/jobs/%d/jsonld", baseURL(c), APIPrefix, jid)
+}
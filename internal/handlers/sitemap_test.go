@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/apperr"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"project/internal/schemaorg"
+	"project/internal/sitemap"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_API_JobPosting(t *testing.T) {
+	posted := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	jobData := models.Jobs{
+		Model:     gorm.Model{ID: 11, CreatedAt: posted, UpdatedAt: posted},
+		Company:   models.Company{Model: gorm.Model{ID: 7}, Name: "tek"},
+		Cid:       7,
+		Name:      "developer",
+		Location:  "pune",
+		MinSalary: 800000,
+		Status:    models.JobPublished,
+	}
+	gin.SetMode(gin.TestMode)
+	ms := mock_files.NewMockUserService(gomock.NewController(t))
+	ms.EXPECT().PublicJob(gomock.Any(), uint64(11)).Return(jobData, nil).Times(2)
+	ms.EXPECT().PublicJob(gomock.Any(), uint64(12)).Return(models.Jobs{}, apperr.New(apperr.NotFound, "could not find the job"))
+	r := API(stubAuth{}, ms, WithValidation())
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/11/jsonld", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, schemaorg.MediaType+"; charset=utf-8", rr.Header().Get("Content-Type"))
+	var got schemaorg.JobPosting
+	err := json.Unmarshal(rr.Body.Bytes(), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "JobPosting", got.Type)
+	assert.Equal(t, "https://example.com/api/v1/jobs/11/jsonld", got.URL)
+	assert.Equal(t, "tek", got.HiringOrganization.Name)
+	assert.Equal(t, "pune", got.JobLocation.Address.AddressLocality)
+	assert.Equal(t, 800000, got.BaseSalary.Value.MinValue)
+
+	tag := rr.Header().Get("ETag")
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/jobs/11/jsonld", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("If-None-Match", tag)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/12/jsonld", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func Test_API_Sitemap(t *testing.T) {
+	updated := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	small := models.Sitemap{Jobs: 2, Pages: 1, Updated: updated}
+	large := models.Sitemap{Jobs: 2*sitemap.MaxURLs + 1, Pages: 3, Updated: updated}
+	jobs := []models.SitemapJob{{ID: 11, UpdatedAt: updated}, {ID: 12, UpdatedAt: updated.Add(-time.Hour)}}
+	tests := []struct {
+		name     string
+		path     string
+		header   map[string]string
+		setup    func(ms *mock_files.MockUserService)
+		wantCode int
+		want     []string
+	}{
+		{
+			name: "one page",
+			path: "/api/v1/sitemap.xml",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().Sitemap(gomock.Any()).Return(small, nil)
+				ms.EXPECT().SitemapPage(gomock.Any(), 1).Return(models.SitemapPage{Sitemap: small, Page: 1, Jobs: jobs}, nil)
+			},
+			wantCode: http.StatusOK,
+			want: []string{
+				"<urlset",
+				"<loc>http://example.com/api/v1/jobs/11/jsonld</loc>",
+				"<lastmod>2024-01-02T02:04:05Z</lastmod>",
+			},
+		},
+		{
+			name: "split in an index",
+			path: "/api/v1/sitemap.xml",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().Sitemap(gomock.Any()).Return(large, nil)
+			},
+			wantCode: http.StatusOK,
+			want: []string{
+				"<sitemapindex",
+				"<loc>http://example.com/api/v1/sitemaps/1</loc>",
+				"<loc>http://example.com/api/v1/sitemaps/3</loc>",
+			},
+		},
+		{
+			name:   "unchanged",
+			path:   "/api/v1/sitemap.xml",
+			header: map[string]string{"If-Modified-Since": "Tue, 02 Jan 2024 03:04:05 GMT"},
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().Sitemap(gomock.Any()).Return(small, nil)
+			},
+			wantCode: http.StatusNotModified,
+		},
+		{
+			name:   "a job closed since",
+			path:   "/api/v1/sitemap.xml",
+			header: map[string]string{"If-None-Match": small.ETag()},
+			setup: func(ms *mock_files.MockUserService) {
+				closed := models.Sitemap{Jobs: 1, Pages: 1, Updated: updated.Add(time.Minute)}
+				ms.EXPECT().Sitemap(gomock.Any()).Return(closed, nil)
+				ms.EXPECT().SitemapPage(gomock.Any(), 1).Return(models.SitemapPage{Sitemap: closed, Page: 1, Jobs: jobs[1:]}, nil)
+			},
+			wantCode: http.StatusOK,
+			want:     []string{"<loc>http://example.com/api/v1/jobs/12/jsonld</loc>"},
+		},
+		{
+			name: "page",
+			path: "/api/v1/sitemaps/2",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().SitemapPage(gomock.Any(), 2).Return(models.SitemapPage{Sitemap: large, Page: 2, Jobs: jobs}, nil)
+			},
+			wantCode: http.StatusOK,
+			want:     []string{"<urlset", "<loc>http://example.com/api/v1/jobs/12/jsonld</loc>"},
+		},
+		{
+			name: "page out of range",
+			path: "/api/v1/sitemaps/4",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().SitemapPage(gomock.Any(), 4).Return(models.SitemapPage{}, apperr.New(apperr.NotFound, "could not find the sitemap page"))
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "invalid page",
+			path:     "/api/v1/sitemaps/first",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, sitemap.MediaType+"; charset=utf-8", rr.Header().Get("Content-Type"))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(rr.Body.String(), want) {
+					t.Errorf("the sitemap lacks %s:\n%s", want, rr.Body.String())
+				}
+			}
+		})
+	}
+}
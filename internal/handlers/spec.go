@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"net/http"
+	"project/internal/email"
+	"project/internal/middleware"
+	"project/internal/models"
+	"project/internal/openapi"
+)
+
+// SpecPath is where the OpenAPI document of the api is served
+const SpecPath = "/openapi.json"
+
+// loginRequest is the body Login reads, documented apart from NewUser since the
+// username is not needed to sign in
+type loginRequest struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type tokenResponse struct {
+	Token string `json:"token"`
+}
+
+type checkResponse struct {
+	Message string `json:"Message"`
+}
+
+// Spec builds the OpenAPI document from the route table, every legacy alias is
+// listed as a deprecated operation
+func Spec() *openapi.Document {
+	doc := openapi.New("Job Portal API", "1.0.0")
+	doc.Add(openapi.Endpoint{Method: http.MethodGet, Path: "/check", Handler: Check, Public: true, Response: checkResponse{}})
+	doc.Add(openapi.Endpoint{Method: http.MethodGet, Path: SpecPath, Public: true, Response: map[string]interface{}{}})
+
+	for _, rt := range routes(&handler{}) {
+		e := openapi.Endpoint{
+			Method:   rt.method,
+			Path:     APIPrefix + rt.path,
+			Handler:  rt.handler,
+			Public:   rt.public,
+			Params:   rt.params,
+			Request:  rt.request,
+			Response: rt.response,
+			Status:   rt.status,
+			Export:   rt.export,
+		}
+		if rt.idempotent {
+			e.Params = append(append([]openapi.Parameter{}, rt.params...), idempotencyKey())
+		}
+		doc.Add(e)
+
+		e.Deprecated = true
+		e.FromQuery = rt.fromQuery
+		for _, path := range rt.legacy {
+			e.Path = path
+			doc.Add(e)
+		}
+	}
+	return doc
+}
+
+// shapeParams documents ?include and ?fields, includes lists what the endpoint can embed
+func shapeParams(includes ...string) []openapi.Parameter {
+	params := []openapi.Parameter{openapi.QueryList("fields", "string")}
+	if len(includes) > 0 {
+		params = append(params, openapi.QueryList("include", "string", includes...))
+	}
+	return params
+}
+
+// ifNoneMatch documents conditional reads of an endpoint that sends an ETag
+func ifNoneMatch() openapi.Parameter {
+	return openapi.Header("If-None-Match", "ETag the client holds, a 304 without a body answers when it is still current")
+}
+
+// feedParams documents the filters of a feed and its conditional reads
+func feedParams() []openapi.Parameter {
+	return append(filterParams(), conditionalParams()...)
+}
+
+// conditionalParams documents the conditional reads of a public document, by
+// ETag or by date
+func conditionalParams() []openapi.Parameter {
+	return []openapi.Parameter{ifNoneMatch(),
+		openapi.Header("If-Modified-Since", "Last-Modified the client holds, a 304 without a body answers when nothing changed since")}
+}
+
+// ifMatch documents the ETag a write must send, 428 answers when it is missing and
+// 412 when the record changed since. Creating a profile needs no ETag
+func ifMatch() openapi.Parameter {
+	return openapi.Header("If-Match", "ETag of the record being changed")
+}
+
+// aggregatorParams documents the choice of feed format
+func aggregatorParams() []openapi.Parameter {
+	return []openapi.Parameter{openapi.Query("format", "string", aggregatorFormatNames()...)}
+}
+
+// emailPreviewParams documents the choice of email template and its locale
+func emailPreviewParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		openapi.Query("template", "string", email.Names...),
+		openapi.Query("locale", "string"),
+	}
+}
+
+// unsubscribeParams documents the token of an unsubscribe link
+func unsubscribeParams() []openapi.Parameter {
+	return []openapi.Parameter{openapi.Query("token", "string")}
+}
+
+// importParams documents the options of a bulk import
+func importParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		openapi.Query("dry_run", "boolean"),
+		openapi.Query("chunk_size", "integer"),
+	}
+}
+
+// idempotencyKey documents the header that makes retrying a POST safe, the POSTs
+// creating a resource take it
+func idempotencyKey() openapi.Parameter {
+	return openapi.Header(middleware.IdempotencyKeyHeader,
+		"unique key of the request, a retry with the same key and body gets the first response again. "+
+			"409 answers while the first request runs and 422 when the key was used for a different request")
+}
+
+func searchParams() []openapi.Parameter {
+	return append(filterParams(),
+		openapi.Query("page", "integer"),
+		openapi.Query("page_size", "integer"),
+	)
+}
+
+// filterParams documents the job filters jobFilterFromQuery reads
+func filterParams() []openapi.Parameter {
+	salaries := make([]string, 0, len(models.SalaryBuckets))
+	for _, b := range models.SalaryBuckets {
+		salaries = append(salaries, b.Key)
+	}
+	return []openapi.Parameter{
+		openapi.Query("q", "string"),
+		openapi.QueryList("company", "integer"),
+		openapi.QueryList("location", "string"),
+		openapi.QueryList("employment_type", "string",
+			models.EmploymentFullTime, models.EmploymentPartTime, models.EmploymentContract, models.EmploymentInternship),
+		openapi.QueryList("remote_policy", "string", models.RemoteOnsite, models.RemoteHybrid, models.RemoteFull),
+		openapi.QueryList("salary", "string", salaries...),
+	}
+}
+
+// streamParams documents the filters of the job stream and where it resumes
+func streamParams() []openapi.Parameter {
+	return append(filterParams(),
+		openapi.Header("Last-Event-ID", "id of the last event the client got, the stream resumes after it"))
+}
+
+// socketParams documents how a browser, which cannot set headers on a websocket,
+// passes its token
+func socketParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		openapi.Query("access_token", "string"),
+	}
+}
+
+func talentParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		openapi.QueryList("skill", "string"),
+		openapi.Query("location", "string"),
+		openapi.Query("min_experience", "integer"),
+		openapi.Query("max_experience", "integer"),
+		openapi.QueryList("availability", "string",
+			models.AvailableImmediately, models.AvailableTwoWeeks, models.AvailableOneMonth,
+			models.AvailableThreeMonths, models.AvailableNotLooking),
+		openapi.Query("page", "integer"),
+		openapi.Query("page_size", "integer"),
+	}
+}
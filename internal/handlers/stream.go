@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/jobstream"
+	"project/internal/middleware"
+	"project/internal/openapi"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// defaultHeartbeat keeps idle streams from being closed by proxies in between
+const defaultHeartbeat = 15 * time.Second
+
+// reconnectDelay is how long clients wait before reconnecting a dropped stream
+const reconnectDelay = 3 * time.Second
+
+// eventReset tells a client that resumed too late to reload the listing, the
+// events it missed are gone
+const eventReset = "reset"
+
+// StreamJobs sends the events of the jobs matching the listing filters as
+// server-sent events until the client leaves or the server stops. A client
+// reconnecting with Last-Event-ID first gets the events it missed
+func (h *handler) StreamJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+
+	filter, err := jobFilterFromQuery(c)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	var lastID uint64
+	if v := c.GetHeader("Last-Event-ID"); v != "" {
+		lastID, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid Last-Event-ID"))
+			return
+		}
+	}
+
+	sub, err := h.service.FollowJobs(ctx, filter, lastID)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", openapi.EventStreamType)
+	c.Header("Cache-Control", "no-cache")
+	// nginx would otherwise hold the events back in its buffer
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds())
+	if sub.Gap {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", eventReset)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeatInterval())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-sub.Events():
+			if !ok {
+				// the server is stopping or the client fell too far behind,
+				// either way it reconnects and resumes
+				return
+			}
+			err = writeEvent(w, e)
+		case <-heartbeat.C:
+			_, err = io.WriteString(w, ": heartbeat\n\n")
+		}
+		if err != nil {
+			return
+		}
+		w.Flush()
+	}
+}
+
+func (h *handler) heartbeatInterval() time.Duration {
+	if h.heartbeat > 0 {
+		return h.heartbeat
+	}
+	return defaultHeartbeat
+}
+
+func writeEvent(w io.Writer, e jobstream.Event) error {
+	data, err := json.Marshal(e.Job)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/jobstream"
+	"project/internal/middleware"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"project/internal/openapi"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+// readEvent reads the lines of the next event or comment of an event stream
+func readEvent(t *testing.T, r *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("the stream ended after %q: %v", lines, err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func Test_API_StreamJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	b, err := jobstream.NewBroker(jobstream.DefaultReplay)
+	if err != nil {
+		t.Fatalf("NewBroker() error = %v", err)
+	}
+	for i := 1; i <= 6; i++ {
+		b.Publish(models.EventJobCreated, models.Jobs{Model: gorm.Model{ID: uint(i)}, Cid: 7, Name: "developer"})
+	}
+	ms := mock_files.NewMockUserService(gomock.NewController(t))
+	ms.EXPECT().FollowJobs(gomock.Any(), models.JobFilter{Cid: []uint{7}, Location: []string{"pune"}}, uint64(5)).
+		DoAndReturn(func(ctx context.Context, filter models.JobFilter, lastEventID uint64) (*jobstream.Subscription, error) {
+			return b.Subscribe(nil, lastEventID)
+		})
+	srv := httptest.NewServer(API(stubAuth{}, ms))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/jobs/stream?company=7&location=pune", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Last-Event-ID", "5")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("could not open the stream: %v", err)
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, openapi.EventStreamType, resp.Header.Get("Content-Type"))
+
+	r := bufio.NewReader(resp.Body)
+	assert.Equal(t, []string{"retry: 3000"}, readEvent(t, r))
+	// the event missed since the last one the client got
+	assert.Equal(t, []string{"id: 6", "event: job.created", `data: {"ID":6,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"cid":7,"name":"developer","salary":"","notice_period":""}`},
+		readEvent(t, r))
+
+	b.Publish(models.EventJobClosed, models.Jobs{Model: gorm.Model{ID: 2}, Cid: 7, Name: "developer", Status: models.JobClosed})
+	event := readEvent(t, r)
+	assert.Equal(t, []string{"id: 7", "event: job.closed"}, event[:2])
+
+	// stopping the broker ends the stream so the server can shut down
+	b.Close()
+	rest, err := io.ReadAll(r)
+	if err != nil || len(rest) != 0 {
+		t.Errorf("the stream went on with %q, %v after the broker closed", rest, err)
+	}
+}
+
+func Test_handler_StreamJobs(t *testing.T) {
+	b, _ := jobstream.NewBroker(1)
+	for i := 0; i < 3; i++ {
+		b.Publish(models.EventJobCreated, models.Jobs{})
+	}
+	ms := mock_files.NewMockUserService(gomock.NewController(t))
+	ms.EXPECT().FollowJobs(gomock.Any(), models.JobFilter{}, uint64(1)).
+		DoAndReturn(func(ctx context.Context, filter models.JobFilter, lastEventID uint64) (*jobstream.Subscription, error) {
+			return b.Subscribe(nil, lastEventID)
+		})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/jobs/stream", func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), middleware.TraceIDKey, "123")
+		ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{Subject: "4"})
+		c.Request = c.Request.WithContext(ctx)
+	}, (&handler{service: ms, heartbeat: 10 * time.Millisecond}).StreamJobs)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/jobs/stream", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("could not open the stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body := bufio.NewReader(resp.Body)
+	assert.Equal(t, []string{"retry: 3000"}, readEvent(t, body))
+	// event 2 is no longer held, the client has to reload
+	assert.Equal(t, []string{"event: reset", "data: {}"}, readEvent(t, body))
+	assert.Equal(t, "id: 3", readEvent(t, body)[0])
+	assert.Equal(t, []string{": heartbeat"}, readEvent(t, body))
+}
+
+func Test_handler_StreamJobs_invalid(t *testing.T) {
+	tests := []struct {
+		name             string
+		lastEventID      string
+		setup            func(ms *mock_files.MockUserService)
+		expectedStatus   int
+		expectedResponse string
+	}{
+		{
+			name:             "invalid Last-Event-ID",
+			lastEventID:      "yesterday",
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: problemBody(http.StatusBadRequest, "invalid Last-Event-ID", "/jobs/stream", "123"),
+		},
+		{
+			name: "server shutting down",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().FollowJobs(gomock.Any(), models.JobFilter{}, uint64(0)).
+					Return(nil, apperr.Wrap(apperr.Unavailable, jobstream.ErrClosed, "the job stream is shutting down, reconnect later"))
+			},
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedResponse: problemBody(http.StatusServiceUnavailable, "the job stream is shutting down, reconnect later", "/jobs/stream", "123"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newUserContext(http.MethodGet, "http://test.com/jobs/stream", "", "4")
+			if tt.lastEventID != "" {
+				c.Request.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			h := &handler{
+				service: ms,
+			}
+			h.StreamJobs(c)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
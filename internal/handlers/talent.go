@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/middleware"
+	"project/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func (h *handler) SearchTalent(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	filter := models.TalentFilter{
+		Skills:       queryList(c, "skill"),
+		Location:     c.Query("location"),
+		Availability: queryList(c, "availability"),
+	}
+	numbers := []struct {
+		key  string
+		dest *int
+	}{
+		{"min_experience", &filter.MinExperience},
+		{"max_experience", &filter.MaxExperience},
+		{"page", &filter.Page},
+		{"page_size", &filter.PageSize},
+	}
+	for _, n := range numbers {
+		v := c.Query(n.key)
+		if v == "" {
+			continue
+		}
+		*n.dest, err = strconv.Atoi(v)
+		if err != nil || *n.dest < 0 {
+			apperr.Abort(c, traceid, apperr.New(apperr.Validation, n.key+" must be a positive number"))
+			return
+		}
+	}
+
+	result, err := h.service.SearchTalent(ctx, uid, filter)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *handler) ViewCandidate(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	candidateID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	candidate, err := h.service.ViewCandidate(ctx, uid, uint(candidateID))
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, candidate)
+}
+
+func (h *handler) ProfileViews(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	views, err := h.service.ProfileViews(ctx, uid)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, views)
+}
+
+func (h *handler) AddMember(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	cid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	var membership models.Membership
+	err = json.NewDecoder(c.Request.Body).Decode(&membership)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide a valid user_id and role"))
+		return
+	}
+	membership.CompanyID = uint(cid)
+
+	validate := validator.New()
+	err = validate.Struct(membership)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide a valid user_id and role"))
+		return
+	}
+
+	membership, err = h.service.AddMember(ctx, uid, membership)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, membership)
+}
+
+// VerifyCompany lets the members of the company search candidates, admins verify
+// companies once they checked who they are
+func (h *handler) VerifyCompany(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
+	if !ok {
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		apperr.Abort(c, traceid, apperr.ErrUnauthorized)
+		return
+	}
+	uid, err := userID(claims)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	cid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "invalid id"))
+		return
+	}
+
+	verification, err := h.service.VerifyCompany(ctx, uid, cid)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	service "project/internal/service"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_SearchTalent(t *testing.T) {
+	tests := []struct {
+		name               string
+		target             string
+		setup              func(ms *mock_files.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name:               "invalid experience",
+			target:             "http://test.com/talent/search?min_experience=many",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   problemBody(http.StatusBadRequest, "min_experience must be a positive number", "/talent/search", "123"),
+		},
+		{
+			name:   "not a recruiter",
+			target: "http://test.com/talent/search",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().SearchTalent(gomock.Any(), uint(4), gomock.Any()).Return(models.TalentSearchResult{}, service.ErrNotRecruiter)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   problemBody(http.StatusForbidden, "only recruiters of verified companies can view candidates", "/talent/search", "123"),
+		},
+		{
+			name:   "success",
+			target: "http://test.com/talent/search?skill=go,sql&location=bang&min_experience=2&availability=immediate",
+			setup: func(ms *mock_files.MockUserService) {
+				filter := models.TalentFilter{
+					Skills:        []string{"go", "sql"},
+					Location:      "bang",
+					MinExperience: 2,
+					Availability:  []string{"immediate"},
+				}
+				ms.EXPECT().SearchTalent(gomock.Any(), uint(4), filter).Return(models.TalentSearchResult{
+					Candidates: []models.Candidate{{UserID: 11, Headline: "gopher", Skills: []string{"go", "sql"}, ExperienceYears: 3}},
+					Total:      1,
+					Page:       1,
+					PageSize:   20,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"candidates":[{"user_id":11,"headline":"gopher","skills":["go","sql"],"location":"","experience_years":3}],"total":1,"page":1,"page_size":20}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			c, rr := newUserContext(http.MethodGet, tt.target, "", "4")
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			h := &handler{
+				service: ms,
+			}
+			h.SearchTalent(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
@@ -4,17 +4,22 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"project/internal/apperr"
+	"project/internal/graph"
 	"project/internal/middleware"
 	"project/internal/models"
 	service "project/internal/service"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/rs/zerolog/log"
 )
 
 type handler struct {
 	service service.UserService
+	graph   *graph.Server
+	// heartbeat is how often an idle event stream sends a comment, defaultHeartbeat when zero
+	heartbeat time.Duration
 }
 
 //go:generate mockgen -source=user.go -destination=user_mock.go -package=handlers
@@ -24,17 +29,65 @@ type UserHandler interface {
 	ViewCompany(c *gin.Context)
 	ViewAllCompanies(c *gin.Context)
 	AddCompany(c *gin.Context)
+	UpdateCompany(c *gin.Context)
+	PatchCompany(c *gin.Context)
+	DeleteCompany(c *gin.Context)
 	JobByID(c *gin.Context)
 	AllJobs(c *gin.Context)
 	Jobs(c *gin.Context)
 	CreateJobs(c *gin.Context)
+	UpdateJob(c *gin.Context)
+	PatchJob(c *gin.Context)
+	DeleteJob(c *gin.Context)
+	ImportCompanies(c *gin.Context)
+	ImportJobs(c *gin.Context)
+	SearchJobs(c *gin.Context)
+	StreamJobs(c *gin.Context)
+	JobsRSS(c *gin.Context)
+	JobsAtom(c *gin.Context)
+	CompanyJobsRSS(c *gin.Context)
+	CompanyJobsAtom(c *gin.Context)
+	JobPosting(c *gin.Context)
+	AggregatorFeed(c *gin.Context)
+	AggregatorReport(c *gin.Context)
+	Sitemap(c *gin.Context)
+	SitemapPage(c *gin.Context)
+	ViewProfile(c *gin.Context)
+	SaveProfile(c *gin.Context)
+	Recommendations(c *gin.Context)
+	SearchTalent(c *gin.Context)
+	ViewCandidate(c *gin.Context)
+	ProfileViews(c *gin.Context)
+	Notifications(c *gin.Context)
+	ReadNotifications(c *gin.Context)
+	UnreadNotifications(c *gin.Context)
+	NotificationSocket(c *gin.Context)
+	AddMember(c *gin.Context)
+	VerifyCompany(c *gin.Context)
+	CreateWebhook(c *gin.Context)
+	Webhooks(c *gin.Context)
+	DeleteWebhook(c *gin.Context)
+	WebhookDeliveries(c *gin.Context)
+	Redeliver(c *gin.Context)
+	CreateConnector(c *gin.Context)
+	Connectors(c *gin.Context)
+	DeleteConnector(c *gin.Context)
+	PreviewEmail(c *gin.Context)
+	Unsubscribe(c *gin.Context)
+	UnsubscribeLink(c *gin.Context)
+	GraphQL(c *gin.Context)
 }
-func Newhandler(s service.UserService) (UserHandler, error) {
+func Newhandler(s service.UserService, opts ...graph.Option) (UserHandler, error) {
 	if s == nil {
 		return nil, errors.New("service cannot be null")
 	}
+	g, err := graph.NewServer(s, opts...)
+	if err != nil {
+		return nil, err
+	}
 	return &handler{
 		service: s,
+		graph:   g,
 	}, nil
 }
 
@@ -43,10 +96,7 @@ func (h *handler) Login(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
-		log.Error().Msg("traceid missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
-		})
+		apperr.Abort(c, "", errTraceIDMissing)
 		return
 	}
 
@@ -54,19 +104,13 @@ func (h *handler) Login(c *gin.Context) {
 
 	err := json.NewDecoder(c.Request.Body).Decode(&userData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide valid email and password",
-		})
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide valid email and password"))
 		return
 	}
 
 	token, err := h.service.UserLogin(ctx, userData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		apperr.Abort(c, traceid, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -80,42 +124,45 @@ func (h *handler) SignUp(c *gin.Context) {
 
 	traceid, ok := ctx.Value(middleware.TraceIDKey).(string)
 	if !ok {
-		log.Error().Msg("traceid missing from context")
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": http.StatusText(http.StatusInternalServerError),
-		})
+		apperr.Abort(c, "", errTraceIDMissing)
+		return
+	}
+	s, err := parseShape(c)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
 		return
 	}
+
 	var userData models.NewUser
 
-	err := json.NewDecoder(c.Request.Body).Decode(&userData)
+	err = json.NewDecoder(c.Request.Body).Decode(&userData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide valid username, email and password",
-		})
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide valid username, email and password"))
 		return
 	}
 
 	validate := validator.New()
 	err = validate.Struct(userData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": "please provide valid username, email and password",
-		})
+		apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "please provide valid username, email and password"))
 		return
 	}
 
 	userDetails, err := h.service.UserSignup(ctx, userData)
 	if err != nil {
-		log.Error().Err(err).Str("trace id", traceid)
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		apperr.Abort(c, traceid, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, userDetails)
+	if s.plain() {
+		c.JSON(http.StatusOK, userDetails)
+		return
+	}
+	body, err := s.object("", userDetails)
+	if err != nil {
+		apperr.Abort(c, traceid, err)
+		return
+	}
+	c.JSON(http.StatusOK, body)
 
 }
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	service "project/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_API_webhooks(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	hook := models.Webhook{Model: gorm.Model{ID: 3, CreatedAt: created, UpdatedAt: created}, Cid: 42, URL: "https://partner.example/hooks", Events: []string{models.EventJobCreated}}
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		setup    func(ms *mock_files.MockUserService)
+		wantCode int
+		want     string
+	}{
+		{
+			name:   "create",
+			method: http.MethodPost,
+			path:   "/api/v1/companies/42/webhooks",
+			body:   `{"url":"https://partner.example/hooks","events":["job.created"],"secret":"0123456789abcdef"}`,
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().CreateWebhook(gomock.Any(), uint(4), uint64(42), models.Webhook{URL: "https://partner.example/hooks", Events: []string{models.EventJobCreated}, Secret: "0123456789abcdef"}).
+					Return(hook, nil)
+			},
+			wantCode: http.StatusOK,
+			want:     `"events":["job.created"]`,
+		},
+		{
+			name:     "create with an unknown event",
+			method:   http.MethodPost,
+			path:     "/api/v1/companies/42/webhooks",
+			body:     `{"url":"https://partner.example/hooks","events":["job.deleted"],"secret":"0123456789abcdef"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "create with a short secret",
+			method:   http.MethodPost,
+			path:     "/api/v1/companies/42/webhooks",
+			body:     `{"url":"https://partner.example/hooks","secret":"short"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:   "list as a member who cannot recruit",
+			method: http.MethodGet,
+			path:   "/api/v1/companies/42/webhooks",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().Webhooks(gomock.Any(), uint(4), uint64(42)).Return(nil, service.ErrNotCompanyMember)
+			},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			path:   "/api/v1/companies/42/webhooks/3",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().DeleteWebhook(gomock.Any(), uint(4), uint64(42), uint64(3)).Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "delete with an invalid webhook id",
+			method:   http.MethodDelete,
+			path:     "/api/v1/companies/42/webhooks/first",
+			wantCode: http.StatusBadRequest,
+			want:     "webhook_id must be of type integer",
+		},
+		{
+			name:   "delivery log",
+			method: http.MethodGet,
+			path:   "/api/v1/companies/42/webhooks/3/deliveries",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().WebhookDeliveries(gomock.Any(), uint(4), uint64(42), uint64(3)).Return([]models.WebhookDelivery{
+					{Model: gorm.Model{ID: 8, CreatedAt: created, UpdatedAt: created}, WebhookID: 3, Event: models.EventJobCreated, Payload: `{}`,
+						Status: models.DeliveryFailed, Attempts: 10, NextAttemptAt: created, LastStatusCode: 500, LastError: "the receiver answered 500"},
+				}, nil)
+			},
+			wantCode: http.StatusOK,
+			want:     `"status":"failed"`,
+		},
+		{
+			name:   "redeliver",
+			method: http.MethodPost,
+			path:   "/api/v1/companies/42/webhooks/3/deliveries/8/redeliver",
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().Redeliver(gomock.Any(), uint(4), uint64(42), uint64(3), uint64(8)).Return(models.WebhookDelivery{
+					Model: gorm.Model{ID: 8, CreatedAt: created, UpdatedAt: created}, WebhookID: 3, Event: models.EventJobCreated, Payload: `{}`,
+					Status: models.DeliveryPending, NextAttemptAt: created,
+				}, nil)
+			},
+			wantCode: http.StatusOK,
+			want:     `"status":"pending"`,
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			r := API(stubAuth{}, ms, WithValidation())
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, true, strings.Contains(rr.Body.String(), tt.want))
+			if rr.Code == http.StatusOK {
+				// the secret is never sent back
+				assert.Equal(t, false, strings.Contains(rr.Body.String(), "secret"))
+			}
+		})
+	}
+}
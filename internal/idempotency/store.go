@@ -0,0 +1,116 @@
+// Package idempotency remembers the responses of requests sent with an
+// Idempotency-Key so a client retrying after a timeout gets the first answer
+// again instead of creating the same record twice
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"project/internal/apperr"
+	"sync"
+	"time"
+)
+
+// DefaultWindow is how long a key and its response are kept
+const DefaultWindow = 24 * time.Hour
+
+var (
+	// ErrInFlight is returned while the first request with a key is still running
+	ErrInFlight = apperr.New(apperr.Conflict, "a request with this Idempotency-Key is still being processed")
+	// ErrMismatch is returned when a key comes back with a different request
+	ErrMismatch = apperr.New(apperr.Unprocessable, "the Idempotency-Key was already used for a different request")
+)
+
+// Response is what the first request with a key answered
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+type Store interface {
+	// Reserve claims key for a request hashing to hash. It returns the response
+	// of an earlier request with the key, or nil when the caller should run the
+	// request and then Save or Release the key
+	Reserve(ctx context.Context, key, hash string) (*Response, error)
+	Save(ctx context.Context, key string, resp Response) error
+	Release(ctx context.Context, key string) error
+}
+
+type entry struct {
+	hash    string
+	resp    *Response
+	expires time.Time
+}
+
+// MemoryStore keeps keys in the memory of one instance, keys are forgotten a
+// window after they were reserved
+type MemoryStore struct {
+	mu        sync.Mutex
+	window    time.Duration
+	entries   map[string]*entry
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore(window time.Duration) *MemoryStore {
+	return &MemoryStore{
+		window:  window,
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Reserve(ctx context.Context, key, hash string) (*Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	e, ok := s.entries[key]
+	if !ok || now.After(e.expires) {
+		s.entries[key] = &entry{hash: hash, expires: now.Add(s.window)}
+		return nil, nil
+	}
+	if e.hash != hash {
+		return nil, ErrMismatch
+	}
+	if e.resp == nil {
+		return nil, ErrInFlight
+	}
+	return e.resp, nil
+}
+
+func (s *MemoryStore) Save(ctx context.Context, key string, resp Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	e.resp = &resp
+	return nil
+}
+
+// Release forgets the key so the request can be tried again
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep drops expired keys, at most once a minute so reserving stays cheap
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	for key, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+	s.nextSweep = now.Add(time.Minute)
+}
@@ -0,0 +1,54 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := NewMemoryStore(time.Hour)
+	s.now = func() time.Time { return now }
+
+	resp, err := s.Reserve(ctx, "k", "a")
+	if resp != nil || err != nil {
+		t.Fatalf("Reserve() of a new key = %v, %v", resp, err)
+	}
+	_, err = s.Reserve(ctx, "k", "a")
+	if !errors.Is(err, ErrInFlight) {
+		t.Errorf("Reserve() while in flight error = %v, want %v", err, ErrInFlight)
+	}
+	_, err = s.Reserve(ctx, "k", "b")
+	if !errors.Is(err, ErrMismatch) {
+		t.Errorf("Reserve() with another hash error = %v, want %v", err, ErrMismatch)
+	}
+
+	want := Response{Status: http.StatusOK, Header: http.Header{"Content-Type": {"application/json"}}, Body: []byte(`{"id":1}`)}
+	err = s.Save(ctx, "k", want)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	resp, err = s.Reserve(ctx, "k", "a")
+	if err != nil || !reflect.DeepEqual(resp, &want) {
+		t.Errorf("Reserve() after Save() = %v, %v, want %v", resp, err, want)
+	}
+
+	now = now.Add(time.Hour + time.Second)
+	resp, err = s.Reserve(ctx, "k", "b")
+	if resp != nil || err != nil {
+		t.Errorf("Reserve() after the window = %v, %v", resp, err)
+	}
+	err = s.Release(ctx, "k")
+	if err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	resp, err = s.Reserve(ctx, "k", "c")
+	if resp != nil || err != nil {
+		t.Errorf("Reserve() after Release() = %v, %v", resp, err)
+	}
+}
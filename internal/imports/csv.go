@@ -0,0 +1,168 @@
+package imports
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"project/internal/apperr"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// csvSource maps the columns of the header row to the members of T by their json
+// name, lists are separated by semicolons and empty cells leave the zero value
+type csvSource[T any] struct {
+	reader   *csv.Reader
+	columns  []column
+	validate *validator.Validate
+	done     bool
+}
+
+type column struct {
+	name  string
+	index []int
+}
+
+func newCSVSource[T any](r io.Reader) (*csvSource[T], error) {
+	s := &csvSource[T]{reader: csv.NewReader(r), validate: newValidator()}
+	s.reader.TrimLeadingSpace = true
+	header, err := s.reader.Read()
+	if errors.Is(err, io.EOF) {
+		s.done = true
+		return s, nil
+	}
+	if err != nil {
+		return nil, apperr.Wrap(apperr.Validation, err, "could not read the header row")
+	}
+
+	fields := make(map[string][]int)
+	importable(reflect.TypeOf(new(T)).Elem(), nil, fields)
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		index, ok := fields[name]
+		if !ok {
+			return nil, apperr.New(apperr.Validation, fmt.Sprintf("unknown column %q", name))
+		}
+		if seen[name] {
+			return nil, apperr.New(apperr.Validation, fmt.Sprintf("column %q appears twice", name))
+		}
+		seen[name] = true
+		s.columns = append(s.columns, column{name: name, index: index})
+	}
+	return s, nil
+}
+
+func (s *csvSource[T]) Next() (Row[T], error) {
+	if s.done {
+		return Row[T]{}, io.EOF
+	}
+	record, err := s.reader.Read()
+	if errors.Is(err, io.EOF) {
+		s.done = true
+		return Row[T]{}, io.EOF
+	}
+	// a malformed record fails on its own, the reader carries on with the next one
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return Row[T]{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return Row[T]{}, apperr.Wrap(apperr.Validation, err, "could not read the import")
+	}
+
+	line, _ := s.reader.FieldPos(0)
+	row := Row[T]{Line: line}
+	v := reflect.ValueOf(&row.Value).Elem()
+	for i, cell := range record {
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			continue
+		}
+		err := set(v.FieldByIndex(s.columns[i].index), cell)
+		if err != nil {
+			row.Err = fmt.Errorf("%s: %w", s.columns[i].name, err)
+			return row, nil
+		}
+	}
+	row.Err = check(s.validate, row.Value)
+	return row, nil
+}
+
+// importable collects the members of t a cell can be set on, keyed by json name.
+// Embedded structs without a json name contribute their own members
+func importable(t reflect.Type, index []int, fields map[string][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		at := append(append([]int(nil), index...), i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			importable(f.Type, at, fields)
+			continue
+		}
+		if !f.IsExported() || name == "" || name == "-" || !settable(f.Type) {
+			continue
+		}
+		fields[strings.ToLower(name)] = at
+	}
+}
+
+func settable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+func set(v reflect.Value, cell string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", cell)
+		}
+		v.SetBool(b)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(cell, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a number", cell)
+		}
+		v.SetFloat(f)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", cell)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(cell, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a positive integer", cell)
+		}
+		v.SetUint(n)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(cell, ";") {
+			item = strings.TrimSpace(item)
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items).Convert(v.Type()))
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Package imports reads the records of a bulk import one at a time from CSV with
+// a header row or from newline delimited json, so a large upload is never held
+// in memory. Every record is validated with the rules of its model
+package imports
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"project/internal/apperr"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// media types an import can be sent as
+const (
+	CSVType    = "text/csv"
+	NDJSONType = "application/x-ndjson"
+)
+
+// maxLine is the longest NDJSON line read, a longer one fails the import
+const maxLine = 1 << 20
+
+// Row is one record of an import, Err says why it cannot be imported and Line
+// is where it starts in the upload
+type Row[T any] struct {
+	Line  int
+	Value T
+	Err   error
+}
+
+// Source yields the rows of an import in order and io.EOF after the last one.
+// Any other error means the rest of the upload cannot be read
+type Source[T any] interface {
+	Next() (Row[T], error)
+}
+
+// NewSource reads records of type T from r in the format named by contentType
+func NewSource[T any](contentType string, r io.Reader) (Source[T], error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+	switch mediaType {
+	case CSVType:
+		s, err := newCSVSource[T](r)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case NDJSONType:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+		return &ndjsonSource[T]{scanner: scanner, validate: newValidator()}, nil
+	}
+	return nil, apperr.New(apperr.UnsupportedMediaType, "send the import as "+CSVType+" or "+NDJSONType)
+}
+
+type ndjsonSource[T any] struct {
+	scanner  *bufio.Scanner
+	validate *validator.Validate
+	line     int
+}
+
+func (s *ndjsonSource[T]) Next() (Row[T], error) {
+	for s.scanner.Scan() {
+		s.line++
+		b := bytes.TrimSpace(s.scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		row := Row[T]{Line: s.line}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&row.Value)
+		if err != nil {
+			row.Err = fmt.Errorf("not a valid record: %w", err)
+			return row, nil
+		}
+		row.Err = check(s.validate, row.Value)
+		return row, nil
+	}
+	err := s.scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		return Row[T]{}, apperr.Wrap(apperr.Validation, err, fmt.Sprintf("line %d is longer than %d bytes", s.line+1, maxLine))
+	}
+	if err != nil {
+		return Row[T]{}, apperr.Wrap(apperr.Validation, err, "could not read the import")
+	}
+	return Row[T]{}, io.EOF
+}
+
+// newValidator names fields by their json name in errors, the names clients used
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			return f.Name
+		}
+		return name
+	})
+	return v
+}
+
+// check validates value and lists every rule it breaks
+func check(v *validator.Validate, value interface{}) error {
+	err := v.Struct(value)
+	var fields validator.ValidationErrors
+	if !errors.As(err, &fields) {
+		return err
+	}
+	broken := make([]string, 0, len(fields))
+	for _, fe := range fields {
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+		broken = append(broken, fe.Field()+" must satisfy "+rule)
+	}
+	return errors.New(strings.Join(broken, "; "))
+}
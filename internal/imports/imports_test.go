@@ -0,0 +1,133 @@
+package imports
+
+import (
+	"errors"
+	"io"
+	"project/internal/apperr"
+	"project/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type result struct {
+	line  int
+	value models.JobRow
+	err   string
+}
+
+func readAll(t *testing.T, s Source[models.JobRow]) []result {
+	t.Helper()
+	var got []result
+	for {
+		row, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			return got
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		r := result{line: row.Line, value: row.Value}
+		if row.Err != nil {
+			r.err = row.Err.Error()
+		}
+		got = append(got, r)
+	}
+}
+
+func TestSource(t *testing.T) {
+	developer := models.JobRow{Company: "tek", Jobs: models.Jobs{Name: "developer", MinSalary: 100, Skills: []string{"go", "sql"}, RemotePolicy: "remote"}}
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        []result
+	}{
+		{
+			name:        "csv",
+			contentType: "text/csv; charset=utf-8",
+			body: "\ufeffCompany,name, min_salary,skills,remote_policy\n" +
+				"tek,developer,100,go; sql,remote\n" +
+				"\n" +
+				"tek,tester,lots,,\n" +
+				",designer,,,\n" +
+				"tek,\"lead\nengineer\",,,office\n",
+			want: []result{
+				{line: 2, value: developer},
+				{line: 4, value: models.JobRow{Company: "tek", Jobs: models.Jobs{Name: "tester"}}, err: `min_salary: "lots" is not an integer`},
+				{line: 5, value: models.JobRow{Jobs: models.Jobs{Name: "designer"}}, err: "company must satisfy required"},
+				{line: 6, value: models.JobRow{Company: "tek", Jobs: models.Jobs{Name: "lead\nengineer", RemotePolicy: "office"}}, err: "remote_policy must satisfy oneof=onsite hybrid remote"},
+			},
+		},
+		{
+			name:        "csv row with a missing cell",
+			contentType: "text/csv",
+			body:        "company,name\ntek\ntek,developer\n",
+			want: []result{
+				{line: 2, err: "wrong number of fields"},
+				{line: 3, value: models.JobRow{Company: "tek", Jobs: models.Jobs{Name: "developer"}}},
+			},
+		},
+		{
+			name:        "empty csv",
+			contentType: "text/csv",
+		},
+		{
+			name:        "ndjson",
+			contentType: "application/x-ndjson",
+			body: `{"company":"tek","name":"developer","min_salary":100,"skills":["go","sql"],"remote_policy":"remote"}` + "\n\n" +
+				`{"company":"tek","salary":5}` + "\n" +
+				`{"company":"tek","name":"tester","bonus":true}`,
+			want: []result{
+				{line: 1, value: developer},
+				{line: 3, value: models.JobRow{Company: "tek"}, err: "not a valid record: json: cannot unmarshal number into Go struct field JobRow.salary of type string"},
+				{line: 4, value: models.JobRow{Company: "tek", Jobs: models.Jobs{Name: "tester"}}, err: `not a valid record: json: unknown field "bonus"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSource[models.JobRow](tt.contentType, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("NewSource() error = %v", err)
+			}
+			got := readAll(t, s)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSource_errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        apperr.Kind
+	}{
+		{name: "json", contentType: "application/json", body: `[]`, want: apperr.UnsupportedMediaType},
+		{name: "unknown column", contentType: "text/csv", body: "company,name,salary_band\n", want: apperr.Validation},
+		{name: "model column", contentType: "text/csv", body: "ID,name\n", want: apperr.Validation},
+		{name: "repeated column", contentType: "text/csv", body: "name,Name\n", want: apperr.Validation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSource[models.JobRow](tt.contentType, strings.NewReader(tt.body))
+			if apperr.KindOf(err) != tt.want || err == nil {
+				t.Errorf("NewSource() error = %v, want kind %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSource_longLine(t *testing.T) {
+	s, err := NewSource[models.Company](NDJSONType, strings.NewReader(`{"name":"`+strings.Repeat("a", maxLine)+`"}`))
+	if err != nil {
+		t.Fatalf("NewSource() error = %v", err)
+	}
+	_, err = s.Next()
+	if apperr.KindOf(err) != apperr.Validation {
+		t.Errorf("Next() error = %v, want a validation error", err)
+	}
+}
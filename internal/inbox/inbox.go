@@ -0,0 +1,113 @@
+// Package inbox fans the notification messages of a user out to every session
+// the user has connected
+package inbox
+
+import (
+	"errors"
+	"project/internal/models"
+	"sync"
+)
+
+// sessionBuffer is how far a session can fall behind before it is dropped, the
+// client reconnects and gets its unread notifications again
+const sessionBuffer = 32
+
+var ErrClosed = errors.New("the notification hub is closed")
+
+// Hub keeps the connected sessions of every user. It only reaches the sessions
+// of this process
+type Hub struct {
+	mu       sync.Mutex
+	sessions map[uint]map[*Session]struct{}
+	closed   bool
+}
+
+func NewHub() *Hub {
+	return &Hub{sessions: make(map[uint]map[*Session]struct{})}
+}
+
+// Join opens a session of the user
+func (h *Hub) Join(userID uint) (*Session, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrClosed
+	}
+	s := &Session{
+		hub:      h,
+		userID:   userID,
+		messages: make(chan models.NotificationMessage, sessionBuffer),
+	}
+	if h.sessions[userID] == nil {
+		h.sessions[userID] = make(map[*Session]struct{})
+	}
+	h.sessions[userID][s] = struct{}{}
+	return s, nil
+}
+
+// Publish hands msg to every session of the user, a session too far behind to
+// take it is dropped rather than holding up the others
+func (h *Hub) Publish(userID uint, msg models.NotificationMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.sessions[userID] {
+		select {
+		case s.messages <- msg:
+		default:
+			h.drop(s)
+		}
+	}
+}
+
+// Sessions counts the connected sessions of the user
+func (h *Hub) Sessions(userID uint) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.sessions[userID])
+}
+
+// Close ends every session and refuses new ones. Shutting the http server down
+// does not wait for hijacked connections, so call it when the server stops
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, sessions := range h.sessions {
+		for s := range sessions {
+			h.drop(s)
+		}
+	}
+}
+
+// drop ends s, h.mu must be held
+func (h *Hub) drop(s *Session) {
+	sessions := h.sessions[s.userID]
+	if _, ok := sessions[s]; !ok {
+		return
+	}
+	delete(sessions, s)
+	if len(sessions) == 0 {
+		delete(h.sessions, s.userID)
+	}
+	close(s.messages)
+}
+
+// Session is one connection of a user
+type Session struct {
+	hub      *Hub
+	userID   uint
+	messages chan models.NotificationMessage
+}
+
+// Messages delivers the messages of the user in order, it is closed when the
+// session ends
+func (s *Session) Messages() <-chan models.NotificationMessage {
+	return s.messages
+}
+
+// Close ends the session, it can be called more than once
+func (s *Session) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.drop(s)
+}
@@ -0,0 +1,68 @@
+package inbox
+
+import (
+	"errors"
+	"project/internal/models"
+	"testing"
+)
+
+func TestHub_Publish(t *testing.T) {
+	h := NewHub()
+	phone, _ := h.Join(4)
+	laptop, _ := h.Join(4)
+	other, _ := h.Join(5)
+
+	h.Publish(4, models.NotificationMessage{Type: models.MessageRead, IDs: []uint{1}})
+	for name, s := range map[string]*Session{"phone": phone, "laptop": laptop} {
+		select {
+		case msg := <-s.Messages():
+			if msg.Type != models.MessageRead {
+				t.Errorf("the %s got %+v", name, msg)
+			}
+		default:
+			t.Errorf("the %s got nothing", name)
+		}
+	}
+	select {
+	case msg := <-other.Messages():
+		t.Errorf("another user got %+v", msg)
+	default:
+	}
+
+	phone.Close()
+	phone.Close()
+	if got := h.Sessions(4); got != 1 {
+		t.Errorf("Sessions() = %d after one closed, want 1", got)
+	}
+}
+
+func TestHub_slowSession(t *testing.T) {
+	h := NewHub()
+	slow, _ := h.Join(4)
+	for i := 0; i <= sessionBuffer; i++ {
+		h.Publish(4, models.NotificationMessage{Type: models.MessageNotification})
+	}
+	got := 0
+	for range slow.Messages() {
+		got++
+	}
+	if got != sessionBuffer {
+		t.Errorf("the slow session got %d messages before it was dropped, want %d", got, sessionBuffer)
+	}
+	if h.Sessions(4) != 0 {
+		t.Error("the slow session is still connected")
+	}
+}
+
+func TestHub_Close(t *testing.T) {
+	h := NewHub()
+	s, _ := h.Join(4)
+	h.Close()
+	if _, ok := <-s.Messages(); ok {
+		t.Error("the session outlived the hub")
+	}
+	_, err := h.Join(4)
+	if !errors.Is(err, ErrClosed) {
+		t.Errorf("Join() after Close error = %v, want %v", err, ErrClosed)
+	}
+}
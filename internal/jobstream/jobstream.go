@@ -0,0 +1,157 @@
+// Package jobstream fans job events out to the clients following them live and
+// keeps the latest ones, so a client that reconnects can catch up on what it missed
+package jobstream
+
+import (
+	"errors"
+	"project/internal/models"
+	"sync"
+)
+
+// DefaultReplay is how many events a broker keeps for clients resuming a stream
+const DefaultReplay = 256
+
+// subscriberBuffer is how far a subscriber can fall behind before it is dropped,
+// it then resumes from the replay buffer like any client that reconnects
+const subscriberBuffer = 64
+
+var ErrClosed = errors.New("the job stream is closed")
+
+// Event is a change to a job. IDs start at 1 and grow by one with every event
+// the broker publishes
+type Event struct {
+	ID   uint64
+	Type string
+	Job  models.Jobs
+}
+
+// Broker publishes events to every subscription whose filter the job matches.
+// It only reaches the subscribers of this process
+type Broker struct {
+	mu     sync.Mutex
+	size   int
+	last   uint64
+	replay []Event
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+func NewBroker(replay int) (*Broker, error) {
+	if replay < 1 {
+		return nil, errors.New("the replay buffer must hold at least one event")
+	}
+	return &Broker{
+		size:   replay,
+		replay: make([]Event, 0, replay),
+		subs:   make(map[*Subscription]struct{}),
+	}, nil
+}
+
+// Publish records the event and hands it to the subscribers. A subscriber too far
+// behind to take it is dropped rather than holding up the others
+func (b *Broker) Publish(eventType string, job models.Jobs) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.last++
+	e := Event{ID: b.last, Type: eventType, Job: job}
+	if len(b.replay) == b.size {
+		copy(b.replay, b.replay[1:])
+		b.replay = b.replay[:b.size-1]
+	}
+	b.replay = append(b.replay, e)
+
+	for sub := range b.subs {
+		if !sub.match(job) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			b.drop(sub)
+		}
+	}
+	return e
+}
+
+// Subscribe follows the events of the jobs match accepts, a nil match accepts
+// all. A lastID above 0 first replays the matching events after it
+func (b *Broker) Subscribe(match func(models.Jobs) bool, lastID uint64) (*Subscription, error) {
+	if match == nil {
+		match = func(models.Jobs) bool { return true }
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	var missed []Event
+	gap := false
+	if lastID > 0 {
+		// an id this broker never sent comes from before a restart
+		gap = lastID > b.last
+		if len(b.replay) > 0 && lastID < b.replay[0].ID-1 {
+			gap = true
+		}
+		for _, e := range b.replay {
+			if e.ID > lastID && match(e.Job) {
+				missed = append(missed, e)
+			}
+		}
+	}
+
+	sub := &Subscription{
+		Gap:    gap,
+		broker: b,
+		match:  match,
+		events: make(chan Event, len(missed)+subscriberBuffer),
+	}
+	for _, e := range missed {
+		sub.events <- e
+	}
+	b.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// Close ends every subscription and refuses new ones, call it before the server
+// shuts down so streams do not hold it up
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subs {
+		b.drop(sub)
+	}
+}
+
+// drop ends sub, b.mu must be held
+func (b *Broker) drop(sub *Subscription) {
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.events)
+	}
+}
+
+// Subscription receives the events of one client
+type Subscription struct {
+	// Gap is set when the client resumed after events the broker no longer holds,
+	// it has to reload what it shows instead
+	Gap bool
+
+	broker *Broker
+	match  func(models.Jobs) bool
+	events chan Event
+}
+
+// Events delivers the events in order, it is closed when the subscription ends
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close stops the events, it can be called more than once
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.drop(s)
+}
@@ -0,0 +1,111 @@
+package jobstream
+
+import (
+	"errors"
+	"project/internal/models"
+	"testing"
+)
+
+// received drains what the subscription holds without waiting for more
+func received(sub *Subscription) []uint64 {
+	var ids []uint64
+	for {
+		select {
+		case e, ok := <-sub.Events():
+			if !ok {
+				return ids
+			}
+			ids = append(ids, e.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func equal(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	tek := func(j models.Jobs) bool { return j.Cid == 7 }
+	tests := []struct {
+		name    string
+		match   func(models.Jobs) bool
+		lastID  uint64
+		want    []uint64
+		wantGap bool
+	}{
+		{name: "live only", match: tek},
+		{name: "resume", match: tek, lastID: 3, want: []uint64{5}},
+		{name: "resume without a filter", lastID: 3, want: []uint64{4, 5}},
+		{name: "resume before the replay buffer", match: tek, lastID: 1, want: []uint64{3, 5}, wantGap: true},
+		{name: "resume from the oldest event held", match: tek, lastID: 2, want: []uint64{3, 5}},
+		{name: "resume from another process", match: tek, lastID: 40, wantGap: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewBroker(3)
+			if err != nil {
+				t.Fatalf("NewBroker() error = %v", err)
+			}
+			// events 1 to 5, 3 to 5 are still held
+			for _, cid := range []uint{7, 8, 7, 8, 7} {
+				b.Publish(models.EventJobCreated, models.Jobs{Cid: cid})
+			}
+
+			sub, err := b.Subscribe(tt.match, tt.lastID)
+			if err != nil {
+				t.Fatalf("Subscribe() error = %v", err)
+			}
+			defer sub.Close()
+			if got := received(sub); !equal(got, tt.want) {
+				t.Errorf("replayed %v, want %v", got, tt.want)
+			}
+			if sub.Gap != tt.wantGap {
+				t.Errorf("Gap = %v, want %v", sub.Gap, tt.wantGap)
+			}
+
+			b.Publish(models.EventJobUpdated, models.Jobs{Cid: 7})
+			if got := received(sub); !equal(got, []uint64{6}) {
+				t.Errorf("received %v live, want [6]", got)
+			}
+		})
+	}
+}
+
+func TestBroker_slowSubscriber(t *testing.T) {
+	b, _ := NewBroker(DefaultReplay)
+	slow, _ := b.Subscribe(nil, 0)
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.Publish(models.EventJobCreated, models.Jobs{})
+	}
+	if got := len(received(slow)); got != subscriberBuffer {
+		t.Errorf("the slow subscriber got %d events, want %d before it was dropped", got, subscriberBuffer)
+	}
+	if _, ok := <-slow.Events(); ok {
+		t.Error("the slow subscriber is still subscribed")
+	}
+	// closing a dropped subscription is harmless
+	slow.Close()
+}
+
+func TestBroker_Close(t *testing.T) {
+	b, _ := NewBroker(DefaultReplay)
+	sub, _ := b.Subscribe(nil, 0)
+	b.Close()
+	if _, ok := <-sub.Events(); ok {
+		t.Error("the subscription outlived the broker")
+	}
+	_, err := b.Subscribe(nil, 0)
+	if !errors.Is(err, ErrClosed) {
+		t.Errorf("Subscribe() after Close error = %v, want %v", err, ErrClosed)
+	}
+}
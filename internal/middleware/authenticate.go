@@ -3,12 +3,11 @@ package middleware
 import (
 	"context"
 	"errors"
-	"net/http"
+	"project/internal/apperr"
 	"project/internal/auth"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/rs/zerolog/log"
 )
 
 func (m *Mid) Authenticate(next gin.HandlerFunc) gin.HandlerFunc {
@@ -17,15 +16,18 @@ func (m *Mid) Authenticate(next gin.HandlerFunc) gin.HandlerFunc {
 
 		traceID, ok := ctx.Value(TraceIDKey).(string)
 		if !ok {
-			log.Error().Msg("trace id not present in the context")
-
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": http.StatusText(http.StatusInternalServerError),
-			})
+			apperr.Abort(c, "", errors.New("trace id not present in the context"))
 			return
 		}
 
 		authHeader := c.Request.Header.Get("Authorization")
+		// Browsers cannot set headers when opening a websocket, so the token of an
+		// upgrade can come in the access_token query parameter instead
+		if authHeader == "" && c.IsWebsocket() {
+			if token := c.Query("access_token"); token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
 
 		// Splitting the Authorization header based on the space character.
 		// Boats "Bearer" and the actual token
@@ -33,17 +35,12 @@ func (m *Mid) Authenticate(next gin.HandlerFunc) gin.HandlerFunc {
 		// Checking the format of the Authorization header
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
 			// If the header format doesn't match required format, log and send an error
-			err := errors.New("expected authorization header format: Bearer <token>")
-			log.Error().Err(err).Str("Trace Id", traceID).Send()
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			apperr.Abort(c, traceID, apperr.New(apperr.Unauthorized, "expected authorization header format: Bearer <token>"))
 			return
 		}
 		claims, err := m.auth.ValidateToken(parts[1])
 		if err != nil {
-			log.Error().Err(err).Str("trace id", traceID).Send()
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": http.StatusText(http.StatusInternalServerError),
-			})
+			apperr.Abort(c, traceID, apperr.Wrap(apperr.Unauthorized, err, "invalid token"))
 			return
 		}
 
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks a legacy route, the response carries a Deprecation header and
+// a Link to the successor path with the route parameters filled in. Parameters
+// named in fromQuery are copied from the query string into the route parameters
+// for old paths that never had them in the path
+func (m *Mid) Deprecated(successor string, fromQuery ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, name := range fromQuery {
+			if v, ok := c.GetQuery(name); ok {
+				c.Params = append(c.Params, gin.Param{Key: name, Value: v})
+			}
+		}
+
+		link := successor
+		for _, p := range c.Params {
+			link = strings.Replace(link, ":"+p.Key, p.Value, 1)
+		}
+		c.Header("Deprecation", "true")
+		c.Header("Link", "<"+link+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/idempotency"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// IdempotencyKeyHeader names the header clients send to make a retried POST safe
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKey keeps keys to the size of a uuid with plenty of room
+const maxIdempotencyKey = 255
+
+// maxIdempotentBody bounds the body read into memory to hash it, the resources
+// created under a key are single records far smaller than that
+const maxIdempotentBody = 1 << 20
+
+// Idempotent replays the stored response when a request comes back with an
+// Idempotency-Key already seen. Keys belong to the signed in caller, the same key
+// from two users never collides, and anonymous requests are never replayed.
+// Responses of 5xx are not stored so a retry runs again
+func (m *Mid) Idempotent(store idempotency.Store) func(gin.HandlerFunc) gin.HandlerFunc {
+	return func(next gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			key := c.GetHeader(IdempotencyKeyHeader)
+			ctx := c.Request.Context()
+			claims, _ := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+			if key == "" || claims.Subject == "" {
+				next(c)
+				return
+			}
+			traceid, _ := ctx.Value(TraceIDKey).(string)
+			if len(key) > maxIdempotencyKey {
+				apperr.Abort(c, traceid, apperr.New(apperr.Validation, "Idempotency-Key must be at most 255 characters"))
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBody+1))
+			if err != nil {
+				apperr.Abort(c, traceid, apperr.Wrap(apperr.Validation, err, "could not read the request body"))
+				return
+			}
+			if len(body) > maxIdempotentBody {
+				apperr.Abort(c, traceid, apperr.New(apperr.Validation, "the body of a request with an Idempotency-Key must be at most 1 MiB"))
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+			key = claims.Subject + " " + key
+			sum := sha256.Sum256(append([]byte(c.Request.Method+" "+c.Request.URL.RequestURI()+"\n"), body...))
+			hash := hex.EncodeToString(sum[:])
+
+			resp, err := store.Reserve(ctx, key, hash)
+			if err != nil {
+				apperr.Abort(c, traceid, err)
+				return
+			}
+			if resp != nil {
+				for name, values := range resp.Header {
+					c.Writer.Header()[name] = values
+				}
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(resp.Status, resp.Header.Get("Content-Type"), resp.Body)
+				return
+			}
+
+			saved := false
+			defer func() {
+				// a panic or a failure frees the key for the next try
+				if !saved {
+					err := store.Release(ctx, key)
+					if err != nil {
+						log.Error().Err(err).Str("trace id", traceid).Send()
+					}
+				}
+			}()
+
+			w := &recordingWriter{ResponseWriter: c.Writer}
+			c.Writer = w
+			next(c)
+			c.Writer = w.ResponseWriter
+
+			if w.Status() >= http.StatusInternalServerError {
+				return
+			}
+			err = store.Save(ctx, key, idempotency.Response{
+				Status: w.Status(),
+				Header: w.Header().Clone(),
+				Body:   w.body.Bytes(),
+			})
+			if err != nil {
+				log.Error().Err(err).Str("trace id", traceid).Send()
+				return
+			}
+			saved = true
+		}
+	}
+}
+
+// recordingWriter keeps a copy of the body while it is sent
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
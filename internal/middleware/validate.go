@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// Validate rejects requests that do not match the api spec as validation errors. With
+// responses set the response bodies are checked too and a mismatch turns into a
+// 500, meant for tests where a handler drifting from the spec should fail loudly
+func (m *Mid) Validate(spec *openapi.Document, responses bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			c.Next()
+			return
+		}
+		err := spec.ValidateRequest(c.Request.Method, route, c.Request, c.Param)
+		if errors.Is(err, openapi.ErrNoOperation) {
+			c.Next()
+			return
+		}
+		traceid, _ := c.Request.Context().Value(TraceIDKey).(string)
+		if errors.Is(err, openapi.ErrTooLarge) {
+			apperr.Abort(c, traceid, apperr.New(apperr.TooLarge, err.Error()))
+			return
+		}
+		if errors.Is(err, openapi.ErrMediaType) {
+			apperr.Abort(c, traceid, apperr.New(apperr.UnsupportedMediaType, err.Error()))
+			return
+		}
+		if err != nil {
+			apperr.Abort(c, traceid, apperr.New(apperr.Validation, err.Error()))
+			return
+		}
+		if !responses {
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+		if w.Status() == http.StatusSwitchingProtocols {
+			// the connection was hijacked, there is nothing left to check or send
+			return
+		}
+
+		err = spec.ValidateResponse(c.Request.Method, route, w.Status(), w.Header().Get("Content-Type"), w.body.Bytes())
+		if err != nil {
+			apperr.Abort(c, traceid, apperr.New(apperr.Internal, "response does not match the api spec: "+err.Error()))
+			return
+		}
+		_, err = w.ResponseWriter.Write(w.body.Bytes())
+		if err != nil {
+			log.Error().Err(err).Send()
+		}
+	}
+}
+
+// bufferedWriter holds back the body so it can be checked before it is sent
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
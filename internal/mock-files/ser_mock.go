@@ -10,6 +10,9 @@ package mock_files
 
 import (
 	context "context"
+	imports "project/internal/imports"
+	inbox "project/internal/inbox"
+	jobstream "project/internal/jobstream"
 	models "project/internal/models"
 	reflect "reflect"
 
@@ -40,33 +43,595 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 }
 
 // AddCompanyDetails mocks base method.
-func (m *MockUserService) AddCompanyDetails(ctx context.Context, companyData models.Company) (models.Company, error) {
+func (m *MockUserService) AddCompanyDetails(ctx context.Context, companyData models.Company, ownerID uint) (models.Company, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddCompanyDetails", ctx, companyData)
+	ret := m.ctrl.Call(m, "AddCompanyDetails", ctx, companyData, ownerID)
 	ret0, _ := ret[0].(models.Company)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddCompanyDetails indicates an expected call of AddCompanyDetails.
-func (mr *MockUserServiceMockRecorder) AddCompanyDetails(ctx, companyData any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) AddCompanyDetails(ctx, companyData, ownerID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCompanyDetails", reflect.TypeOf((*MockUserService)(nil).AddCompanyDetails), ctx, companyData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCompanyDetails", reflect.TypeOf((*MockUserService)(nil).AddCompanyDetails), ctx, companyData, ownerID)
 }
 
 // AddJobDetails mocks base method.
-func (m *MockUserService) AddJobDetails(ctx context.Context, jobData models.Jobs, cid uint64) (models.Jobs, error) {
+func (m *MockUserService) AddJobDetails(ctx context.Context, actorID uint, jobData models.Jobs, cid uint64) (models.Jobs, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddJobDetails", ctx, jobData, cid)
+	ret := m.ctrl.Call(m, "AddJobDetails", ctx, actorID, jobData, cid)
 	ret0, _ := ret[0].(models.Jobs)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddJobDetails indicates an expected call of AddJobDetails.
-func (mr *MockUserServiceMockRecorder) AddJobDetails(ctx, jobData, cid any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) AddJobDetails(ctx, actorID, jobData, cid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddJobDetails", reflect.TypeOf((*MockUserService)(nil).AddJobDetails), ctx, jobData, cid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddJobDetails", reflect.TypeOf((*MockUserService)(nil).AddJobDetails), ctx, actorID, jobData, cid)
+}
+
+// AddMember mocks base method.
+func (m *MockUserService) AddMember(ctx context.Context, actorID uint, membership models.Membership) (models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, actorID, membership)
+	ret0, _ := ret[0].(models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockUserServiceMockRecorder) AddMember(ctx, actorID, membership any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockUserService)(nil).AddMember), ctx, actorID, membership)
+}
+
+// CompaniesByIds mocks base method.
+func (m *MockUserService) CompaniesByIds(ctx context.Context, ids []uint) (map[uint]models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompaniesByIds", ctx, ids)
+	ret0, _ := ret[0].(map[uint]models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompaniesByIds indicates an expected call of CompaniesByIds.
+func (mr *MockUserServiceMockRecorder) CompaniesByIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompaniesByIds", reflect.TypeOf((*MockUserService)(nil).CompaniesByIds), ctx, ids)
+}
+
+// CompanyJobFeed mocks base method.
+func (m *MockUserService) CompanyJobFeed(ctx context.Context, cid uint64, filter models.JobFilter) (models.JobFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompanyJobFeed", ctx, cid, filter)
+	ret0, _ := ret[0].(models.JobFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompanyJobFeed indicates an expected call of CompanyJobFeed.
+func (mr *MockUserServiceMockRecorder) CompanyJobFeed(ctx, cid, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompanyJobFeed", reflect.TypeOf((*MockUserService)(nil).CompanyJobFeed), ctx, cid, filter)
+}
+
+// Connectors mocks base method.
+func (m *MockUserService) Connectors(ctx context.Context, actorID uint, cid uint64) ([]models.ATSConnector, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Connectors", ctx, actorID, cid)
+	ret0, _ := ret[0].([]models.ATSConnector)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Connectors indicates an expected call of Connectors.
+func (mr *MockUserServiceMockRecorder) Connectors(ctx, actorID, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connectors", reflect.TypeOf((*MockUserService)(nil).Connectors), ctx, actorID, cid)
+}
+
+// CreateConnector mocks base method.
+func (m *MockUserService) CreateConnector(ctx context.Context, actorID uint, cid uint64, connector models.ATSConnector) (models.ATSConnector, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConnector", ctx, actorID, cid, connector)
+	ret0, _ := ret[0].(models.ATSConnector)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConnector indicates an expected call of CreateConnector.
+func (mr *MockUserServiceMockRecorder) CreateConnector(ctx, actorID, cid, connector any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConnector", reflect.TypeOf((*MockUserService)(nil).CreateConnector), ctx, actorID, cid, connector)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockUserService) CreateWebhook(ctx context.Context, actorID uint, cid uint64, hook models.Webhook) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, actorID, cid, hook)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockUserServiceMockRecorder) CreateWebhook(ctx, actorID, cid, hook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockUserService)(nil).CreateWebhook), ctx, actorID, cid, hook)
+}
+
+// DeleteCompany mocks base method.
+func (m *MockUserService) DeleteCompany(ctx context.Context, actorID uint, cid uint64, ifMatch string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompany", ctx, actorID, cid, ifMatch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCompany indicates an expected call of DeleteCompany.
+func (mr *MockUserServiceMockRecorder) DeleteCompany(ctx, actorID, cid, ifMatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompany", reflect.TypeOf((*MockUserService)(nil).DeleteCompany), ctx, actorID, cid, ifMatch)
+}
+
+// DeleteConnector mocks base method.
+func (m *MockUserService) DeleteConnector(ctx context.Context, actorID uint, cid, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConnector", ctx, actorID, cid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConnector indicates an expected call of DeleteConnector.
+func (mr *MockUserServiceMockRecorder) DeleteConnector(ctx, actorID, cid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConnector", reflect.TypeOf((*MockUserService)(nil).DeleteConnector), ctx, actorID, cid, id)
+}
+
+// DeleteJob mocks base method.
+func (m *MockUserService) DeleteJob(ctx context.Context, actorID uint, jid uint64, ifMatch string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteJob", ctx, actorID, jid, ifMatch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteJob indicates an expected call of DeleteJob.
+func (mr *MockUserServiceMockRecorder) DeleteJob(ctx, actorID, jid, ifMatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJob", reflect.TypeOf((*MockUserService)(nil).DeleteJob), ctx, actorID, jid, ifMatch)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockUserService) DeleteWebhook(ctx context.Context, actorID uint, cid, wid uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, actorID, cid, wid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockUserServiceMockRecorder) DeleteWebhook(ctx, actorID, cid, wid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockUserService)(nil).DeleteWebhook), ctx, actorID, cid, wid)
+}
+
+// EmailEvent mocks base method.
+func (m *MockUserService) EmailEvent(ctx context.Context, event models.DomainEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmailEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EmailEvent indicates an expected call of EmailEvent.
+func (mr *MockUserServiceMockRecorder) EmailEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailEvent", reflect.TypeOf((*MockUserService)(nil).EmailEvent), ctx, event)
+}
+
+// ExportCompanies mocks base method.
+func (m *MockUserService) ExportCompanies(ctx context.Context, fn func(models.Company) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportCompanies", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportCompanies indicates an expected call of ExportCompanies.
+func (mr *MockUserServiceMockRecorder) ExportCompanies(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCompanies", reflect.TypeOf((*MockUserService)(nil).ExportCompanies), ctx, fn)
+}
+
+// ExportJobs mocks base method.
+func (m *MockUserService) ExportJobs(ctx context.Context, filter models.JobFilter, fn func(models.Jobs) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportJobs", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportJobs indicates an expected call of ExportJobs.
+func (mr *MockUserServiceMockRecorder) ExportJobs(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportJobs", reflect.TypeOf((*MockUserService)(nil).ExportJobs), ctx, filter, fn)
+}
+
+// ExportPublishedJobs mocks base method.
+func (m *MockUserService) ExportPublishedJobs(ctx context.Context, fn func(models.Jobs) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportPublishedJobs", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportPublishedJobs indicates an expected call of ExportPublishedJobs.
+func (mr *MockUserServiceMockRecorder) ExportPublishedJobs(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportPublishedJobs", reflect.TypeOf((*MockUserService)(nil).ExportPublishedJobs), ctx, fn)
+}
+
+// FollowJobs mocks base method.
+func (m *MockUserService) FollowJobs(ctx context.Context, filter models.JobFilter, lastEventID uint64) (*jobstream.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowJobs", ctx, filter, lastEventID)
+	ret0, _ := ret[0].(*jobstream.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowJobs indicates an expected call of FollowJobs.
+func (mr *MockUserServiceMockRecorder) FollowJobs(ctx, filter, lastEventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowJobs", reflect.TypeOf((*MockUserService)(nil).FollowJobs), ctx, filter, lastEventID)
+}
+
+// ImportCompanies mocks base method.
+func (m *MockUserService) ImportCompanies(ctx context.Context, actorID uint, rows imports.Source[models.Company], opts models.ImportOptions) (models.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCompanies", ctx, actorID, rows, opts)
+	ret0, _ := ret[0].(models.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCompanies indicates an expected call of ImportCompanies.
+func (mr *MockUserServiceMockRecorder) ImportCompanies(ctx, actorID, rows, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCompanies", reflect.TypeOf((*MockUserService)(nil).ImportCompanies), ctx, actorID, rows, opts)
+}
+
+// ImportJobs mocks base method.
+func (m *MockUserService) ImportJobs(ctx context.Context, actorID uint, rows imports.Source[models.JobRow], opts models.ImportOptions) (models.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportJobs", ctx, actorID, rows, opts)
+	ret0, _ := ret[0].(models.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportJobs indicates an expected call of ImportJobs.
+func (mr *MockUserServiceMockRecorder) ImportJobs(ctx, actorID, rows, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportJobs", reflect.TypeOf((*MockUserService)(nil).ImportJobs), ctx, actorID, rows, opts)
+}
+
+// JobFeed mocks base method.
+func (m *MockUserService) JobFeed(ctx context.Context, filter models.JobFilter) (models.JobFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobFeed", ctx, filter)
+	ret0, _ := ret[0].(models.JobFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobFeed indicates an expected call of JobFeed.
+func (mr *MockUserServiceMockRecorder) JobFeed(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobFeed", reflect.TypeOf((*MockUserService)(nil).JobFeed), ctx, filter)
+}
+
+// JobsByCompanyIds mocks base method.
+func (m *MockUserService) JobsByCompanyIds(ctx context.Context, cids []uint) (map[uint][]models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobsByCompanyIds", ctx, cids)
+	ret0, _ := ret[0].(map[uint][]models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobsByCompanyIds indicates an expected call of JobsByCompanyIds.
+func (mr *MockUserServiceMockRecorder) JobsByCompanyIds(ctx, cids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobsByCompanyIds", reflect.TypeOf((*MockUserService)(nil).JobsByCompanyIds), ctx, cids)
+}
+
+// JoinNotifications mocks base method.
+func (m *MockUserService) JoinNotifications(ctx context.Context, userID uint) (*inbox.Session, []models.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinNotifications", ctx, userID)
+	ret0, _ := ret[0].(*inbox.Session)
+	ret1, _ := ret[1].([]models.Notification)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// JoinNotifications indicates an expected call of JoinNotifications.
+func (mr *MockUserServiceMockRecorder) JoinNotifications(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinNotifications", reflect.TypeOf((*MockUserService)(nil).JoinNotifications), ctx, userID)
+}
+
+// MarkNotifications mocks base method.
+func (m *MockUserService) MarkNotifications(ctx context.Context, userID uint, ids []uint, read bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotifications", ctx, userID, ids, read)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotifications indicates an expected call of MarkNotifications.
+func (mr *MockUserServiceMockRecorder) MarkNotifications(ctx, userID, ids, read any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotifications", reflect.TypeOf((*MockUserService)(nil).MarkNotifications), ctx, userID, ids, read)
+}
+
+// Notifications mocks base method.
+func (m *MockUserService) Notifications(ctx context.Context, userID uint, unreadOnly bool) ([]models.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notifications", ctx, userID, unreadOnly)
+	ret0, _ := ret[0].([]models.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Notifications indicates an expected call of Notifications.
+func (mr *MockUserServiceMockRecorder) Notifications(ctx, userID, unreadOnly any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notifications", reflect.TypeOf((*MockUserService)(nil).Notifications), ctx, userID, unreadOnly)
+}
+
+// PatchCompany mocks base method.
+func (m *MockUserService) PatchCompany(ctx context.Context, actorID uint, cid uint64, apply func(models.Company) (models.Company, error), ifMatch string) (models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchCompany", ctx, actorID, cid, apply, ifMatch)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchCompany indicates an expected call of PatchCompany.
+func (mr *MockUserServiceMockRecorder) PatchCompany(ctx, actorID, cid, apply, ifMatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchCompany", reflect.TypeOf((*MockUserService)(nil).PatchCompany), ctx, actorID, cid, apply, ifMatch)
+}
+
+// PatchJob mocks base method.
+func (m *MockUserService) PatchJob(ctx context.Context, actorID uint, jid uint64, apply func(models.Jobs) (models.Jobs, error), ifMatch string) (models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchJob", ctx, actorID, jid, apply, ifMatch)
+	ret0, _ := ret[0].(models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchJob indicates an expected call of PatchJob.
+func (mr *MockUserServiceMockRecorder) PatchJob(ctx, actorID, jid, apply, ifMatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchJob", reflect.TypeOf((*MockUserService)(nil).PatchJob), ctx, actorID, jid, apply, ifMatch)
+}
+
+// PreviewEmail mocks base method.
+func (m *MockUserService) PreviewEmail(ctx context.Context, actorID uint, template, locale string) (models.RenderedEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewEmail", ctx, actorID, template, locale)
+	ret0, _ := ret[0].(models.RenderedEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewEmail indicates an expected call of PreviewEmail.
+func (mr *MockUserServiceMockRecorder) PreviewEmail(ctx, actorID, template, locale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewEmail", reflect.TypeOf((*MockUserService)(nil).PreviewEmail), ctx, actorID, template, locale)
+}
+
+// ProfileViews mocks base method.
+func (m *MockUserService) ProfileViews(ctx context.Context, userID uint) ([]models.ProfileView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProfileViews", ctx, userID)
+	ret0, _ := ret[0].([]models.ProfileView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProfileViews indicates an expected call of ProfileViews.
+func (mr *MockUserServiceMockRecorder) ProfileViews(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProfileViews", reflect.TypeOf((*MockUserService)(nil).ProfileViews), ctx, userID)
+}
+
+// PublicJob mocks base method.
+func (m *MockUserService) PublicJob(ctx context.Context, jid uint64) (models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicJob", ctx, jid)
+	ret0, _ := ret[0].(models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublicJob indicates an expected call of PublicJob.
+func (mr *MockUserServiceMockRecorder) PublicJob(ctx, jid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicJob", reflect.TypeOf((*MockUserService)(nil).PublicJob), ctx, jid)
+}
+
+// Recommendations mocks base method.
+func (m *MockUserService) Recommendations(ctx context.Context, userID uint, limit int) ([]models.Recommendation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recommendations", ctx, userID, limit)
+	ret0, _ := ret[0].([]models.Recommendation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recommendations indicates an expected call of Recommendations.
+func (mr *MockUserServiceMockRecorder) Recommendations(ctx, userID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recommendations", reflect.TypeOf((*MockUserService)(nil).Recommendations), ctx, userID, limit)
+}
+
+// Redeliver mocks base method.
+func (m *MockUserService) Redeliver(ctx context.Context, actorID uint, cid, wid, did uint64) (models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, actorID, cid, wid, did)
+	ret0, _ := ret[0].(models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockUserServiceMockRecorder) Redeliver(ctx, actorID, cid, wid, did any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockUserService)(nil).Redeliver), ctx, actorID, cid, wid, did)
+}
+
+// SaveProfile mocks base method.
+func (m *MockUserService) SaveProfile(ctx context.Context, userID uint, profile models.Profile, ifMatch string) (models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveProfile", ctx, userID, profile, ifMatch)
+	ret0, _ := ret[0].(models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveProfile indicates an expected call of SaveProfile.
+func (mr *MockUserServiceMockRecorder) SaveProfile(ctx, userID, profile, ifMatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProfile", reflect.TypeOf((*MockUserService)(nil).SaveProfile), ctx, userID, profile, ifMatch)
+}
+
+// SearchJobs mocks base method.
+func (m *MockUserService) SearchJobs(ctx context.Context, filter models.JobFilter) (models.JobSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchJobs", ctx, filter)
+	ret0, _ := ret[0].(models.JobSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchJobs indicates an expected call of SearchJobs.
+func (mr *MockUserServiceMockRecorder) SearchJobs(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchJobs", reflect.TypeOf((*MockUserService)(nil).SearchJobs), ctx, filter)
+}
+
+// SearchTalent mocks base method.
+func (m *MockUserService) SearchTalent(ctx context.Context, recruiterID uint, filter models.TalentFilter) (models.TalentSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTalent", ctx, recruiterID, filter)
+	ret0, _ := ret[0].(models.TalentSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTalent indicates an expected call of SearchTalent.
+func (mr *MockUserServiceMockRecorder) SearchTalent(ctx, recruiterID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTalent", reflect.TypeOf((*MockUserService)(nil).SearchTalent), ctx, recruiterID, filter)
+}
+
+// Sitemap mocks base method.
+func (m *MockUserService) Sitemap(ctx context.Context) (models.Sitemap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sitemap", ctx)
+	ret0, _ := ret[0].(models.Sitemap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sitemap indicates an expected call of Sitemap.
+func (mr *MockUserServiceMockRecorder) Sitemap(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sitemap", reflect.TypeOf((*MockUserService)(nil).Sitemap), ctx)
+}
+
+// SitemapPage mocks base method.
+func (m *MockUserService) SitemapPage(ctx context.Context, page int) (models.SitemapPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SitemapPage", ctx, page)
+	ret0, _ := ret[0].(models.SitemapPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SitemapPage indicates an expected call of SitemapPage.
+func (mr *MockUserServiceMockRecorder) SitemapPage(ctx, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SitemapPage", reflect.TypeOf((*MockUserService)(nil).SitemapPage), ctx, page)
+}
+
+// SyncATSJobs mocks base method.
+func (m *MockUserService) SyncATSJobs(ctx context.Context, connector models.ATSConnector, postings []models.Jobs) (models.ATSSync, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncATSJobs", ctx, connector, postings)
+	ret0, _ := ret[0].(models.ATSSync)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncATSJobs indicates an expected call of SyncATSJobs.
+func (mr *MockUserServiceMockRecorder) SyncATSJobs(ctx, connector, postings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncATSJobs", reflect.TypeOf((*MockUserService)(nil).SyncATSJobs), ctx, connector, postings)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockUserService) Unsubscribe(ctx context.Context, token string) (models.Unsubscribed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", ctx, token)
+	ret0, _ := ret[0].(models.Unsubscribed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockUserServiceMockRecorder) Unsubscribe(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockUserService)(nil).Unsubscribe), ctx, token)
+}
+
+// UpdateCompany mocks base method.
+func (m *MockUserService) UpdateCompany(ctx context.Context, actorID uint, cid uint64, companyData models.Company, ifMatch string) (models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCompany", ctx, actorID, cid, companyData, ifMatch)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCompany indicates an expected call of UpdateCompany.
+func (mr *MockUserServiceMockRecorder) UpdateCompany(ctx, actorID, cid, companyData, ifMatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCompany", reflect.TypeOf((*MockUserService)(nil).UpdateCompany), ctx, actorID, cid, companyData, ifMatch)
+}
+
+// UpdateJob mocks base method.
+func (m *MockUserService) UpdateJob(ctx context.Context, actorID uint, jid uint64, jobData models.Jobs, ifMatch string) (models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, actorID, jid, jobData, ifMatch)
+	ret0, _ := ret[0].(models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockUserServiceMockRecorder) UpdateJob(ctx, actorID, jid, jobData, ifMatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockUserService)(nil).UpdateJob), ctx, actorID, jid, jobData, ifMatch)
 }
 
 // UserLogin mocks base method.
@@ -99,6 +664,35 @@ func (mr *MockUserServiceMockRecorder) UserSignup(ctx, userData any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserSignup", reflect.TypeOf((*MockUserService)(nil).UserSignup), ctx, userData)
 }
 
+// VerifyCompany mocks base method.
+func (m *MockUserService) VerifyCompany(ctx context.Context, actorID uint, cid uint64) (models.CompanyVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyCompany", ctx, actorID, cid)
+	ret0, _ := ret[0].(models.CompanyVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyCompany indicates an expected call of VerifyCompany.
+func (mr *MockUserServiceMockRecorder) VerifyCompany(ctx, actorID, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyCompany", reflect.TypeOf((*MockUserService)(nil).VerifyCompany), ctx, actorID, cid)
+}
+
+// VerifyUnsubscribe mocks base method.
+func (m *MockUserService) VerifyUnsubscribe(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUnsubscribe", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyUnsubscribe indicates an expected call of VerifyUnsubscribe.
+func (mr *MockUserServiceMockRecorder) VerifyUnsubscribe(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUnsubscribe", reflect.TypeOf((*MockUserService)(nil).VerifyUnsubscribe), ctx, token)
+}
+
 // ViewAllCompanies mocks base method.
 func (m *MockUserService) ViewAllCompanies(ctx context.Context) ([]models.Company, error) {
 	m.ctrl.T.Helper()
@@ -129,6 +723,21 @@ func (mr *MockUserServiceMockRecorder) ViewAllJobs(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewAllJobs", reflect.TypeOf((*MockUserService)(nil).ViewAllJobs), ctx)
 }
 
+// ViewCandidate mocks base method.
+func (m *MockUserService) ViewCandidate(ctx context.Context, recruiterID, candidateID uint) (models.Candidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCandidate", ctx, recruiterID, candidateID)
+	ret0, _ := ret[0].(models.Candidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCandidate indicates an expected call of ViewCandidate.
+func (mr *MockUserServiceMockRecorder) ViewCandidate(ctx, recruiterID, candidateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCandidate", reflect.TypeOf((*MockUserService)(nil).ViewCandidate), ctx, recruiterID, candidateID)
+}
+
 // ViewCompanyDetails mocks base method.
 func (m *MockUserService) ViewCompanyDetails(ctx context.Context, cid uint64) (models.Company, error) {
 	m.ctrl.T.Helper()
@@ -160,16 +769,90 @@ func (mr *MockUserServiceMockRecorder) ViewJob(ctx, cid any) *gomock.Call {
 }
 
 // ViewJobById mocks base method.
-func (m *MockUserService) ViewJobById(ctx context.Context, jid uint64) (models.Jobs, error) {
+func (m *MockUserService) ViewJobById(ctx context.Context, actorID uint, jid uint64) (models.Jobs, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ViewJobById", ctx, jid)
+	ret := m.ctrl.Call(m, "ViewJobById", ctx, actorID, jid)
 	ret0, _ := ret[0].(models.Jobs)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ViewJobById indicates an expected call of ViewJobById.
-func (mr *MockUserServiceMockRecorder) ViewJobById(ctx, jid any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) ViewJobById(ctx, actorID, jid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobById", reflect.TypeOf((*MockUserService)(nil).ViewJobById), ctx, actorID, jid)
+}
+
+// ViewProfile mocks base method.
+func (m *MockUserService) ViewProfile(ctx context.Context, userID uint) (models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewProfile", ctx, userID)
+	ret0, _ := ret[0].(models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewProfile indicates an expected call of ViewProfile.
+func (mr *MockUserServiceMockRecorder) ViewProfile(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewProfile", reflect.TypeOf((*MockUserService)(nil).ViewProfile), ctx, userID)
+}
+
+// ViewUser mocks base method.
+func (m *MockUserService) ViewUser(ctx context.Context, userID uint) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewUser", ctx, userID)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewUser indicates an expected call of ViewUser.
+func (mr *MockUserServiceMockRecorder) ViewUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewUser", reflect.TypeOf((*MockUserService)(nil).ViewUser), ctx, userID)
+}
+
+// WebhookDeliveries mocks base method.
+func (m *MockUserService) WebhookDeliveries(ctx context.Context, actorID uint, cid, wid uint64) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookDeliveries", ctx, actorID, cid, wid)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WebhookDeliveries indicates an expected call of WebhookDeliveries.
+func (mr *MockUserServiceMockRecorder) WebhookDeliveries(ctx, actorID, cid, wid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookDeliveries", reflect.TypeOf((*MockUserService)(nil).WebhookDeliveries), ctx, actorID, cid, wid)
+}
+
+// WebhookEvent mocks base method.
+func (m *MockUserService) WebhookEvent(ctx context.Context, event models.DomainEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WebhookEvent indicates an expected call of WebhookEvent.
+func (mr *MockUserServiceMockRecorder) WebhookEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookEvent", reflect.TypeOf((*MockUserService)(nil).WebhookEvent), ctx, event)
+}
+
+// Webhooks mocks base method.
+func (m *MockUserService) Webhooks(ctx context.Context, actorID uint, cid uint64) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Webhooks", ctx, actorID, cid)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Webhooks indicates an expected call of Webhooks.
+func (mr *MockUserServiceMockRecorder) Webhooks(ctx, actorID, cid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobById", reflect.TypeOf((*MockUserService)(nil).ViewJobById), ctx, jid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Webhooks", reflect.TypeOf((*MockUserService)(nil).Webhooks), ctx, actorID, cid)
 }
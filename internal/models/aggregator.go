@@ -0,0 +1,16 @@
+package models
+
+// AggregatorReport is what a feed for job aggregators lists and what it leaves
+// out, with the reasons
+type AggregatorReport struct {
+	Format   string                `json:"format"`
+	Included int                   `json:"included"`
+	Excluded []AggregatorExclusion `json:"excluded"`
+}
+
+// AggregatorExclusion is a job the feed left out
+type AggregatorExclusion struct {
+	JobID   uint     `json:"job_id"`
+	Name    string   `json:"name"`
+	Reasons []string `json:"reasons"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// applicant tracking systems whose public job boards a connector can pull
+const (
+	ATSGreenhouse = "greenhouse"
+	ATSLever      = "lever"
+)
+
+// ATSConnector pulls the public job board of a company in an external applicant
+// tracking system into its jobs. URL is where the board is served as json, the
+// scheduler pulls it again at NextSyncAt. A company has at most one connector
+// of a provider, the external ids of its jobs would collide otherwise
+type ATSConnector struct {
+	gorm.Model
+	Cid          uint       `json:"cid" gorm:"uniqueIndex:idx_connector_provider,where:deleted_at IS NULL"`
+	Provider     string     `json:"provider" gorm:"uniqueIndex:idx_connector_provider,where:deleted_at IS NULL" validate:"required,oneof=greenhouse lever"`
+	URL          string     `json:"url" validate:"required,http_url"`
+	NextSyncAt   time.Time  `json:"next_sync_at" gorm:"index"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	LastSync     ATSSync    `json:"last_sync" gorm:"serializer:json"`
+	LastError    string     `json:"last_error,omitempty"`
+}
+
+// ExternalID is the external id of the job the board lists under id, unique
+// within the company as it has no other connector of the provider
+func (c ATSConnector) ExternalID(id string) string {
+	return c.Provider + ":" + id
+}
+
+// ATSSync is what one pull of a board changed in the jobs of its company
+type ATSSync struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Closed    int `json:"closed"`
+	Unchanged int `json:"unchanged"`
+}
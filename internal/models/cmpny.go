@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"project/internal/etag"
+
+	"gorm.io/gorm"
+)
 
 type Company struct {
 	gorm.Model
@@ -9,11 +13,45 @@ type Company struct {
 	Field    string `json:"field" validate:"required"`
 }
 
+// ETag changes on every update of the company, writes send it back in If-Match
+func (c Company) ETag() string {
+	return etag.Of("company", c.ID, c.UpdatedAt)
+}
+
 type Jobs struct {
 	gorm.Model
-	Company      Company `json:"-" gorm:"ForeignKey:cid"`
-	Cid          uint    `json:"cid"`
-	Name         string  `json:"name"`
-	Salary       string  `json:"salary"`
-	NoticePeriod string  `json:"notice_period"`
+	Company        Company  `json:"-" gorm:"ForeignKey:cid" validate:"-"`
+	Cid            uint     `json:"cid"`
+	Name           string   `json:"name"`
+	Salary         string   `json:"salary"`
+	NoticePeriod   string   `json:"notice_period"`
+	Location       string   `json:"location,omitempty" gorm:"index"`
+	EmploymentType string   `json:"employment_type,omitempty" gorm:"index" validate:"omitempty,oneof=full_time part_time contract internship"`
+	RemotePolicy   string   `json:"remote_policy,omitempty" gorm:"index" validate:"omitempty,oneof=onsite hybrid remote"`
+	MinSalary      int      `json:"min_salary,omitempty" validate:"gte=0"`
+	MaxSalary      int      `json:"max_salary,omitempty" validate:"omitempty,gtefield=MinSalary"`
+	Skills         []string `json:"skills,omitempty" gorm:"serializer:json"`
+	Seniority      string   `json:"seniority,omitempty" validate:"omitempty,oneof=intern junior mid senior lead"`
+	Latitude       float64  `json:"latitude,omitempty" validate:"gte=-90,lte=90"`
+	Longitude      float64  `json:"longitude,omitempty" validate:"gte=-180,lte=180"`
+	Status         string   `json:"status,omitempty" gorm:"index;default:published" validate:"omitempty,oneof=draft published closed"`
+	ExternalID     string   `json:"external_id,omitempty" gorm:"index"`
+	// SyncClosed is set while the job is closed because its board stopped listing
+	// it, a sync only publishes a job again that it closed itself
+	SyncClosed bool `json:"-"`
 }
+
+// ETag changes on every update of the job, writes send it back in If-Match
+func (j Jobs) ETag() string {
+	return etag.Of("job", j.ID, j.UpdatedAt)
+}
+
+// SalaryCurrency is the currency MinSalary and MaxSalary are posted in
+const SalaryCurrency = "INR"
+
+// states of a job, only published jobs are shown to candidates
+const (
+	JobDraft     = "draft"
+	JobPublished = "published"
+	JobClosed    = "closed"
+)
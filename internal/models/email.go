@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// states of a queued email, a pending one is retried until it is sent or runs
+// out of attempts and failed
+const (
+	EmailPending = "pending"
+	EmailSent    = "sent"
+	EmailFailed  = "failed"
+)
+
+// RenderedEmail is what a template makes of the data of an email
+type RenderedEmail struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// Email is a rendered email in the send queue. Key tells apart the emails of
+// one thing happening to one user, the same email is queued once
+type Email struct {
+	gorm.Model
+	Key           string `json:"key" gorm:"uniqueIndex"`
+	UserID        uint   `json:"user_id" gorm:"index"`
+	To            string `json:"to"`
+	Name          string `json:"name"`
+	Template      string `json:"template"`
+	Locale        string `json:"locale"`
+	RenderedEmail `gorm:"embedded"`
+	// Unsubscribe is the url stopping email notifications of the user
+	Unsubscribe   string     `json:"unsubscribe,omitempty"`
+	Status        string     `json:"status" gorm:"index:idx_email_due,priority:1"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"index:idx_email_due,priority:2"`
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// Unsubscribed answers an unsubscribe link
+type Unsubscribed struct {
+	Email          string    `json:"email"`
+	UnsubscribedAt time.Time `json:"unsubscribed_at"`
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// domain events besides the job events of webhook.go
+const (
+	EventCompanyCreated = "company.created"
+	EventUserSignedUp   = "user.signed_up"
+)
+
+// DomainEvent is something that happened in the portal that other parts of it
+// react to, the events of one aggregate are handled in the order they happened
+type DomainEvent interface {
+	EventType() string
+	Aggregate() string
+}
+
+// CompanyCreated is recorded when a company is created with its owner
+type CompanyCreated struct {
+	Company Company `json:"company"`
+	OwnerID uint    `json:"owner_id"`
+}
+
+func (e CompanyCreated) EventType() string { return EventCompanyCreated }
+func (e CompanyCreated) Aggregate() string { return aggregate("company", e.Company.ID) }
+
+// JobCreated is recorded when a job is posted
+type JobCreated struct {
+	Job Jobs `json:"job"`
+}
+
+func (e JobCreated) EventType() string { return EventJobCreated }
+func (e JobCreated) Aggregate() string { return aggregate("job", e.Job.ID) }
+
+// JobUpdated is recorded when a job is changed and stays open
+type JobUpdated struct {
+	Job Jobs `json:"job"`
+}
+
+func (e JobUpdated) EventType() string { return EventJobUpdated }
+func (e JobUpdated) Aggregate() string { return aggregate("job", e.Job.ID) }
+
+// JobClosedEvent is recorded when the status of a job becomes JobClosed or it
+// is deleted, Job is how it was last
+type JobClosedEvent struct {
+	Job      Jobs      `json:"job"`
+	ClosedAt time.Time `json:"closed_at"`
+}
+
+func (e JobClosedEvent) EventType() string { return EventJobClosed }
+func (e JobClosedEvent) Aggregate() string { return aggregate("job", e.Job.ID) }
+
+// UserSignedUp is recorded when an account is created
+type UserSignedUp struct {
+	User User `json:"user"`
+}
+
+func (e UserSignedUp) EventType() string { return EventUserSignedUp }
+func (e UserSignedUp) Aggregate() string { return aggregate("user", e.User.ID) }
+
+func aggregate(kind string, id uint) string {
+	return fmt.Sprintf("%s:%d", kind, id)
+}
+
+// states of an outbox event, a pending one is retried until every subscriber
+// handled it or it runs out of attempts and is dead
+const (
+	OutboxPending   = "pending"
+	OutboxDelivered = "delivered"
+	OutboxDead      = "dead"
+)
+
+// OutboxEvent is a domain event written in the transaction of the change it
+// describes, the table is the queue the outbox dispatcher works through
+type OutboxEvent struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time `json:"created_at"`
+	Aggregate     string    `json:"aggregate" gorm:"index"`
+	Type          string    `json:"type"`
+	Payload       string    `json:"payload"`
+	Status        string    `json:"status" gorm:"index:idx_outbox_due,priority:1"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at" gorm:"index:idx_outbox_due,priority:2"`
+	// Handled names the subscribers done with the event, a retry skips them
+	Handled     []string   `json:"handled" gorm:"serializer:json"`
+	LastError   string     `json:"last_error,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+}
+
+// NewOutboxEvent stores e in an event due at now
+func NewOutboxEvent(e DomainEvent, now time.Time) (OutboxEvent, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return OutboxEvent{}, err
+	}
+	return OutboxEvent{
+		Aggregate:     e.Aggregate(),
+		Type:          e.EventType(),
+		Payload:       string(payload),
+		Status:        OutboxPending,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// Event reads back the domain event stored in o
+func (o OutboxEvent) Event() (DomainEvent, error) {
+	switch o.Type {
+	case EventCompanyCreated:
+		return decodeEvent[CompanyCreated](o.Payload)
+	case EventJobCreated:
+		return decodeEvent[JobCreated](o.Payload)
+	case EventJobUpdated:
+		return decodeEvent[JobUpdated](o.Payload)
+	case EventJobClosed:
+		return decodeEvent[JobClosedEvent](o.Payload)
+	case EventUserSignedUp:
+		return decodeEvent[UserSignedUp](o.Payload)
+	}
+	return nil, fmt.Errorf("unknown event type %q", o.Type)
+}
+
+func decodeEvent[T DomainEvent](payload string) (DomainEvent, error) {
+	var e T
+	err := json.Unmarshal([]byte(payload), &e)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
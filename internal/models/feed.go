@@ -0,0 +1,29 @@
+package models
+
+import (
+	"project/internal/etag"
+	"strconv"
+	"time"
+)
+
+// JobFeed is the newest published jobs matching a filter, each with its company
+// loaded. Company is set on the feed of one company. Updated is when anything the
+// feed shows last changed, a job leaving it included
+type JobFeed struct {
+	Company *Company
+	Jobs    []Jobs
+	Updated time.Time
+}
+
+// ETag changes whenever a job or company of the feed does or a job leaves it
+func (f JobFeed) ETag() string {
+	tags := make([]string, 0, 2*len(f.Jobs)+2)
+	tags = append(tags, strconv.FormatInt(f.Updated.UnixNano(), 10))
+	if f.Company != nil {
+		tags = append(tags, f.Company.ETag())
+	}
+	for _, jobData := range f.Jobs {
+		tags = append(tags, jobData.ETag(), jobData.Company.ETag())
+	}
+	return etag.Combine(tags...)
+}
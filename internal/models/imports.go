@@ -0,0 +1,70 @@
+package models
+
+// what happened to a row of an import
+const (
+	ImportCreated = "created"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// ImportOptions says how an import is written. A dry run checks every row in a
+// transaction that is rolled back. ChunkSize above 0 commits every that many rows
+// instead of the whole import at once
+type ImportOptions struct {
+	DryRun    bool
+	ChunkSize int
+}
+
+// JobRow is a job to import, Company is the name of the company it is posted by
+type JobRow struct {
+	Company string `json:"company" validate:"required"`
+	Jobs
+}
+
+// ImportRow reports one row of an import, Line is where it starts in the upload
+type ImportRow struct {
+	Line   int    `json:"line"`
+	Status string `json:"status" validate:"oneof=created skipped failed"`
+	ID     uint   `json:"id,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ImportReport counts the rows of an import by what happened to them. Rows created
+// in a dry run have no id since nothing was stored. CommittedLine is the last line
+// of the last batch committed, Error tells why an import stopped before its end
+type ImportReport struct {
+	DryRun        bool        `json:"dry_run"`
+	Created       int         `json:"created"`
+	Skipped       int         `json:"skipped"`
+	Failed        int         `json:"failed"`
+	Rows          []ImportRow `json:"rows"`
+	CommittedLine int         `json:"committed_line"`
+	Error         string      `json:"error,omitempty"`
+}
+
+// Add records row and counts it
+func (r *ImportReport) Add(row ImportRow) {
+	switch row.Status {
+	case ImportCreated:
+		r.Created++
+	case ImportSkipped:
+		r.Skipped++
+	default:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, row)
+}
+
+// Stop ends the report with reason. The rows past CommittedLine belonged to the
+// batch rolled back when the import stopped and are reported failed, a dry run
+// stored none of its rows anyway
+func (r *ImportReport) Stop(reason string) {
+	rows := r.Rows
+	*r = ImportReport{DryRun: r.DryRun, Rows: make([]ImportRow, 0, len(rows)), CommittedLine: r.CommittedLine, Error: reason}
+	for _, row := range rows {
+		if !r.DryRun && row.Line > r.CommittedLine && row.Status != ImportFailed {
+			row = ImportRow{Line: row.Line, Status: ImportFailed, Reason: "not stored, the import stopped before its batch was committed"}
+		}
+		r.Add(row)
+	}
+}
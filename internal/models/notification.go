@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// kinds of notifications
+const (
+	NotificationMemberAdded   = "member.added"
+	NotificationProfileViewed = "profile.viewed"
+)
+
+// Notification is an in-app notification of a user, it stays unread until the
+// user acknowledges it
+type Notification struct {
+	gorm.Model
+	UserID uint       `json:"user_id" gorm:"index"`
+	Type   string     `json:"type"`
+	Text   string     `json:"text"`
+	Link   string     `json:"link,omitempty"`
+	ReadAt *time.Time `json:"read_at"`
+}
+
+// NotificationAck marks notifications of the signed in user read or unread
+type NotificationAck struct {
+	IDs []uint `json:"ids" validate:"required,min=1,max=100,dive,required"`
+}
+
+// types of the messages on the notification socket
+const (
+	// MessageInbox is sent when a session connects, it holds the unread notifications
+	MessageInbox = "inbox"
+	// MessageNotification holds a new notification
+	MessageNotification = "notification"
+	// MessageRead and MessageUnread are sent by a client to acknowledge notifications,
+	// and then to every session of the user so they all agree
+	MessageRead   = "read"
+	MessageUnread = "unread"
+	// MessageError answers a client message that could not be applied
+	MessageError = "error"
+)
+
+// NotificationMessage is what travels over the notification socket in both directions
+type NotificationMessage struct {
+	Type          string         `json:"type"`
+	Notifications []Notification `json:"notifications,omitempty"`
+	IDs           []uint         `json:"ids,omitempty"`
+	Error         string         `json:"error,omitempty"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"project/internal/etag"
+
+	"gorm.io/gorm"
+)
+
+// seniority levels of candidates and jobs, from least to most senior
+const (
+	SeniorityIntern = "intern"
+	SeniorityJunior = "junior"
+	SeniorityMid    = "mid"
+	SenioritySenior = "senior"
+	SeniorityLead   = "lead"
+)
+
+// SeniorityLevels lists the seniority levels in ascending order
+var SeniorityLevels = []string{SeniorityIntern, SeniorityJunior, SeniorityMid, SenioritySenior, SeniorityLead}
+
+// Profile is what a candidate tells about themselves, it drives their job recommendations
+type Profile struct {
+	gorm.Model
+	UserID         uint     `json:"user_id" gorm:"uniqueIndex"`
+	Headline       string   `json:"headline"`
+	Skills         []string `json:"skills" gorm:"serializer:json"`
+	Location       string   `json:"location"`
+	Latitude       float64  `json:"latitude" validate:"gte=-90,lte=90"`
+	Longitude      float64  `json:"longitude" validate:"gte=-180,lte=180"`
+	ExpectedSalary int      `json:"expected_salary" validate:"gte=0"`
+	Seniority      string   `json:"seniority" validate:"omitempty,oneof=intern junior mid senior lead"`
+
+	// talent search, only discoverable profiles can be found by recruiters
+	CurrentEmployer     string `json:"current_employer"`
+	ExperienceYears     int    `json:"experience_years" validate:"gte=0,lte=70"`
+	Availability        string `json:"availability" validate:"omitempty,oneof=immediate two_weeks one_month three_months not_looking"`
+	Discoverable        bool   `json:"discoverable"`
+	HideCurrentEmployer bool   `json:"hide_current_employer"`
+	HideExpectedSalary  bool   `json:"hide_expected_salary"`
+}
+
+// ETag changes on every save of the profile, saving again sends it back in If-Match
+func (p Profile) ETag() string {
+	return etag.Of("profile", p.ID, p.UpdatedAt)
+}
+
+// FactorScore explains how one factor contributed to a recommendation
+type FactorScore struct {
+	Factor string  `json:"factor"`
+	Weight float64 `json:"weight"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// Recommendation is a job scored against a candidate profile, Score is the
+// weighted mean of the factor scores and lies between 0 and 1
+type Recommendation struct {
+	Job     Jobs          `json:"job"`
+	Score   float64       `json:"score"`
+	Factors []FactorScore `json:"factors"`
+}
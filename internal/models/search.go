@@ -0,0 +1,93 @@
+package models
+
+// employment types accepted on a job
+const (
+	EmploymentFullTime   = "full_time"
+	EmploymentPartTime   = "part_time"
+	EmploymentContract   = "contract"
+	EmploymentInternship = "internship"
+)
+
+// remote policies accepted on a job
+const (
+	RemoteOnsite = "onsite"
+	RemoteHybrid = "hybrid"
+	RemoteFull   = "remote"
+)
+
+// SalaryBucket groups jobs by their minimum salary, Max of 0 means unbounded
+type SalaryBucket struct {
+	Key string
+	Min int
+	Max int
+}
+
+// SalaryBuckets are the ranges used for the salary facet, in ascending order
+var SalaryBuckets = []SalaryBucket{
+	{Key: "0-300000", Min: 0, Max: 300000},
+	{Key: "300000-600000", Min: 300000, Max: 600000},
+	{Key: "600000-1200000", Min: 600000, Max: 1200000},
+	{Key: "1200000-2500000", Min: 1200000, Max: 2500000},
+	{Key: "2500000+", Min: 2500000},
+}
+
+// Contains reports whether a job with the minimum salary falls in the bucket, a
+// job without one falls in none
+func (b SalaryBucket) Contains(salary int) bool {
+	return salary > 0 && salary >= b.Min && (b.Max == 0 || salary < b.Max)
+}
+
+// SalaryBucketByKey looks up one of the SalaryBuckets
+func SalaryBucketByKey(key string) (SalaryBucket, bool) {
+	for _, b := range SalaryBuckets {
+		if b.Key == key {
+			return b, true
+		}
+	}
+	return SalaryBucket{}, false
+}
+
+// JobFilter holds the filters and paging of a job search, values inside
+// one field are OR-ed and the fields themselves are AND-ed. Query is matched
+// typo tolerantly against job and company names, Similarity is the minimum
+// score from 0 to 1 a match needs and is set by the service
+// JobFilter narrows a job search. Status lists the states of the jobs found, only
+// published jobs when it is empty
+type JobFilter struct {
+	Query          string
+	Similarity     float64
+	Cid            []uint
+	Location       []string
+	EmploymentType []string
+	RemotePolicy   []string
+	SalaryBucket   []string
+	Status         []string
+	Page           int
+	PageSize       int
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+type JobFacets struct {
+	Company        []FacetCount `json:"company"`
+	Location       []FacetCount `json:"location"`
+	EmploymentType []FacetCount `json:"employment_type"`
+	SalaryBucket   []FacetCount `json:"salary_bucket"`
+	RemotePolicy   []FacetCount `json:"remote_policy"`
+}
+
+// JobSearchResult is the paginated response envelope of a job search
+type JobSearchResult struct {
+	Jobs     []Jobs    `json:"jobs"`
+	Total    int64     `json:"total"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"page_size"`
+	Facets   JobFacets `json:"facets"`
+
+	// Suggestion is the closest known job or company name when a query finds few jobs
+	Suggestion string `json:"did_you_mean,omitempty"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"project/internal/etag"
+	"strconv"
+	"time"
+)
+
+// Sitemap is the state of the sitemap of the published jobs, they are listed in
+// Pages sitemaps. Updated is when any job last changed, a job that was closed or
+// deleted included
+type Sitemap struct {
+	Jobs    int64
+	Pages   int
+	Updated time.Time
+}
+
+// ETag changes whenever a job is published, closed or updated
+func (s Sitemap) ETag() string {
+	return etag.Combine(strconv.FormatInt(s.Jobs, 10), strconv.FormatInt(s.Updated.UnixNano(), 10))
+}
+
+// SitemapJob is a published job as a sitemap lists it
+type SitemapJob struct {
+	ID        uint
+	UpdatedAt time.Time
+}
+
+// SitemapPage is one of the sitemaps of the published jobs, in the order of
+// their ids
+type SitemapPage struct {
+	Sitemap Sitemap
+	Page    int
+	Jobs    []SitemapJob
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// roles of a company member, both can search candidates and owners can add members
+const (
+	RoleOwner     = "owner"
+	RoleRecruiter = "recruiter"
+)
+
+// how soon a candidate can start
+const (
+	AvailableImmediately = "immediate"
+	AvailableTwoWeeks    = "two_weeks"
+	AvailableOneMonth    = "one_month"
+	AvailableThreeMonths = "three_months"
+	AvailableNotLooking  = "not_looking"
+)
+
+// Membership links a user to a company they recruit for
+type Membership struct {
+	gorm.Model
+	CompanyID uint   `json:"company_id" gorm:"uniqueIndex:idx_membership"`
+	UserID    uint   `json:"user_id" gorm:"uniqueIndex:idx_membership" validate:"required"`
+	Role      string `json:"role" validate:"required,oneof=owner recruiter"`
+}
+
+// CanRecruit reports whether the member may search candidate profiles
+func (m Membership) CanRecruit() bool {
+	return m.Role == RoleOwner || m.Role == RoleRecruiter
+}
+
+// CompanyVerification records that an admin checked the company, only members
+// of verified companies can search candidates. Creating a company makes its user
+// an owner, so membership alone grants nothing a user cannot grant themselves
+type CompanyVerification struct {
+	CompanyID  uint      `json:"company_id" gorm:"primarykey;autoIncrement:false"`
+	VerifiedBy uint      `json:"verified_by"`
+	CreatedAt  time.Time `json:"verified_at"`
+}
+
+// where a recruiter saw a profile
+const (
+	ViewSourceSearch  = "search"
+	ViewSourceProfile = "profile"
+)
+
+// ProfileView is one entry of the access log a candidate can read
+type ProfileView struct {
+	ID            uint      `json:"-" gorm:"primarykey"`
+	CreatedAt     time.Time `json:"viewed_at" gorm:"index"`
+	ProfileUserID uint      `json:"-" gorm:"index"`
+	ViewerID      uint      `json:"viewer_id"`
+	CompanyID     uint      `json:"company_id"`
+	CompanyName   string    `json:"company_name" gorm:"-:migration;->"`
+	Source        string    `json:"source"`
+}
+
+// TalentFilter holds the filters of a talent search, all skills must match
+type TalentFilter struct {
+	Skills        []string
+	Location      string
+	MinExperience int
+	MaxExperience int
+	Availability  []string
+	Page          int
+	PageSize      int
+}
+
+// Candidate is a discoverable profile as a recruiter sees it, hidden fields are left out
+type Candidate struct {
+	UserID          uint     `json:"user_id"`
+	Headline        string   `json:"headline"`
+	Skills          []string `json:"skills"`
+	Location        string   `json:"location"`
+	Seniority       string   `json:"seniority,omitempty"`
+	ExperienceYears int      `json:"experience_years"`
+	Availability    string   `json:"availability,omitempty"`
+	CurrentEmployer string   `json:"current_employer,omitempty"`
+	ExpectedSalary  int      `json:"expected_salary,omitempty"`
+}
+
+// Candidate applies the visibility settings of the profile
+func (p Profile) Candidate() Candidate {
+	c := Candidate{
+		UserID:          p.UserID,
+		Headline:        p.Headline,
+		Skills:          p.Skills,
+		Location:        p.Location,
+		Seniority:       p.Seniority,
+		ExperienceYears: p.ExperienceYears,
+		Availability:    p.Availability,
+	}
+	if !p.HideCurrentEmployer {
+		c.CurrentEmployer = p.CurrentEmployer
+	}
+	if !p.HideExpectedSalary {
+		c.ExpectedSalary = p.ExpectedSalary
+	}
+	return c
+}
+
+type TalentSearchResult struct {
+	Candidates []Candidate `json:"candidates"`
+	Total      int64       `json:"total"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"page_size"`
+}
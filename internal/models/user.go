@@ -1,11 +1,16 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type NewUser struct {
 	Username string `json:"username" validate:"required"`
 	Email    string `json:"email" validate:"required"`
 	Password string `json:"password" validate:"required"`
+	Locale   string `json:"locale,omitempty" validate:"omitempty,oneof=en hi"`
 }
 
 type User struct {
@@ -13,4 +18,8 @@ type User struct {
 	Username     string `json:"username" gorm:"unique"`
 	Email        string `json:"email" gorm:"unique"`
 	PasswordHash string `json:"-"`
+	// Locale is the language emails are written to the user in, english when empty
+	Locale string `json:"locale,omitempty" validate:"omitempty,oneof=en hi"`
+	// UnsubscribedAt is when the user stopped email notifications
+	UnsubscribedAt *time.Time `json:"unsubscribed_at,omitempty"`
 }
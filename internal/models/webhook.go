@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// events a webhook can subscribe to. A job is closed when its status becomes
+// closed or it is deleted
+const (
+	EventJobCreated = "job.created"
+	EventJobUpdated = "job.updated"
+	EventJobClosed  = "job.closed"
+)
+
+// states of a webhook delivery, a pending one is retried until it is delivered
+// or runs out of attempts
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook is a subscription of a partner to the job events of a company. The
+// secret signs every payload sent and is never shown again once it is set
+type Webhook struct {
+	gorm.Model
+	Cid    uint     `json:"cid" gorm:"index"`
+	URL    string   `json:"url" validate:"required,http_url"`
+	Events []string `json:"events" gorm:"serializer:json" validate:"dive,oneof=job.created job.updated job.closed"`
+	Secret string   `json:"secret,omitempty" validate:"required,min=16"`
+}
+
+// Subscribed reports whether the webhook wants event, no events means all of them
+func (w Webhook) Subscribed(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is the payload posted to a webhook
+type WebhookEvent struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Job        Jobs      `json:"job"`
+}
+
+// WebhookDelivery is one event queued for one webhook along with how sending it
+// went so far, the table is the queue the dispatcher works through
+type WebhookDelivery struct {
+	gorm.Model
+	// Key is unique to the event and the webhook, an event queued again is not
+	// delivered twice
+	Key            string     `json:"-" gorm:"uniqueIndex"`
+	WebhookID      uint       `json:"webhook_id" gorm:"index"`
+	Event          string     `json:"event"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status" gorm:"index:idx_delivery_due,priority:1" validate:"oneof=pending delivered failed"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"index:idx_delivery_due,priority:2"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// DueDelivery is a delivery claimed for sending with where it goes
+type DueDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
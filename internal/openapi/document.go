@@ -0,0 +1,345 @@
+// Package openapi builds an OpenAPI 3.1 document from the routes of the api and
+// the json and validate tags of the models, and checks traffic against it
+package openapi
+
+import (
+	"net/http"
+	"project/internal/apperr"
+	"project/internal/imports"
+	"project/internal/patch"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI version the document follows
+const Version = "3.1.0"
+
+// jsonPatchOperation is the name of the schema of one JSON Patch operation
+const jsonPatchOperation = "JSONPatchOperation"
+
+// bearerAuth is the name of the security scheme of authenticated operations
+const bearerAuth = "bearerAuth"
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem holds the operations of one path keyed by lower case method
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Endpoint describes one operation to add to the document. Path uses gin's
+// :name syntax, path parameters are ids. Request and Response are values of the
+// body types, nil means the operation has no body. Status is the status of a
+// successful response, 200 when it is zero
+type Endpoint struct {
+	Method     string
+	Path       string
+	Handler    interface{}
+	Public     bool
+	Deprecated bool
+	// FromQuery names path parameters that this path takes from the query string
+	FromQuery []string
+	Params    []Parameter
+	Request   interface{}
+	Response  interface{}
+	Status    int
+	// Export is the record a list response can also be sent as CSV or NDJSON of,
+	// the client picks the format with Accept
+	Export interface{}
+}
+
+// PatchBody documents a request body patching a resource of the type of Of,
+// either as a JSON Merge Patch or as a JSON Patch
+type PatchBody struct {
+	Of interface{}
+}
+
+// StreamBody documents a request body of many records of the type of Of, either
+// as CSV with a header row or as one json object per line
+type StreamBody struct {
+	Of interface{}
+}
+
+// EventStreamType is the media type of a stream of server-sent events
+const EventStreamType = "text/event-stream"
+
+// EventStream documents a response of server-sent events, the data of each event
+// is a json object of the type of Of
+type EventStream struct {
+	Of interface{}
+}
+
+// Text documents a response in a format other than json, such as a feed. The
+// body is listed under MediaType as a string described by Description
+type Text struct {
+	MediaType   string
+	Description string
+}
+
+// Typed documents a json response of the type of Of sent under a media type of
+// its own, such as application/ld+json
+type Typed struct {
+	MediaType string
+	Of        interface{}
+}
+
+func New(title, version string) *Document {
+	d := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: title, Version: version},
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+	return d
+}
+
+// Add adds the operation of e, an operation added twice replaces the first one
+func (d *Document) Add(e Endpoint) {
+	path, params := convertPath(e.Path)
+	op := &Operation{
+		OperationID: operationID(e),
+		Deprecated:  e.Deprecated,
+		Responses: map[string]*Response{
+			"default": {
+				Description: "problem",
+				Content: map[string]MediaType{
+					apperr.ContentType: {Schema: d.schemaFor(reflect.TypeOf(apperr.Problem{}))},
+				},
+			},
+		},
+	}
+	for _, name := range params {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: idSchema()})
+	}
+	for _, name := range e.FromQuery {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "query", Required: true, Schema: idSchema()})
+	}
+	op.Parameters = append(op.Parameters, e.Params...)
+
+	if body, ok := e.Request.(PatchBody); ok {
+		op.RequestBody = &RequestBody{Required: true, Content: d.patchContent(body)}
+	} else if body, ok := e.Request.(StreamBody); ok {
+		op.RequestBody = &RequestBody{Required: true, Content: d.streamContent(body.Of)}
+	} else if e.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(d.schemaFor(reflect.TypeOf(e.Request))),
+		}
+	}
+	status := e.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	ok := &Response{Description: http.StatusText(status)}
+	if stream, isStream := e.Response.(EventStream); isStream {
+		ok.Content = d.eventContent(stream.Of)
+	} else if text, isText := e.Response.(Text); isText {
+		ok.Content = map[string]MediaType{
+			text.MediaType: {Schema: &Schema{Type: "string", Description: text.Description}},
+		}
+	} else if typed, isTyped := e.Response.(Typed); isTyped {
+		ok.Content = map[string]MediaType{
+			typed.MediaType: {Schema: d.schemaFor(reflect.TypeOf(typed.Of))},
+		}
+	} else if e.Response != nil {
+		ok.Content = jsonContent(d.schemaFor(reflect.TypeOf(e.Response)))
+	}
+	if e.Export != nil {
+		for mediaType, media := range d.streamContent(e.Export) {
+			ok.Content[mediaType] = media
+		}
+	}
+	op.Responses[strconv.Itoa(status)] = ok
+	if !e.Public {
+		op.Security = []map[string][]string{{bearerAuth: {}}}
+	}
+
+	item, found := d.Paths[path]
+	if !found {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	(*item)[strings.ToLower(e.Method)] = op
+}
+
+// Operation finds the operation for a method and a path in gin's syntax
+func (d *Document) Operation(method, path string) (*Operation, bool) {
+	path, _ = convertPath(path)
+	item, ok := d.Paths[path]
+	if !ok {
+		return nil, false
+	}
+	op, ok := (*item)[strings.ToLower(method)]
+	return op, ok
+}
+
+// Query documents a query parameter of type typ
+func Query(name, typ string, enum ...string) Parameter {
+	s := &Schema{Type: typ}
+	for _, v := range enum {
+		s.Enum = append(s.Enum, v)
+	}
+	return Parameter{Name: name, In: "query", Schema: s}
+}
+
+// QueryList documents a query parameter that can be repeated or hold a comma
+// separated list, each item is of type typ
+func QueryList(name, typ string, enum ...string) Parameter {
+	item := Query(name, typ, enum...).Schema
+	return Parameter{Name: name, In: "query", Schema: &Schema{Type: "array", Items: item}}
+}
+
+// Header documents a request header, description tells what the server does with it
+func Header(name, description string) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+// convertPath turns /companies/:id into /companies/{id} and returns the parameter names
+func convertPath(path string) (string, []string) {
+	var params []string
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			params = append(params, part[1:])
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/"), params
+}
+
+// operationID is the name of the handler, deprecated aliases get a suffix to keep ids unique
+func operationID(e Endpoint) string {
+	id := strings.ToLower(e.Method) + strings.ReplaceAll(e.Path, "/", "_")
+	if e.Handler != nil {
+		name := runtime.FuncForPC(reflect.ValueOf(e.Handler).Pointer()).Name()
+		name = strings.TrimSuffix(name, "-fm")
+		id = name[strings.LastIndex(name, ".")+1:]
+	}
+	if e.Deprecated {
+		id += "_" + strings.NewReplacer("/", "_", ":", "").Replace(strings.Trim(e.Path, "/"))
+	}
+	return id
+}
+
+// patchContent lists both patch formats, the merge patch is only checked to be an
+// object since any member may be null. The patched resource is validated in full
+// by the handler
+func (d *Document) patchContent(body PatchBody) map[string]MediaType {
+	target := d.schemaFor(reflect.TypeOf(body.Of)).Ref
+	if _, ok := d.Components.Schemas[jsonPatchOperation]; !ok {
+		ops := make([]interface{}, 0, len(patch.Ops))
+		for _, op := range patch.Ops {
+			ops = append(ops, op)
+		}
+		d.Components.Schemas[jsonPatchOperation] = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"op":    {Type: "string", Enum: ops},
+				"path":  {Type: "string"},
+				"from":  {Type: "string"},
+				"value": {},
+			},
+			Required: []string{"op", "path"},
+		}
+	}
+	return map[string]MediaType{
+		patch.MergePatchType: {Schema: &Schema{Type: "object", Description: "JSON Merge Patch of " + target}},
+		patch.JSONPatchType: {Schema: &Schema{
+			Type:        "array",
+			Description: "JSON Patch of " + target,
+			Items:       &Schema{Ref: "#/components/schemas/" + jsonPatchOperation},
+		}},
+	}
+}
+
+// streamContent lists both formats of a stream of records of the type of of, the
+// records are read and written one at a time by the handler
+func (d *Document) streamContent(of interface{}) map[string]MediaType {
+	record := d.schemaFor(reflect.TypeOf(of)).Ref
+	return map[string]MediaType{
+		imports.CSVType: {Schema: &Schema{
+			Type:        "string",
+			Description: "CSV with a header row naming members of " + record + ", lists are separated by semicolons",
+		}},
+		imports.NDJSONType: {Schema: &Schema{
+			Type:        "string",
+			Description: "one json object per line, each a " + record,
+		}},
+	}
+}
+
+// eventContent documents server-sent events carrying records of the type of of
+func (d *Document) eventContent(of interface{}) map[string]MediaType {
+	record := d.schemaFor(reflect.TypeOf(of)).Ref
+	return map[string]MediaType{
+		EventStreamType: {Schema: &Schema{
+			Type:        "string",
+			Description: "server-sent events, the data of each is a " + record,
+		}},
+	}
+}
+
+func idSchema() *Schema {
+	return &Schema{Type: "integer", Minimum: float(0)}
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
@@ -0,0 +1,177 @@
+package openapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type pet struct {
+	gorm.Model
+	Name   string   `json:"name" validate:"required"`
+	Kind   string   `json:"kind,omitempty" validate:"omitempty,oneof=cat dog"`
+	Age    int      `json:"age" validate:"gte=0,lte=40"`
+	Tags   []string `json:"tags" validate:"dive,oneof=calm playful"`
+	Secret string   `json:"-"`
+	Owner  *owner   `json:"owner"`
+}
+
+type owner struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func TestDocument_schema(t *testing.T) {
+	d := New("test", "1")
+	d.Add(Endpoint{Method: http.MethodPost, Path: "/pets/:id", Request: pet{}, Response: []pet{}})
+
+	s, ok := d.Components.Schemas["Pet"]
+	if !ok {
+		t.Fatalf("Pet is not in the components")
+	}
+	if strings.Join(s.Required, ",") != "name" {
+		t.Errorf("required = %v, want [name]", s.Required)
+	}
+	if _, ok := s.Properties["Secret"]; ok {
+		t.Errorf("a field tagged json:\"-\" is in the schema")
+	}
+	if !s.Properties["ID"].ReadOnly || s.Properties["ID"].Type != "integer" {
+		t.Errorf("ID of the embedded gorm.Model = %+v", s.Properties["ID"])
+	}
+	if len(s.Properties["kind"].Enum) != 3 {
+		t.Errorf("kind enum = %v, want the empty string, cat and dog", s.Properties["kind"].Enum)
+	}
+	if tags := s.Properties["tags"]; len(tags.Enum) != 0 || len(tags.Items.Enum) != 2 {
+		t.Errorf("tags enum = %v, items enum = %v, want the rules after dive on the items", tags.Enum, tags.Items.Enum)
+	}
+	age := s.Properties["age"]
+	if age.Minimum == nil || *age.Minimum != 0 || age.Maximum == nil || *age.Maximum != 40 {
+		t.Errorf("age bounds = %v %v", age.Minimum, age.Maximum)
+	}
+	if s.Properties["owner"].AnyOf[0].Ref != "#/components/schemas/Owner" || d.Components.Schemas["Owner"].Properties["email"].Format != "email" {
+		t.Errorf("owner is not a reference to an email carrying schema")
+	}
+	if _, ok := d.Operation(http.MethodPost, "/pets/:id"); !ok {
+		t.Errorf("operation not found by its gin path")
+	}
+}
+
+func TestDocument_ValidateRequest(t *testing.T) {
+	d := New("test", "1")
+	d.Add(Endpoint{
+		Method:  http.MethodPost,
+		Path:    "/pets/:id",
+		Params:  []Parameter{QueryList("kind", "string", "cat", "dog"), Query("page", "integer")},
+		Request: pet{},
+	})
+	params := func(id string) func(string) string {
+		return func(string) string { return id }
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		query   string
+		body    string
+		wantErr string
+	}{
+		{name: "valid", id: "1", query: "kind=cat,dog&page=2", body: `{"name":"rex","age":3,"ID":9}`},
+		{name: "path parameter", id: "x", body: `{"name":"rex"}`, wantErr: "id must be of type integer"},
+		{name: "query list", id: "1", query: "kind=cat&kind=cow", body: `{"name":"rex"}`, wantErr: "kind must be one of [cat dog]"},
+		{name: "query integer", id: "1", query: "page=two", body: `{"name":"rex"}`, wantErr: "page must be of type integer"},
+		{name: "missing body", id: "1", wantErr: "request body is required"},
+		{name: "required", id: "1", body: `{"age":3}`, wantErr: "body.name is required"},
+		{name: "bounds", id: "1", body: `{"name":"rex","age":41}`, wantErr: "body.age must be at most 40"},
+		{name: "nested", id: "1", body: `{"name":"rex","owner":{"email":1}}`, wantErr: "body.owner.email must be of type string"},
+		{name: "nullable", id: "1", body: `{"name":"rex","tags":null,"owner":null}`},
+		{name: "too large", id: "1", body: `{"name":"` + strings.Repeat("x", MaxBodyBytes) + `"}`, wantErr: "request body is too large, send at most 1048576 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/pets/"+tt.id+"?"+tt.query, strings.NewReader(tt.body))
+			err := d.ValidateRequest(http.MethodPost, "/pets/:id", r, params(tt.id))
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.wantErr {
+				t.Errorf("ValidateRequest() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestDocument_ValidateRequest_mediaType checks that a body in a format the
+// operation does not take is refused without being read
+func TestDocument_ValidateRequest_mediaType(t *testing.T) {
+	d := New("test", "1")
+	d.Add(Endpoint{Method: http.MethodPost, Path: "/pets/import", Request: StreamBody{Of: pet{}}})
+
+	body := strings.NewReader("<pets><pet>rex</pet></pets>")
+	r := httptest.NewRequest(http.MethodPost, "/pets/import", body)
+	r.Header.Set("Content-Type", "application/xml")
+	err := d.ValidateRequest(http.MethodPost, "/pets/import", r, func(string) string { return "" })
+	if !errors.Is(err, ErrMediaType) {
+		t.Errorf("ValidateRequest() error = %v, want %v", err, ErrMediaType)
+	}
+	if body.Len() == 0 {
+		t.Errorf("the body of an unsupported media type was read")
+	}
+}
+
+func TestDocument_ValidateResponse(t *testing.T) {
+	d := New("test", "1")
+	d.Add(Endpoint{Method: http.MethodGet, Path: "/pets", Response: []pet{}})
+
+	err := d.ValidateResponse(http.MethodGet, "/pets", http.StatusOK, "application/json; charset=utf-8", []byte(`[{"ID":1,"age":2}]`))
+	if err != nil {
+		t.Errorf("a response without required properties is rejected: %v", err)
+	}
+	err = d.ValidateResponse(http.MethodGet, "/pets", http.StatusOK, "application/json; charset=utf-8", []byte(`[{"kind":"cow"}]`))
+	if err == nil {
+		t.Errorf("a response outside of the enum is accepted")
+	}
+	err = d.ValidateResponse(http.MethodGet, "/pets", http.StatusBadRequest, "application/problem+json", []byte(`{"type":"about:blank","title":"Bad Request","status":400}`))
+	if err != nil {
+		t.Errorf("an error response is rejected: %v", err)
+	}
+	err = d.ValidateResponse(http.MethodGet, "/pets", http.StatusOK, "text/csv", []byte("ID,age\n1,2\n"))
+	if err == nil {
+		t.Errorf("a response in a format the operation does not list is accepted")
+	}
+	d.Add(Endpoint{Method: http.MethodGet, Path: "/pets", Response: []pet{}, Export: pet{}})
+	err = d.ValidateResponse(http.MethodGet, "/pets", http.StatusOK, "text/csv; charset=utf-8", []byte("ID,age\n1,2\n"))
+	if err != nil {
+		t.Errorf("an export is rejected: %v", err)
+	}
+	d.Add(Endpoint{Method: http.MethodGet, Path: "/pets/stream", Response: EventStream{Of: pet{}}})
+	err = d.ValidateResponse(http.MethodGet, "/pets/stream", http.StatusOK, EventStreamType, []byte("id: 1\ndata: {}\n\n"))
+	if err != nil {
+		t.Errorf("an event stream is rejected: %v", err)
+	}
+	d.Add(Endpoint{Method: http.MethodGet, Path: "/pets.atom", Response: Text{MediaType: "application/atom+xml", Description: "atom feed"}})
+	err = d.ValidateResponse(http.MethodGet, "/pets.atom", http.StatusOK, "application/atom+xml; charset=utf-8", []byte("<feed/>"))
+	if err != nil {
+		t.Errorf("a feed is rejected: %v", err)
+	}
+	err = d.ValidateResponse(http.MethodGet, "/pets.atom", http.StatusOK, "application/json", []byte("{}"))
+	if err == nil {
+		t.Error("json is accepted from an operation answering with a feed")
+	}
+	d.Add(Endpoint{Method: http.MethodGet, Path: "/pets/:id/jsonld", Response: Typed{MediaType: "application/ld+json", Of: pet{}}})
+	err = d.ValidateResponse(http.MethodGet, "/pets/:id/jsonld", http.StatusOK, "application/ld+json", []byte(`{"ID":1,"age":2}`))
+	if err != nil {
+		t.Errorf("a typed json response is rejected: %v", err)
+	}
+	err = d.ValidateResponse(http.MethodGet, "/pets/:id/jsonld", http.StatusOK, "application/ld+json", []byte(`{"age":"two"}`))
+	if err == nil {
+		t.Error("a typed json response is not checked against its schema")
+	}
+	err = d.ValidateResponse(http.MethodGet, "/cats", http.StatusOK, "", nil)
+	if err != ErrNoOperation {
+		t.Errorf("unknown operation error = %v", err)
+	}
+}
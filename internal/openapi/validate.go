@@ -0,0 +1,350 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrNoOperation is returned for a method and path the document does not describe
+	ErrNoOperation = errors.New("operation is not in the api spec")
+	// ErrMediaType is returned for a request body in a format the operation does not take
+	ErrMediaType = errors.New("unsupported media type")
+	// ErrTooLarge is returned for a json request body over MaxBodyBytes
+	ErrTooLarge = errors.New("request body is too large")
+)
+
+// MaxBodyBytes bounds the json request bodies read into memory to be checked, the
+// records the api takes as json are far smaller
+const MaxBodyBytes = 1 << 20
+
+// ValidateRequest checks the parameters and the json body of r against the operation
+// for method and route, route is the path in gin's syntax. param returns the value
+// of a path parameter. A json body is read and replaced so handlers can still read
+// it, up to MaxBodyBytes. A body in a format the operation does not take is not read
+func (d *Document) ValidateRequest(method, route string, r *http.Request, param func(string) string) error {
+	op, ok := d.Operation(method, route)
+	if !ok {
+		return ErrNoOperation
+	}
+
+	query := r.URL.Query()
+	for _, p := range op.Parameters {
+		var values []string
+		switch p.In {
+		case "path":
+			values = []string{param(p.Name)}
+		case "query":
+			values = query[p.Name]
+		case "header":
+			values = r.Header.Values(p.Name)
+		}
+		if len(values) == 0 {
+			if p.Required {
+				return fmt.Errorf("%s parameter %s is required", p.In, p.Name)
+			}
+			continue
+		}
+		err := d.validateParameter(p, values)
+		if err != nil {
+			return err
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+	media, mediaType, ok := requestMedia(op.RequestBody, r.Header.Get("Content-Type"))
+	if !ok {
+		if r.ContentLength == 0 && !op.RequestBody.Required {
+			return nil
+		}
+		return fmt.Errorf("%w, send one of %s", ErrMediaType, strings.Join(mediaTypes(op.RequestBody), ", "))
+	}
+	if !isJSON(mediaType) {
+		// streamed bodies are read by the handler as they arrive, not buffered here
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodyBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > MaxBodyBytes {
+		return fmt.Errorf("%w, send at most %d bytes", ErrTooLarge, MaxBodyBytes)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			return errors.New("request body is required")
+		}
+		return nil
+	}
+	v, err := decode(body)
+	if err != nil {
+		return fmt.Errorf("request body is not valid json: %w", err)
+	}
+	return d.validate(media.Schema, v, "body", true)
+}
+
+// ValidateResponse checks a json response body against the operation for method and
+// route, contentType picks the format. Required properties are not enforced since
+// ?fields can trim responses and bodies in other formats are only checked to be listed
+func (d *Document) ValidateResponse(method, route string, status int, contentType string, body []byte) error {
+	op, ok := d.Operation(method, route)
+	if !ok {
+		return ErrNoOperation
+	}
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok && status >= 200 && status < 300 {
+		resp, ok = op.Responses["200"]
+	}
+	if !ok {
+		resp = op.Responses["default"]
+	}
+	if len(resp.Content) == 0 || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "" {
+		mediaType = "application/json"
+	}
+	media, ok := resp.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("response is %s, the spec lists %s", mediaType, strings.Join(contentTypes(resp.Content), ", "))
+	}
+	if !isJSON(mediaType) {
+		return nil
+	}
+	v, err := decode(body)
+	if err != nil {
+		return fmt.Errorf("response body is not valid json: %w", err)
+	}
+	return d.validate(media.Schema, v, "response", false)
+}
+
+// requestMedia picks the body format named by contentType and returns its media
+// type. Bodies of operations taking json are read as json whatever their content
+// type, clients rarely set it
+func requestMedia(body *RequestBody, contentType string) (MediaType, string, bool) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if media, ok := body.Content[mediaType]; ok {
+		return media, mediaType, true
+	}
+	media, ok := body.Content["application/json"]
+	return media, "application/json", ok
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func mediaTypes(body *RequestBody) []string {
+	return contentTypes(body.Content)
+}
+
+func contentTypes(content map[string]MediaType) []string {
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (d *Document) validateParameter(p Parameter, values []string) error {
+	s := p.Schema
+	if s.Type != "array" {
+		return d.validate(s, parameterValue(s, values[0]), p.Name, true)
+	}
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			err := d.validate(s.Items, parameterValue(s.Items, item), p.Name, true)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parameterValue converts a query or path value to what json would hold for the schema
+func parameterValue(s *Schema, v string) interface{} {
+	switch s.Type {
+	case "integer", "number":
+		return json.Number(v)
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+func decode(body []byte) (interface{}, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	err := dec.Decode(&v)
+	return v, err
+}
+
+// validate checks v, a value decoded with UseNumber, against s. Required properties
+// are only checked in requests, read only properties are skipped there
+func (d *Document) validate(s *Schema, v interface{}, at string, request bool) error {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		ref, ok := d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema %s", at, s.Ref)
+		}
+		return d.validate(ref, v, at, request)
+	}
+	if len(s.AnyOf) > 0 {
+		// the first alternative is the one worth explaining, the others are null
+		var first error
+		for i, alt := range s.AnyOf {
+			err := d.validate(alt, v, at, request)
+			if err == nil {
+				first = nil
+				break
+			}
+			if i == 0 {
+				first = err
+			}
+		}
+		if first != nil {
+			return first
+		}
+	}
+
+	types := schemaTypes(s)
+	if len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			matched = matched || isType(t, v)
+		}
+		if !matched {
+			return fmt.Errorf("%s must be of type %s", at, strings.Join(types, " or "))
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			found = found || fmt.Sprint(e) == fmt.Sprint(v)
+		}
+		if !found {
+			return fmt.Errorf("%s must be one of %v", at, s.Enum)
+		}
+	}
+
+	switch v := v.(type) {
+	case json.Number:
+		n, err := v.Float64()
+		if err != nil {
+			return fmt.Errorf("%s must be a number", at)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			return fmt.Errorf("%s must be at least %v", at, *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			return fmt.Errorf("%s must be at most %v", at, *s.Maximum)
+		}
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.MinLength != nil && n < *s.MinLength {
+			return fmt.Errorf("%s must have at least %d characters", at, *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return fmt.Errorf("%s must have at most %d characters", at, *s.MaxLength)
+		}
+	case []interface{}:
+		for i, item := range v {
+			err := d.validate(s.Items, item, fmt.Sprintf("%s[%d]", at, i), request)
+			if err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		if request {
+			for _, name := range s.Required {
+				if _, ok := v[name]; !ok {
+					return fmt.Errorf("%s.%s is required", at, name)
+				}
+			}
+		}
+		for name, value := range v {
+			prop, ok := s.Properties[name]
+			if !ok {
+				prop = s.AdditionalProperties
+			}
+			if prop == nil || (request && prop.ReadOnly) {
+				continue
+			}
+			err := d.validate(prop, value, at+"."+name, request)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func schemaTypes(s *Schema) []string {
+	switch t := s.Type.(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return t
+	}
+	return nil
+}
+
+func isType(t string, v interface{}) bool {
+	switch t {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Float64()
+		return err == nil
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	}
+	return false
+}
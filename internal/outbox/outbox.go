@@ -0,0 +1,221 @@
+// Package outbox hands the domain events recorded in the outbox table to the
+// subscribers in this process. An event is handed over at least once, so
+// subscribers have to cope with seeing one again
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project/internal/models"
+	"project/internal/webhook"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Store is where the events wait, the repository keeps them in the database
+// next to the changes they describe
+type Store interface {
+	ClaimEvents(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.OutboxEvent, error)
+	SaveEvent(ctx context.Context, event models.OutboxEvent) error
+}
+
+// Handler reacts to an event, an error has the event handed to it again later
+type Handler func(ctx context.Context, event models.DomainEvent) error
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+// Dispatcher works through the outbox. Several can share it, a claimed event is
+// left alone by the others until its lease ends
+type Dispatcher struct {
+	store       Store
+	backoff     webhook.Backoff
+	maxAttempts int
+	batch       int
+	interval    time.Duration
+	timeout     time.Duration
+	now         func() time.Time
+
+	mu          sync.RWMutex
+	subscribers map[string][]subscriber
+}
+
+// Option changes the default configuration of the dispatcher
+type Option func(*Dispatcher)
+
+// WithBackoff replaces the default backoff, 10s after the first failure doubling
+// up to an hour
+func WithBackoff(b webhook.Backoff) Option {
+	return func(d *Dispatcher) {
+		d.backoff = b
+	}
+}
+
+// WithMaxAttempts sets how many failed attempts leave an event dead, 10 by default
+func WithMaxAttempts(n int) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = n
+	}
+}
+
+// WithInterval sets how often Run looks for due events, every second by default
+func WithInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.interval = interval
+	}
+}
+
+// WithTimeout sets how long a subscriber gets to handle an event, 30s by default
+func WithTimeout(timeout time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.timeout = timeout
+	}
+}
+
+func NewDispatcher(s Store, opts ...Option) (*Dispatcher, error) {
+	if s == nil {
+		return nil, errors.New("store cannot be null")
+	}
+	d := &Dispatcher{
+		store:       s,
+		backoff:     webhook.Backoff{Base: 10 * time.Second, Max: time.Hour},
+		maxAttempts: 10,
+		batch:       50,
+		interval:    time.Second,
+		timeout:     30 * time.Second,
+		now:         time.Now,
+		subscribers: make(map[string][]subscriber),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.maxAttempts < 1 || d.interval <= 0 || d.timeout <= 0 || d.backoff.Base <= 0 {
+		return nil, errors.New("outbox attempts, interval, timeout and backoff must be positive")
+	}
+	return d, nil
+}
+
+// Subscribe has h handle the events of type eventType. The name tells the
+// subscribers of a type apart in the outbox, a retry skips the ones done with
+// the event, so it must stay the same across restarts
+func (d *Dispatcher) Subscribe(eventType string, name string, h Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscribers[eventType] = append(d.subscribers[eventType], subscriber{name: name, handler: h})
+}
+
+// Run hands due events over until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := d.DispatchDue(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("outbox dispatch stopped")
+			}
+			// a full batch means more may be waiting
+			if err != nil || n < d.batch {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue hands one batch of due events to their subscribers and returns
+// how many it tried
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	now := d.now()
+	// the lease outlasts every subscriber of the batch running out of time, a
+	// dispatcher that dies midway leaves the rest to be claimed again
+	lease := now.Add(time.Duration(d.batch+1) * d.timeout)
+	due, err := d.store.ClaimEvents(ctx, now, lease, d.batch)
+	if err != nil {
+		return 0, err
+	}
+	for _, event := range due {
+		err = d.store.SaveEvent(ctx, d.dispatch(ctx, event))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(due), nil
+}
+
+// dispatch hands the event to the subscribers not done with it yet and returns
+// it updated with the outcome
+func (d *Dispatcher) dispatch(ctx context.Context, event models.OutboxEvent) models.OutboxEvent {
+	event.Attempts++
+	err := d.handle(ctx, &event)
+	if err == nil {
+		now := d.now()
+		event.Status = models.OutboxDelivered
+		event.DeliveredAt = &now
+		event.LastError = ""
+		return event
+	}
+
+	event.LastError = err.Error()
+	if event.Attempts >= d.maxAttempts {
+		event.Status = models.OutboxDead
+		log.Error().Str("event", event.Type).Uint("id", event.ID).Str("error", event.LastError).
+			Msg("outbox event is dead, its subscribers gave up")
+		return event
+	}
+	event.Status = models.OutboxPending
+	event.NextAttemptAt = d.now().Add(d.backoff.Delay(event.Attempts))
+	return event
+}
+
+// handle runs the subscribers, recording in event.Handled the ones that succeed.
+// An event that cannot be read fails for all of them
+func (d *Dispatcher) handle(ctx context.Context, event *models.OutboxEvent) error {
+	d.mu.RLock()
+	subscribers := d.subscribers[event.Type]
+	d.mu.RUnlock()
+	if len(subscribers) == 0 {
+		return nil
+	}
+	e, err := event.Event()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, s := range subscribers {
+		if slices.Contains(event.Handled, s.name) {
+			continue
+		}
+		err = d.call(ctx, s, e)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		event.Handled = append(event.Handled, s.name)
+	}
+	return errors.Join(errs...)
+}
+
+// call runs one subscriber with its timeout, a panic fails the event instead of
+// taking the dispatcher down
+func (d *Dispatcher) call(ctx context.Context, s subscriber, e models.DomainEvent) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return s.handler(ctx, e)
+}
@@ -0,0 +1,144 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"project/internal/models"
+	"project/internal/webhook"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeStore hands out its events once and keeps the outcomes saved
+type fakeStore struct {
+	due   []models.OutboxEvent
+	saved []models.OutboxEvent
+}
+
+func (s *fakeStore) ClaimEvents(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.OutboxEvent, error) {
+	due := s.due
+	s.due = nil
+	return due, nil
+}
+
+func (s *fakeStore) SaveEvent(ctx context.Context, event models.OutboxEvent) error {
+	s.saved = append(s.saved, event)
+	return nil
+}
+
+func jobCreated(t *testing.T, attempts int, handled ...string) models.OutboxEvent {
+	t.Helper()
+	e, err := models.NewOutboxEvent(models.JobCreated{Job: models.Jobs{Model: gorm.Model{ID: 11}, Cid: 7, Name: "developer"}}, time.Now())
+	if err != nil {
+		t.Fatalf("NewOutboxEvent() error = %v", err)
+	}
+	e.ID = 3
+	e.Attempts = attempts
+	e.Handled = handled
+	return e
+}
+
+func TestDispatcher_DispatchDue(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	failing := errors.New("the mail server is down")
+	tests := []struct {
+		name         string
+		event        models.OutboxEvent
+		searchErr    error
+		wantStatus   string
+		wantAttempts int
+		wantHandled  []string
+		wantNext     time.Time
+		wantCalls    []string
+	}{
+		{name: "handled", event: jobCreated(t, 0),
+			wantStatus: models.OutboxDelivered, wantAttempts: 1, wantHandled: []string{"email", "search"}, wantCalls: []string{"email", "search"}},
+		{name: "one subscriber fails", event: jobCreated(t, 2), searchErr: failing,
+			wantStatus: models.OutboxPending, wantAttempts: 3, wantHandled: []string{"email"}, wantNext: now.Add(4 * time.Second), wantCalls: []string{"email", "search"}},
+		{name: "retry skips the subscribers done with it", event: jobCreated(t, 3, "email"),
+			wantStatus: models.OutboxDelivered, wantAttempts: 4, wantHandled: []string{"email", "search"}, wantCalls: []string{"search"}},
+		{name: "dead after the last attempt", event: jobCreated(t, 4, "email"), searchErr: failing,
+			wantStatus: models.OutboxDead, wantAttempts: 5, wantHandled: []string{"email"}, wantCalls: []string{"search"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeStore{due: []models.OutboxEvent{tt.event}}
+			d, err := NewDispatcher(s, WithBackoff(webhook.Backoff{Base: time.Second, Max: time.Minute}), WithMaxAttempts(5))
+			if err != nil {
+				t.Fatalf("NewDispatcher() error = %v", err)
+			}
+			d.now = func() time.Time { return now }
+			var calls []string
+			d.Subscribe(models.EventJobCreated, "email", func(ctx context.Context, e models.DomainEvent) error {
+				calls = append(calls, "email")
+				if job := e.(models.JobCreated).Job; job.ID != 11 || job.Name != "developer" {
+					t.Errorf("the subscriber got %+v", job)
+				}
+				return nil
+			})
+			d.Subscribe(models.EventJobCreated, "search", func(ctx context.Context, e models.DomainEvent) error {
+				calls = append(calls, "search")
+				return tt.searchErr
+			})
+			d.Subscribe(models.EventCompanyCreated, "email", func(ctx context.Context, e models.DomainEvent) error {
+				t.Error("a subscriber of another event was called")
+				return nil
+			})
+
+			n, err := d.DispatchDue(context.Background())
+			if err != nil || n != 1 {
+				t.Fatalf("DispatchDue() = %d, %v, want 1 event", n, err)
+			}
+			got := s.saved[0]
+			if got.Status != tt.wantStatus || got.Attempts != tt.wantAttempts || !reflect.DeepEqual(got.Handled, tt.wantHandled) {
+				t.Errorf("saved %s after %d attempts handled by %v, want %s after %d handled by %v",
+					got.Status, got.Attempts, got.Handled, tt.wantStatus, tt.wantAttempts, tt.wantHandled)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("called %v, want %v", calls, tt.wantCalls)
+			}
+			if !tt.wantNext.IsZero() && !got.NextAttemptAt.Equal(tt.wantNext) {
+				t.Errorf("next attempt at %v, want %v", got.NextAttemptAt, tt.wantNext)
+			}
+			if tt.wantStatus == models.OutboxDelivered && (got.DeliveredAt == nil || got.LastError != "") {
+				t.Errorf("a delivered event has delivered at %v and error %q", got.DeliveredAt, got.LastError)
+			}
+			if tt.searchErr != nil && got.LastError != "search: the mail server is down" {
+				t.Errorf("last error = %q", got.LastError)
+			}
+		})
+	}
+}
+
+func TestDispatcher_panic(t *testing.T) {
+	s := &fakeStore{due: []models.OutboxEvent{jobCreated(t, 0)}}
+	d, _ := NewDispatcher(s)
+	d.Subscribe(models.EventJobCreated, "index", func(ctx context.Context, e models.DomainEvent) error {
+		panic("index out of range")
+	})
+	_, err := d.DispatchDue(context.Background())
+	if err != nil {
+		t.Fatalf("DispatchDue() error = %v", err)
+	}
+	if got := s.saved[0]; got.Status != models.OutboxPending || got.LastError != "index: panic: index out of range" {
+		t.Errorf("saved %s with error %q, want a pending event to retry", got.Status, got.LastError)
+	}
+}
+
+func TestDispatcher_unreadable(t *testing.T) {
+	event := jobCreated(t, 0)
+	event.Payload = "{"
+	s := &fakeStore{due: []models.OutboxEvent{event}}
+	d, _ := NewDispatcher(s, WithMaxAttempts(1))
+	d.Subscribe(models.EventJobCreated, "index", func(ctx context.Context, e models.DomainEvent) error {
+		t.Error("the subscriber got an event that could not be read")
+		return nil
+	})
+	_, _ = d.DispatchDue(context.Background())
+	if got := s.saved[0]; got.Status != models.OutboxDead {
+		t.Errorf("an unreadable event is %s, want %s", got.Status, models.OutboxDead)
+	}
+}
@@ -0,0 +1,312 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"project/internal/apperr"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Ops lists the operations of JSON Patch
+var Ops = []string{"add", "remove", "replace", "move", "copy", "test"}
+
+// Operation is one step of a JSON Patch, Value is empty when the member is missing
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatch applies the operations of p to doc in order, the first one failing
+// leaves doc untouched
+func JSONPatch(doc, p []byte) ([]byte, error) {
+	v, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	var ops []Operation
+	err = json.Unmarshal(p, &ops)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.Validation, err, "the patch must be a json array of operations")
+	}
+	for i, op := range ops {
+		v, err = apply(v, op)
+		if err != nil {
+			return nil, opError(i, err)
+		}
+	}
+	return json.Marshal(v)
+}
+
+func apply(doc interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return nil, apperr.New(apperr.Validation, op.Op+" needs a value")
+		}
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, apperr.Wrap(apperr.Validation, err, "the value is not valid json")
+		}
+		switch op.Op {
+		case "add":
+			return add(doc, path, value, op.Path)
+		case "replace":
+			doc, err = remove(doc, path, op.Path)
+			if err != nil {
+				return nil, err
+			}
+			return add(doc, path, value, op.Path)
+		}
+		current, err := get(doc, path, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(current, value) {
+			return nil, apperr.New(apperr.Conflict, "test of "+op.Path+" failed")
+		}
+		return doc, nil
+	case "remove":
+		return remove(doc, path, op.Path)
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from, op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if isPrefix(from, path) {
+				return nil, apperr.New(apperr.Validation, "cannot move "+op.From+" into itself")
+			}
+			doc, err = remove(doc, from, op.From)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// the copy must not share maps or slices with the source
+			b, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			value, err = decode(b)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return add(doc, path, value, op.Path)
+	}
+	return nil, apperr.New(apperr.Validation, "unknown op "+strconv.Quote(op.Op))
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped tokens, the
+// empty pointer is the whole document
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, apperr.New(apperr.Validation, "path "+strconv.Quote(pointer)+" must start with /")
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) >= len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func get(doc interface{}, path []string, pointer string) (interface{}, error) {
+	for _, token := range path {
+		switch container := doc.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, missing(pointer)
+			}
+			doc = value
+		case []interface{}:
+			i, err := index(token, len(container)-1)
+			if err != nil {
+				return nil, missing(pointer)
+			}
+			doc = container[i]
+		default:
+			return nil, missing(pointer)
+		}
+	}
+	return doc, nil
+}
+
+// add sets path to value, inserting into arrays, and returns the new document
+func add(doc interface{}, path []string, value interface{}, p string) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token := path[0]
+	switch container := doc.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			container[token] = value
+			return container, nil
+		}
+		child, ok := container[token]
+		if !ok {
+			return nil, missing(p)
+		}
+		child, err := add(child, path[1:], value, p)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = child
+		return container, nil
+	case []interface{}:
+		if len(path) == 1 {
+			i := len(container)
+			if token != "-" {
+				var err error
+				i, err = index(token, len(container))
+				if err != nil {
+					return nil, missing(p)
+				}
+			}
+			container = append(container, nil)
+			copy(container[i+1:], container[i:])
+			container[i] = value
+			return container, nil
+		}
+		i, err := index(token, len(container)-1)
+		if err != nil {
+			return nil, missing(p)
+		}
+		container[i], err = add(container[i], path[1:], value, p)
+		if err != nil {
+			return nil, err
+		}
+		return container, nil
+	}
+	return nil, missing(p)
+}
+
+// remove deletes path, which has to exist, and returns the new document
+func remove(doc interface{}, path []string, p string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	token := path[0]
+	switch container := doc.(type) {
+	case map[string]interface{}:
+		child, ok := container[token]
+		if !ok {
+			return nil, missing(p)
+		}
+		if len(path) == 1 {
+			delete(container, token)
+			return container, nil
+		}
+		child, err := remove(child, path[1:], p)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = child
+		return container, nil
+	case []interface{}:
+		i, err := index(token, len(container)-1)
+		if err != nil {
+			return nil, missing(p)
+		}
+		if len(path) == 1 {
+			return append(container[:i], container[i+1:]...), nil
+		}
+		container[i], err = remove(container[i], path[1:], p)
+		if err != nil {
+			return nil, err
+		}
+		return container, nil
+	}
+	return nil, missing(p)
+}
+
+// index parses an array index no larger than max, leading zeros are not allowed
+func index(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max {
+		return 0, fmt.Errorf("invalid index %q", token)
+	}
+	return i, nil
+}
+
+// opError says which operation failed in the message clients see
+func opError(i int, err error) error {
+	var e *apperr.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	return apperr.Wrap(e.Kind, e.Err, fmt.Sprintf("operation %d: %s", i, e.Msg))
+}
+
+func missing(pointer string) error {
+	return apperr.New(apperr.Unprocessable, "path "+pointer+" does not exist")
+}
+
+// equal compares two decoded values, numbers are equal when their values are
+func equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		bn, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		af, aerr := a.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	case []interface{}:
+		bs, ok := b.([]interface{})
+		if !ok || len(a) != len(bs) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], bs[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bm, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(bm) {
+			return false
+		}
+		for name, value := range a {
+			other, ok := bm[name]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
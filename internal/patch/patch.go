@@ -0,0 +1,76 @@
+// Package patch applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902)
+// documents to the json of a record
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"project/internal/apperr"
+)
+
+// media types of the two patch formats
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// Apply patches doc with p, contentType says which format p is in
+func Apply(contentType string, doc, p []byte) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+	switch mediaType {
+	case MergePatchType:
+		return Merge(doc, p)
+	case JSONPatchType:
+		return JSONPatch(doc, p)
+	}
+	return nil, apperr.New(apperr.UnsupportedMediaType, "send the patch as "+MergePatchType+" or "+JSONPatchType)
+}
+
+// Merge applies the merge patch p to doc, null in p removes a member and objects
+// are merged recursively while any other value replaces the one in doc
+func Merge(doc, p []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := decode(p)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.Validation, err, "the patch is not valid json")
+	}
+	return json.Marshal(merge(target, patch))
+}
+
+func merge(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+	for name, value := range p {
+		if value == nil {
+			delete(t, name)
+			continue
+		}
+		t[name] = merge(t[name], value)
+	}
+	return t
+}
+
+// decode keeps numbers as json.Number so large integers survive a round trip
+func decode(b []byte) (interface{}, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	err := dec.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
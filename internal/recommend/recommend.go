@@ -0,0 +1,242 @@
+// Package recommend ranks jobs for a candidate. The Engine interface lets the
+// hand tuned Weighted scorer be swapped for a learned model later
+package recommend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"project/internal/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// factors scored by the Weighted engine
+const (
+	FactorSkills    = "skills"
+	FactorLocation  = "location"
+	FactorSalary    = "salary"
+	FactorSeniority = "seniority"
+	FactorRecency   = "recency"
+)
+
+// neutral is the score of a factor that cannot be judged because data is missing
+const neutral = 0.5
+
+//go:generate mockgen -source=recommend.go -destination=recommend_mock.go -package=recommend
+type Engine interface {
+	// Recommend returns jobs ranked best first, each with the score of every factor
+	Recommend(ctx context.Context, profile models.Profile, jobs []models.Jobs) ([]models.Recommendation, error)
+}
+
+// Weights sets how much each factor counts towards the final score, only the
+// ratio between the weights matters
+type Weights struct {
+	Skills    float64
+	Location  float64
+	Salary    float64
+	Seniority float64
+	Recency   float64
+}
+
+func DefaultWeights() Weights {
+	return Weights{
+		Skills:    0.4,
+		Location:  0.2,
+		Salary:    0.15,
+		Seniority: 0.15,
+		Recency:   0.1,
+	}
+}
+
+// Weighted scores every factor between 0 and 1 and combines them as a weighted mean
+type Weighted struct {
+	weights Weights
+	// maxDistanceKm is the distance at which the location score reaches 0
+	maxDistanceKm float64
+	// recencyHalfLife is the job age at which the recency score halves
+	recencyHalfLife time.Duration
+	now             func() time.Time
+}
+
+func NewWeighted(w Weights) (*Weighted, error) {
+	for _, v := range []float64{w.Skills, w.Location, w.Salary, w.Seniority, w.Recency} {
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errors.New("weights cannot be negative")
+		}
+	}
+	if w.Skills+w.Location+w.Salary+w.Seniority+w.Recency == 0 {
+		return nil, errors.New("at least one weight must be positive")
+	}
+	return &Weighted{
+		weights:         w,
+		maxDistanceKm:   100,
+		recencyHalfLife: 14 * 24 * time.Hour,
+		now:             time.Now,
+	}, nil
+}
+
+func (e *Weighted) Recommend(ctx context.Context, profile models.Profile, jobs []models.Jobs) ([]models.Recommendation, error) {
+	recommendations := make([]models.Recommendation, 0, len(jobs))
+	for _, job := range jobs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, e.score(profile, job))
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Job.ID > recommendations[j].Job.ID
+	})
+	return recommendations, nil
+}
+
+func (e *Weighted) score(profile models.Profile, job models.Jobs) models.Recommendation {
+	factors := []models.FactorScore{
+		e.skills(profile, job),
+		e.location(profile, job),
+		e.salary(profile, job),
+		e.seniority(profile, job),
+		e.recency(job),
+	}
+	var total, weights float64
+	for _, f := range factors {
+		total += f.Weight * f.Score
+		weights += f.Weight
+	}
+	return models.Recommendation{
+		Job:     job,
+		Score:   round(total / weights),
+		Factors: factors,
+	}
+}
+
+func (e *Weighted) skills(profile models.Profile, job models.Jobs) models.FactorScore {
+	f := models.FactorScore{Factor: FactorSkills, Weight: e.weights.Skills, Score: neutral}
+	if len(job.Skills) == 0 {
+		f.Reason = "the job lists no skills"
+		return f
+	}
+	have := make(map[string]bool, len(profile.Skills))
+	for _, skill := range profile.Skills {
+		have[strings.ToLower(strings.TrimSpace(skill))] = true
+	}
+	var matched []string
+	for _, skill := range job.Skills {
+		if have[strings.ToLower(strings.TrimSpace(skill))] {
+			matched = append(matched, skill)
+		}
+	}
+	f.Score = round(float64(len(matched)) / float64(len(job.Skills)))
+	if len(matched) == 0 {
+		f.Reason = "none of the required skills match"
+	} else {
+		f.Reason = fmt.Sprintf("matches %d of %d skills: %s", len(matched), len(job.Skills), strings.Join(matched, ", "))
+	}
+	return f
+}
+
+func (e *Weighted) location(profile models.Profile, job models.Jobs) models.FactorScore {
+	f := models.FactorScore{Factor: FactorLocation, Weight: e.weights.Location, Score: neutral}
+	switch {
+	case job.RemotePolicy == models.RemoteFull:
+		f.Score = 1
+		f.Reason = "the job is fully remote"
+	case hasCoordinates(profile.Latitude, profile.Longitude) && hasCoordinates(job.Latitude, job.Longitude):
+		d := DistanceKm(profile.Latitude, profile.Longitude, job.Latitude, job.Longitude)
+		f.Score = round(math.Max(0, 1-d/e.maxDistanceKm))
+		f.Reason = fmt.Sprintf("%.0f km away", d)
+	case profile.Location != "" && job.Location != "":
+		f.Score = 0
+		f.Reason = "in " + job.Location
+		if strings.EqualFold(strings.TrimSpace(profile.Location), strings.TrimSpace(job.Location)) {
+			f.Score = 1
+			f.Reason = "in your city"
+		}
+	default:
+		f.Reason = "location unknown"
+	}
+	return f
+}
+
+func (e *Weighted) salary(profile models.Profile, job models.Jobs) models.FactorScore {
+	f := models.FactorScore{Factor: FactorSalary, Weight: e.weights.Salary, Score: neutral}
+	offered := max(job.MinSalary, job.MaxSalary)
+	switch {
+	case profile.ExpectedSalary <= 0:
+		f.Reason = "no salary expectation set"
+	case offered <= 0:
+		f.Reason = "the job has no salary range"
+	case offered >= profile.ExpectedSalary:
+		f.Score = 1
+		f.Reason = "meets your salary expectation"
+	default:
+		f.Score = round(float64(offered) / float64(profile.ExpectedSalary))
+		f.Reason = fmt.Sprintf("pays up to %d, below your expectation of %d", offered, profile.ExpectedSalary)
+	}
+	return f
+}
+
+func (e *Weighted) seniority(profile models.Profile, job models.Jobs) models.FactorScore {
+	f := models.FactorScore{Factor: FactorSeniority, Weight: e.weights.Seniority, Score: neutral}
+	want, have := seniorityLevel(job.Seniority), seniorityLevel(profile.Seniority)
+	if want < 0 || have < 0 {
+		f.Reason = "seniority unknown"
+		return f
+	}
+	switch gap := want - have; {
+	case gap == 0:
+		f.Score = 1
+		f.Reason = "matches your seniority"
+	case gap == 1 || gap == -1:
+		f.Score = 0.5
+		f.Reason = "one level from your seniority"
+	default:
+		f.Score = 0
+		f.Reason = "seniority is far from yours"
+	}
+	return f
+}
+
+func (e *Weighted) recency(job models.Jobs) models.FactorScore {
+	f := models.FactorScore{Factor: FactorRecency, Weight: e.weights.Recency, Score: neutral}
+	if job.CreatedAt.IsZero() {
+		f.Reason = "posting date unknown"
+		return f
+	}
+	age := max(e.now().Sub(job.CreatedAt), 0)
+	f.Score = round(math.Pow(0.5, float64(age)/float64(e.recencyHalfLife)))
+	f.Reason = fmt.Sprintf("posted %d days ago", int(age.Hours()/24))
+	return f
+}
+
+func seniorityLevel(s string) int {
+	for i, level := range models.SeniorityLevels {
+		if level == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasCoordinates(lat, lng float64) bool {
+	return lat != 0 || lng != 0
+}
+
+// DistanceKm is the great circle distance between two points on earth
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadiusKm = 6371
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := rad(lat2 - lat1)
+	dLng := rad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+func round(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
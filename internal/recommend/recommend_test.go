@@ -0,0 +1,145 @@
+package recommend
+
+import (
+	"context"
+	"math"
+	"project/internal/models"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestNewWeighted(t *testing.T) {
+	if _, err := NewWeighted(Weights{Skills: -1, Recency: 1}); err == nil {
+		t.Errorf("NewWeighted() with a negative weight should fail")
+	}
+	if _, err := NewWeighted(Weights{}); err == nil {
+		t.Errorf("NewWeighted() with all weights zero should fail")
+	}
+	if _, err := NewWeighted(DefaultWeights()); err != nil {
+		t.Errorf("NewWeighted() error = %v", err)
+	}
+}
+
+func TestWeighted_Recommend(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	profile := models.Profile{
+		Skills:         []string{"Go", "postgres", "docker"},
+		Location:       "Bangalore",
+		Latitude:       12.9716,
+		Longitude:      77.5946,
+		ExpectedSalary: 1000000,
+		Seniority:      models.SenioritySenior,
+	}
+	jobs := []models.Jobs{
+		{
+			Model:     gorm.Model{ID: 1, CreatedAt: now.Add(-60 * 24 * time.Hour)},
+			Name:      "accountant",
+			Skills:    []string{"excel"},
+			Location:  "Delhi",
+			Latitude:  28.6139,
+			Longitude: 77.2090,
+			MaxSalary: 400000,
+			Seniority: models.SeniorityJunior,
+		},
+		{
+			Model:     gorm.Model{ID: 2, CreatedAt: now},
+			Name:      "backend engineer",
+			Skills:    []string{"go", "Postgres"},
+			Location:  "Bangalore",
+			Latitude:  12.9352,
+			Longitude: 77.6245,
+			MinSalary: 900000,
+			MaxSalary: 1400000,
+			Seniority: models.SenioritySenior,
+		},
+		{
+			Model:        gorm.Model{ID: 3, CreatedAt: now.Add(-14 * 24 * time.Hour)},
+			Name:         "platform engineer",
+			Skills:       []string{"go", "kubernetes"},
+			RemotePolicy: models.RemoteFull,
+			Seniority:    models.SeniorityLead,
+		},
+	}
+
+	e, _ := NewWeighted(DefaultWeights())
+	e.now = func() time.Time { return now }
+	got, err := e.Recommend(context.Background(), profile, jobs)
+	if err != nil {
+		t.Fatalf("Weighted.Recommend() error = %v", err)
+	}
+
+	var order []uint
+	for _, r := range got {
+		order = append(order, r.Job.ID)
+	}
+	if len(order) != 3 || order[0] != 2 || order[1] != 3 || order[2] != 1 {
+		t.Fatalf("Weighted.Recommend() order = %v, want [2 3 1]", order)
+	}
+
+	best := got[0]
+	if len(best.Factors) != 5 {
+		t.Fatalf("Weighted.Recommend() returned %d factors, want 5", len(best.Factors))
+	}
+	want := map[string]float64{
+		FactorSkills:    1,
+		FactorSalary:    1,
+		FactorSeniority: 1,
+		FactorRecency:   1,
+	}
+	for _, f := range best.Factors {
+		if score, ok := want[f.Factor]; ok && f.Score != score {
+			t.Errorf("factor %s score = %v, want %v (%s)", f.Factor, f.Score, score, f.Reason)
+		}
+		if f.Reason == "" {
+			t.Errorf("factor %s has no reason", f.Factor)
+		}
+		if f.Factor == FactorLocation && (f.Score < 0.9 || f.Score >= 1) {
+			t.Errorf("location score = %v, want a few km away", f.Score)
+		}
+	}
+
+	for _, f := range got[1].Factors {
+		if f.Factor == FactorRecency && f.Score != 0.5 {
+			t.Errorf("recency score after one half life = %v, want 0.5", f.Score)
+		}
+		if f.Factor == FactorLocation && f.Score != 1 {
+			t.Errorf("location score of a remote job = %v, want 1", f.Score)
+		}
+		if f.Factor == FactorSkills && f.Score != 0.5 {
+			t.Errorf("skills score with one of two skills = %v, want 0.5", f.Score)
+		}
+	}
+}
+
+func TestWeighted_RecommendUsesWeights(t *testing.T) {
+	profile := models.Profile{Skills: []string{"go"}, ExpectedSalary: 100}
+	jobs := []models.Jobs{
+		{Model: gorm.Model{ID: 1}, Skills: []string{"go"}, MaxSalary: 10},
+		{Model: gorm.Model{ID: 2}, Skills: []string{"java"}, MaxSalary: 100},
+	}
+
+	skillsOnly, _ := NewWeighted(Weights{Skills: 1})
+	got, _ := skillsOnly.Recommend(context.Background(), profile, jobs)
+	if got[0].Job.ID != 1 || got[0].Score != 1 {
+		t.Errorf("skills weighted engine ranked job %d with %v first, want job 1 with 1", got[0].Job.ID, got[0].Score)
+	}
+
+	salaryOnly, _ := NewWeighted(Weights{Salary: 1})
+	got, _ = salaryOnly.Recommend(context.Background(), profile, jobs)
+	if got[0].Job.ID != 2 {
+		t.Errorf("salary weighted engine ranked job %d first, want job 2", got[0].Job.ID)
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	// bangalore to chennai is about 290 km
+	d := DistanceKm(12.9716, 77.5946, 13.0827, 80.2707)
+	if math.Abs(d-290) > 5 {
+		t.Errorf("DistanceKm() = %v, want about 290", d)
+	}
+	if d := DistanceKm(10, 10, 10, 10); d != 0 {
+		t.Errorf("DistanceKm() of the same point = %v, want 0", d)
+	}
+}
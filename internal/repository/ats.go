@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"project/internal/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repo) CreateConnector(ctx context.Context, connector models.ATSConnector) (models.ATSConnector, error) {
+	result := r.DB.WithContext(ctx).Create(&connector)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.ATSConnector{}, dbError(result.Error, "could not create the connector")
+	}
+	return connector, nil
+}
+
+func (r *Repo) ConnectorsByCompany(ctx context.Context, cid uint) ([]models.ATSConnector, error) {
+	var connectors []models.ATSConnector
+	result := r.DB.WithContext(ctx).Where("cid = ?", cid).Order("id").Find(&connectors)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the connectors")
+	}
+	return connectors, nil
+}
+
+// DeleteConnector stops the pulls of the connector, the jobs it brought in stay
+func (r *Repo) DeleteConnector(ctx context.Context, cid uint, id uint) error {
+	result := r.DB.WithContext(ctx).Where("id = ? AND cid = ?", id, cid).Delete(&models.ATSConnector{})
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not delete the connector")
+	}
+	if result.RowsAffected == 0 {
+		return dbError(gorm.ErrRecordNotFound, "connector not found")
+	}
+	return nil
+}
+
+// ClaimConnectors takes up to limit connectors due at now and holds them until
+// the lease ends, so other schedulers pass over them while their boards are pulled
+func (r *Repo) ClaimConnectors(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.ATSConnector, error) {
+	var connectors []models.ATSConnector
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("next_sync_at <= ?", now).
+			Order("next_sync_at").Limit(limit).Find(&connectors)
+		if result.Error != nil || len(connectors) == 0 {
+			return result.Error
+		}
+		ids := make([]uint, 0, len(connectors))
+		for i := range connectors {
+			ids = append(ids, connectors[i].ID)
+			connectors[i].NextSyncAt = lease
+		}
+		return tx.Model(&models.ATSConnector{}).Where("id IN ?", ids).Update("next_sync_at", lease).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, dbError(err, "could not claim the connectors")
+	}
+	return connectors, nil
+}
+
+// SaveSync stores how pulling the board of the connector went
+func (r *Repo) SaveSync(ctx context.Context, connector models.ATSConnector) error {
+	result := r.DB.WithContext(ctx).Model(&connector).
+		Select("next_sync_at", "last_synced_at", "last_sync", "last_error").
+		Updates(&connector)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not save the connector sync")
+	}
+	return nil
+}
+
+// JobsByExternalIDPrefix finds the jobs of the company whose external id starts
+// with prefix, deleted ones included so a board does not bring them back
+func (r *Repo) JobsByExternalIDPrefix(ctx context.Context, cid uint, prefix string) ([]models.Jobs, error) {
+	var jobs []models.Jobs
+	result := r.DB.WithContext(ctx).Unscoped().
+		Where("cid = ? AND external_id LIKE ?", cid, prefix+"%").
+		Order("id").Find(&jobs)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the jobs of the board")
+	}
+	return jobs, nil
+}
@@ -4,15 +4,24 @@ import (
 	"context"
 	"errors"
 	"project/internal/models"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
-func (r *Repo) CreateUserCompany(ctx context.Context, companyData models.Company) (models.Company, error) {
-	result := r.DB.Create(&companyData)
-	if result.Error != nil {
-		log.Info().Err(result.Error).Send()
-		return models.Company{}, errors.New("could not create the company")
+// CreateUserCompany creates the company and makes ownerID its owner in one transaction
+func (r *Repo) CreateUserCompany(ctx context.Context, companyData models.Company, ownerID uint) (models.Company, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Create(&companyData)
+		if result.Error != nil {
+			return result.Error
+		}
+		return tx.Create(&models.Membership{CompanyID: companyData.ID, UserID: ownerID, Role: models.RoleOwner}).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.Company{}, dbError(err, "could not create the company")
 	}
 	return companyData, nil
 }
@@ -22,7 +31,7 @@ func (r *Repo) Companies(ctx context.Context) ([]models.Company, error) {
 	result := r.DB.Find(&userDetails)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
-		return nil, errors.New("could not find the companies")
+		return nil, dbError(result.Error, "could not find the companies")
 	}
 	return userDetails, nil
 }
@@ -32,7 +41,59 @@ func (r *Repo) CompanyById(ctx context.Context, cid uint64) (models.Company, err
 	result := r.DB.Where("id = ?", cid).First(&companyData)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
-		return models.Company{}, errors.New("could not find the company")
+		return models.Company{}, dbError(result.Error, "could not find the company")
 	}
 	return companyData, nil
 }
+
+func (r *Repo) CompaniesByIds(ctx context.Context, ids []uint) ([]models.Company, error) {
+	var companyDatas []models.Company
+	if len(ids) == 0 {
+		return companyDatas, nil
+	}
+	result := r.DB.WithContext(ctx).Where("id IN ?", ids).Find(&companyDatas)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the companies")
+	}
+	return companyDatas, nil
+}
+
+// UpdateCompany replaces the fields of the company if it was last updated at
+// version, otherwise someone else changed it in between
+func (r *Repo) UpdateCompany(ctx context.Context, companyData models.Company, version time.Time) (models.Company, error) {
+	result := r.DB.WithContext(ctx).Model(&companyData).
+		Where("updated_at = ?", version).
+		Select("*").Omit("id", "created_at", "deleted_at").
+		Updates(&companyData)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.Company{}, dbError(result.Error, "could not update the company")
+	}
+	if result.RowsAffected == 0 {
+		return models.Company{}, errCompanyChanged
+	}
+	return r.CompanyById(ctx, uint64(companyData.ID))
+}
+
+// DeleteCompany deletes the company and its jobs if it was last updated at version
+func (r *Repo) DeleteCompany(ctx context.Context, cid uint, version time.Time) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND updated_at = ?", cid, version).Delete(&models.Company{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errCompanyChanged
+		}
+		return tx.Where("cid = ?", cid).Delete(&models.Jobs{}).Error
+	})
+	if errors.Is(err, errCompanyChanged) {
+		return err
+	}
+	if err != nil {
+		log.Info().Err(err).Send()
+		return dbError(err, "could not delete the company")
+	}
+	return nil
+}
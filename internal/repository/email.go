@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"project/internal/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QueueEmails queues the emails, one whose key is queued already is left out
+func (r *Repo) QueueEmails(ctx context.Context, emails []models.Email) error {
+	if len(emails) == 0 {
+		return nil
+	}
+	result := r.DB.WithContext(ctx).Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).
+		Create(&emails)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not queue the emails")
+	}
+	return nil
+}
+
+// ClaimEmails takes up to limit pending emails due at now and holds them until
+// the lease ends, so other senders pass over them while they are sent
+func (r *Repo) ClaimEmails(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.Email, error) {
+	var emails []models.Email
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.EmailPending, now).
+			Order("next_attempt_at").Limit(limit).Find(&emails)
+		if result.Error != nil || len(emails) == 0 {
+			return result.Error
+		}
+		ids := make([]uint, 0, len(emails))
+		for i := range emails {
+			ids = append(ids, emails[i].ID)
+			emails[i].NextAttemptAt = lease
+		}
+		return tx.Model(&models.Email{}).Where("id IN ?", ids).Update("next_attempt_at", lease).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, dbError(err, "could not claim the emails")
+	}
+	return emails, nil
+}
+
+// SaveEmail stores how sending the email went
+func (r *Repo) SaveEmail(ctx context.Context, e models.Email) error {
+	result := r.DB.WithContext(ctx).Model(&e).
+		Select("status", "attempts", "next_attempt_at", "last_error", "sent_at").
+		Updates(&e)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not save the email attempt")
+	}
+	return nil
+}
+
+// Unsubscribe stops the email notifications of the user and returns the user.
+// Unsubscribing again keeps the time of the first
+func (r *Repo) Unsubscribe(ctx context.Context, userID uint, at time.Time) (models.User, error) {
+	result := r.DB.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND unsubscribed_at IS NULL", userID).
+		Update("unsubscribed_at", at)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.User{}, dbError(result.Error, "could not unsubscribe")
+	}
+	return r.UserByID(ctx, userID)
+}
+
+// CompanyMembers are the users who recruit for the company
+func (r *Repo) CompanyMembers(ctx context.Context, cid uint) ([]models.User, error) {
+	var users []models.User
+	result := r.DB.WithContext(ctx).
+		Joins("JOIN memberships ON memberships.user_id = users.id AND memberships.deleted_at IS NULL").
+		Where("memberships.company_id = ? AND memberships.role IN ?", cid, []string{models.RoleOwner, models.RoleRecruiter}).
+		Order("users.id").Find(&users)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the members of the company")
+	}
+	return users, nil
+}
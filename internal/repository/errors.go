@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"project/internal/apperr"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// postgres error codes the api can explain to a client,
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgNotNullViolation     = "23502"
+	pgCheckViolation       = "23514"
+	pgInvalidText          = "22P02"
+	pgStringTooLong        = "22001"
+	pgNumericOutOfRange    = "22003"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// conditional writes change nothing when the row was updated since the client
+// read it, or when it is gone
+var (
+	errCompanyChanged = apperr.New(apperr.PreconditionFailed, "the company was changed or deleted since it was read")
+	errJobChanged     = apperr.New(apperr.PreconditionFailed, "the job was changed or deleted since it was read")
+	errProfileChanged = apperr.New(apperr.PreconditionFailed, "the profile was changed since it was read")
+)
+
+// dbError classifies an error of gorm or the postgres driver, msg tells the
+// client what failed and the kind tells why
+func dbError(err error, msg string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return apperr.Wrap(apperr.NotFound, err, msg)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return apperr.Wrap(apperr.Conflict, err, msg+", it already exists")
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return apperr.Wrap(apperr.Validation, err, msg+", it refers to a record that does not exist")
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return apperr.Wrap(apperr.Internal, err, msg)
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return apperr.Wrap(apperr.Conflict, err, msg+", it already exists")
+	case pgForeignKeyViolation:
+		return apperr.Wrap(apperr.Validation, err, msg+", it refers to a record that does not exist")
+	case pgNotNullViolation, pgCheckViolation, pgInvalidText, pgStringTooLong, pgNumericOutOfRange:
+		return apperr.Wrap(apperr.Validation, err, msg+", a value is invalid")
+	case pgSerializationFailure, pgDeadlockDetected:
+		return apperr.Wrap(apperr.Conflict, err, msg+", it changed concurrently, try again")
+	}
+	return apperr.Wrap(apperr.Internal, err, msg)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"project/internal/apperr"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func Test_dbError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantKind apperr.Kind
+		wantMsg  string
+	}{
+		{name: "no error"},
+		{
+			name:     "record not found",
+			err:      gorm.ErrRecordNotFound,
+			wantKind: apperr.NotFound,
+			wantMsg:  "could not find the company",
+		},
+		{
+			name:     "unique violation",
+			err:      fmt.Errorf("insert: %w", &pgconn.PgError{Code: pgUniqueViolation}),
+			wantKind: apperr.Conflict,
+			wantMsg:  "could not find the company, it already exists",
+		},
+		{
+			name:     "foreign key violation",
+			err:      &pgconn.PgError{Code: pgForeignKeyViolation},
+			wantKind: apperr.Validation,
+			wantMsg:  "could not find the company, it refers to a record that does not exist",
+		},
+		{
+			name:     "serialization failure",
+			err:      &pgconn.PgError{Code: pgSerializationFailure},
+			wantKind: apperr.Conflict,
+			wantMsg:  "could not find the company, it changed concurrently, try again",
+		},
+		{
+			name:     "lost connection",
+			err:      errors.New("conn closed"),
+			wantKind: apperr.Internal,
+			wantMsg:  "could not find the company",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dbError(tt.err, "could not find the company")
+			if tt.err == nil {
+				if err != nil {
+					t.Errorf("dbError(nil) = %v", err)
+				}
+				return
+			}
+			var e *apperr.Error
+			if !errors.As(err, &e) {
+				t.Fatalf("dbError() = %v is not an apperr.Error", err)
+			}
+			if e.Kind != tt.wantKind || e.Msg != tt.wantMsg {
+				t.Errorf("dbError() = %v %q, want %v %q", e.Kind, e.Msg, tt.wantKind, tt.wantMsg)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("dbError() lost the cause")
+			}
+		})
+	}
+}
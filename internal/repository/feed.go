@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"project/internal/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// FeedJobs returns up to limit published jobs matching filter with their company,
+// newest first, and when a job the filter matches last changed. Closed and deleted
+// jobs count for the time too, a job leaving the feed changes it
+func (r *Repo) FeedJobs(ctx context.Context, filter models.JobFilter, limit int) ([]models.Jobs, time.Time, error) {
+	var (
+		jobDatas []models.Jobs
+		modified sql.NullTime
+	)
+	err := r.searchJobs(ctx, filter, func(db *gorm.DB, q jobQuery) error {
+		result := q.apply(db, "").
+			Preload("Company").
+			Order("jobs.created_at DESC, jobs.id DESC").
+			Limit(limit).
+			Find(&jobDatas)
+		if result.Error != nil {
+			log.Info().Err(result.Error).Send()
+			return dbError(result.Error, "could not find the jobs")
+		}
+
+		changed := q
+		changed.filter.Status = []string{models.JobDraft, models.JobPublished, models.JobClosed}
+		err := changed.apply(db.Unscoped().Model(&models.Jobs{}), "").
+			Select("MAX(GREATEST(jobs.updated_at, jobs.deleted_at))").
+			Row().Scan(&modified)
+		if err != nil {
+			log.Info().Err(err).Send()
+			return dbError(err, "could not find when the jobs changed")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return jobDatas, modified.Time, nil
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"project/internal/fuzzy"
+	"project/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// fuzzyBatchSize is how many rows the go fallback reads at a time, a store without
+// trigrams cannot narrow the names down so every one of them is read and scored
+const fuzzyBatchSize = 1000
+
+// named is a row of an id and the name the go fallback scores
+type named struct {
+	ID          uint
+	Name        string
+	CompanyName string
+}
+
+// scanNamed hands the rows of query to fn in batches of fuzzyBatchSize in the
+// order of key, the id column of the rows, so no row is left out however many
+func scanNamed(db *gorm.DB, key string, query func(db *gorm.DB) *gorm.DB, fn func([]named)) error {
+	var last uint
+	for {
+		var rows []named
+		result := query(db).Where(key+" > ?", last).Order(key).Limit(fuzzyBatchSize).Scan(&rows)
+		if result.Error != nil {
+			return result.Error
+		}
+		fn(rows)
+		if len(rows) < fuzzyBatchSize {
+			return nil
+		}
+		last = rows[len(rows)-1].ID
+	}
+}
+
+// trigram reports whether the store supports pg_trgm, every other store falls
+// back to scoring the names in go
+func (r *Repo) trigram() bool {
+	return r.DB.Dialector.Name() == "postgres"
+}
+
+// withThreshold runs fn in a transaction where the pg_trgm setting, such as
+// pg_trgm.word_similarity_threshold, is threshold. The gin trigram indexes only
+// serve the % and <% operators, which match at that setting, and not a similarity
+// compared with a threshold. Without trigrams or a threshold fn runs as is
+func (r *Repo) withThreshold(ctx context.Context, setting string, threshold float64, fn func(db *gorm.DB) error) error {
+	db := r.DB.WithContext(ctx)
+	if !r.trigram() || threshold <= 0 {
+		return fn(db)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		// set_config with is_local is SET LOCAL taking its value as a parameter
+		result := tx.Exec("SELECT set_config(?, ?, true)", setting, strconv.FormatFloat(threshold, 'f', -1, 64))
+		if result.Error != nil {
+			log.Info().Err(result.Error).Send()
+			return dbError(result.Error, "could not search the jobs")
+		}
+		return fn(tx)
+	})
+}
+
+// searchJobs runs fn with the prepared filter on a db its query can be matched on
+func (r *Repo) searchJobs(ctx context.Context, filter models.JobFilter, fn func(db *gorm.DB, q jobQuery) error) error {
+	q := jobQuery{filter: filter, trigram: r.trigram()}
+	if filter.Query == "" {
+		return fn(r.DB.WithContext(ctx), q)
+	}
+	if q.trigram {
+		return r.withThreshold(ctx, "pg_trgm.word_similarity_threshold", filter.Similarity, func(db *gorm.DB) error {
+			return fn(db, q)
+		})
+	}
+	scores, err := r.fuzzyScores(ctx, filter.Query, filter.Similarity)
+	if err != nil {
+		return err
+	}
+	q.scores = scores
+	return fn(r.DB.WithContext(ctx), q)
+}
+
+// fuzzyScores scores every job by the better of its own name and its company name
+// and keeps the ones reaching the threshold
+func (r *Repo) fuzzyScores(ctx context.Context, query string, threshold float64) (map[uint]float64, error) {
+	scores := make(map[uint]float64)
+	err := scanNamed(r.DB.WithContext(ctx), "jobs.id", func(db *gorm.DB) *gorm.DB {
+		return db.Model(&models.Jobs{}).
+			Select("jobs.id, jobs.name, companies.name AS company_name").
+			Joins("LEFT JOIN companies ON companies.id = jobs.cid")
+	}, func(rows []named) {
+		for _, row := range rows {
+			score := max(fuzzy.WordSimilarity(query, row.Name), fuzzy.WordSimilarity(query, row.CompanyName))
+			if score >= threshold {
+				scores[row.ID] = score
+			}
+		}
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, dbError(err, "could not search the jobs")
+	}
+	return scores, nil
+}
+
+// searchJobsByScore pages through the go scored matches, best score first
+func (r *Repo) searchJobsByScore(db *gorm.DB, q jobQuery) ([]models.Jobs, int64, error) {
+	var ids []uint
+	result := q.apply(db.Model(&models.Jobs{}), "").Pluck("jobs.id", &ids)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, 0, dbError(result.Error, "could not find the jobs")
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if q.scores[ids[i]] != q.scores[ids[j]] {
+			return q.scores[ids[i]] > q.scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	total := int64(len(ids))
+	start := min((q.filter.Page-1)*q.filter.PageSize, len(ids))
+	end := min(start+q.filter.PageSize, len(ids))
+	page := ids[start:end]
+	if len(page) == 0 {
+		return []models.Jobs{}, total, nil
+	}
+
+	var jobDatas []models.Jobs
+	result = db.Where("id IN ?", page).Find(&jobDatas)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, 0, dbError(result.Error, "could not find the jobs")
+	}
+	position := make(map[uint]int, len(page))
+	for i, id := range page {
+		position[id] = i
+	}
+	sort.Slice(jobDatas, func(i, j int) bool {
+		return position[jobDatas[i].ID] < position[jobDatas[j].ID]
+	})
+	return jobDatas, total, nil
+}
+
+// SuggestJobTerm returns the published job or company name closest to query, or an
+// empty string when nothing reaches the threshold or the best match is the query itself
+func (r *Repo) SuggestJobTerm(ctx context.Context, query string, threshold float64) (string, error) {
+	var terms []string
+	if r.trigram() {
+		err := r.withThreshold(ctx, "pg_trgm.similarity_threshold", threshold, func(db *gorm.DB) error {
+			result := db.Raw(`SELECT term FROM (
+				SELECT name AS term FROM jobs WHERE name % ? AND status = ? AND deleted_at IS NULL
+				UNION SELECT name FROM companies WHERE name % ? AND deleted_at IS NULL
+			) t WHERE similarity(term, ?) >= ? ORDER BY similarity(term, ?) DESC, term LIMIT 2`,
+				query, models.JobPublished, query, query, threshold, query).Scan(&terms)
+			if result.Error != nil {
+				log.Info().Err(result.Error).Send()
+				return dbError(result.Error, "could not suggest a search term")
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	} else {
+		// only the best name other than the query itself is kept while every name is read
+		best, bestScore := "", threshold
+		suggest := func(rows []named) {
+			for _, row := range rows {
+				score := fuzzy.Similarity(query, row.Name)
+				if score < bestScore || (score == bestScore && best != "" && row.Name >= best) ||
+					strings.EqualFold(fuzzy.Normalize(row.Name), fuzzy.Normalize(query)) {
+					continue
+				}
+				best, bestScore = row.Name, score
+			}
+		}
+		db := r.DB.WithContext(ctx)
+		err := scanNamed(db, "id", func(db *gorm.DB) *gorm.DB {
+			return db.Model(&models.Jobs{}).Select("id, name").Where("status = ?", models.JobPublished)
+		}, suggest)
+		if err == nil {
+			err = scanNamed(db, "id", func(db *gorm.DB) *gorm.DB {
+				return db.Model(&models.Company{}).Select("id, name")
+			}, suggest)
+		}
+		if err != nil {
+			log.Info().Err(err).Send()
+			return "", dbError(err, "could not suggest a search term")
+		}
+		if best != "" {
+			terms = append(terms, best)
+		}
+	}
+
+	for _, term := range terms {
+		if !strings.EqualFold(fuzzy.Normalize(term), fuzzy.Normalize(query)) {
+			return term, nil
+		}
+	}
+	return "", nil
+}
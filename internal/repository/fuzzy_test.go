@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils/tests"
+)
+
+// numbered is a database driver whose every table has the rows 1 to the number in
+// its dsn, named after their id but for the last, named developer. A query gets
+// the rows after the id it takes last, up to fuzzyBatchSize of them
+type numbered struct {
+	empty
+	rows uint64
+}
+
+func (numbered) Open(dsn string) (driver.Conn, error) {
+	rows, err := strconv.ParseUint(dsn, 10, 64)
+	return numbered{rows: rows}, err
+}
+
+func (n numbered) QueryContext(_ context.Context, _ string, args []driver.NamedValue) (driver.Rows, error) {
+	after, ok := args[len(args)-1].Value.(int64)
+	if !ok {
+		return nil, fmt.Errorf("the query does not take the id to read after last: %v", args)
+	}
+	return &numberedRows{next: uint64(after) + 1, last: min(uint64(after)+fuzzyBatchSize, n.rows), rows: n.rows}, nil
+}
+
+type numberedRows struct {
+	next, last, rows uint64
+}
+
+func (*numberedRows) Columns() []string { return []string{"id", "name", "company_name"} }
+func (*numberedRows) Close() error      { return nil }
+func (r *numberedRows) Next(dest []driver.Value) error {
+	if r.next > r.last {
+		return io.EOF
+	}
+	name := "job " + strconv.FormatUint(r.next, 10)
+	if r.next == r.rows {
+		name = "developer"
+	}
+	dest[0], dest[1], dest[2] = int64(r.next), name, "tek"
+	r.next++
+	return nil
+}
+
+func init() {
+	sql.Register("numbered", numbered{})
+}
+
+// numberedRepo is a repo on a database of the given number of rows in every table
+func numberedRepo(t *testing.T, rows int) *Repo {
+	conn, err := sql.Open("numbered", strconv.Itoa(rows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{ConnPool: conn, Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Repo{DB: db}
+}
+
+// Test_Repo_fuzzyEveryRow checks that the go fallback reads past the first batch,
+// the only match is the last row
+func Test_Repo_fuzzyEveryRow(t *testing.T) {
+	rows := 2*fuzzyBatchSize + 5
+	r := numberedRepo(t, rows)
+
+	scores, err := r.fuzzyScores(context.Background(), "developer", 0.5)
+	if err != nil {
+		t.Fatalf("Repo.fuzzyScores() error = %v", err)
+	}
+	if len(scores) != 1 || scores[uint(rows)] != 1 {
+		t.Errorf("Repo.fuzzyScores() = %v, want job %d alone", scores, rows)
+	}
+
+	got, err := r.SuggestJobTerm(context.Background(), "develper", 0.3)
+	if err != nil {
+		t.Fatalf("Repo.SuggestJobTerm() error = %v", err)
+	}
+	if got != "developer" {
+		t.Errorf("Repo.SuggestJobTerm() = %q, want %q", got, "developer")
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"project/internal/models"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// rowSavepoint is set before every write of a batch, a failed write rolls back to it
+const rowSavepoint = "import_row"
+
+type batch struct {
+	tx     *gorm.DB
+	events []models.OutboxEvent
+}
+
+func (r *Repo) BeginBatch(ctx context.Context) (Batch, error) {
+	tx := r.DB.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		log.Info().Err(tx.Error).Send()
+		return nil, dbError(tx.Error, "could not start the import")
+	}
+	return &batch{tx: tx}, nil
+}
+
+func (b *batch) CompanyByName(name string) (models.Company, bool, error) {
+	var companyData models.Company
+	result := b.tx.Where("name = ?", name).Limit(1).Find(&companyData)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.Company{}, false, dbError(result.Error, "could not find the company")
+	}
+	return companyData, result.RowsAffected > 0, nil
+}
+
+func (b *batch) CreateCompany(companyData models.Company, ownerID uint) (models.Company, error) {
+	err := b.row(func(tx *gorm.DB) error {
+		result := tx.Create(&companyData)
+		if result.Error != nil {
+			return result.Error
+		}
+		return tx.Create(&models.Membership{CompanyID: companyData.ID, UserID: ownerID, Role: models.RoleOwner}).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.Company{}, dbError(err, "could not create the company")
+	}
+	return companyData, nil
+}
+
+func (b *batch) JobExists(cid uint, name string) (bool, error) {
+	var count int64
+	result := b.tx.Model(&models.Jobs{}).Where("cid = ? AND name = ?", cid, name).Count(&count)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return false, dbError(result.Error, "could not find the job")
+	}
+	return count > 0, nil
+}
+
+func (b *batch) CreateJob(jobData models.Jobs) (models.Jobs, error) {
+	err := b.row(func(tx *gorm.DB) error {
+		return tx.Create(&jobData).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.Jobs{}, dbError(err, "could not create the job")
+	}
+	return jobData, nil
+}
+
+func (b *batch) AppendEvents(events ...models.OutboxEvent) {
+	b.events = append(b.events, events...)
+}
+
+// Commit writes the appended events and commits them with the rows, a batch that
+// cannot write its events is rolled back
+func (b *batch) Commit() error {
+	if len(b.events) > 0 {
+		err := b.tx.Create(&b.events).Error
+		if err != nil {
+			log.Info().Err(err).Send()
+			return errors.Join(dbError(err, "could not record the events of the import"), b.Rollback())
+		}
+	}
+	err := b.tx.Commit().Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return dbError(err, "could not commit the import")
+	}
+	return nil
+}
+
+func (b *batch) Rollback() error {
+	err := b.tx.Rollback().Error
+	if err != nil && !errors.Is(err, gorm.ErrInvalidTransaction) {
+		log.Info().Err(err).Send()
+		return dbError(err, "could not roll back the import")
+	}
+	return nil
+}
+
+// row runs write behind a savepoint, a failing statement aborts the whole
+// transaction in postgres unless it is rolled back to before the write
+func (b *batch) row(write func(tx *gorm.DB) error) error {
+	err := b.tx.SavePoint(rowSavepoint).Error
+	if err != nil {
+		return err
+	}
+	err = write(b.tx)
+	if err != nil {
+		return errors.Join(err, b.tx.RollbackTo(rowSavepoint).Error)
+	}
+	return b.tx.Exec("RELEASE SAVEPOINT " + rowSavepoint).Error
+}
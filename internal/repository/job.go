@@ -2,18 +2,18 @@ package repository
 
 import (
 	"context"
-	"errors"
 	"project/internal/models"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
 func (r *Repo) Jobbyjid(ctx context.Context, jid uint64) (models.Jobs, error) {
 	var jobData models.Jobs
-	result := r.DB.Where("id = ?", jid).Find(&jobData)
+	result := r.DB.Where("id = ?", jid).First(&jobData)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
-		return models.Jobs{}, errors.New("could not create the jobs")
+		return models.Jobs{}, dbError(result.Error, "could not find the job")
 	}
 	return jobData, nil
 }
@@ -22,27 +22,84 @@ func (r *Repo) CreateUserJob(ctx context.Context, jobData models.Jobs) (models.J
 	result := r.DB.Create(&jobData)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
-		return models.Jobs{}, errors.New("could not create the jobs")
+		return models.Jobs{}, dbError(result.Error, "could not create the jobs")
 	}
 	return jobData, nil
 }
 
+// FetchAllJobs returns the published jobs, drafts and closed jobs are not listed
 func (r *Repo) FetchAllJobs(ctx context.Context) ([]models.Jobs, error) {
 	var jobDatas []models.Jobs
-	result := r.DB.Find(&jobDatas)
+	result := r.DB.Where("status = ?", models.JobPublished).Find(&jobDatas)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
-		return nil, errors.New("could not find the jobs")
+		return nil, dbError(result.Error, "could not find the jobs")
 	}
 	return jobDatas, nil
 }
 
+// Jobbycid returns the published jobs of the company
 func (r *Repo) Jobbycid(ctx context.Context, cid uint64) ([]models.Jobs, error) {
 	var jobData []models.Jobs
-	result := r.DB.Where("cid = ?", cid).Find(&jobData)
+	result := r.DB.Where("cid = ? AND status = ?", cid, models.JobPublished).Find(&jobData)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
-		return nil, errors.New("could not find the company")
+		return nil, dbError(result.Error, "could not find the company")
 	}
 	return jobData, nil
 }
+
+// JobsByCids returns the published jobs of the companies
+func (r *Repo) JobsByCids(ctx context.Context, cids []uint) ([]models.Jobs, error) {
+	var jobDatas []models.Jobs
+	if len(cids) == 0 {
+		return jobDatas, nil
+	}
+	result := r.DB.WithContext(ctx).Where("cid IN ? AND status = ?", cids, models.JobPublished).Order("id").Find(&jobDatas)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the jobs")
+	}
+	return jobDatas, nil
+}
+
+// PublishedJobs returns the published jobs, newest first
+func (r *Repo) PublishedJobs(ctx context.Context, limit int) ([]models.Jobs, error) {
+	var jobDatas []models.Jobs
+	result := r.DB.WithContext(ctx).Where("status = ?", models.JobPublished).Order("created_at DESC").Limit(limit).Find(&jobDatas)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the jobs")
+	}
+	return jobDatas, nil
+}
+
+// UpdateJob replaces the fields of the job if it was last updated at version,
+// the company of a job never changes
+func (r *Repo) UpdateJob(ctx context.Context, jobData models.Jobs, version time.Time) (models.Jobs, error) {
+	result := r.DB.WithContext(ctx).Model(&jobData).
+		Where("updated_at = ?", version).
+		Select("*").Omit("id", "cid", "created_at", "deleted_at", "Company").
+		Updates(&jobData)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.Jobs{}, dbError(result.Error, "could not update the job")
+	}
+	if result.RowsAffected == 0 {
+		return models.Jobs{}, errJobChanged
+	}
+	return r.Jobbyjid(ctx, uint64(jobData.ID))
+}
+
+// DeleteJob deletes the job if it was last updated at version
+func (r *Repo) DeleteJob(ctx context.Context, jid uint, version time.Time) error {
+	result := r.DB.WithContext(ctx).Where("id = ? AND updated_at = ?", jid, version).Delete(&models.Jobs{})
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not delete the job")
+	}
+	if result.RowsAffected == 0 {
+		return errJobChanged
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"project/internal/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// notificationListSize is how many notifications a user is shown, newest first
+const notificationListSize = 100
+
+func (r *Repo) CreateNotification(ctx context.Context, n models.Notification) (models.Notification, error) {
+	result := r.DB.WithContext(ctx).Create(&n)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.Notification{}, dbError(result.Error, "could not create the notification")
+	}
+	return n, nil
+}
+
+func (r *Repo) Notifications(ctx context.Context, userID uint, unreadOnly bool) ([]models.Notification, error) {
+	var notifications []models.Notification
+	db := r.DB.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		db = db.Where("read_at IS NULL")
+	}
+	result := db.Order("id DESC").Limit(notificationListSize).Find(&notifications)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the notifications")
+	}
+	return notifications, nil
+}
+
+// MarkNotifications sets when the notifications of the user were read, nil marks
+// them unread. Ids of notifications of other users are ignored
+func (r *Repo) MarkNotifications(ctx context.Context, userID uint, ids []uint, readAt *time.Time) error {
+	result := r.DB.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Update("read_at", readAt)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not update the notifications")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"project/internal/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Transaction runs fn with a repository whose writes are committed together when
+// fn returns nil and rolled back otherwise. fn should not hold on to tx
+func (r *Repo) Transaction(ctx context.Context, fn func(tx UserRepo) error) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&Repo{DB: tx})
+	})
+}
+
+// AppendEvents writes events to the outbox, inside Transaction they commit with
+// the change they describe
+func (r *Repo) AppendEvents(ctx context.Context, events []models.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	result := r.DB.WithContext(ctx).Create(&events)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not record the events")
+	}
+	return nil
+}
+
+// ClaimEvents takes up to limit pending events due at now and holds them until
+// the lease ends. Only the oldest pending event of an aggregate can be claimed,
+// the ones after it wait until it is delivered or dead
+func (r *Repo) ClaimEvents(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxPending, now).
+			Where("NOT EXISTS (SELECT 1 FROM outbox_events earlier WHERE earlier.aggregate = outbox_events.aggregate "+
+				"AND earlier.status = ? AND earlier.id < outbox_events.id)", models.OutboxPending).
+			Order("id").Limit(limit).Find(&events)
+		if result.Error != nil || len(events) == 0 {
+			return result.Error
+		}
+
+		ids := make([]uint, 0, len(events))
+		for i := range events {
+			ids = append(ids, events[i].ID)
+			events[i].NextAttemptAt = lease
+		}
+		return tx.Model(&models.OutboxEvent{}).Where("id IN ?", ids).
+			Update("next_attempt_at", lease).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, dbError(err, "could not claim the events")
+	}
+	return events, nil
+}
+
+// SaveEvent stores how handing the event to its subscribers went
+func (r *Repo) SaveEvent(ctx context.Context, event models.OutboxEvent) error {
+	result := r.DB.WithContext(ctx).Model(&event).
+		Select("status", "attempts", "next_attempt_at", "handled", "last_error", "delivered_at").
+		Updates(&event)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not save the event")
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"project/internal/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm/clause"
+)
+
+// profileColumns are replaced when a profile is saved again
+var profileColumns = []string{
+	"updated_at", "headline", "skills", "location", "latitude", "longitude", "expected_salary", "seniority",
+	"current_employer", "experience_years", "availability", "discoverable", "hide_current_employer", "hide_expected_salary",
+}
+
+func (r *Repo) ProfileByUserID(ctx context.Context, userID uint) (models.Profile, error) {
+	var profile models.Profile
+	result := r.DB.WithContext(ctx).Where("user_id = ?", userID).First(&profile)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.Profile{}, dbError(result.Error, "profile not found")
+	}
+	return profile, nil
+}
+
+// SaveProfile creates the profile of the user when version is zero, otherwise it
+// replaces the profile if it was last saved at version
+func (r *Repo) SaveProfile(ctx context.Context, profile models.Profile, version time.Time) (models.Profile, error) {
+	conflict := clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}}, DoNothing: true}
+	if !version.IsZero() {
+		conflict = clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns(profileColumns),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "profiles.updated_at = ?", Vars: []interface{}{version}},
+			}},
+		}
+	}
+	result := r.DB.WithContext(ctx).Clauses(conflict).Create(&profile)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.Profile{}, dbError(result.Error, "could not save the profile")
+	}
+	if result.RowsAffected == 0 {
+		return models.Profile{}, errProfileChanged
+	}
+	return r.ProfileByUserID(ctx, profile.UserID)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"project/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -16,15 +17,90 @@ type Repo struct {
 type UserRepo interface {
 	CreateUser(ctx context.Context, userData models.User) (models.User, error)
 	Userbyemail(ctx context.Context, email string) (models.User, error)
+	UserByID(ctx context.Context, userID uint) (models.User, error)
 
-	CreateUserCompany(ctx context.Context, companyData models.Company) (models.Company, error)
+	CreateUserCompany(ctx context.Context, companyData models.Company, ownerID uint) (models.Company, error)
 	Companies(ctx context.Context) ([]models.Company, error)
 	CompanyById(ctx context.Context, cid uint64) (models.Company, error)
+	CompaniesByIds(ctx context.Context, ids []uint) ([]models.Company, error)
+	UpdateCompany(ctx context.Context, companyData models.Company, version time.Time) (models.Company, error)
+	DeleteCompany(ctx context.Context, cid uint, version time.Time) error
 
 	CreateUserJob(ctx context.Context, jobData models.Jobs) (models.Jobs, error)
 	Jobbycid(ctx context.Context, cid uint64) ([]models.Jobs, error)
 	FetchAllJobs(ctx context.Context) ([]models.Jobs, error)
 	Jobbyjid(ctx context.Context, jid uint64) (models.Jobs, error)
+	JobsByCids(ctx context.Context, cids []uint) ([]models.Jobs, error)
+	PublishedJobs(ctx context.Context, limit int) ([]models.Jobs, error)
+	FeedJobs(ctx context.Context, filter models.JobFilter, limit int) ([]models.Jobs, time.Time, error)
+	SitemapState(ctx context.Context) (int64, time.Time, error)
+	SitemapJobs(ctx context.Context, offset, limit int) ([]models.SitemapJob, error)
+	UpdateJob(ctx context.Context, jobData models.Jobs, version time.Time) (models.Jobs, error)
+	DeleteJob(ctx context.Context, jid uint, version time.Time) error
+
+	ProfileByUserID(ctx context.Context, userID uint) (models.Profile, error)
+	SaveProfile(ctx context.Context, profile models.Profile, version time.Time) (models.Profile, error)
+
+	CreateMembership(ctx context.Context, membership models.Membership) (models.Membership, error)
+	MembershipsByUser(ctx context.Context, userID uint) ([]models.Membership, error)
+	VerifyCompany(ctx context.Context, verification models.CompanyVerification) (models.CompanyVerification, error)
+	VerifiedCompanies(ctx context.Context, ids []uint) ([]uint, error)
+	SearchCandidates(ctx context.Context, filter models.TalentFilter) ([]models.Profile, int64, error)
+	RecordProfileViews(ctx context.Context, views []models.ProfileView) error
+	ProfileViews(ctx context.Context, profileUserID uint) ([]models.ProfileView, error)
+	SearchJobs(ctx context.Context, filter models.JobFilter) ([]models.Jobs, int64, error)
+	JobFacets(ctx context.Context, filter models.JobFilter) (models.JobFacets, error)
+	SuggestJobTerm(ctx context.Context, query string, threshold float64) (string, error)
+	StreamJobs(ctx context.Context, filter models.JobFilter, fn func(models.Jobs) error) error
+	StreamPublishedJobs(ctx context.Context, fn func(models.Jobs) error) error
+	StreamCompanies(ctx context.Context, fn func(models.Company) error) error
+
+	BeginBatch(ctx context.Context) (Batch, error)
+	Transaction(ctx context.Context, fn func(tx UserRepo) error) error
+
+	CreateWebhook(ctx context.Context, hook models.Webhook) (models.Webhook, error)
+	WebhooksByCompany(ctx context.Context, cid uint) ([]models.Webhook, error)
+	WebhookByID(ctx context.Context, cid uint, id uint) (models.Webhook, error)
+	DeleteWebhook(ctx context.Context, cid uint, id uint) error
+	EnqueueDeliveries(ctx context.Context, cid uint, event string, key string, payload string) error
+	WebhookDeliveries(ctx context.Context, webhookID uint) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, webhookID uint, id uint) (models.WebhookDelivery, error)
+	ClaimDeliveries(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.DueDelivery, error)
+	SaveAttempt(ctx context.Context, delivery models.WebhookDelivery) error
+
+	CreateConnector(ctx context.Context, connector models.ATSConnector) (models.ATSConnector, error)
+	ConnectorsByCompany(ctx context.Context, cid uint) ([]models.ATSConnector, error)
+	DeleteConnector(ctx context.Context, cid uint, id uint) error
+	ClaimConnectors(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.ATSConnector, error)
+	SaveSync(ctx context.Context, connector models.ATSConnector) error
+	JobsByExternalIDPrefix(ctx context.Context, cid uint, prefix string) ([]models.Jobs, error)
+
+	QueueEmails(ctx context.Context, emails []models.Email) error
+	ClaimEmails(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.Email, error)
+	SaveEmail(ctx context.Context, e models.Email) error
+	Unsubscribe(ctx context.Context, userID uint, at time.Time) (models.User, error)
+	CompanyMembers(ctx context.Context, cid uint) ([]models.User, error)
+
+	CreateNotification(ctx context.Context, n models.Notification) (models.Notification, error)
+	Notifications(ctx context.Context, userID uint, unreadOnly bool) ([]models.Notification, error)
+	MarkNotifications(ctx context.Context, userID uint, ids []uint, readAt *time.Time) error
+
+	AppendEvents(ctx context.Context, events []models.OutboxEvent) error
+	ClaimEvents(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.OutboxEvent, error)
+	SaveEvent(ctx context.Context, event models.OutboxEvent) error
+}
+
+// Batch writes the rows of an import in one transaction. Each write is undone on
+// its own when it fails so the other rows can still be committed. The events
+// appended are written when the batch commits
+type Batch interface {
+	CompanyByName(name string) (models.Company, bool, error)
+	CreateCompany(companyData models.Company, ownerID uint) (models.Company, error)
+	JobExists(cid uint, name string) (bool, error)
+	CreateJob(jobData models.Jobs) (models.Jobs, error)
+	AppendEvents(events ...models.OutboxEvent)
+	Commit() error
+	Rollback() error
 }
 
 func NewRepository(db *gorm.DB) (UserRepo, error) {
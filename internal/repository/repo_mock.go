@@ -12,6 +12,7 @@ import (
 	context "context"
 	models "project/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -39,6 +40,95 @@ func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
 	return m.recorder
 }
 
+// AppendEvents mocks base method.
+func (m *MockUserRepo) AppendEvents(ctx context.Context, events []models.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendEvents", ctx, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendEvents indicates an expected call of AppendEvents.
+func (mr *MockUserRepoMockRecorder) AppendEvents(ctx, events any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendEvents", reflect.TypeOf((*MockUserRepo)(nil).AppendEvents), ctx, events)
+}
+
+// BeginBatch mocks base method.
+func (m *MockUserRepo) BeginBatch(ctx context.Context) (Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginBatch", ctx)
+	ret0, _ := ret[0].(Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginBatch indicates an expected call of BeginBatch.
+func (mr *MockUserRepoMockRecorder) BeginBatch(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginBatch", reflect.TypeOf((*MockUserRepo)(nil).BeginBatch), ctx)
+}
+
+// ClaimConnectors mocks base method.
+func (m *MockUserRepo) ClaimConnectors(ctx context.Context, now, lease time.Time, limit int) ([]models.ATSConnector, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimConnectors", ctx, now, lease, limit)
+	ret0, _ := ret[0].([]models.ATSConnector)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimConnectors indicates an expected call of ClaimConnectors.
+func (mr *MockUserRepoMockRecorder) ClaimConnectors(ctx, now, lease, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimConnectors", reflect.TypeOf((*MockUserRepo)(nil).ClaimConnectors), ctx, now, lease, limit)
+}
+
+// ClaimDeliveries mocks base method.
+func (m *MockUserRepo) ClaimDeliveries(ctx context.Context, now, lease time.Time, limit int) ([]models.DueDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveries", ctx, now, lease, limit)
+	ret0, _ := ret[0].([]models.DueDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveries indicates an expected call of ClaimDeliveries.
+func (mr *MockUserRepoMockRecorder) ClaimDeliveries(ctx, now, lease, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveries", reflect.TypeOf((*MockUserRepo)(nil).ClaimDeliveries), ctx, now, lease, limit)
+}
+
+// ClaimEmails mocks base method.
+func (m *MockUserRepo) ClaimEmails(ctx context.Context, now, lease time.Time, limit int) ([]models.Email, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimEmails", ctx, now, lease, limit)
+	ret0, _ := ret[0].([]models.Email)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimEmails indicates an expected call of ClaimEmails.
+func (mr *MockUserRepoMockRecorder) ClaimEmails(ctx, now, lease, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimEmails", reflect.TypeOf((*MockUserRepo)(nil).ClaimEmails), ctx, now, lease, limit)
+}
+
+// ClaimEvents mocks base method.
+func (m *MockUserRepo) ClaimEvents(ctx context.Context, now, lease time.Time, limit int) ([]models.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimEvents", ctx, now, lease, limit)
+	ret0, _ := ret[0].([]models.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimEvents indicates an expected call of ClaimEvents.
+func (mr *MockUserRepoMockRecorder) ClaimEvents(ctx, now, lease, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimEvents", reflect.TypeOf((*MockUserRepo)(nil).ClaimEvents), ctx, now, lease, limit)
+}
+
 // Companies mocks base method.
 func (m *MockUserRepo) Companies(ctx context.Context) ([]models.Company, error) {
 	m.ctrl.T.Helper()
@@ -54,6 +144,21 @@ func (mr *MockUserRepoMockRecorder) Companies(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Companies", reflect.TypeOf((*MockUserRepo)(nil).Companies), ctx)
 }
 
+// CompaniesByIds mocks base method.
+func (m *MockUserRepo) CompaniesByIds(ctx context.Context, ids []uint) ([]models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompaniesByIds", ctx, ids)
+	ret0, _ := ret[0].([]models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompaniesByIds indicates an expected call of CompaniesByIds.
+func (mr *MockUserRepoMockRecorder) CompaniesByIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompaniesByIds", reflect.TypeOf((*MockUserRepo)(nil).CompaniesByIds), ctx, ids)
+}
+
 // CompanyById mocks base method.
 func (m *MockUserRepo) CompanyById(ctx context.Context, cid uint64) (models.Company, error) {
 	m.ctrl.T.Helper()
@@ -69,6 +174,81 @@ func (mr *MockUserRepoMockRecorder) CompanyById(ctx, cid any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompanyById", reflect.TypeOf((*MockUserRepo)(nil).CompanyById), ctx, cid)
 }
 
+// CompanyMembers mocks base method.
+func (m *MockUserRepo) CompanyMembers(ctx context.Context, cid uint) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompanyMembers", ctx, cid)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompanyMembers indicates an expected call of CompanyMembers.
+func (mr *MockUserRepoMockRecorder) CompanyMembers(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompanyMembers", reflect.TypeOf((*MockUserRepo)(nil).CompanyMembers), ctx, cid)
+}
+
+// ConnectorsByCompany mocks base method.
+func (m *MockUserRepo) ConnectorsByCompany(ctx context.Context, cid uint) ([]models.ATSConnector, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectorsByCompany", ctx, cid)
+	ret0, _ := ret[0].([]models.ATSConnector)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConnectorsByCompany indicates an expected call of ConnectorsByCompany.
+func (mr *MockUserRepoMockRecorder) ConnectorsByCompany(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectorsByCompany", reflect.TypeOf((*MockUserRepo)(nil).ConnectorsByCompany), ctx, cid)
+}
+
+// CreateConnector mocks base method.
+func (m *MockUserRepo) CreateConnector(ctx context.Context, connector models.ATSConnector) (models.ATSConnector, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConnector", ctx, connector)
+	ret0, _ := ret[0].(models.ATSConnector)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConnector indicates an expected call of CreateConnector.
+func (mr *MockUserRepoMockRecorder) CreateConnector(ctx, connector any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConnector", reflect.TypeOf((*MockUserRepo)(nil).CreateConnector), ctx, connector)
+}
+
+// CreateMembership mocks base method.
+func (m *MockUserRepo) CreateMembership(ctx context.Context, membership models.Membership) (models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMembership", ctx, membership)
+	ret0, _ := ret[0].(models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMembership indicates an expected call of CreateMembership.
+func (mr *MockUserRepoMockRecorder) CreateMembership(ctx, membership any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMembership", reflect.TypeOf((*MockUserRepo)(nil).CreateMembership), ctx, membership)
+}
+
+// CreateNotification mocks base method.
+func (m *MockUserRepo) CreateNotification(ctx context.Context, n models.Notification) (models.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", ctx, n)
+	ret0, _ := ret[0].(models.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockUserRepoMockRecorder) CreateNotification(ctx, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockUserRepo)(nil).CreateNotification), ctx, n)
+}
+
 // CreateUser mocks base method.
 func (m *MockUserRepo) CreateUser(ctx context.Context, userData models.User) (models.User, error) {
 	m.ctrl.T.Helper()
@@ -85,18 +265,18 @@ func (mr *MockUserRepoMockRecorder) CreateUser(ctx, userData any) *gomock.Call {
 }
 
 // CreateUserCompany mocks base method.
-func (m *MockUserRepo) CreateUserCompany(ctx context.Context, companyData models.Company) (models.Company, error) {
+func (m *MockUserRepo) CreateUserCompany(ctx context.Context, companyData models.Company, ownerID uint) (models.Company, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUserCompany", ctx, companyData)
+	ret := m.ctrl.Call(m, "CreateUserCompany", ctx, companyData, ownerID)
 	ret0, _ := ret[0].(models.Company)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateUserCompany indicates an expected call of CreateUserCompany.
-func (mr *MockUserRepoMockRecorder) CreateUserCompany(ctx, companyData any) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) CreateUserCompany(ctx, companyData, ownerID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserCompany", reflect.TypeOf((*MockUserRepo)(nil).CreateUserCompany), ctx, companyData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserCompany", reflect.TypeOf((*MockUserRepo)(nil).CreateUserCompany), ctx, companyData, ownerID)
 }
 
 // CreateUserJob mocks base method.
@@ -114,6 +294,107 @@ func (mr *MockUserRepoMockRecorder) CreateUserJob(ctx, jobData any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserJob", reflect.TypeOf((*MockUserRepo)(nil).CreateUserJob), ctx, jobData)
 }
 
+// CreateWebhook mocks base method.
+func (m *MockUserRepo) CreateWebhook(ctx context.Context, hook models.Webhook) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, hook)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockUserRepoMockRecorder) CreateWebhook(ctx, hook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockUserRepo)(nil).CreateWebhook), ctx, hook)
+}
+
+// DeleteCompany mocks base method.
+func (m *MockUserRepo) DeleteCompany(ctx context.Context, cid uint, version time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompany", ctx, cid, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCompany indicates an expected call of DeleteCompany.
+func (mr *MockUserRepoMockRecorder) DeleteCompany(ctx, cid, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompany", reflect.TypeOf((*MockUserRepo)(nil).DeleteCompany), ctx, cid, version)
+}
+
+// DeleteConnector mocks base method.
+func (m *MockUserRepo) DeleteConnector(ctx context.Context, cid, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConnector", ctx, cid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConnector indicates an expected call of DeleteConnector.
+func (mr *MockUserRepoMockRecorder) DeleteConnector(ctx, cid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConnector", reflect.TypeOf((*MockUserRepo)(nil).DeleteConnector), ctx, cid, id)
+}
+
+// DeleteJob mocks base method.
+func (m *MockUserRepo) DeleteJob(ctx context.Context, jid uint, version time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteJob", ctx, jid, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteJob indicates an expected call of DeleteJob.
+func (mr *MockUserRepoMockRecorder) DeleteJob(ctx, jid, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJob", reflect.TypeOf((*MockUserRepo)(nil).DeleteJob), ctx, jid, version)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockUserRepo) DeleteWebhook(ctx context.Context, cid, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, cid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockUserRepoMockRecorder) DeleteWebhook(ctx, cid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockUserRepo)(nil).DeleteWebhook), ctx, cid, id)
+}
+
+// EnqueueDeliveries mocks base method.
+func (m *MockUserRepo) EnqueueDeliveries(ctx context.Context, cid uint, event, key, payload string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeliveries", ctx, cid, event, key, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueDeliveries indicates an expected call of EnqueueDeliveries.
+func (mr *MockUserRepoMockRecorder) EnqueueDeliveries(ctx, cid, event, key, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeliveries", reflect.TypeOf((*MockUserRepo)(nil).EnqueueDeliveries), ctx, cid, event, key, payload)
+}
+
+// FeedJobs mocks base method.
+func (m *MockUserRepo) FeedJobs(ctx context.Context, filter models.JobFilter, limit int) ([]models.Jobs, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FeedJobs", ctx, filter, limit)
+	ret0, _ := ret[0].([]models.Jobs)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FeedJobs indicates an expected call of FeedJobs.
+func (mr *MockUserRepoMockRecorder) FeedJobs(ctx, filter, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeedJobs", reflect.TypeOf((*MockUserRepo)(nil).FeedJobs), ctx, filter, limit)
+}
+
 // FetchAllJobs mocks base method.
 func (m *MockUserRepo) FetchAllJobs(ctx context.Context) ([]models.Jobs, error) {
 	m.ctrl.T.Helper()
@@ -129,6 +410,21 @@ func (mr *MockUserRepoMockRecorder) FetchAllJobs(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAllJobs", reflect.TypeOf((*MockUserRepo)(nil).FetchAllJobs), ctx)
 }
 
+// JobFacets mocks base method.
+func (m *MockUserRepo) JobFacets(ctx context.Context, filter models.JobFilter) (models.JobFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobFacets", ctx, filter)
+	ret0, _ := ret[0].(models.JobFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobFacets indicates an expected call of JobFacets.
+func (mr *MockUserRepoMockRecorder) JobFacets(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobFacets", reflect.TypeOf((*MockUserRepo)(nil).JobFacets), ctx, filter)
+}
+
 // Jobbycid mocks base method.
 func (m *MockUserRepo) Jobbycid(ctx context.Context, cid uint64) ([]models.Jobs, error) {
 	m.ctrl.T.Helper()
@@ -159,6 +455,433 @@ func (mr *MockUserRepoMockRecorder) Jobbyjid(ctx, jid any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Jobbyjid", reflect.TypeOf((*MockUserRepo)(nil).Jobbyjid), ctx, jid)
 }
 
+// JobsByCids mocks base method.
+func (m *MockUserRepo) JobsByCids(ctx context.Context, cids []uint) ([]models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobsByCids", ctx, cids)
+	ret0, _ := ret[0].([]models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobsByCids indicates an expected call of JobsByCids.
+func (mr *MockUserRepoMockRecorder) JobsByCids(ctx, cids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobsByCids", reflect.TypeOf((*MockUserRepo)(nil).JobsByCids), ctx, cids)
+}
+
+// JobsByExternalIDPrefix mocks base method.
+func (m *MockUserRepo) JobsByExternalIDPrefix(ctx context.Context, cid uint, prefix string) ([]models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobsByExternalIDPrefix", ctx, cid, prefix)
+	ret0, _ := ret[0].([]models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobsByExternalIDPrefix indicates an expected call of JobsByExternalIDPrefix.
+func (mr *MockUserRepoMockRecorder) JobsByExternalIDPrefix(ctx, cid, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobsByExternalIDPrefix", reflect.TypeOf((*MockUserRepo)(nil).JobsByExternalIDPrefix), ctx, cid, prefix)
+}
+
+// MarkNotifications mocks base method.
+func (m *MockUserRepo) MarkNotifications(ctx context.Context, userID uint, ids []uint, readAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotifications", ctx, userID, ids, readAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotifications indicates an expected call of MarkNotifications.
+func (mr *MockUserRepoMockRecorder) MarkNotifications(ctx, userID, ids, readAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotifications", reflect.TypeOf((*MockUserRepo)(nil).MarkNotifications), ctx, userID, ids, readAt)
+}
+
+// MembershipsByUser mocks base method.
+func (m *MockUserRepo) MembershipsByUser(ctx context.Context, userID uint) ([]models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MembershipsByUser", ctx, userID)
+	ret0, _ := ret[0].([]models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MembershipsByUser indicates an expected call of MembershipsByUser.
+func (mr *MockUserRepoMockRecorder) MembershipsByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MembershipsByUser", reflect.TypeOf((*MockUserRepo)(nil).MembershipsByUser), ctx, userID)
+}
+
+// Notifications mocks base method.
+func (m *MockUserRepo) Notifications(ctx context.Context, userID uint, unreadOnly bool) ([]models.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notifications", ctx, userID, unreadOnly)
+	ret0, _ := ret[0].([]models.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Notifications indicates an expected call of Notifications.
+func (mr *MockUserRepoMockRecorder) Notifications(ctx, userID, unreadOnly any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notifications", reflect.TypeOf((*MockUserRepo)(nil).Notifications), ctx, userID, unreadOnly)
+}
+
+// ProfileByUserID mocks base method.
+func (m *MockUserRepo) ProfileByUserID(ctx context.Context, userID uint) (models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProfileByUserID", ctx, userID)
+	ret0, _ := ret[0].(models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProfileByUserID indicates an expected call of ProfileByUserID.
+func (mr *MockUserRepoMockRecorder) ProfileByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProfileByUserID", reflect.TypeOf((*MockUserRepo)(nil).ProfileByUserID), ctx, userID)
+}
+
+// ProfileViews mocks base method.
+func (m *MockUserRepo) ProfileViews(ctx context.Context, profileUserID uint) ([]models.ProfileView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProfileViews", ctx, profileUserID)
+	ret0, _ := ret[0].([]models.ProfileView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProfileViews indicates an expected call of ProfileViews.
+func (mr *MockUserRepoMockRecorder) ProfileViews(ctx, profileUserID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProfileViews", reflect.TypeOf((*MockUserRepo)(nil).ProfileViews), ctx, profileUserID)
+}
+
+// PublishedJobs mocks base method.
+func (m *MockUserRepo) PublishedJobs(ctx context.Context, limit int) ([]models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishedJobs", ctx, limit)
+	ret0, _ := ret[0].([]models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishedJobs indicates an expected call of PublishedJobs.
+func (mr *MockUserRepoMockRecorder) PublishedJobs(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishedJobs", reflect.TypeOf((*MockUserRepo)(nil).PublishedJobs), ctx, limit)
+}
+
+// QueueEmails mocks base method.
+func (m *MockUserRepo) QueueEmails(ctx context.Context, emails []models.Email) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueEmails", ctx, emails)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueEmails indicates an expected call of QueueEmails.
+func (mr *MockUserRepoMockRecorder) QueueEmails(ctx, emails any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueEmails", reflect.TypeOf((*MockUserRepo)(nil).QueueEmails), ctx, emails)
+}
+
+// RecordProfileViews mocks base method.
+func (m *MockUserRepo) RecordProfileViews(ctx context.Context, views []models.ProfileView) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordProfileViews", ctx, views)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordProfileViews indicates an expected call of RecordProfileViews.
+func (mr *MockUserRepoMockRecorder) RecordProfileViews(ctx, views any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordProfileViews", reflect.TypeOf((*MockUserRepo)(nil).RecordProfileViews), ctx, views)
+}
+
+// Redeliver mocks base method.
+func (m *MockUserRepo) Redeliver(ctx context.Context, webhookID, id uint) (models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, webhookID, id)
+	ret0, _ := ret[0].(models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockUserRepoMockRecorder) Redeliver(ctx, webhookID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockUserRepo)(nil).Redeliver), ctx, webhookID, id)
+}
+
+// SaveAttempt mocks base method.
+func (m *MockUserRepo) SaveAttempt(ctx context.Context, delivery models.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAttempt", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAttempt indicates an expected call of SaveAttempt.
+func (mr *MockUserRepoMockRecorder) SaveAttempt(ctx, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAttempt", reflect.TypeOf((*MockUserRepo)(nil).SaveAttempt), ctx, delivery)
+}
+
+// SaveEmail mocks base method.
+func (m *MockUserRepo) SaveEmail(ctx context.Context, e models.Email) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveEmail", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveEmail indicates an expected call of SaveEmail.
+func (mr *MockUserRepoMockRecorder) SaveEmail(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveEmail", reflect.TypeOf((*MockUserRepo)(nil).SaveEmail), ctx, e)
+}
+
+// SaveEvent mocks base method.
+func (m *MockUserRepo) SaveEvent(ctx context.Context, event models.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveEvent indicates an expected call of SaveEvent.
+func (mr *MockUserRepoMockRecorder) SaveEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveEvent", reflect.TypeOf((*MockUserRepo)(nil).SaveEvent), ctx, event)
+}
+
+// SaveProfile mocks base method.
+func (m *MockUserRepo) SaveProfile(ctx context.Context, profile models.Profile, version time.Time) (models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveProfile", ctx, profile, version)
+	ret0, _ := ret[0].(models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveProfile indicates an expected call of SaveProfile.
+func (mr *MockUserRepoMockRecorder) SaveProfile(ctx, profile, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProfile", reflect.TypeOf((*MockUserRepo)(nil).SaveProfile), ctx, profile, version)
+}
+
+// SaveSync mocks base method.
+func (m *MockUserRepo) SaveSync(ctx context.Context, connector models.ATSConnector) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSync", ctx, connector)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSync indicates an expected call of SaveSync.
+func (mr *MockUserRepoMockRecorder) SaveSync(ctx, connector any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSync", reflect.TypeOf((*MockUserRepo)(nil).SaveSync), ctx, connector)
+}
+
+// SearchCandidates mocks base method.
+func (m *MockUserRepo) SearchCandidates(ctx context.Context, filter models.TalentFilter) ([]models.Profile, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCandidates", ctx, filter)
+	ret0, _ := ret[0].([]models.Profile)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchCandidates indicates an expected call of SearchCandidates.
+func (mr *MockUserRepoMockRecorder) SearchCandidates(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCandidates", reflect.TypeOf((*MockUserRepo)(nil).SearchCandidates), ctx, filter)
+}
+
+// SearchJobs mocks base method.
+func (m *MockUserRepo) SearchJobs(ctx context.Context, filter models.JobFilter) ([]models.Jobs, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchJobs", ctx, filter)
+	ret0, _ := ret[0].([]models.Jobs)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchJobs indicates an expected call of SearchJobs.
+func (mr *MockUserRepoMockRecorder) SearchJobs(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchJobs", reflect.TypeOf((*MockUserRepo)(nil).SearchJobs), ctx, filter)
+}
+
+// SitemapJobs mocks base method.
+func (m *MockUserRepo) SitemapJobs(ctx context.Context, offset, limit int) ([]models.SitemapJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SitemapJobs", ctx, offset, limit)
+	ret0, _ := ret[0].([]models.SitemapJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SitemapJobs indicates an expected call of SitemapJobs.
+func (mr *MockUserRepoMockRecorder) SitemapJobs(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SitemapJobs", reflect.TypeOf((*MockUserRepo)(nil).SitemapJobs), ctx, offset, limit)
+}
+
+// SitemapState mocks base method.
+func (m *MockUserRepo) SitemapState(ctx context.Context) (int64, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SitemapState", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SitemapState indicates an expected call of SitemapState.
+func (mr *MockUserRepoMockRecorder) SitemapState(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SitemapState", reflect.TypeOf((*MockUserRepo)(nil).SitemapState), ctx)
+}
+
+// StreamCompanies mocks base method.
+func (m *MockUserRepo) StreamCompanies(ctx context.Context, fn func(models.Company) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamCompanies", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamCompanies indicates an expected call of StreamCompanies.
+func (mr *MockUserRepoMockRecorder) StreamCompanies(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamCompanies", reflect.TypeOf((*MockUserRepo)(nil).StreamCompanies), ctx, fn)
+}
+
+// StreamJobs mocks base method.
+func (m *MockUserRepo) StreamJobs(ctx context.Context, filter models.JobFilter, fn func(models.Jobs) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamJobs", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamJobs indicates an expected call of StreamJobs.
+func (mr *MockUserRepoMockRecorder) StreamJobs(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamJobs", reflect.TypeOf((*MockUserRepo)(nil).StreamJobs), ctx, filter, fn)
+}
+
+// StreamPublishedJobs mocks base method.
+func (m *MockUserRepo) StreamPublishedJobs(ctx context.Context, fn func(models.Jobs) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamPublishedJobs", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamPublishedJobs indicates an expected call of StreamPublishedJobs.
+func (mr *MockUserRepoMockRecorder) StreamPublishedJobs(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamPublishedJobs", reflect.TypeOf((*MockUserRepo)(nil).StreamPublishedJobs), ctx, fn)
+}
+
+// SuggestJobTerm mocks base method.
+func (m *MockUserRepo) SuggestJobTerm(ctx context.Context, query string, threshold float64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestJobTerm", ctx, query, threshold)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestJobTerm indicates an expected call of SuggestJobTerm.
+func (mr *MockUserRepoMockRecorder) SuggestJobTerm(ctx, query, threshold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestJobTerm", reflect.TypeOf((*MockUserRepo)(nil).SuggestJobTerm), ctx, query, threshold)
+}
+
+// Transaction mocks base method.
+func (m *MockUserRepo) Transaction(ctx context.Context, fn func(UserRepo) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockUserRepoMockRecorder) Transaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockUserRepo)(nil).Transaction), ctx, fn)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockUserRepo) Unsubscribe(ctx context.Context, userID uint, at time.Time) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", ctx, userID, at)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockUserRepoMockRecorder) Unsubscribe(ctx, userID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockUserRepo)(nil).Unsubscribe), ctx, userID, at)
+}
+
+// UpdateCompany mocks base method.
+func (m *MockUserRepo) UpdateCompany(ctx context.Context, companyData models.Company, version time.Time) (models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCompany", ctx, companyData, version)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCompany indicates an expected call of UpdateCompany.
+func (mr *MockUserRepoMockRecorder) UpdateCompany(ctx, companyData, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCompany", reflect.TypeOf((*MockUserRepo)(nil).UpdateCompany), ctx, companyData, version)
+}
+
+// UpdateJob mocks base method.
+func (m *MockUserRepo) UpdateJob(ctx context.Context, jobData models.Jobs, version time.Time) (models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, jobData, version)
+	ret0, _ := ret[0].(models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockUserRepoMockRecorder) UpdateJob(ctx, jobData, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockUserRepo)(nil).UpdateJob), ctx, jobData, version)
+}
+
+// UserByID mocks base method.
+func (m *MockUserRepo) UserByID(ctx context.Context, userID uint) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserByID", ctx, userID)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserByID indicates an expected call of UserByID.
+func (mr *MockUserRepoMockRecorder) UserByID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserByID", reflect.TypeOf((*MockUserRepo)(nil).UserByID), ctx, userID)
+}
+
 // Userbyemail mocks base method.
 func (m *MockUserRepo) Userbyemail(ctx context.Context, email string) (models.User, error) {
 	m.ctrl.T.Helper()
@@ -173,3 +896,206 @@ func (mr *MockUserRepoMockRecorder) Userbyemail(ctx, email any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Userbyemail", reflect.TypeOf((*MockUserRepo)(nil).Userbyemail), ctx, email)
 }
+
+// VerifiedCompanies mocks base method.
+func (m *MockUserRepo) VerifiedCompanies(ctx context.Context, ids []uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifiedCompanies", ctx, ids)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifiedCompanies indicates an expected call of VerifiedCompanies.
+func (mr *MockUserRepoMockRecorder) VerifiedCompanies(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifiedCompanies", reflect.TypeOf((*MockUserRepo)(nil).VerifiedCompanies), ctx, ids)
+}
+
+// VerifyCompany mocks base method.
+func (m *MockUserRepo) VerifyCompany(ctx context.Context, verification models.CompanyVerification) (models.CompanyVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyCompany", ctx, verification)
+	ret0, _ := ret[0].(models.CompanyVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyCompany indicates an expected call of VerifyCompany.
+func (mr *MockUserRepoMockRecorder) VerifyCompany(ctx, verification any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyCompany", reflect.TypeOf((*MockUserRepo)(nil).VerifyCompany), ctx, verification)
+}
+
+// WebhookByID mocks base method.
+func (m *MockUserRepo) WebhookByID(ctx context.Context, cid, id uint) (models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookByID", ctx, cid, id)
+	ret0, _ := ret[0].(models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WebhookByID indicates an expected call of WebhookByID.
+func (mr *MockUserRepoMockRecorder) WebhookByID(ctx, cid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookByID", reflect.TypeOf((*MockUserRepo)(nil).WebhookByID), ctx, cid, id)
+}
+
+// WebhookDeliveries mocks base method.
+func (m *MockUserRepo) WebhookDeliveries(ctx context.Context, webhookID uint) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookDeliveries", ctx, webhookID)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WebhookDeliveries indicates an expected call of WebhookDeliveries.
+func (mr *MockUserRepoMockRecorder) WebhookDeliveries(ctx, webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookDeliveries", reflect.TypeOf((*MockUserRepo)(nil).WebhookDeliveries), ctx, webhookID)
+}
+
+// WebhooksByCompany mocks base method.
+func (m *MockUserRepo) WebhooksByCompany(ctx context.Context, cid uint) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhooksByCompany", ctx, cid)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WebhooksByCompany indicates an expected call of WebhooksByCompany.
+func (mr *MockUserRepoMockRecorder) WebhooksByCompany(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhooksByCompany", reflect.TypeOf((*MockUserRepo)(nil).WebhooksByCompany), ctx, cid)
+}
+
+// MockBatch is a mock of Batch interface.
+type MockBatch struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchMockRecorder
+}
+
+// MockBatchMockRecorder is the mock recorder for MockBatch.
+type MockBatchMockRecorder struct {
+	mock *MockBatch
+}
+
+// NewMockBatch creates a new mock instance.
+func NewMockBatch(ctrl *gomock.Controller) *MockBatch {
+	mock := &MockBatch{ctrl: ctrl}
+	mock.recorder = &MockBatchMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatch) EXPECT() *MockBatchMockRecorder {
+	return m.recorder
+}
+
+// AppendEvents mocks base method.
+func (m *MockBatch) AppendEvents(events ...models.OutboxEvent) {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "AppendEvents", varargs...)
+}
+
+// AppendEvents indicates an expected call of AppendEvents.
+func (mr *MockBatchMockRecorder) AppendEvents(events ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendEvents", reflect.TypeOf((*MockBatch)(nil).AppendEvents), events...)
+}
+
+// Commit mocks base method.
+func (m *MockBatch) Commit() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockBatchMockRecorder) Commit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockBatch)(nil).Commit))
+}
+
+// CompanyByName mocks base method.
+func (m *MockBatch) CompanyByName(name string) (models.Company, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompanyByName", name)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CompanyByName indicates an expected call of CompanyByName.
+func (mr *MockBatchMockRecorder) CompanyByName(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompanyByName", reflect.TypeOf((*MockBatch)(nil).CompanyByName), name)
+}
+
+// CreateCompany mocks base method.
+func (m *MockBatch) CreateCompany(companyData models.Company, ownerID uint) (models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCompany", companyData, ownerID)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCompany indicates an expected call of CreateCompany.
+func (mr *MockBatchMockRecorder) CreateCompany(companyData, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCompany", reflect.TypeOf((*MockBatch)(nil).CreateCompany), companyData, ownerID)
+}
+
+// CreateJob mocks base method.
+func (m *MockBatch) CreateJob(jobData models.Jobs) (models.Jobs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", jobData)
+	ret0, _ := ret[0].(models.Jobs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockBatchMockRecorder) CreateJob(jobData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockBatch)(nil).CreateJob), jobData)
+}
+
+// JobExists mocks base method.
+func (m *MockBatch) JobExists(cid uint, name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobExists", cid, name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobExists indicates an expected call of JobExists.
+func (mr *MockBatchMockRecorder) JobExists(cid, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobExists", reflect.TypeOf((*MockBatch)(nil).JobExists), cid, name)
+}
+
+// Rollback mocks base method.
+func (m *MockBatch) Rollback() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockBatchMockRecorder) Rollback() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockBatch)(nil).Rollback))
+}
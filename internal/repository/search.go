@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"project/internal/models"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// names of the facets, a facet skips its own filter when it is counted
+const (
+	facetCompany        = "company"
+	facetLocation       = "location"
+	facetEmploymentType = "employment_type"
+	facetSalaryBucket   = "salary_bucket"
+	facetRemotePolicy   = "remote_policy"
+)
+
+func (r *Repo) SearchJobs(ctx context.Context, filter models.JobFilter) ([]models.Jobs, int64, error) {
+	var (
+		jobDatas []models.Jobs
+		total    int64
+	)
+	err := r.searchJobs(ctx, filter, func(db *gorm.DB, q jobQuery) error {
+		var err error
+		if q.scores != nil {
+			jobDatas, total, err = r.searchJobsByScore(db, q)
+			return err
+		}
+
+		result := q.apply(db.Model(&models.Jobs{}), "").Count(&total)
+		if result.Error != nil {
+			log.Info().Err(result.Error).Send()
+			return dbError(result.Error, "could not count the jobs")
+		}
+
+		page := q.apply(db, "")
+		if filter.Query != "" {
+			// best trigram matches first
+			page = page.Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:  "word_similarity(?, jobs.name) DESC",
+				Vars: []interface{}{filter.Query},
+			}})
+		}
+		result = page.Order("jobs.id").
+			Offset((filter.Page - 1) * filter.PageSize).
+			Limit(filter.PageSize).
+			Find(&jobDatas)
+		if result.Error != nil {
+			log.Info().Err(result.Error).Send()
+			return dbError(result.Error, "could not find the jobs")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return jobDatas, total, nil
+}
+
+func (r *Repo) JobFacets(ctx context.Context, filter models.JobFilter) (models.JobFacets, error) {
+	var facets models.JobFacets
+	err := r.searchJobs(ctx, filter, func(db *gorm.DB, q jobQuery) error {
+		var err error
+		facets, err = jobFacets(db, q)
+		return err
+	})
+	if err != nil {
+		return models.JobFacets{}, err
+	}
+	return facets, nil
+}
+
+func jobFacets(db *gorm.DB, q jobQuery) (models.JobFacets, error) {
+	var facets models.JobFacets
+
+	// company facet is labelled with the company name
+	result := q.apply(db.Model(&models.Jobs{}), facetCompany).
+		Select("CAST(jobs.cid AS TEXT) AS value, companies.name AS label, count(*) AS count").
+		Joins("JOIN companies ON companies.id = jobs.cid").
+		Group("jobs.cid, companies.name").
+		Order("count DESC, value").
+		Scan(&facets.Company)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.JobFacets{}, dbError(result.Error, "could not count the company facet")
+	}
+
+	columns := []struct {
+		facet  string
+		column string
+		dest   *[]models.FacetCount
+	}{
+		{facetLocation, "jobs.location", &facets.Location},
+		{facetEmploymentType, "jobs.employment_type", &facets.EmploymentType},
+		{facetRemotePolicy, "jobs.remote_policy", &facets.RemotePolicy},
+	}
+	for _, col := range columns {
+		result = q.apply(db.Model(&models.Jobs{}), col.facet).
+			Select(col.column + " AS value, count(*) AS count").
+			Where(col.column + " <> ''").
+			Group(col.column).
+			Order("count DESC, value").
+			Scan(col.dest)
+		if result.Error != nil {
+			log.Info().Err(result.Error).Send()
+			return models.JobFacets{}, dbError(result.Error, fmt.Sprintf("could not count the %s facet", col.facet))
+		}
+	}
+
+	// jobs without a salary do not belong to any bucket
+	result = q.apply(db.Model(&models.Jobs{}), facetSalaryBucket).
+		Select(salaryBucketCase() + " AS value, count(*) AS count").
+		Where("jobs.min_salary > 0").
+		Group("value").
+		Scan(&facets.SalaryBucket)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.JobFacets{}, dbError(result.Error, "could not count the salary facet")
+	}
+	sort.SliceStable(facets.SalaryBucket, func(i, j int) bool {
+		return bucketIndex(facets.SalaryBucket[i].Value) < bucketIndex(facets.SalaryBucket[j].Value)
+	})
+	return facets, nil
+}
+
+// jobQuery is a job filter prepared for one store, scores is only set when the
+// query is matched in go instead of with trigrams
+type jobQuery struct {
+	filter  models.JobFilter
+	trigram bool
+	scores  map[uint]float64
+}
+
+// apply adds every filter of the query on db except the one belonging to the skip
+// facet, the jobs are only the published ones unless the filter names the states
+func (q jobQuery) apply(db *gorm.DB, skip string) *gorm.DB {
+	f := q.filter
+	if f.Query != "" {
+		if q.trigram {
+			// <% matches at pg_trgm.word_similarity_threshold, see withThreshold
+			db = db.Where("(? <% jobs.name OR jobs.cid IN (SELECT id FROM companies WHERE ? <% companies.name AND deleted_at IS NULL))",
+				f.Query, f.Query)
+		} else {
+			ids := make([]uint, 0, len(q.scores))
+			for id := range q.scores {
+				ids = append(ids, id)
+			}
+			if len(ids) == 0 {
+				// nothing matched, keep the query valid but empty
+				ids = append(ids, 0)
+			}
+			db = db.Where("jobs.id IN ?", ids)
+		}
+	}
+	status := f.Status
+	if len(status) == 0 {
+		status = []string{models.JobPublished}
+	}
+	db = db.Where("jobs.status IN ?", status)
+	if len(f.Cid) > 0 && skip != facetCompany {
+		db = db.Where("jobs.cid IN ?", f.Cid)
+	}
+	if len(f.Location) > 0 && skip != facetLocation {
+		db = db.Where("jobs.location IN ?", f.Location)
+	}
+	if len(f.EmploymentType) > 0 && skip != facetEmploymentType {
+		db = db.Where("jobs.employment_type IN ?", f.EmploymentType)
+	}
+	if len(f.RemotePolicy) > 0 && skip != facetRemotePolicy {
+		db = db.Where("jobs.remote_policy IN ?", f.RemotePolicy)
+	}
+	if len(f.SalaryBucket) > 0 && skip != facetSalaryBucket {
+		var conds []string
+		var args []interface{}
+		for _, key := range f.SalaryBucket {
+			b, ok := models.SalaryBucketByKey(key)
+			if !ok {
+				continue
+			}
+			if b.Max > 0 {
+				conds = append(conds, "(jobs.min_salary >= ? AND jobs.min_salary < ?)")
+				args = append(args, b.Min, b.Max)
+			} else {
+				conds = append(conds, "jobs.min_salary >= ?")
+				args = append(args, b.Min)
+			}
+		}
+		if len(conds) > 0 {
+			db = db.Where("jobs.min_salary > 0 AND ("+strings.Join(conds, " OR ")+")", args...)
+		}
+	}
+	return db
+}
+
+// salaryBucketCase builds the sql expression mapping a min salary to its bucket key,
+// the bounds come from models.SalaryBuckets and never from user input
+func salaryBucketCase() string {
+	var sb strings.Builder
+	sb.WriteString("CASE")
+	for _, b := range models.SalaryBuckets {
+		if b.Max > 0 {
+			fmt.Fprintf(&sb, " WHEN jobs.min_salary < %d THEN '%s'", b.Max, b.Key)
+		} else {
+			fmt.Fprintf(&sb, " ELSE '%s'", b.Key)
+		}
+	}
+	sb.WriteString(" END")
+	return sb.String()
+}
+
+func bucketIndex(key string) int {
+	for i, b := range models.SalaryBuckets {
+		if b.Key == key {
+			return i
+		}
+	}
+	return len(models.SalaryBuckets)
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"project/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils/tests"
+)
+
+// statements records the sql sent to a database answering every query with no rows
+type statements struct {
+	logger.Interface
+	sql []string
+}
+
+func (s *statements) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	stmt, _ := fc()
+	s.sql = append(s.sql, stmt)
+}
+
+// empty is a database driver without any rows
+type empty struct{}
+
+func (empty) Open(string) (driver.Conn, error) { return empty{}, nil }
+func (empty) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("statements are not prepared")
+}
+func (empty) Close() error              { return nil }
+func (empty) Begin() (driver.Tx, error) { return empty{}, nil }
+func (empty) Commit() error             { return nil }
+func (empty) Rollback() error           { return nil }
+func (empty) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return emptyRows{}, nil
+}
+func (empty) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+func init() {
+	sql.Register("empty", empty{})
+}
+
+// emptyRepo is a repo on a database without any rows, recording its sql
+func emptyRepo(t *testing.T) (*Repo, *statements) {
+	conn, err := sql.Open("empty", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &statements{Interface: logger.Discard}
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{ConnPool: conn, Logger: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Repo{DB: db}, s
+}
+
+// Test_Repo_publishedJobsOnly checks that candidates never get drafts or closed
+// jobs, every query reading jobs for them keeps the published ones only
+func Test_Repo_publishedJobsOnly(t *testing.T) {
+	filter := models.JobFilter{Location: []string{"pune"}, Page: 1, PageSize: 20}
+	tests := []struct {
+		name string
+		read func(r *Repo) error
+		want string
+	}{
+		{
+			name: "search",
+			read: func(r *Repo) error {
+				_, _, err := r.SearchJobs(context.Background(), filter)
+				return err
+			},
+			want: `jobs.status IN ("published")`,
+		},
+		{
+			name: "facets",
+			read: func(r *Repo) error {
+				_, err := r.JobFacets(context.Background(), filter)
+				return err
+			},
+			want: `jobs.status IN ("published")`,
+		},
+		{
+			name: "export",
+			read: func(r *Repo) error {
+				return r.StreamJobs(context.Background(), filter, func(models.Jobs) error { return nil })
+			},
+			want: `jobs.status IN ("published")`,
+		},
+		{
+			name: "every job",
+			read: func(r *Repo) error {
+				_, err := r.FetchAllJobs(context.Background())
+				return err
+			},
+			want: `status = "published"`,
+		},
+		{
+			name: "jobs of a company",
+			read: func(r *Repo) error {
+				_, err := r.Jobbycid(context.Background(), 7)
+				return err
+			},
+			want: `status = "published"`,
+		},
+		{
+			name: "jobs of companies",
+			read: func(r *Repo) error {
+				_, err := r.JobsByCids(context.Background(), []uint{7, 8})
+				return err
+			},
+			want: `status = "published"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, s := emptyRepo(t)
+			err := tt.read(r)
+			if err != nil {
+				t.Fatalf("read error = %v", err)
+			}
+			if len(s.sql) == 0 {
+				t.Fatal("no query was sent")
+			}
+			for _, stmt := range s.sql {
+				if !strings.Contains(stmt, tt.want) {
+					t.Errorf("%s\ndoes not keep the published jobs only, want %s", stmt, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// Test_Repo_draftsFiltered checks that a filter naming the states finds them
+func Test_Repo_draftsFiltered(t *testing.T) {
+	r, s := emptyRepo(t)
+	_, _, err := r.SearchJobs(context.Background(), models.JobFilter{Status: []string{models.JobDraft}, Page: 1, PageSize: 20})
+	if err != nil {
+		t.Fatalf("Repo.SearchJobs() error = %v", err)
+	}
+	for _, stmt := range s.sql {
+		if !strings.Contains(stmt, `jobs.status IN ("draft")`) {
+			t.Errorf("%s\ndoes not find the drafts", stmt)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"project/internal/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SitemapState counts the published jobs and returns when any job last changed.
+// Closed and deleted jobs count for the time too, a job leaving the sitemap
+// changes it
+func (r *Repo) SitemapState(ctx context.Context) (int64, time.Time, error) {
+	var count int64
+	result := r.DB.WithContext(ctx).Model(&models.Jobs{}).
+		Where("jobs.status = ?", models.JobPublished).
+		Count(&count)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return 0, time.Time{}, dbError(result.Error, "could not count the jobs")
+	}
+
+	var modified sql.NullTime
+	err := r.DB.WithContext(ctx).Unscoped().Model(&models.Jobs{}).
+		Select("MAX(GREATEST(jobs.updated_at, jobs.deleted_at))").
+		Row().Scan(&modified)
+	if err != nil {
+		log.Info().Err(err).Send()
+		return 0, time.Time{}, dbError(err, "could not find when the jobs changed")
+	}
+	return count, modified.Time, nil
+}
+
+// SitemapJobs returns the ids and update times of up to limit published jobs in
+// the order of their ids, skipping the first offset
+func (r *Repo) SitemapJobs(ctx context.Context, offset, limit int) ([]models.SitemapJob, error) {
+	var jobs []models.SitemapJob
+	result := r.DB.WithContext(ctx).Model(&models.Jobs{}).
+		Select("jobs.id, jobs.updated_at").
+		Where("jobs.status = ?", models.JobPublished).
+		Order("jobs.id").
+		Offset(offset).
+		Limit(limit).
+		Scan(&jobs)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the jobs")
+	}
+	return jobs, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"project/internal/models"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StreamJobs calls fn with every job matching filter, paging is ignored. The jobs
+// are read from a cursor one at a time and fn returning an error stops the stream
+// with that error
+func (r *Repo) StreamJobs(ctx context.Context, filter models.JobFilter, fn func(models.Jobs) error) error {
+	return r.searchJobs(ctx, filter, func(db *gorm.DB, q jobQuery) error {
+		db = q.apply(db.Model(&models.Jobs{}), "")
+		if filter.Query != "" && q.trigram {
+			db = db.Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:  "word_similarity(?, jobs.name) DESC",
+				Vars: []interface{}{filter.Query},
+			}})
+		}
+		return stream(db.Order("jobs.id"), "could not export the jobs", fn)
+	})
+}
+
+// StreamPublishedJobs calls fn with every published job in order of company and
+// id, read like StreamJobs
+func (r *Repo) StreamPublishedJobs(ctx context.Context, fn func(models.Jobs) error) error {
+	db := r.DB.WithContext(ctx).Model(&models.Jobs{}).Where("jobs.status = ?", models.JobPublished)
+	return stream(db.Order("jobs.cid, jobs.id"), "could not export the jobs", fn)
+}
+
+// StreamCompanies calls fn with every company in order of id, read like StreamJobs
+func (r *Repo) StreamCompanies(ctx context.Context, fn func(models.Company) error) error {
+	return stream(r.DB.WithContext(ctx).Model(&models.Company{}).Order("id"), "could not export the companies", fn)
+}
+
+func stream[T any](db *gorm.DB, msg string, fn func(T) error) error {
+	rows, err := db.Rows()
+	if err != nil {
+		log.Info().Err(err).Send()
+		return dbError(err, msg)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var v T
+		err = db.ScanRows(rows, &v)
+		if err != nil {
+			log.Info().Err(err).Send()
+			return dbError(err, msg)
+		}
+		err = fn(v)
+		if err != nil {
+			return err
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		log.Info().Err(err).Send()
+		return dbError(err, msg)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"project/internal/models"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repo) CreateMembership(ctx context.Context, membership models.Membership) (models.Membership, error) {
+	result := r.DB.WithContext(ctx).Create(&membership)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.Membership{}, dbError(result.Error, "could not add the member")
+	}
+	return membership, nil
+}
+
+func (r *Repo) MembershipsByUser(ctx context.Context, userID uint) ([]models.Membership, error) {
+	var memberships []models.Membership
+	result := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&memberships)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the memberships")
+	}
+	return memberships, nil
+}
+
+// VerifyCompany records the verification of the company, verifying it again keeps
+// the first one
+func (r *Repo) VerifyCompany(ctx context.Context, verification models.CompanyVerification) (models.CompanyVerification, error) {
+	result := r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&verification)
+	if result.Error == nil {
+		result = r.DB.WithContext(ctx).Where("company_id = ?", verification.CompanyID).First(&verification)
+	}
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.CompanyVerification{}, dbError(result.Error, "could not verify the company")
+	}
+	return verification, nil
+}
+
+// VerifiedCompanies returns which of the companies are verified
+func (r *Repo) VerifiedCompanies(ctx context.Context, ids []uint) ([]uint, error) {
+	verified := []uint{}
+	if len(ids) == 0 {
+		return verified, nil
+	}
+	result := r.DB.WithContext(ctx).Model(&models.CompanyVerification{}).Where("company_id IN ?", ids).Pluck("company_id", &verified)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the verified companies")
+	}
+	return verified, nil
+}
+
+// likeEscaper makes a search term match itself in a LIKE pattern escaped by \
+var likeEscaper = strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+
+// SearchCandidates only ever returns discoverable profiles
+func (r *Repo) SearchCandidates(ctx context.Context, filter models.TalentFilter) ([]models.Profile, int64, error) {
+	db := r.DB.WithContext(ctx).Model(&models.Profile{}).Where("discoverable = ?", true)
+	if len(filter.Skills) > 0 {
+		skills := make([]string, 0, len(filter.Skills))
+		for _, skill := range filter.Skills {
+			skills = append(skills, strings.ToLower(skill))
+		}
+		b, err := json.Marshal(skills)
+		if err != nil {
+			return nil, 0, err
+		}
+		// skills are stored as a json array, containment checks that all of them are present
+		db = db.Where("lower(skills)::jsonb @> ?::jsonb", string(b))
+	}
+	if filter.Location != "" {
+		db = db.Where(`location ILIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(filter.Location)+"%")
+	}
+	if filter.MinExperience > 0 {
+		db = db.Where("experience_years >= ?", filter.MinExperience)
+	}
+	if filter.MaxExperience > 0 {
+		db = db.Where("experience_years <= ?", filter.MaxExperience)
+	}
+	if len(filter.Availability) > 0 {
+		db = db.Where("availability IN ?", filter.Availability)
+	}
+
+	var total int64
+	result := db.Count(&total)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, 0, dbError(result.Error, "could not count the candidates")
+	}
+	var profiles []models.Profile
+	result = db.Order("updated_at DESC, id").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&profiles)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, 0, dbError(result.Error, "could not find the candidates")
+	}
+	return profiles, total, nil
+}
+
+func (r *Repo) RecordProfileViews(ctx context.Context, views []models.ProfileView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	result := r.DB.WithContext(ctx).Create(&views)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not record the profile views")
+	}
+	return nil
+}
+
+// ProfileViews lists who looked at the profile, newest first
+func (r *Repo) ProfileViews(ctx context.Context, profileUserID uint) ([]models.ProfileView, error) {
+	var views []models.ProfileView
+	result := r.DB.WithContext(ctx).
+		Select("profile_views.*, companies.name AS company_name").
+		Joins("LEFT JOIN companies ON companies.id = profile_views.company_id").
+		Where("profile_views.profile_user_id = ?", profileUserID).
+		Order("profile_views.created_at DESC").
+		Find(&views)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the profile views")
+	}
+	return views, nil
+}
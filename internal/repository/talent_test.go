@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"project/internal/models"
+	"strings"
+	"testing"
+)
+
+// Test_Repo_SearchCandidates_location checks that the wildcards of a location
+// are matched as they are written
+func Test_Repo_SearchCandidates_location(t *testing.T) {
+	r, s := emptyRepo(t)
+	_, _, err := r.SearchCandidates(context.Background(), models.TalentFilter{Location: `pune_1%\`, Page: 1, PageSize: 20})
+	if err != nil {
+		t.Fatalf("Repo.SearchCandidates() error = %v", err)
+	}
+	if len(s.sql) == 0 {
+		t.Fatal("no query was sent")
+	}
+	want := `location ILIKE "%pune\_1\%\\%" ESCAPE '\'`
+	for _, stmt := range s.sql {
+		if !strings.Contains(stmt, want) {
+			t.Errorf("%s\ndoes not match the location as written, want %s", stmt, want)
+		}
+	}
+}
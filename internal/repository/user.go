@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"errors"
 	"project/internal/models"
 
 	"github.com/rs/zerolog/log"
@@ -12,7 +11,7 @@ func (r *Repo) CreateUser(ctx context.Context, UserDetails models.User) (models.
 	result := r.DB.Create(&UserDetails)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
-		return models.User{}, errors.New("could not create the user")
+		return models.User{}, dbError(result.Error, "could not create the user")
 	}
 	return UserDetails, nil
 }
@@ -22,8 +21,18 @@ func (r *Repo) Userbyemail(ctx context.Context, email string) (models.User, erro
 	result := r.DB.Where("email = ?", email).First(&userDetails)
 	if result.Error != nil {
 		log.Info().Err(result.Error).Send()
-		return models.User{}, errors.New("email not found")
+		return models.User{}, dbError(result.Error, "email not found")
 	}
 	return userDetails, nil
 
 }
+
+func (r *Repo) UserByID(ctx context.Context, userID uint) (models.User, error) {
+	var userDetails models.User
+	result := r.DB.WithContext(ctx).First(&userDetails, userID)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.User{}, dbError(result.Error, "user not found")
+	}
+	return userDetails, nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"project/internal/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// deliveryLogSize is how many deliveries of a webhook the log shows, newest first
+const deliveryLogSize = 100
+
+func (r *Repo) CreateWebhook(ctx context.Context, hook models.Webhook) (models.Webhook, error) {
+	result := r.DB.WithContext(ctx).Create(&hook)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.Webhook{}, dbError(result.Error, "could not create the webhook")
+	}
+	return hook, nil
+}
+
+func (r *Repo) WebhooksByCompany(ctx context.Context, cid uint) ([]models.Webhook, error) {
+	var hooks []models.Webhook
+	result := r.DB.WithContext(ctx).Where("cid = ?", cid).Order("id").Find(&hooks)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the webhooks")
+	}
+	return hooks, nil
+}
+
+// WebhookByID finds a webhook of the company, one of another company is not found
+func (r *Repo) WebhookByID(ctx context.Context, cid uint, id uint) (models.Webhook, error) {
+	var hook models.Webhook
+	result := r.DB.WithContext(ctx).Where("id = ? AND cid = ?", id, cid).First(&hook)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.Webhook{}, dbError(result.Error, "webhook not found")
+	}
+	return hook, nil
+}
+
+// DeleteWebhook deletes the webhook, its pending deliveries are dropped when
+// they come due
+func (r *Repo) DeleteWebhook(ctx context.Context, cid uint, id uint) error {
+	result := r.DB.WithContext(ctx).Where("id = ? AND cid = ?", id, cid).Delete(&models.Webhook{})
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not delete the webhook")
+	}
+	if result.RowsAffected == 0 {
+		return dbError(gorm.ErrRecordNotFound, "webhook not found")
+	}
+	return nil
+}
+
+// EnqueueDeliveries queues payload for every webhook of the company subscribed
+// to event, the deliveries are due right away. key names the event, queueing it
+// again adds no delivery to a webhook that already has it
+func (r *Repo) EnqueueDeliveries(ctx context.Context, cid uint, event string, key string, payload string) error {
+	hooks, err := r.WebhooksByCompany(ctx, cid)
+	if err != nil {
+		return err
+	}
+	var deliveries []models.WebhookDelivery
+	now := time.Now()
+	for _, hook := range hooks {
+		if hook.Subscribed(event) {
+			deliveries = append(deliveries, models.WebhookDelivery{
+				Key:           fmt.Sprintf("%s webhook:%d", key, hook.ID),
+				WebhookID:     hook.ID,
+				Event:         event,
+				Payload:       payload,
+				Status:        models.DeliveryPending,
+				NextAttemptAt: now,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	result := r.DB.WithContext(ctx).Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).
+		Create(&deliveries)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not queue the webhook deliveries")
+	}
+	return nil
+}
+
+func (r *Repo) WebhookDeliveries(ctx context.Context, webhookID uint) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	result := r.DB.WithContext(ctx).Where("webhook_id = ?", webhookID).Order("id DESC").Limit(deliveryLogSize).Find(&deliveries)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return nil, dbError(result.Error, "could not find the deliveries")
+	}
+	return deliveries, nil
+}
+
+// Redeliver queues the delivery again with a fresh set of attempts, whatever
+// came of it before
+func (r *Repo) Redeliver(ctx context.Context, webhookID uint, id uint) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	result := r.DB.WithContext(ctx).Model(&delivery).Clauses(clause.Returning{}).
+		Where("id = ? AND webhook_id = ?", id, webhookID).
+		Updates(map[string]interface{}{
+			"status":          models.DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.WebhookDelivery{}, dbError(result.Error, "could not redeliver")
+	}
+	if result.RowsAffected == 0 {
+		return models.WebhookDelivery{}, dbError(gorm.ErrRecordNotFound, "delivery not found")
+	}
+	return delivery, nil
+}
+
+// ClaimDeliveries takes up to limit pending deliveries due at now and holds them
+// until the lease ends, so other dispatchers pass over them while they are sent.
+// A delivery whose webhook was deleted is marked failed instead
+func (r *Repo) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.DueDelivery, error) {
+	var due []models.DueDelivery
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deliveries []models.WebhookDelivery
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+			Order("next_attempt_at").Limit(limit).Find(&deliveries)
+		if result.Error != nil || len(deliveries) == 0 {
+			return result.Error
+		}
+
+		ids := make([]uint, 0, len(deliveries))
+		hookIDs := make([]uint, 0, len(deliveries))
+		for _, d := range deliveries {
+			ids = append(ids, d.ID)
+			hookIDs = append(hookIDs, d.WebhookID)
+		}
+		var hooks []models.Webhook
+		result = tx.Where("id IN ?", hookIDs).Find(&hooks)
+		if result.Error != nil {
+			return result.Error
+		}
+		byID := make(map[uint]models.Webhook, len(hooks))
+		for _, hook := range hooks {
+			byID[hook.ID] = hook
+		}
+
+		var orphans []uint
+		for _, d := range deliveries {
+			hook, ok := byID[d.WebhookID]
+			if !ok {
+				orphans = append(orphans, d.ID)
+				continue
+			}
+			d.NextAttemptAt = lease
+			due = append(due, models.DueDelivery{WebhookDelivery: d, URL: hook.URL, Secret: hook.Secret})
+		}
+		if len(orphans) > 0 {
+			result = tx.Model(&models.WebhookDelivery{}).Where("id IN ?", orphans).
+				Updates(map[string]interface{}{"status": models.DeliveryFailed, "last_error": "the webhook was deleted"})
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).
+			Where("status = ?", models.DeliveryPending).
+			Update("next_attempt_at", lease).Error
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, dbError(err, "could not claim the webhook deliveries")
+	}
+	return due, nil
+}
+
+// SaveAttempt stores how sending the delivery went
+func (r *Repo) SaveAttempt(ctx context.Context, delivery models.WebhookDelivery) error {
+	result := r.DB.WithContext(ctx).Model(&delivery).
+		Select("status", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at").
+		Updates(&delivery)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return dbError(result.Error, "could not save the delivery attempt")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/middleware"
+	"project/internal/rpc/portalpb"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// publicMethods can be called without a token
+var publicMethods = map[string]bool{
+	portalpb.Portal_SignUp_FullMethodName: true,
+	portalpb.Portal_Login_FullMethodName:  true,
+}
+
+// interceptors give every call a trace id and check its token, like the Log and
+// Authenticate middlewares do for http requests
+type interceptors struct {
+	auth auth.UserAuth
+}
+
+func (in *interceptors) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := in.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (in *interceptors) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := in.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate adds the trace id and, unless method is public, the claims of the
+// token sent in the authorization metadata
+func (in *interceptors) authenticate(ctx context.Context, method string) (context.Context, error) {
+	traceID := uuid.NewString()
+	ctx = context.WithValue(ctx, middleware.TraceIDKey, traceID)
+	log.Info().Str("Trace ID", traceID).Str("Method", method).Msg("Call Started")
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	var parts []string
+	if len(values) == 1 {
+		parts = strings.Split(values[0], " ")
+	}
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return nil, statusOf(ctx, apperr.New(apperr.Unauthorized, "expected authorization metadata format: Bearer <token>"))
+	}
+	claims, err := in.auth.ValidateToken(parts[1])
+	if err != nil {
+		return nil, statusOf(ctx, apperr.Wrap(apperr.Unauthorized, err, "invalid token"))
+	}
+	return context.WithValue(ctx, auth.Key, claims), nil
+}
+
+// serverStream replaces the context of a stream with the authenticated one
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// subject reads the user id from the subject of the token claims
+func subject(claims jwt.RegisteredClaims) (uint, error) {
+	id, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil || id == 0 {
+		return 0, apperr.New(apperr.Unauthorized, "token subject is not a user id")
+	}
+	return uint(id), nil
+}
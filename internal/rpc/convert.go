@@ -0,0 +1,124 @@
+package rpc
+
+import (
+	"context"
+	"project/internal/apperr"
+	"project/internal/middleware"
+	"project/internal/models"
+	"project/internal/rpc/portalpb"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// codeOf is the grpc code matching the status the http api answers kind with
+func codeOf(kind apperr.Kind) codes.Code {
+	switch kind {
+	case apperr.NotFound:
+		return codes.NotFound
+	case apperr.Conflict:
+		return codes.AlreadyExists
+	case apperr.Validation, apperr.Unprocessable, apperr.UnsupportedMediaType:
+		return codes.InvalidArgument
+	case apperr.Unauthorized:
+		return codes.Unauthenticated
+	case apperr.Forbidden:
+		return codes.PermissionDenied
+	case apperr.PreconditionFailed, apperr.PreconditionRequired:
+		return codes.FailedPrecondition
+	case apperr.Unavailable:
+		return codes.Unavailable
+	}
+	return codes.Internal
+}
+
+// statusOf logs err and turns it into the status sent back, only the message of
+// a typed error reaches the caller as with apperr.Abort
+func statusOf(ctx context.Context, err error) error {
+	traceID, _ := ctx.Value(middleware.TraceIDKey).(string)
+	code := codeOf(apperr.KindOf(err))
+	if code == codes.Internal {
+		log.Error().Err(err).Str("trace id", traceID).Send()
+	} else {
+		log.Info().Err(err).Str("trace id", traceID).Send()
+	}
+	p := apperr.ProblemOf(err)
+	msg := p.Detail
+	if msg == "" {
+		msg = p.Title
+	}
+	return status.Error(code, msg)
+}
+
+func userPB(u models.User) *portalpb.User {
+	return &portalpb.User{
+		Id:        uint64(u.ID),
+		Username:  u.Username,
+		Email:     u.Email,
+		CreatedAt: timestamppb.New(u.CreatedAt),
+	}
+}
+
+func companyPB(c models.Company) *portalpb.Company {
+	return &portalpb.Company{
+		Id:        uint64(c.ID),
+		Name:      c.Name,
+		Location:  c.Location,
+		Field:     c.Field,
+		CreatedAt: timestamppb.New(c.CreatedAt),
+		UpdatedAt: timestamppb.New(c.UpdatedAt),
+	}
+}
+
+// companyFrom reads the fields a caller can set, the id and times are the
+// database's to give
+func companyFrom(c *portalpb.Company) models.Company {
+	return models.Company{
+		Name:     c.GetName(),
+		Location: c.GetLocation(),
+		Field:    c.GetField(),
+	}
+}
+
+func jobPB(j models.Jobs) *portalpb.Job {
+	return &portalpb.Job{
+		Id:             uint64(j.ID),
+		CompanyId:      uint64(j.Cid),
+		Name:           j.Name,
+		Salary:         j.Salary,
+		NoticePeriod:   j.NoticePeriod,
+		Location:       j.Location,
+		EmploymentType: j.EmploymentType,
+		RemotePolicy:   j.RemotePolicy,
+		MinSalary:      int64(j.MinSalary),
+		MaxSalary:      int64(j.MaxSalary),
+		Skills:         j.Skills,
+		Seniority:      j.Seniority,
+		Latitude:       j.Latitude,
+		Longitude:      j.Longitude,
+		Status:         j.Status,
+		CreatedAt:      timestamppb.New(j.CreatedAt),
+		UpdatedAt:      timestamppb.New(j.UpdatedAt),
+	}
+}
+
+// jobFrom reads the fields a caller can set, the company comes from the request
+func jobFrom(j *portalpb.Job) models.Jobs {
+	return models.Jobs{
+		Name:           j.GetName(),
+		Salary:         j.GetSalary(),
+		NoticePeriod:   j.GetNoticePeriod(),
+		Location:       j.GetLocation(),
+		EmploymentType: j.GetEmploymentType(),
+		RemotePolicy:   j.GetRemotePolicy(),
+		MinSalary:      int(j.GetMinSalary()),
+		MaxSalary:      int(j.GetMaxSalary()),
+		Skills:         j.GetSkills(),
+		Seniority:      j.GetSeniority(),
+		Latitude:       j.GetLatitude(),
+		Longitude:      j.GetLongitude(),
+		Status:         j.GetStatus(),
+	}
+}
@@ -0,0 +1,425 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: portal.proto
+
+package portalpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Portal_SignUp_FullMethodName        = "/portal.v1.Portal/SignUp"
+	Portal_Login_FullMethodName         = "/portal.v1.Portal/Login"
+	Portal_ListCompanies_FullMethodName = "/portal.v1.Portal/ListCompanies"
+	Portal_GetCompany_FullMethodName    = "/portal.v1.Portal/GetCompany"
+	Portal_CreateCompany_FullMethodName = "/portal.v1.Portal/CreateCompany"
+	Portal_ListJobs_FullMethodName      = "/portal.v1.Portal/ListJobs"
+	Portal_GetJob_FullMethodName        = "/portal.v1.Portal/GetJob"
+	Portal_CreateJob_FullMethodName     = "/portal.v1.Portal/CreateJob"
+)
+
+// PortalClient is the client API for Portal service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PortalClient interface {
+	SignUp(ctx context.Context, in *SignUpRequest, opts ...grpc.CallOption) (*User, error)
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	ListCompanies(ctx context.Context, in *ListCompaniesRequest, opts ...grpc.CallOption) (Portal_ListCompaniesClient, error)
+	GetCompany(ctx context.Context, in *GetCompanyRequest, opts ...grpc.CallOption) (*Company, error)
+	CreateCompany(ctx context.Context, in *CreateCompanyRequest, opts ...grpc.CallOption) (*Company, error)
+	// ListJobs streams every job, or only those of company_id when it is set
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (Portal_ListJobsClient, error)
+	GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*Job, error)
+	CreateJob(ctx context.Context, in *CreateJobRequest, opts ...grpc.CallOption) (*Job, error)
+}
+
+type portalClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPortalClient(cc grpc.ClientConnInterface) PortalClient {
+	return &portalClient{cc}
+}
+
+func (c *portalClient) SignUp(ctx context.Context, in *SignUpRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, Portal_SignUp_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portalClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, Portal_Login_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portalClient) ListCompanies(ctx context.Context, in *ListCompaniesRequest, opts ...grpc.CallOption) (Portal_ListCompaniesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Portal_ServiceDesc.Streams[0], Portal_ListCompanies_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &portalListCompaniesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Portal_ListCompaniesClient interface {
+	Recv() (*Company, error)
+	grpc.ClientStream
+}
+
+type portalListCompaniesClient struct {
+	grpc.ClientStream
+}
+
+func (x *portalListCompaniesClient) Recv() (*Company, error) {
+	m := new(Company)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *portalClient) GetCompany(ctx context.Context, in *GetCompanyRequest, opts ...grpc.CallOption) (*Company, error) {
+	out := new(Company)
+	err := c.cc.Invoke(ctx, Portal_GetCompany_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portalClient) CreateCompany(ctx context.Context, in *CreateCompanyRequest, opts ...grpc.CallOption) (*Company, error) {
+	out := new(Company)
+	err := c.cc.Invoke(ctx, Portal_CreateCompany_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portalClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (Portal_ListJobsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Portal_ServiceDesc.Streams[1], Portal_ListJobs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &portalListJobsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Portal_ListJobsClient interface {
+	Recv() (*Job, error)
+	grpc.ClientStream
+}
+
+type portalListJobsClient struct {
+	grpc.ClientStream
+}
+
+func (x *portalListJobsClient) Recv() (*Job, error) {
+	m := new(Job)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *portalClient) GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*Job, error) {
+	out := new(Job)
+	err := c.cc.Invoke(ctx, Portal_GetJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portalClient) CreateJob(ctx context.Context, in *CreateJobRequest, opts ...grpc.CallOption) (*Job, error) {
+	out := new(Job)
+	err := c.cc.Invoke(ctx, Portal_CreateJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PortalServer is the server API for Portal service.
+// All implementations must embed UnimplementedPortalServer
+// for forward compatibility
+type PortalServer interface {
+	SignUp(context.Context, *SignUpRequest) (*User, error)
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	ListCompanies(*ListCompaniesRequest, Portal_ListCompaniesServer) error
+	GetCompany(context.Context, *GetCompanyRequest) (*Company, error)
+	CreateCompany(context.Context, *CreateCompanyRequest) (*Company, error)
+	// ListJobs streams every job, or only those of company_id when it is set
+	ListJobs(*ListJobsRequest, Portal_ListJobsServer) error
+	GetJob(context.Context, *GetJobRequest) (*Job, error)
+	CreateJob(context.Context, *CreateJobRequest) (*Job, error)
+	mustEmbedUnimplementedPortalServer()
+}
+
+// UnimplementedPortalServer must be embedded to have forward compatible implementations.
+type UnimplementedPortalServer struct {
+}
+
+func (UnimplementedPortalServer) SignUp(context.Context, *SignUpRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignUp not implemented")
+}
+func (UnimplementedPortalServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedPortalServer) ListCompanies(*ListCompaniesRequest, Portal_ListCompaniesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListCompanies not implemented")
+}
+func (UnimplementedPortalServer) GetCompany(context.Context, *GetCompanyRequest) (*Company, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCompany not implemented")
+}
+func (UnimplementedPortalServer) CreateCompany(context.Context, *CreateCompanyRequest) (*Company, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCompany not implemented")
+}
+func (UnimplementedPortalServer) ListJobs(*ListJobsRequest, Portal_ListJobsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedPortalServer) GetJob(context.Context, *GetJobRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedPortalServer) CreateJob(context.Context, *CreateJobRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateJob not implemented")
+}
+func (UnimplementedPortalServer) mustEmbedUnimplementedPortalServer() {}
+
+// UnsafePortalServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PortalServer will
+// result in compilation errors.
+type UnsafePortalServer interface {
+	mustEmbedUnimplementedPortalServer()
+}
+
+func RegisterPortalServer(s grpc.ServiceRegistrar, srv PortalServer) {
+	s.RegisterService(&Portal_ServiceDesc, srv)
+}
+
+func _Portal_SignUp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignUpRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortalServer).SignUp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Portal_SignUp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortalServer).SignUp(ctx, req.(*SignUpRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Portal_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortalServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Portal_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortalServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Portal_ListCompanies_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListCompaniesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PortalServer).ListCompanies(m, &portalListCompaniesServer{stream})
+}
+
+type Portal_ListCompaniesServer interface {
+	Send(*Company) error
+	grpc.ServerStream
+}
+
+type portalListCompaniesServer struct {
+	grpc.ServerStream
+}
+
+func (x *portalListCompaniesServer) Send(m *Company) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Portal_GetCompany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCompanyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortalServer).GetCompany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Portal_GetCompany_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortalServer).GetCompany(ctx, req.(*GetCompanyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Portal_CreateCompany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCompanyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortalServer).CreateCompany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Portal_CreateCompany_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortalServer).CreateCompany(ctx, req.(*CreateCompanyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Portal_ListJobs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListJobsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PortalServer).ListJobs(m, &portalListJobsServer{stream})
+}
+
+type Portal_ListJobsServer interface {
+	Send(*Job) error
+	grpc.ServerStream
+}
+
+type portalListJobsServer struct {
+	grpc.ServerStream
+}
+
+func (x *portalListJobsServer) Send(m *Job) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Portal_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortalServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Portal_GetJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortalServer).GetJob(ctx, req.(*GetJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Portal_CreateJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortalServer).CreateJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Portal_CreateJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortalServer).CreateJob(ctx, req.(*CreateJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Portal_ServiceDesc is the grpc.ServiceDesc for Portal service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Portal_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "portal.v1.Portal",
+	HandlerType: (*PortalServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignUp",
+			Handler:    _Portal_SignUp_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _Portal_Login_Handler,
+		},
+		{
+			MethodName: "GetCompany",
+			Handler:    _Portal_GetCompany_Handler,
+		},
+		{
+			MethodName: "CreateCompany",
+			Handler:    _Portal_CreateCompany_Handler,
+		},
+		{
+			MethodName: "GetJob",
+			Handler:    _Portal_GetJob_Handler,
+		},
+		{
+			MethodName: "CreateJob",
+			Handler:    _Portal_CreateJob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListCompanies",
+			Handler:       _Portal_ListCompanies_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListJobs",
+			Handler:       _Portal_ListJobs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "portal.proto",
+}
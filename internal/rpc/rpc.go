@@ -0,0 +1,165 @@
+// Package rpc serves the job portal over gRPC for other services, next to the
+// http api and over the same service.UserService
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative -I portalpb portalpb/portal.proto
+
+import (
+	"context"
+	"errors"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/models"
+	"project/internal/rpc/portalpb"
+	service "project/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+)
+
+type portal struct {
+	portalpb.UnimplementedPortalServer
+	service service.UserService
+}
+
+// NewServer builds the grpc server with the Portal service registered. Every call
+// but SignUp and Login must carry a token that a accepts, the same tokens the
+// http api takes
+func NewServer(a auth.UserAuth, svc service.UserService, opts ...grpc.ServerOption) (*grpc.Server, error) {
+	if a == nil || svc == nil {
+		return nil, errors.New("auth and service cannot be null")
+	}
+	in := &interceptors{auth: a}
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(in.unary),
+		grpc.ChainStreamInterceptor(in.stream),
+	}, opts...)
+	s := grpc.NewServer(opts...)
+	portalpb.RegisterPortalServer(s, &portal{service: svc})
+	return s, nil
+}
+
+func (p *portal) SignUp(ctx context.Context, req *portalpb.SignUpRequest) (*portalpb.User, error) {
+	userData := models.NewUser{Username: req.GetUsername(), Email: req.GetEmail(), Password: req.GetPassword()}
+	err := validator.New().Struct(userData)
+	if err != nil {
+		return nil, statusOf(ctx, apperr.Wrap(apperr.Validation, err, "please provide valid username, email and password"))
+	}
+
+	userDetails, err := p.service.UserSignup(ctx, userData)
+	if err != nil {
+		return nil, statusOf(ctx, err)
+	}
+	return userPB(userDetails), nil
+}
+
+func (p *portal) Login(ctx context.Context, req *portalpb.LoginRequest) (*portalpb.LoginResponse, error) {
+	if req.GetEmail() == "" || req.GetPassword() == "" {
+		return nil, statusOf(ctx, apperr.New(apperr.Validation, "please provide valid email and password"))
+	}
+
+	token, err := p.service.UserLogin(ctx, models.NewUser{Email: req.GetEmail(), Password: req.GetPassword()})
+	if err != nil {
+		return nil, statusOf(ctx, err)
+	}
+	return &portalpb.LoginResponse{Token: token}, nil
+}
+
+// ListCompanies sends the companies as they are read from the database
+func (p *portal) ListCompanies(req *portalpb.ListCompaniesRequest, stream portalpb.Portal_ListCompaniesServer) error {
+	ctx := stream.Context()
+	err := p.service.ExportCompanies(ctx, func(companyData models.Company) error {
+		return stream.Send(companyPB(companyData))
+	})
+	if err != nil {
+		return statusOf(ctx, err)
+	}
+	return nil
+}
+
+func (p *portal) GetCompany(ctx context.Context, req *portalpb.GetCompanyRequest) (*portalpb.Company, error) {
+	companyData, err := p.service.ViewCompanyDetails(ctx, req.GetId())
+	if err != nil {
+		return nil, statusOf(ctx, err)
+	}
+	return companyPB(companyData), nil
+}
+
+func (p *portal) CreateCompany(ctx context.Context, req *portalpb.CreateCompanyRequest) (*portalpb.Company, error) {
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, statusOf(ctx, err)
+	}
+	companyData := companyFrom(req.GetCompany())
+	err = validator.New().Struct(companyData)
+	if err != nil {
+		return nil, statusOf(ctx, apperr.Wrap(apperr.Validation, err, "please provide valid name, location and field"))
+	}
+
+	companyData, err = p.service.AddCompanyDetails(ctx, companyData, uid)
+	if err != nil {
+		return nil, statusOf(ctx, err)
+	}
+	return companyPB(companyData), nil
+}
+
+// ListJobs sends the jobs as they are read from the database, in the order the
+// http api lists them
+func (p *portal) ListJobs(req *portalpb.ListJobsRequest, stream portalpb.Portal_ListJobsServer) error {
+	ctx := stream.Context()
+	var filter models.JobFilter
+	if req.GetCompanyId() != 0 {
+		filter.Cid = []uint{uint(req.GetCompanyId())}
+	}
+	err := p.service.ExportJobs(ctx, filter, func(jobData models.Jobs) error {
+		return stream.Send(jobPB(jobData))
+	})
+	if err != nil {
+		return statusOf(ctx, err)
+	}
+	return nil
+}
+
+func (p *portal) GetJob(ctx context.Context, req *portalpb.GetJobRequest) (*portalpb.Job, error) {
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, statusOf(ctx, err)
+	}
+	jobData, err := p.service.ViewJobById(ctx, uid, req.GetId())
+	if err != nil {
+		return nil, statusOf(ctx, err)
+	}
+	return jobPB(jobData), nil
+}
+
+func (p *portal) CreateJob(ctx context.Context, req *portalpb.CreateJobRequest) (*portalpb.Job, error) {
+	uid, err := userID(ctx)
+	if err != nil {
+		return nil, statusOf(ctx, err)
+	}
+	if req.GetCompanyId() == 0 {
+		return nil, statusOf(ctx, apperr.New(apperr.Validation, "invalid company id"))
+	}
+	jobData := jobFrom(req.GetJob())
+	err = validator.New().Struct(jobData)
+	if err != nil {
+		return nil, statusOf(ctx, apperr.Wrap(apperr.Validation, err, "please provide a valid job"))
+	}
+
+	jobData, err = p.service.AddJobDetails(ctx, uid, jobData, req.GetCompanyId())
+	if err != nil {
+		return nil, statusOf(ctx, err)
+	}
+	return jobPB(jobData), nil
+}
+
+// userID reads the id of the signed in user from the claims the interceptors
+// put in the context
+func userID(ctx context.Context) (uint, error) {
+	claims, ok := ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	if !ok {
+		return 0, apperr.ErrUnauthorized
+	}
+	return subject(claims)
+}
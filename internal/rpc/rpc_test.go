@@ -0,0 +1,234 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"project/internal/apperr"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"project/internal/rpc/portalpb"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+)
+
+// stubAuth accepts the token "token" as user 4
+type stubAuth struct{}
+
+func (stubAuth) GenerateToken(claims jwt.RegisteredClaims) (string, error) {
+	return "token", nil
+}
+
+func (stubAuth) ValidateToken(token string) (jwt.RegisteredClaims, error) {
+	if token != "token" {
+		return jwt.RegisteredClaims{}, errors.New("token is not valid")
+	}
+	return jwt.RegisteredClaims{Subject: "4"}, nil
+}
+
+// dial serves svc on an in-process listener and returns a client for it
+func dial(t *testing.T, svc *mock_files.MockUserService) portalpb.PortalClient {
+	t.Helper()
+	s, err := NewServer(stubAuth{}, svc)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("could not dial the server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return portalpb.NewPortalClient(conn)
+}
+
+func signedIn(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestPortal_unary(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		call     func(ctx context.Context, c portalpb.PortalClient) error
+		setup    func(ms *mock_files.MockUserService)
+		wantCode codes.Code
+	}{
+		{
+			name: "login needs no token",
+			ctx:  context.Background(),
+			call: func(ctx context.Context, c portalpb.PortalClient) error {
+				resp, err := c.Login(ctx, &portalpb.LoginRequest{Email: "a@b.com", Password: "pass"})
+				if err == nil && resp.Token != "jwt" {
+					t.Errorf("Login() token = %q, want jwt", resp.Token)
+				}
+				return err
+			},
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().UserLogin(gomock.Any(), models.NewUser{Email: "a@b.com", Password: "pass"}).Return("jwt", nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "signup without a password",
+			ctx:  context.Background(),
+			call: func(ctx context.Context, c portalpb.PortalClient) error {
+				_, err := c.SignUp(ctx, &portalpb.SignUpRequest{Username: "bhoomi", Email: "a@b.com"})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "no token",
+			ctx:  context.Background(),
+			call: func(ctx context.Context, c portalpb.PortalClient) error {
+				_, err := c.GetCompany(ctx, &portalpb.GetCompanyRequest{Id: 7})
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "wrong token",
+			ctx:  signedIn("forged"),
+			call: func(ctx context.Context, c portalpb.PortalClient) error {
+				_, err := c.GetCompany(ctx, &portalpb.GetCompanyRequest{Id: 7})
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "company",
+			ctx:  signedIn("token"),
+			call: func(ctx context.Context, c portalpb.PortalClient) error {
+				resp, err := c.GetCompany(ctx, &portalpb.GetCompanyRequest{Id: 7})
+				if err == nil && resp.Name != "tek" {
+					t.Errorf("GetCompany() name = %q, want tek", resp.Name)
+				}
+				return err
+			},
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewCompanyDetails(gomock.Any(), uint64(7)).Return(models.Company{Model: gorm.Model{ID: 7}, Name: "tek"}, nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "missing company",
+			ctx:  signedIn("token"),
+			call: func(ctx context.Context, c portalpb.PortalClient) error {
+				_, err := c.GetCompany(ctx, &portalpb.GetCompanyRequest{Id: 9})
+				return err
+			},
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().ViewCompanyDetails(gomock.Any(), uint64(9)).Return(models.Company{}, apperr.New(apperr.NotFound, "company not found"))
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "company owned by the caller",
+			ctx:  signedIn("token"),
+			call: func(ctx context.Context, c portalpb.PortalClient) error {
+				_, err := c.CreateCompany(ctx, &portalpb.CreateCompanyRequest{Company: &portalpb.Company{Name: "tek", Location: "pune", Field: "it"}})
+				return err
+			},
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().AddCompanyDetails(gomock.Any(), models.Company{Name: "tek", Location: "pune", Field: "it"}, uint(4)).
+					Return(models.Company{Model: gorm.Model{ID: 1}, Name: "tek", Location: "pune", Field: "it"}, nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "database failure",
+			ctx:  signedIn("token"),
+			call: func(ctx context.Context, c portalpb.PortalClient) error {
+				_, err := c.CreateJob(ctx, &portalpb.CreateJobRequest{CompanyId: 7, Job: &portalpb.Job{Name: "developer"}})
+				if status.Convert(err).Message() != "Internal Server Error" {
+					t.Errorf("CreateJob() message = %q, the cause must not be sent", status.Convert(err).Message())
+				}
+				return err
+			},
+			setup: func(ms *mock_files.MockUserService) {
+				ms.EXPECT().AddJobDetails(gomock.Any(), uint(4), models.Jobs{Name: "developer"}, uint64(7)).
+					Return(models.Jobs{}, errors.New("connection refused"))
+			},
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := mock_files.NewMockUserService(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(ms)
+			}
+			c := dial(t, ms)
+
+			err := tt.call(tt.ctx, c)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("code = %v, want %v (%v)", got, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestPortal_ListJobs(t *testing.T) {
+	ms := mock_files.NewMockUserService(gomock.NewController(t))
+	ms.EXPECT().ExportJobs(gomock.Any(), models.JobFilter{Cid: []uint{7}}, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, filter models.JobFilter, fn func(models.Jobs) error) error {
+			for _, name := range []string{"developer", "tester"} {
+				err := fn(models.Jobs{Cid: 7, Name: name})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	c := dial(t, ms)
+
+	stream, err := c.ListJobs(signedIn("token"), &portalpb.ListJobsRequest{CompanyId: 7})
+	if err != nil {
+		t.Fatalf("ListJobs() error = %v", err)
+	}
+	var names []string
+	for {
+		job, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		names = append(names, job.Name)
+	}
+	if len(names) != 2 || names[0] != "developer" || names[1] != "tester" {
+		t.Errorf("ListJobs() sent %v, want [developer tester]", names)
+	}
+}
+
+func TestPortal_ListCompanies_noToken(t *testing.T) {
+	c := dial(t, mock_files.NewMockUserService(gomock.NewController(t)))
+
+	stream, err := c.ListCompanies(context.Background(), &portalpb.ListCompaniesRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ListCompanies() error = %v, want Unauthenticated", err)
+	}
+}
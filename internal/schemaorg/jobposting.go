@@ -0,0 +1,151 @@
+// Package schemaorg describes jobs in the schema.org vocabulary, the JSON-LD
+// structured data search engines read job postings from
+package schemaorg
+
+import (
+	"project/internal/feed"
+	"project/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MediaType is the media type of JSON-LD
+const MediaType = "application/ld+json"
+
+// Validity is how long ahead a published job is announced as open. It has no
+// closing date of its own, so the date moves on with every day it stays published
+const Validity = 30 * 24 * time.Hour
+
+// JobPosting is a https://schema.org/JobPosting
+type JobPosting struct {
+	Context            string          `json:"@context"`
+	Type               string          `json:"@type"`
+	Title              string          `json:"title"`
+	Description        string          `json:"description"`
+	Identifier         PropertyValue   `json:"identifier"`
+	URL                string          `json:"url"`
+	DatePosted         string          `json:"datePosted"`
+	ValidThrough       string          `json:"validThrough"`
+	EmploymentType     string          `json:"employmentType,omitempty"`
+	HiringOrganization Organization    `json:"hiringOrganization"`
+	JobLocation        *Place          `json:"jobLocation,omitempty"`
+	JobLocationType    string          `json:"jobLocationType,omitempty"`
+	BaseSalary         *MonetaryAmount `json:"baseSalary,omitempty"`
+	Skills             string          `json:"skills,omitempty"`
+	Industry           string          `json:"industry,omitempty"`
+}
+
+// Organization is the company hiring for a job
+type Organization struct {
+	Type    string         `json:"@type"`
+	Name    string         `json:"name"`
+	Address *PostalAddress `json:"address,omitempty"`
+}
+
+// Place is where the job is done
+type Place struct {
+	Type    string          `json:"@type"`
+	Address PostalAddress   `json:"address"`
+	Geo     *GeoCoordinates `json:"geo,omitempty"`
+}
+
+type PostalAddress struct {
+	Type            string `json:"@type"`
+	AddressLocality string `json:"addressLocality"`
+}
+
+type GeoCoordinates struct {
+	Type      string  `json:"@type"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// MonetaryAmount is a yearly salary, or the range of one
+type MonetaryAmount struct {
+	Type     string            `json:"@type"`
+	Currency string            `json:"currency"`
+	Value    QuantitativeValue `json:"value"`
+}
+
+type QuantitativeValue struct {
+	Type     string `json:"@type"`
+	MinValue int    `json:"minValue,omitempty"`
+	MaxValue int    `json:"maxValue,omitempty"`
+	UnitText string `json:"unitText"`
+}
+
+// PropertyValue identifies the job within its company
+type PropertyValue struct {
+	Type  string `json:"@type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// employmentTypes maps the employment types of a job to the schema.org ones
+var employmentTypes = map[string]string{
+	models.EmploymentFullTime:   "FULL_TIME",
+	models.EmploymentPartTime:   "PART_TIME",
+	models.EmploymentContract:   "CONTRACTOR",
+	models.EmploymentInternship: "INTERN",
+}
+
+// NewJobPosting describes a job whose company is loaded, url is the url of its
+// detail endpoint. A published job is valid through Validity after the start of
+// the day of now, a closed one was valid until it was closed
+func NewJobPosting(jobData models.Jobs, url string, now time.Time) JobPosting {
+	validThrough := now.UTC().Truncate(24 * time.Hour).Add(Validity)
+	if jobData.Status == models.JobClosed {
+		validThrough = jobData.UpdatedAt
+	}
+	posting := JobPosting{
+		Context:     "https://schema.org",
+		Type:        "JobPosting",
+		Title:       jobData.Name,
+		Description: feed.Summary(jobData),
+		Identifier: PropertyValue{
+			Type:  "PropertyValue",
+			Name:  jobData.Company.Name,
+			Value: strconv.FormatUint(uint64(jobData.ID), 10),
+		},
+		URL:                url,
+		DatePosted:         jobData.CreatedAt.UTC().Format(time.RFC3339),
+		ValidThrough:       validThrough.UTC().Format(time.RFC3339),
+		EmploymentType:     employmentTypes[jobData.EmploymentType],
+		HiringOrganization: Organization{Type: "Organization", Name: jobData.Company.Name},
+		Skills:             strings.Join(jobData.Skills, ", "),
+		Industry:           jobData.Company.Field,
+	}
+	if posting.Description == "" {
+		posting.Description = jobData.Name
+	}
+	if jobData.Company.Location != "" {
+		posting.HiringOrganization.Address = address(jobData.Company.Location)
+	}
+	if jobData.Location != "" {
+		posting.JobLocation = &Place{Type: "Place", Address: *address(jobData.Location)}
+		if jobData.Latitude != 0 || jobData.Longitude != 0 {
+			posting.JobLocation.Geo = &GeoCoordinates{Type: "GeoCoordinates", Latitude: jobData.Latitude, Longitude: jobData.Longitude}
+		}
+	}
+	if jobData.RemotePolicy == models.RemoteFull {
+		posting.JobLocationType = "TELECOMMUTE"
+	}
+	if jobData.MinSalary > 0 || jobData.MaxSalary > 0 {
+		posting.BaseSalary = &MonetaryAmount{
+			Type:     "MonetaryAmount",
+			Currency: models.SalaryCurrency,
+			Value: QuantitativeValue{
+				Type:     "QuantitativeValue",
+				MinValue: jobData.MinSalary,
+				MaxValue: jobData.MaxSalary,
+				UnitText: "YEAR",
+			},
+		}
+	}
+	return posting
+}
+
+func address(locality string) *PostalAddress {
+	return &PostalAddress{Type: "PostalAddress", AddressLocality: locality}
+}
@@ -0,0 +1,115 @@
+package schemaorg
+
+import (
+	"encoding/json"
+	"project/internal/models"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestNewJobPosting(t *testing.T) {
+	posted := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	now := time.Date(2024, 3, 1, 15, 4, 5, 0, time.UTC)
+	tek := models.Company{Model: gorm.Model{ID: 7}, Name: "tek", Location: "bang", Field: "software"}
+	tests := []struct {
+		name    string
+		jobData models.Jobs
+		want    JobPosting
+	}{
+		{
+			name: "published",
+			jobData: models.Jobs{
+				Model:          gorm.Model{ID: 11, CreatedAt: posted, UpdatedAt: posted},
+				Company:        tek,
+				Cid:            7,
+				Name:           "developer",
+				Location:       "pune",
+				EmploymentType: models.EmploymentContract,
+				MinSalary:      800000,
+				MaxSalary:      1200000,
+				Skills:         []string{"go", "sql"},
+				Latitude:       18.52,
+				Longitude:      73.85,
+				Status:         models.JobPublished,
+			},
+			want: JobPosting{
+				Context:            "https://schema.org",
+				Type:               "JobPosting",
+				Title:              "developer",
+				Description:        "pune · contract · salary 800000-1200000 · skills: go, sql",
+				Identifier:         PropertyValue{Type: "PropertyValue", Name: "tek", Value: "11"},
+				URL:                "https://portal.example/api/v1/jobs/11",
+				DatePosted:         "2024-01-02T03:04:05Z",
+				ValidThrough:       "2024-03-31T00:00:00Z",
+				EmploymentType:     "CONTRACTOR",
+				HiringOrganization: Organization{Type: "Organization", Name: "tek", Address: &PostalAddress{Type: "PostalAddress", AddressLocality: "bang"}},
+				JobLocation: &Place{
+					Type:    "Place",
+					Address: PostalAddress{Type: "PostalAddress", AddressLocality: "pune"},
+					Geo:     &GeoCoordinates{Type: "GeoCoordinates", Latitude: 18.52, Longitude: 73.85},
+				},
+				BaseSalary: &MonetaryAmount{
+					Type:     "MonetaryAmount",
+					Currency: "INR",
+					Value:    QuantitativeValue{Type: "QuantitativeValue", MinValue: 800000, MaxValue: 1200000, UnitText: "YEAR"},
+				},
+				Skills:   "go, sql",
+				Industry: "software",
+			},
+		},
+		{
+			name: "closed remote job",
+			jobData: models.Jobs{
+				Model:        gorm.Model{ID: 12, CreatedAt: posted, UpdatedAt: posted.Add(time.Hour)},
+				Company:      models.Company{Name: "tek"},
+				Name:         "tester",
+				RemotePolicy: models.RemoteFull,
+				Status:       models.JobClosed,
+			},
+			want: JobPosting{
+				Context:            "https://schema.org",
+				Type:               "JobPosting",
+				Title:              "tester",
+				Description:        "remote",
+				Identifier:         PropertyValue{Type: "PropertyValue", Name: "tek", Value: "12"},
+				URL:                "https://portal.example/api/v1/jobs/12",
+				DatePosted:         "2024-01-02T03:04:05Z",
+				ValidThrough:       "2024-01-02T04:04:05Z",
+				HiringOrganization: Organization{Type: "Organization", Name: "tek"},
+				JobLocationType:    "TELECOMMUTE",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "https://portal.example/api/v1/jobs/" + tt.want.Identifier.Value
+			got := NewJobPosting(tt.jobData, url, now)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewJobPosting() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobPosting_json(t *testing.T) {
+	b, err := json.Marshal(NewJobPosting(models.Jobs{Name: "tester"}, "", time.Time{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	err = json.Unmarshal(b, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["@context"] != "https://schema.org" || got["@type"] != "JobPosting" || got["description"] != "tester" {
+		t.Errorf("the posting is written as %s", b)
+	}
+	for _, key := range []string{"jobLocation", "baseSalary", "employmentType"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("%s is written for a job without it: %s", key, b)
+		}
+	}
+}
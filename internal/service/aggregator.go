@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"project/internal/apperr"
+	"project/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ExportPublishedJobs calls fn with every published job and its company. The jobs
+// come grouped by company, only the company of the current group is held. A job
+// whose company is gone is passed without one
+func (s *Service) ExportPublishedJobs(ctx context.Context, fn func(models.Jobs) error) error {
+	var companyData models.Company
+	return s.UserRepo.StreamPublishedJobs(ctx, func(jobData models.Jobs) error {
+		if companyData.ID != jobData.Cid {
+			var err error
+			companyData, err = s.UserRepo.CompanyById(ctx, uint64(jobData.Cid))
+			if apperr.KindOf(err) == apperr.NotFound {
+				companyData, err = models.Company{Model: gorm.Model{ID: jobData.Cid}}, nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		jobData.Company = companyData
+		return fn(jobData)
+	})
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/models"
+	"project/internal/repository"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_ExportPublishedJobs(t *testing.T) {
+	tek := models.Company{Model: gorm.Model{ID: 7}, Name: "tek"}
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	mockRepo.EXPECT().StreamPublishedJobs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(models.Jobs) error) error {
+			for _, jobData := range []models.Jobs{{Cid: 7, Name: "developer"}, {Cid: 7, Name: "tester"}, {Cid: 8, Name: "orphan"}} {
+				err := fn(jobData)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	// each company is looked up once for its group of jobs
+	mockRepo.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(tek, nil)
+	mockRepo.EXPECT().CompanyById(gomock.Any(), uint64(8)).Return(models.Company{}, apperr.New(apperr.NotFound, "could not find the company"))
+	s, _ := NewService(mockRepo, &auth.Auth{})
+
+	var got []models.Jobs
+	err := s.ExportPublishedJobs(context.Background(), func(jobData models.Jobs) error {
+		got = append(got, jobData)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Service.ExportPublishedJobs() error = %v", err)
+	}
+	want := []models.Jobs{
+		{Company: tek, Cid: 7, Name: "developer"},
+		{Company: tek, Cid: 7, Name: "tester"},
+		{Company: models.Company{Model: gorm.Model{ID: 8}}, Cid: 8, Name: "orphan"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Service.ExportPublishedJobs() exported %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"project/internal/apperr"
+	"project/internal/egress"
+	"project/internal/models"
+	"project/internal/repository"
+	"time"
+)
+
+// CreateConnector pulls the board of the connector into the jobs of the company
+// from now on, only those who recruit for it can. A company has one connector
+// per provider and the board must be on the public internet
+func (s *Service) CreateConnector(ctx context.Context, actorID uint, cid uint64, connector models.ATSConnector) (models.ATSConnector, error) {
+	connectors, err := s.Connectors(ctx, actorID, cid)
+	if err != nil {
+		return models.ATSConnector{}, err
+	}
+	err = egress.CheckURL(connector.URL)
+	if err != nil {
+		return models.ATSConnector{}, apperr.Wrap(apperr.Validation, err, "the board url must be on the public internet")
+	}
+	for _, c := range connectors {
+		if c.Provider == connector.Provider {
+			return models.ATSConnector{}, apperr.New(apperr.Conflict, "the company already has a "+c.Provider+" connector")
+		}
+	}
+	return s.UserRepo.CreateConnector(ctx, models.ATSConnector{
+		Cid:        uint(cid),
+		Provider:   connector.Provider,
+		URL:        connector.URL,
+		NextSyncAt: time.Now(),
+	})
+}
+
+func (s *Service) Connectors(ctx context.Context, actorID uint, cid uint64) ([]models.ATSConnector, error) {
+	err := s.canRecruit(ctx, actorID, cid)
+	if err != nil {
+		return nil, err
+	}
+	return s.UserRepo.ConnectorsByCompany(ctx, uint(cid))
+}
+
+func (s *Service) DeleteConnector(ctx context.Context, actorID uint, cid uint64, id uint64) error {
+	err := s.canRecruit(ctx, actorID, cid)
+	if err != nil {
+		return err
+	}
+	return s.UserRepo.DeleteConnector(ctx, uint(cid), uint(id))
+}
+
+// SyncATSJobs brings the jobs the connector brought in before in line with the
+// postings of its board. New postings are published as jobs, the fields the
+// board knows are taken over into the jobs still listed, and published jobs no
+// longer listed are closed. A job deleted in the portal is not brought back and
+// the status set in the portal is kept, only jobs the sync closed are published
+// again once they are listed
+func (s *Service) SyncATSJobs(ctx context.Context, connector models.ATSConnector, postings []models.Jobs) (models.ATSSync, error) {
+	var result models.ATSSync
+	var events []models.DomainEvent
+	var current map[string]models.Jobs
+	err := s.record(ctx, func(tx repository.UserRepo) ([]models.DomainEvent, error) {
+		result, events = models.ATSSync{}, nil
+		jobs, err := tx.JobsByExternalIDPrefix(ctx, connector.Cid, connector.ExternalID(""))
+		if err != nil {
+			return nil, err
+		}
+		current = make(map[string]models.Jobs, len(jobs))
+		for _, jobData := range jobs {
+			current[jobData.ExternalID] = jobData
+		}
+
+		listed := make(map[string]bool, len(postings))
+		for _, posting := range postings {
+			if listed[posting.ExternalID] {
+				continue
+			}
+			listed[posting.ExternalID] = true
+			jobData, ok := current[posting.ExternalID]
+			switch {
+			case !ok:
+				posting.Cid = connector.Cid
+				posting.Status = models.JobPublished
+				jobData, err = tx.CreateUserJob(ctx, posting)
+				if err != nil {
+					return nil, err
+				}
+				result.Created++
+				events = append(events, models.JobCreated{Job: jobData})
+			case jobData.DeletedAt.Valid:
+				// deleted in the portal, it stays deleted
+			case !takeOver(&jobData, posting):
+				result.Unchanged++
+			default:
+				jobData, err = tx.UpdateJob(ctx, jobData, jobData.UpdatedAt)
+				if err != nil {
+					return nil, err
+				}
+				result.Updated++
+				events = append(events, models.JobUpdated{Job: jobData})
+			}
+		}
+
+		for _, jobData := range jobs {
+			if listed[jobData.ExternalID] || jobData.DeletedAt.Valid || jobData.Status != models.JobPublished {
+				continue
+			}
+			version := jobData.UpdatedAt
+			jobData.Status = models.JobClosed
+			jobData.SyncClosed = true
+			jobData, err = tx.UpdateJob(ctx, jobData, version)
+			if err != nil {
+				return nil, err
+			}
+			result.Closed++
+			events = append(events, models.JobClosedEvent{Job: jobData, ClosedAt: jobData.UpdatedAt})
+		}
+		return events, nil
+	})
+	if err != nil {
+		return models.ATSSync{}, err
+	}
+	for _, e := range events {
+		jobData, _, _ := jobEvent(e)
+		s.publishJob(e.EventType(), current[jobData.ExternalID], jobData)
+	}
+	return result, nil
+}
+
+// takeOver copies the fields the board knows from posting into jobData and
+// reports whether any changed. A job the sync closed is published again as the
+// board lists it, any other status is left as the portal set it
+func takeOver(jobData *models.Jobs, posting models.Jobs) bool {
+	changed := jobData.Name != posting.Name ||
+		jobData.Location != posting.Location ||
+		jobData.EmploymentType != posting.EmploymentType ||
+		jobData.RemotePolicy != posting.RemotePolicy ||
+		jobData.MinSalary != posting.MinSalary ||
+		jobData.MaxSalary != posting.MaxSalary ||
+		jobData.SyncClosed
+	jobData.Name = posting.Name
+	jobData.Location = posting.Location
+	jobData.EmploymentType = posting.EmploymentType
+	jobData.RemotePolicy = posting.RemotePolicy
+	jobData.MinSalary = posting.MinSalary
+	jobData.MaxSalary = posting.MaxSalary
+	if jobData.SyncClosed {
+		jobData.Status = models.JobPublished
+		jobData.SyncClosed = false
+	}
+	return changed
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/egress"
+	"project/internal/models"
+	"project/internal/repository"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_SyncATSJobs(t *testing.T) {
+	updated := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	connector := models.ATSConnector{Model: gorm.Model{ID: 4}, Cid: 7, Provider: models.ATSLever}
+	unchanged := models.Jobs{Model: gorm.Model{ID: 11, UpdatedAt: updated}, Cid: 7, Name: "tester", Location: "pune", Status: models.JobPublished, ExternalID: "lever:a"}
+	renamed := models.Jobs{Model: gorm.Model{ID: 12, UpdatedAt: updated}, Cid: 7, Name: "dev", Salary: "10L", Status: models.JobPublished, ExternalID: "lever:b"}
+	gone := models.Jobs{Model: gorm.Model{ID: 13, UpdatedAt: updated}, Cid: 7, Name: "designer", Status: models.JobPublished, ExternalID: "lever:c"}
+	closed := models.Jobs{Model: gorm.Model{ID: 14, UpdatedAt: updated}, Cid: 7, Name: "analyst", Status: models.JobClosed, ExternalID: "lever:d"}
+	deleted := models.Jobs{Model: gorm.Model{ID: 15, UpdatedAt: updated, DeletedAt: gorm.DeletedAt{Time: updated, Valid: true}}, Cid: 7, Name: "intern", ExternalID: "lever:e"}
+	draft := models.Jobs{Model: gorm.Model{ID: 17, UpdatedAt: updated}, Cid: 7, Name: "support", Status: models.JobDraft, ExternalID: "lever:g"}
+	unlisted := models.Jobs{Model: gorm.Model{ID: 18, UpdatedAt: updated}, Cid: 7, Name: "writer", Status: models.JobDraft, ExternalID: "lever:h"}
+	relisted := models.Jobs{Model: gorm.Model{ID: 19, UpdatedAt: updated}, Cid: 7, Name: "sales", Status: models.JobClosed, SyncClosed: true, ExternalID: "lever:i"}
+
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	inTransaction(mockRepo)
+	mockRepo.EXPECT().JobsByExternalIDPrefix(gomock.Any(), uint(7), "lever:").
+		Return([]models.Jobs{unchanged, renamed, gone, closed, deleted, draft, unlisted, relisted}, nil)
+	// the fields the board does not know are kept
+	wantRenamed := renamed
+	wantRenamed.Name = "developer"
+	mockRepo.EXPECT().UpdateJob(gomock.Any(), wantRenamed, updated).Return(wantRenamed, nil)
+	wantGone := gone
+	wantGone.Status = models.JobClosed
+	wantGone.SyncClosed = true
+	mockRepo.EXPECT().UpdateJob(gomock.Any(), wantGone, updated).Return(wantGone, nil)
+	// only the job the sync closed itself is published again, the statuses set in
+	// the portal stay and a draft is not closed
+	wantRelisted := relisted
+	wantRelisted.Status = models.JobPublished
+	wantRelisted.SyncClosed = false
+	mockRepo.EXPECT().UpdateJob(gomock.Any(), wantRelisted, updated).Return(wantRelisted, nil)
+	mockRepo.EXPECT().CreateUserJob(gomock.Any(), models.Jobs{Cid: 7, Name: "lead", Status: models.JobPublished, ExternalID: "lever:f"}).
+		Return(models.Jobs{Model: gorm.Model{ID: 16}, Cid: 7, Name: "lead", Status: models.JobPublished, ExternalID: "lever:f"}, nil)
+	// every change is recorded with the pull for the webhooks to hear of
+	mockRepo.EXPECT().AppendEvents(gomock.Any(), recorded("job.updated job:12", "job.created job:16", "job.updated job:19", "job.closed job:13")).Return(nil)
+
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	got, err := s.SyncATSJobs(context.Background(), connector, []models.Jobs{
+		{Name: "tester", Location: "pune", ExternalID: "lever:a"},
+		{Name: "developer", ExternalID: "lever:b"},
+		{Name: "developer", ExternalID: "lever:b"},
+		{Name: "intern", ExternalID: "lever:e"},
+		{Name: "lead", ExternalID: "lever:f"},
+		{Name: "analyst", ExternalID: "lever:d"},
+		{Name: "support", ExternalID: "lever:g"},
+		{Name: "sales", ExternalID: "lever:i"},
+	})
+	if err != nil {
+		t.Fatalf("Service.SyncATSJobs() error = %v", err)
+	}
+	want := models.ATSSync{Created: 1, Updated: 2, Closed: 1, Unchanged: 3}
+	if got != want {
+		t.Errorf("Service.SyncATSJobs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestService_CreateConnector(t *testing.T) {
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{
+		{CompanyID: 7, UserID: 4, Role: models.RoleRecruiter},
+	}, nil).Times(3)
+	mockRepo.EXPECT().ConnectorsByCompany(gomock.Any(), uint(7)).Return(nil, nil).Times(2)
+	mockRepo.EXPECT().ConnectorsByCompany(gomock.Any(), uint(7)).
+		Return([]models.ATSConnector{{Model: gorm.Model{ID: 3}, Cid: 7, Provider: models.ATSLever}}, nil)
+	// the connector is due right away and always created at the company of the path
+	mockRepo.EXPECT().CreateConnector(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, c models.ATSConnector) (models.ATSConnector, error) {
+			if c.Cid != 7 || c.ID != 0 || c.NextSyncAt.IsZero() || c.NextSyncAt.After(time.Now()) {
+				t.Errorf("the connector is created as %+v", c)
+			}
+			c.ID = 4
+			return c, nil
+		})
+
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	connector := models.ATSConnector{Model: gorm.Model{ID: 9}, Cid: 8, Provider: models.ATSLever, URL: "https://api.lever.co/v0/postings/tek?mode=json"}
+	_, err := s.CreateConnector(context.Background(), 4, 7, models.ATSConnector{Provider: models.ATSLever, URL: "http://10.0.0.5/postings"})
+	if !errors.Is(err, egress.ErrForbiddenAddress) {
+		t.Errorf("Service.CreateConnector() of a private board error = %v, want %v", err, egress.ErrForbiddenAddress)
+	}
+	got, err := s.CreateConnector(context.Background(), 4, 7, connector)
+	if err != nil || got.ID != 4 {
+		t.Fatalf("Service.CreateConnector() = %+v, %v, want connector 4", got, err)
+	}
+	_, err = s.CreateConnector(context.Background(), 4, 7, connector)
+	if apperr.KindOf(err) != apperr.Conflict {
+		t.Errorf("Service.CreateConnector() of a second lever connector error = %v, want a conflict", err)
+	}
+}
@@ -2,11 +2,24 @@ package service
 
 import (
 	"context"
+	"project/internal/apperr"
 	"project/internal/models"
+	"project/internal/repository"
 )
 
-func (s *Service) AddCompanyDetails(ctx context.Context, companyData models.Company) (models.Company, error) {
-	companyData, err := s.UserRepo.CreateUserCompany(ctx, companyData)
+// ErrNotCompanyOwner is returned when a user who does not own the company changes or deletes it
+var ErrNotCompanyOwner = apperr.New(apperr.Forbidden, "only company owners can change the company")
+
+// AddCompanyDetails creates the company with ownerID as its first member
+func (s *Service) AddCompanyDetails(ctx context.Context, companyData models.Company, ownerID uint) (models.Company, error) {
+	err := s.record(ctx, func(tx repository.UserRepo) ([]models.DomainEvent, error) {
+		var err error
+		companyData, err = tx.CreateUserCompany(ctx, companyData, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		return []models.DomainEvent{models.CompanyCreated{Company: companyData, OwnerID: ownerID}}, nil
+	})
 	if err != nil {
 		return models.Company{}, err
 	}
@@ -28,3 +41,89 @@ func (s *Service) ViewCompanyDetails(ctx context.Context, cid uint64) (models.Co
 	}
 	return companyData, nil
 }
+
+// CompaniesByIds loads the companies with one query, keyed by their id
+func (s *Service) CompaniesByIds(ctx context.Context, ids []uint) (map[uint]models.Company, error) {
+	companyDatas, err := s.UserRepo.CompaniesByIds(ctx, uniqueIds(ids))
+	if err != nil {
+		return nil, err
+	}
+	companies := make(map[uint]models.Company, len(companyDatas))
+	for _, companyData := range companyDatas {
+		companies[companyData.ID] = companyData
+	}
+	return companies, nil
+}
+
+func uniqueIds(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	var unique []uint
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// UpdateCompany replaces the company if ifMatch is its current ETag, only its owners can
+func (s *Service) UpdateCompany(ctx context.Context, actorID uint, cid uint64, companyData models.Company, ifMatch string) (models.Company, error) {
+	return s.PatchCompany(ctx, actorID, cid, func(models.Company) (models.Company, error) {
+		return companyData, nil
+	}, ifMatch)
+}
+
+// PatchCompany stores what apply makes of the current company if ifMatch is its
+// ETag, apply returning an error leaves the company as it is
+func (s *Service) PatchCompany(ctx context.Context, actorID uint, cid uint64, apply func(models.Company) (models.Company, error), ifMatch string) (models.Company, error) {
+	current, err := s.ownedCompany(ctx, actorID, cid, ifMatch)
+	if err != nil {
+		return models.Company{}, err
+	}
+	companyData, err := apply(current)
+	if err != nil {
+		return models.Company{}, err
+	}
+	companyData.Model = current.Model
+	companyData, err = s.UserRepo.UpdateCompany(ctx, companyData, current.UpdatedAt)
+	if err != nil {
+		return models.Company{}, err
+	}
+	return companyData, nil
+}
+
+// DeleteCompany deletes the company and its jobs if ifMatch is its current ETag, only its owners can
+func (s *Service) DeleteCompany(ctx context.Context, actorID uint, cid uint64, ifMatch string) error {
+	current, err := s.ownedCompany(ctx, actorID, cid, ifMatch)
+	if err != nil {
+		return err
+	}
+	return s.UserRepo.DeleteCompany(ctx, current.ID, current.UpdatedAt)
+}
+
+// ownedCompany loads the company a write targets once actorID is known to own it
+// and ifMatch to be its current ETag
+func (s *Service) ownedCompany(ctx context.Context, actorID uint, cid uint64, ifMatch string) (models.Company, error) {
+	m, err := s.membership(ctx, actorID, uint(cid))
+	if err != nil {
+		return models.Company{}, err
+	}
+	if m.Role != models.RoleOwner {
+		return models.Company{}, ErrNotCompanyOwner
+	}
+	current, err := s.UserRepo.CompanyById(ctx, cid)
+	if err != nil {
+		return models.Company{}, err
+	}
+	err = checkIfMatch(ifMatch, current.ETag())
+	if err != nil {
+		return models.Company{}, err
+	}
+	return current, nil
+}
+
+// ExportCompanies calls fn with every company
+func (s *Service) ExportCompanies(ctx context.Context, fn func(models.Company) error) error {
+	return s.UserRepo.StreamCompanies(ctx, fn)
+}
@@ -3,13 +3,16 @@ package service
 import (
 	"context"
 	"errors"
+	"project/internal/apperr"
 	"project/internal/auth"
 	"project/internal/models"
 	"project/internal/repository"
 	"reflect"
 	"testing"
+	"time"
 
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestService_AddCompanyDetails(t *testing.T) {
@@ -62,10 +65,14 @@ func TestService_AddCompanyDetails(t *testing.T) {
 			mc := gomock.NewController(t)
 			mockRepo := repository.NewMockUserRepo(mc)
 			if tt.mockRepoResponse != nil {
-				mockRepo.EXPECT().CreateUserCompany(gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
+				inTransaction(mockRepo)
+				mockRepo.EXPECT().CreateUserCompany(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
+			}
+			if !tt.wantErr {
+				mockRepo.EXPECT().AppendEvents(gomock.Any(), recorded("company.created company:0")).Return(nil)
 			}
 			s, _ := NewService(mockRepo, &auth.Auth{})
-			got, err := s.AddCompanyDetails(tt.args.ctx, tt.args.companyData)
+			got, err := s.AddCompanyDetails(tt.args.ctx, tt.args.companyData, 1)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.AddCompanyDetails() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -205,3 +212,107 @@ func TestService_ViewCompanyDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestService_CompaniesByIds(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().CompaniesByIds(gomock.Any(), []uint{2, 7}).Return([]models.Company{
+		{Model: gorm.Model{ID: 2}, Name: "tcs"},
+		{Model: gorm.Model{ID: 7}, Name: "ibm"},
+	}, nil).Times(1)
+
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	got, err := s.CompaniesByIds(context.Background(), []uint{2, 7, 2, 2})
+	if err != nil {
+		t.Fatalf("Service.CompaniesByIds() error = %v", err)
+	}
+	want := map[uint]models.Company{
+		2: {Model: gorm.Model{ID: 2}, Name: "tcs"},
+		7: {Model: gorm.Model{ID: 7}, Name: "ibm"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Service.CompaniesByIds() = %v, want %v", got, want)
+	}
+}
+
+func TestService_UpdateCompany(t *testing.T) {
+	version := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	current := models.Company{Model: gorm.Model{ID: 7, CreatedAt: version, UpdatedAt: version}, Name: "ibm"}
+	memberships := []models.Membership{
+		{CompanyID: 7, UserID: 4, Role: models.RoleOwner},
+		{CompanyID: 8, UserID: 4, Role: models.RoleRecruiter},
+	}
+	tests := []struct {
+		name      string
+		cid       uint64
+		ifMatch   string
+		wantErr   error
+		setupMock func(r *repository.MockUserRepo)
+	}{
+		{
+			name:    "not the owner",
+			cid:     8,
+			ifMatch: current.ETag(),
+			wantErr: ErrNotCompanyOwner,
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return(memberships, nil)
+			},
+		},
+		{
+			name:    "no etag",
+			cid:     7,
+			wantErr: ErrPreconditionRequired,
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return(memberships, nil)
+				r.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(current, nil)
+			},
+		},
+		{
+			name:    "weak etag",
+			cid:     7,
+			ifMatch: "W/" + current.ETag(),
+			wantErr: ErrPreconditionFailed,
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return(memberships, nil)
+				r.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(current, nil)
+			},
+		},
+		{
+			name:    "changed in between",
+			cid:     7,
+			ifMatch: current.ETag(),
+			wantErr: apperr.ErrPreconditionFailed,
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return(memberships, nil)
+				r.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(current, nil)
+				r.EXPECT().UpdateCompany(gomock.Any(), gomock.Any(), version).
+					Return(models.Company{}, apperr.New(apperr.PreconditionFailed, "the company was changed or deleted since it was read"))
+			},
+		},
+		{
+			name:    "success",
+			cid:     7,
+			ifMatch: current.ETag(),
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return(memberships, nil)
+				r.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(current, nil)
+				// the id and timestamps of the stored company are kept
+				r.EXPECT().UpdateCompany(gomock.Any(), models.Company{Model: current.Model, Name: "tek"}, version).
+					Return(models.Company{Model: current.Model, Name: "tek"}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			tt.setupMock(mockRepo)
+
+			s, _ := NewService(mockRepo, &auth.Auth{})
+			_, err := s.UpdateCompany(context.Background(), 4, tt.cid, models.Company{Model: gorm.Model{ID: 99}, Name: "tek"}, tt.ifMatch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.UpdateCompany() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
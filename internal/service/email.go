@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/apperr"
+	"project/internal/email"
+	"project/internal/models"
+	"slices"
+	"time"
+)
+
+// ErrNotAdmin is returned when a user who is not an admin uses the admin tools
+var ErrNotAdmin = apperr.New(apperr.Forbidden, "only admins can use this")
+
+// EmailEvent queues the emails the event sends, the outbox dispatcher hands it
+// the events of signups, new companies and new jobs. Users who unsubscribed get
+// none, and an event handed over again queues none twice
+func (s *Service) EmailEvent(ctx context.Context, event models.DomainEvent) error {
+	var (
+		template string
+		to       []models.User
+		data     email.Data
+	)
+	switch e := event.(type) {
+	case models.UserSignedUp:
+		template, to = email.TemplateWelcome, []models.User{e.User}
+		data = email.Data{Link: s.links.Profile()}
+	case models.CompanyCreated:
+		owner, err := s.UserRepo.UserByID(ctx, e.OwnerID)
+		if err != nil {
+			return err
+		}
+		template, to = email.TemplateCompanyCreated, []models.User{owner}
+		data = email.Data{Company: e.Company, Link: s.links.Company(e.Company.ID)}
+	case models.JobCreated:
+		company, err := s.UserRepo.CompanyById(ctx, uint64(e.Job.Cid))
+		if err != nil {
+			return err
+		}
+		members, err := s.UserRepo.CompanyMembers(ctx, e.Job.Cid)
+		if err != nil {
+			return err
+		}
+		template, to = email.TemplateJobCreated, members
+		data = email.Data{Company: company, Job: e.Job, Link: s.links.Job(e.Job.ID)}
+	default:
+		return nil
+	}
+
+	emails := make([]models.Email, 0, len(to))
+	for _, user := range to {
+		if user.UnsubscribedAt != nil {
+			continue
+		}
+		m, err := s.composeEmail(event, template, user, data)
+		if err != nil {
+			return err
+		}
+		emails = append(emails, m)
+	}
+	return s.UserRepo.QueueEmails(ctx, emails)
+}
+
+// composeEmail renders the email of the event to the user, in the locale of the user
+func (s *Service) composeEmail(event models.DomainEvent, template string, to models.User, data email.Data) (models.Email, error) {
+	data.User = to
+	data.Unsubscribe = s.links.Unsubscribe(s.tokens.Sign(to.ID))
+	rendered, err := s.emails.Render(template, to.Locale, data)
+	if err != nil {
+		return models.Email{}, fmt.Errorf("email %s to user %d: %w", template, to.ID, err)
+	}
+	return models.Email{
+		Key:           fmt.Sprintf("%s %s user:%d", event.EventType(), event.Aggregate(), to.ID),
+		UserID:        to.ID,
+		To:            to.Email,
+		Name:          to.Username,
+		Template:      template,
+		Locale:        to.Locale,
+		RenderedEmail: rendered,
+		Unsubscribe:   data.Unsubscribe,
+		Status:        models.EmailPending,
+		NextAttemptAt: time.Now(),
+	}, nil
+}
+
+// PreviewEmail renders the email template in locale from made up data, only
+// admins can
+func (s *Service) PreviewEmail(ctx context.Context, actorID uint, template string, locale string) (models.RenderedEmail, error) {
+	if !s.admins[actorID] {
+		return models.RenderedEmail{}, ErrNotAdmin
+	}
+	if !slices.Contains(email.Names, template) {
+		return models.RenderedEmail{}, apperr.New(apperr.NotFound, "there is no email template "+template)
+	}
+	if locale == "" {
+		locale = email.DefaultLocale
+	}
+	if !slices.Contains(s.emails.Locales(), locale) {
+		return models.RenderedEmail{}, apperr.New(apperr.NotFound, "there are no email templates of the locale "+locale)
+	}
+	return s.emails.Render(template, locale, email.Sample())
+}
+
+// VerifyUnsubscribe checks the token of an unsubscribe link without unsubscribing
+func (s *Service) VerifyUnsubscribe(ctx context.Context, token string) error {
+	_, err := s.tokens.Verify(token)
+	if err != nil {
+		return apperr.Wrap(apperr.Validation, err, "invalid unsubscribe link")
+	}
+	return nil
+}
+
+// Unsubscribe stops the email notifications of the user the token was signed for
+func (s *Service) Unsubscribe(ctx context.Context, token string) (models.Unsubscribed, error) {
+	userID, err := s.tokens.Verify(token)
+	if err != nil {
+		return models.Unsubscribed{}, apperr.Wrap(apperr.Validation, err, "invalid unsubscribe link")
+	}
+	user, err := s.UserRepo.Unsubscribe(ctx, userID, time.Now().UTC())
+	if err != nil {
+		return models.Unsubscribed{}, err
+	}
+	unsubscribed := models.Unsubscribed{Email: user.Email}
+	if user.UnsubscribedAt != nil {
+		unsubscribed.UnsubscribedAt = *user.UnsubscribedAt
+	}
+	return unsubscribed, nil
+}
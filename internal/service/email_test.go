@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/email"
+	"project/internal/models"
+	"project/internal/repository"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_EmailEvent(t *testing.T) {
+	tokens, _ := email.NewTokens([]byte("0123456789abcdef"))
+	unsubscribed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	asha := models.User{Model: gorm.Model{ID: 3}, Username: "asha", Email: "asha@example.com", Locale: "hi"}
+	ravi := models.User{Model: gorm.Model{ID: 4}, Username: "ravi", Email: "ravi@example.com"}
+	gone := models.User{Model: gorm.Model{ID: 5}, Username: "gone", Email: "gone@example.com", UnsubscribedAt: &unsubscribed}
+	tek := models.Company{Model: gorm.Model{ID: 7}, Name: "tek", Location: "bang", Field: "software"}
+	job := models.Jobs{Model: gorm.Model{ID: 11}, Cid: 7, Name: "developer"}
+
+	// queued is an email by its template, recipient and key
+	type queued struct{ template, to, key string }
+	tests := []struct {
+		name    string
+		event   models.DomainEvent
+		setup   func(r *repository.MockUserRepo)
+		want    []queued
+		wantErr bool
+	}{
+		{
+			name:  "signed up",
+			event: models.UserSignedUp{User: asha},
+			want:  []queued{{email.TemplateWelcome, "asha@example.com", "user.signed_up user:3 user:3"}},
+		},
+		{
+			name:  "company created",
+			event: models.CompanyCreated{Company: tek, OwnerID: 4},
+			setup: func(r *repository.MockUserRepo) {
+				r.EXPECT().UserByID(gomock.Any(), uint(4)).Return(ravi, nil)
+			},
+			want: []queued{{email.TemplateCompanyCreated, "ravi@example.com", "company.created company:7 user:4"}},
+		},
+		{
+			name:  "job created skips who unsubscribed",
+			event: models.JobCreated{Job: job},
+			setup: func(r *repository.MockUserRepo) {
+				r.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(tek, nil)
+				r.EXPECT().CompanyMembers(gomock.Any(), uint(7)).Return([]models.User{asha, ravi, gone}, nil)
+			},
+			want: []queued{
+				{email.TemplateJobCreated, "asha@example.com", "job.created job:11 user:3"},
+				{email.TemplateJobCreated, "ravi@example.com", "job.created job:11 user:4"},
+			},
+		},
+		{
+			name:  "owner not found",
+			event: models.CompanyCreated{Company: tek, OwnerID: 4},
+			setup: func(r *repository.MockUserRepo) {
+				r.EXPECT().UserByID(gomock.Any(), uint(4)).Return(models.User{}, errors.New("db down"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+			if tt.setup != nil {
+				tt.setup(mockRepo)
+			}
+			var got []models.Email
+			if !tt.wantErr {
+				mockRepo.EXPECT().QueueEmails(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, emails []models.Email) error {
+						got = emails
+						return nil
+					})
+			}
+			s, _ := NewService(mockRepo, &auth.Auth{}, WithEmail(tokens, "https://portal.example"))
+
+			err := s.EmailEvent(context.Background(), tt.event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Service.EmailEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Service.EmailEvent() queued %d emails, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				e := got[i]
+				if e.Template != want.template || e.To != want.to || e.Key != want.key || e.Status != models.EmailPending {
+					t.Errorf("queued %s to %s as %q with status %s, want %+v", e.Template, e.To, e.Key, e.Status, want)
+				}
+				userID, err := tokens.Verify(strings.TrimPrefix(e.Unsubscribe, "https://portal.example/api/v1/emails/unsubscribe?token="))
+				if err != nil || userID != e.UserID {
+					t.Errorf("the unsubscribe link %s is not the one of user %d", e.Unsubscribe, e.UserID)
+				}
+				if !strings.Contains(e.Text, e.Unsubscribe) || e.HTML == "" {
+					t.Errorf("the email does not offer to unsubscribe:\n%s", e.Text)
+				}
+			}
+			if len(got) > 0 && got[0].To == "asha@example.com" && !strings.Contains(got[0].Text, "नमस्ते") {
+				t.Errorf("the email is not written in the locale of its user:\n%s", got[0].Text)
+			}
+		})
+	}
+}
+
+func TestService_PreviewEmail(t *testing.T) {
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	s, _ := NewService(mockRepo, &auth.Auth{}, WithAdmins(1))
+
+	got, err := s.PreviewEmail(context.Background(), 1, email.TemplateJobCreated, "hi")
+	if err != nil || !strings.Contains(got.Subject, "Backend developer") {
+		t.Errorf("Service.PreviewEmail() = %+v, %v", got, err)
+	}
+	_, err = s.PreviewEmail(context.Background(), 2, email.TemplateJobCreated, "")
+	if !errors.Is(err, ErrNotAdmin) {
+		t.Errorf("Service.PreviewEmail() by a user error = %v, want %v", err, ErrNotAdmin)
+	}
+	for _, tc := range [][2]string{{"farewell", "en"}, {email.TemplateWelcome, "fr"}} {
+		_, err = s.PreviewEmail(context.Background(), 1, tc[0], tc[1])
+		if apperr.KindOf(err) != apperr.NotFound {
+			t.Errorf("Service.PreviewEmail(%s, %s) error = %v, want not found", tc[0], tc[1], err)
+		}
+	}
+}
+
+func TestService_Unsubscribe(t *testing.T) {
+	tokens, _ := email.NewTokens([]byte("0123456789abcdef"))
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	mockRepo.EXPECT().Unsubscribe(gomock.Any(), uint(3), gomock.Any()).
+		Return(models.User{Model: gorm.Model{ID: 3}, Email: "asha@example.com", UnsubscribedAt: &at}, nil)
+	s, _ := NewService(mockRepo, &auth.Auth{}, WithEmail(tokens, "https://portal.example"))
+
+	got, err := s.Unsubscribe(context.Background(), tokens.Sign(3))
+	if err != nil || got != (models.Unsubscribed{Email: "asha@example.com", UnsubscribedAt: at}) {
+		t.Errorf("Service.Unsubscribe() = %+v, %v", got, err)
+	}
+	_, err = s.Unsubscribe(context.Background(), "3.forged")
+	if apperr.KindOf(err) != apperr.Validation {
+		t.Errorf("Service.Unsubscribe() of a forged token error = %v, want a validation error", err)
+	}
+}
+
+func TestService_VerifyUnsubscribe(t *testing.T) {
+	tokens, _ := email.NewTokens([]byte("0123456789abcdef"))
+	s, _ := NewService(repository.NewMockUserRepo(gomock.NewController(t)), &auth.Auth{}, WithEmail(tokens, "https://portal.example"))
+
+	err := s.VerifyUnsubscribe(context.Background(), tokens.Sign(3))
+	if err != nil {
+		t.Errorf("Service.VerifyUnsubscribe() error = %v", err)
+	}
+	err = s.VerifyUnsubscribe(context.Background(), "3.forged")
+	if apperr.KindOf(err) != apperr.Validation {
+		t.Errorf("Service.VerifyUnsubscribe() of a forged token error = %v, want a validation error", err)
+	}
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"project/internal/apperr"
+	"project/internal/etag"
+)
+
+var (
+	// ErrPreconditionRequired is returned when a write does not say which version it changes
+	ErrPreconditionRequired = apperr.New(apperr.PreconditionRequired, "send the ETag of the record in If-Match")
+	// ErrPreconditionFailed is returned when the record changed since the client read it
+	ErrPreconditionFailed = apperr.New(apperr.PreconditionFailed, "the record was changed since it was read, fetch it again")
+)
+
+// checkIfMatch lets a write through only if ifMatch names the current tag of the record
+func checkIfMatch(ifMatch, current string) error {
+	if ifMatch == "" {
+		return ErrPreconditionRequired
+	}
+	if !etag.Match(ifMatch, current, false) {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
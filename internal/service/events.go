@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"project/internal/models"
+	"project/internal/repository"
+	"time"
+)
+
+// record runs write in a transaction and appends the events it returns to the
+// outbox in the same transaction, so the events exist exactly when the change does
+func (s *Service) record(ctx context.Context, write func(tx repository.UserRepo) ([]models.DomainEvent, error)) error {
+	return s.UserRepo.Transaction(ctx, func(tx repository.UserRepo) error {
+		events, err := write(tx)
+		if err != nil {
+			return err
+		}
+		rows, err := outboxEvents(events...)
+		if err != nil {
+			return err
+		}
+		return tx.AppendEvents(ctx, rows)
+	})
+}
+
+func outboxEvents(events ...models.DomainEvent) ([]models.OutboxEvent, error) {
+	now := time.Now().UTC()
+	rows := make([]models.OutboxEvent, 0, len(events))
+	for _, e := range events {
+		row, err := models.NewOutboxEvent(e, now)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project/internal/auth"
+	"project/internal/models"
+	"project/internal/repository"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+// inTransaction has the mock run the writes of a transaction on itself
+func inTransaction(r *repository.MockUserRepo) *gomock.Call {
+	return r.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(tx repository.UserRepo) error) error {
+			return fn(r)
+		})
+}
+
+// outboxMatcher matches outbox events, or a slice of them, by "type aggregate"
+type outboxMatcher []string
+
+func recorded(events ...string) gomock.Matcher {
+	return outboxMatcher(events)
+}
+
+func (m outboxMatcher) Matches(x interface{}) bool {
+	var events []models.OutboxEvent
+	switch x := x.(type) {
+	case models.OutboxEvent:
+		events = []models.OutboxEvent{x}
+	case []models.OutboxEvent:
+		events = x
+	default:
+		return false
+	}
+	if len(events) != len(m) {
+		return false
+	}
+	for i, e := range events {
+		if e.Type+" "+e.Aggregate != m[i] || e.Status != models.OutboxPending {
+			return false
+		}
+	}
+	return true
+}
+
+func (m outboxMatcher) String() string {
+	return fmt.Sprintf("records the events %q", []string(m))
+}
+
+func TestService_record(t *testing.T) {
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	inTransaction(mockRepo).Times(2)
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{{CompanyID: 7, UserID: 4, Role: models.RoleOwner}}, nil).Times(2)
+	mockRepo.EXPECT().CreateUserJob(gomock.Any(), models.Jobs{Cid: 7, Name: "developer"}).
+		Return(models.Jobs{Model: gorm.Model{ID: 11}, Cid: 7, Name: "developer"}, nil)
+	var appended []models.OutboxEvent
+	mockRepo.EXPECT().AppendEvents(gomock.Any(), recorded("job.created job:11")).
+		DoAndReturn(func(ctx context.Context, events []models.OutboxEvent) error {
+			appended = events
+			return nil
+		})
+	s, _ := NewService(mockRepo, &auth.Auth{})
+
+	_, err := s.AddJobDetails(context.Background(), 4, models.Jobs{Name: "developer"}, 7)
+	if err != nil {
+		t.Fatalf("Service.AddJobDetails() error = %v", err)
+	}
+	e, err := appended[0].Event()
+	if err != nil {
+		t.Fatalf("OutboxEvent.Event() error = %v", err)
+	}
+	if got, ok := e.(models.JobCreated); !ok || got.Job.ID != 11 || got.Job.Name != "developer" {
+		t.Errorf("the outbox holds %#v, want the created job", e)
+	}
+
+	// a failed write records nothing
+	mockRepo.EXPECT().CreateUserJob(gomock.Any(), gomock.Any()).Return(models.Jobs{}, errors.New("connection refused"))
+	_, err = s.AddJobDetails(context.Background(), 4, models.Jobs{Name: "tester"}, 7)
+	if err == nil {
+		t.Error("Service.AddJobDetails() error = nil, want the repository error")
+	}
+}
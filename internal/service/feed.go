@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"project/internal/models"
+)
+
+// feedSize is how many of the newest jobs a feed lists
+const feedSize = 50
+
+// JobFeed is the feed of the newest published jobs matching filter, paging is ignored
+func (s *Service) JobFeed(ctx context.Context, filter models.JobFilter) (models.JobFeed, error) {
+	return s.jobFeed(ctx, filter, nil)
+}
+
+// CompanyJobFeed is JobFeed limited to the jobs of one company
+func (s *Service) CompanyJobFeed(ctx context.Context, cid uint64, filter models.JobFilter) (models.JobFeed, error) {
+	companyData, err := s.UserRepo.CompanyById(ctx, cid)
+	if err != nil {
+		return models.JobFeed{}, err
+	}
+	filter.Cid = []uint{companyData.ID}
+	return s.jobFeed(ctx, filter, &companyData)
+}
+
+func (s *Service) jobFeed(ctx context.Context, filter models.JobFilter, companyData *models.Company) (models.JobFeed, error) {
+	jobDatas, modified, err := s.UserRepo.FeedJobs(ctx, s.matching(filter), feedSize)
+	if err != nil {
+		return models.JobFeed{}, err
+	}
+	feed := models.JobFeed{Company: companyData, Jobs: jobDatas, Updated: modified}
+	if companyData != nil && companyData.UpdatedAt.After(feed.Updated) {
+		feed.Updated = companyData.UpdatedAt
+	}
+	// renaming a company changes the entries of its jobs
+	for _, jobData := range jobDatas {
+		if jobData.Company.UpdatedAt.After(feed.Updated) {
+			feed.Updated = jobData.Company.UpdatedAt
+		}
+	}
+	if feed.Jobs == nil {
+		feed.Jobs = []models.Jobs{}
+	}
+	return feed, nil
+}
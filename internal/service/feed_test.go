@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/auth"
+	"project/internal/models"
+	"project/internal/repository"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_CompanyJobFeed(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tek := models.Company{Model: gorm.Model{ID: 7, UpdatedAt: modified.Add(time.Hour)}, Name: "tek"}
+	tests := []struct {
+		name    string
+		setup   func(r *repository.MockUserRepo)
+		want    models.JobFeed
+		wantErr bool
+	}{
+		{
+			name: "renamed company dates the feed",
+			setup: func(r *repository.MockUserRepo) {
+				r.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(tek, nil)
+				r.EXPECT().FeedJobs(gomock.Any(), models.JobFilter{Cid: []uint{7}, Location: []string{"pune"}}, feedSize).
+					Return([]models.Jobs{{Model: gorm.Model{ID: 11}, Cid: 7, Company: tek}}, modified, nil)
+			},
+			want: models.JobFeed{
+				Company: &tek,
+				Jobs:    []models.Jobs{{Model: gorm.Model{ID: 11}, Cid: 7, Company: tek}},
+				Updated: modified.Add(time.Hour),
+			},
+		},
+		{
+			name: "no jobs",
+			setup: func(r *repository.MockUserRepo) {
+				r.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(tek, nil)
+				r.EXPECT().FeedJobs(gomock.Any(), gomock.Any(), feedSize).Return(nil, time.Time{}, nil)
+			},
+			want: models.JobFeed{Company: &tek, Jobs: []models.Jobs{}, Updated: tek.UpdatedAt},
+		},
+		{
+			name: "unknown company",
+			setup: func(r *repository.MockUserRepo) {
+				r.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(models.Company{}, errors.New("not found"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+			tt.setup(mockRepo)
+			s, _ := NewService(mockRepo, &auth.Auth{})
+
+			got, err := s.CompanyJobFeed(context.Background(), 7, models.JobFilter{Location: []string{"pune"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Service.CompanyJobFeed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.CompanyJobFeed() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"project/internal/apperr"
+	"project/internal/imports"
+	"project/internal/models"
+	"project/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ImportCompanies creates the companies of rows with actorID as their owner, a
+// company whose name is taken is skipped
+func (s *Service) ImportCompanies(ctx context.Context, actorID uint, rows imports.Source[models.Company], opts models.ImportOptions) (models.ImportReport, error) {
+	return runImport(ctx, s.UserRepo, rows, opts, nil, func(b repository.Batch, companyData models.Company) models.ImportRow {
+		_, found, err := b.CompanyByName(companyData.Name)
+		if err != nil {
+			return failedRow(err)
+		}
+		if found {
+			return models.ImportRow{Status: models.ImportSkipped, Reason: "a company with this name already exists"}
+		}
+		companyData.Model = gorm.Model{}
+		companyData, err = b.CreateCompany(companyData, actorID)
+		if err != nil {
+			return failedRow(err)
+		}
+		err = appendEvents(b, models.CompanyCreated{Company: companyData, OwnerID: actorID})
+		if err != nil {
+			return failedRow(err)
+		}
+		return models.ImportRow{Status: models.ImportCreated, ID: companyData.ID}
+	})
+}
+
+// ImportJobs creates the jobs of rows at the companies they name, actorID has to
+// recruit for each of them. A job named like one the company already has is skipped.
+// The job stream hears of the jobs of a batch once it is committed
+func (s *Service) ImportJobs(ctx context.Context, actorID uint, rows imports.Source[models.JobRow], opts models.ImportOptions) (models.ImportReport, error) {
+	memberships, err := s.UserRepo.MembershipsByUser(ctx, actorID)
+	if err != nil {
+		return models.ImportReport{}, err
+	}
+	recruits := make(map[uint]bool, len(memberships))
+	for _, m := range memberships {
+		recruits[m.CompanyID] = m.CanRecruit()
+	}
+
+	var created []models.Jobs
+	done := func(committed bool) {
+		if committed {
+			for _, jobData := range created {
+				s.publishJob(models.EventJobCreated, models.Jobs{}, jobData)
+			}
+		}
+		created = nil
+	}
+
+	return runImport(ctx, s.UserRepo, rows, opts, done, func(b repository.Batch, row models.JobRow) models.ImportRow {
+		companyData, found, err := b.CompanyByName(row.Company)
+		if err != nil {
+			return failedRow(err)
+		}
+		if !found {
+			return models.ImportRow{Status: models.ImportFailed, Reason: "there is no company named " + row.Company}
+		}
+		if !recruits[companyData.ID] {
+			return failedRow(ErrNotCompanyMember)
+		}
+		exists, err := b.JobExists(companyData.ID, row.Name)
+		if err != nil {
+			return failedRow(err)
+		}
+		if exists {
+			return models.ImportRow{Status: models.ImportSkipped, Reason: "the company already has a job with this name"}
+		}
+		jobData := row.Jobs
+		jobData.Model = gorm.Model{}
+		jobData.Cid = companyData.ID
+		jobData.ExternalID = ""
+		jobData, err = b.CreateJob(jobData)
+		if err != nil {
+			return failedRow(err)
+		}
+		err = appendEvents(b, models.JobCreated{Job: jobData})
+		if err != nil {
+			return failedRow(err)
+		}
+		created = append(created, jobData)
+		return models.ImportRow{Status: models.ImportCreated, ID: jobData.ID}
+	})
+}
+
+// runImport writes every valid row with write and reports each of them. Rows are
+// written in one batch, or in batches of opts.ChunkSize rows, and a dry run rolls
+// its batch back. An error reading rows or committing a batch ends the import,
+// batches committed before stay and the report returned with the error says up to
+// which line. done, when set, is told how each batch ended
+func runImport[T any](ctx context.Context, repo repository.UserRepo, rows imports.Source[T], opts models.ImportOptions, done func(committed bool), write func(repository.Batch, T) models.ImportRow) (models.ImportReport, error) {
+	report := models.ImportReport{DryRun: opts.DryRun, Rows: []models.ImportRow{}}
+	var b repository.Batch
+	pending, last := 0, 0
+	// a dry run keeps a single batch, later rows may refer to companies created before
+	chunked := opts.ChunkSize > 0 && !opts.DryRun
+	ended := func(committed bool) {
+		if done != nil {
+			done(committed)
+		}
+	}
+	finish := func() error {
+		if b == nil {
+			return nil
+		}
+		defer func() { b, pending = nil, 0 }()
+		if opts.DryRun {
+			ended(false)
+			return b.Rollback()
+		}
+		err := b.Commit()
+		ended(err == nil)
+		if err == nil {
+			report.CommittedLine = last
+		}
+		return err
+	}
+	stop := func(err error) (models.ImportReport, error) {
+		report.Stop(failedRow(err).Reason)
+		return report, err
+	}
+
+	for {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if b != nil {
+				_ = b.Rollback()
+				ended(false)
+			}
+			return stop(err)
+		}
+		if row.Err != nil {
+			report.Add(models.ImportRow{Line: row.Line, Status: models.ImportFailed, Reason: row.Err.Error()})
+			continue
+		}
+		if b == nil {
+			b, err = repo.BeginBatch(ctx)
+			if err != nil {
+				return stop(err)
+			}
+		}
+		result := write(b, row.Value)
+		result.Line = row.Line
+		if opts.DryRun {
+			result.ID = 0
+		}
+		report.Add(result)
+		pending, last = pending+1, row.Line
+		if chunked && pending >= opts.ChunkSize {
+			err = finish()
+			if err != nil {
+				return stop(err)
+			}
+		}
+	}
+
+	err := finish()
+	if err != nil {
+		return stop(err)
+	}
+	return report, nil
+}
+
+// appendEvents has the batch record events when it commits
+func appendEvents(b repository.Batch, events ...models.DomainEvent) error {
+	rows, err := outboxEvents(events...)
+	if err != nil {
+		return err
+	}
+	b.AppendEvents(rows...)
+	return nil
+}
+
+// failedRow reports a row that could not be written, only messages meant for
+// clients are shown
+func failedRow(err error) models.ImportRow {
+	p := apperr.ProblemOf(err)
+	reason := p.Detail
+	if reason == "" {
+		reason = p.Title
+	}
+	return models.ImportRow{Status: models.ImportFailed, Reason: reason}
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/imports"
+	"project/internal/models"
+	"project/internal/repository"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+// sliceSource yields rows and then err, or io.EOF
+type sliceSource[T any] struct {
+	rows []imports.Row[T]
+	err  error
+}
+
+func (s *sliceSource[T]) Next() (imports.Row[T], error) {
+	if len(s.rows) == 0 {
+		if s.err != nil {
+			return imports.Row[T]{}, s.err
+		}
+		return imports.Row[T]{}, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+func TestService_ImportJobs(t *testing.T) {
+	tek := models.Company{Model: gorm.Model{ID: 7}, Name: "tek"}
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	batch := repository.NewMockBatch(mc)
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{{CompanyID: 7, UserID: 4, Role: models.RoleRecruiter}}, nil)
+	mockRepo.EXPECT().BeginBatch(gomock.Any()).Return(batch, nil)
+	batch.EXPECT().CompanyByName("tek").Return(tek, true, nil).Times(3)
+	batch.EXPECT().CompanyByName("acme").Return(models.Company{}, false, nil)
+	batch.EXPECT().CompanyByName("other").Return(models.Company{Model: gorm.Model{ID: 8}, Name: "other"}, true, nil)
+	batch.EXPECT().JobExists(uint(7), "developer").Return(false, nil)
+	batch.EXPECT().JobExists(uint(7), "tester").Return(true, nil)
+	batch.EXPECT().JobExists(uint(7), "designer").Return(false, nil)
+	// the row's own id and company are replaced, and it cannot pass for a synced posting
+	batch.EXPECT().CreateJob(models.Jobs{Cid: 7, Name: "developer"}).Return(models.Jobs{Model: gorm.Model{ID: 11}, Cid: 7, Name: "developer"}, nil)
+	batch.EXPECT().AppendEvents(recorded("job.created job:11"))
+	batch.EXPECT().CreateJob(models.Jobs{Cid: 7, Name: "designer"}).Return(models.Jobs{}, apperr.New(apperr.Conflict, "could not create the job, it already exists"))
+	batch.EXPECT().Commit().Return(nil)
+
+	rows := &sliceSource[models.JobRow]{rows: []imports.Row[models.JobRow]{
+		{Line: 2, Value: models.JobRow{Company: "tek", Jobs: models.Jobs{Model: gorm.Model{ID: 99}, Cid: 8, Name: "developer", ExternalID: "greenhouse:1"}}},
+		{Line: 3, Err: errors.New("company must satisfy required")},
+		{Line: 4, Value: models.JobRow{Company: "acme", Jobs: models.Jobs{Name: "developer"}}},
+		{Line: 5, Value: models.JobRow{Company: "other", Jobs: models.Jobs{Name: "developer"}}},
+		{Line: 6, Value: models.JobRow{Company: "tek", Jobs: models.Jobs{Name: "tester"}}},
+		{Line: 7, Value: models.JobRow{Company: "tek", Jobs: models.Jobs{Name: "designer"}}},
+	}}
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	got, err := s.ImportJobs(context.Background(), 4, rows, models.ImportOptions{})
+	if err != nil {
+		t.Fatalf("Service.ImportJobs() error = %v", err)
+	}
+	want := models.ImportReport{Created: 1, Skipped: 1, Failed: 4, Rows: []models.ImportRow{
+		{Line: 2, Status: models.ImportCreated, ID: 11},
+		{Line: 3, Status: models.ImportFailed, Reason: "company must satisfy required"},
+		{Line: 4, Status: models.ImportFailed, Reason: "there is no company named acme"},
+		{Line: 5, Status: models.ImportFailed, Reason: "only owners and recruiters of the company can change its jobs"},
+		{Line: 6, Status: models.ImportSkipped, Reason: "the company already has a job with this name"},
+		{Line: 7, Status: models.ImportFailed, Reason: "could not create the job, it already exists"},
+	}, CommittedLine: 7}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Service.ImportJobs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestService_ImportCompanies(t *testing.T) {
+	companies := func() *sliceSource[models.Company] {
+		return &sliceSource[models.Company]{rows: []imports.Row[models.Company]{
+			{Line: 2, Value: models.Company{Name: "tek"}},
+			{Line: 3, Value: models.Company{Name: "acme"}},
+			{Line: 4, Value: models.Company{Name: "ibm"}},
+		}}
+	}
+	tests := []struct {
+		name    string
+		rows    *sliceSource[models.Company]
+		opts    models.ImportOptions
+		expect  func(*repository.MockUserRepo, *repository.MockBatch)
+		want    models.ImportReport
+		wantErr bool
+	}{
+		{
+			name: "chunks of two",
+			rows: companies(),
+			opts: models.ImportOptions{ChunkSize: 2},
+			expect: func(r *repository.MockUserRepo, b *repository.MockBatch) {
+				r.EXPECT().BeginBatch(gomock.Any()).Return(b, nil).Times(2)
+				b.EXPECT().CompanyByName("tek").Return(models.Company{Model: gorm.Model{ID: 1}}, true, nil)
+				b.EXPECT().CompanyByName(gomock.Any()).Return(models.Company{}, false, nil).Times(2)
+				b.EXPECT().CreateCompany(models.Company{Name: "acme"}, uint(4)).Return(models.Company{Model: gorm.Model{ID: 2}}, nil)
+				b.EXPECT().CreateCompany(models.Company{Name: "ibm"}, uint(4)).Return(models.Company{Model: gorm.Model{ID: 3}}, nil)
+				b.EXPECT().AppendEvents(recorded("company.created company:2"))
+				b.EXPECT().AppendEvents(recorded("company.created company:3"))
+				b.EXPECT().Commit().Return(nil).Times(2)
+			},
+			want: models.ImportReport{Created: 2, Skipped: 1, Rows: []models.ImportRow{
+				{Line: 2, Status: models.ImportSkipped, Reason: "a company with this name already exists"},
+				{Line: 3, Status: models.ImportCreated, ID: 2},
+				{Line: 4, Status: models.ImportCreated, ID: 3},
+			}, CommittedLine: 4},
+		},
+		{
+			name: "dry run ignores chunks and rolls back",
+			rows: companies(),
+			opts: models.ImportOptions{DryRun: true, ChunkSize: 1},
+			expect: func(r *repository.MockUserRepo, b *repository.MockBatch) {
+				r.EXPECT().BeginBatch(gomock.Any()).Return(b, nil)
+				b.EXPECT().CompanyByName(gomock.Any()).Return(models.Company{}, false, nil).Times(3)
+				b.EXPECT().CreateCompany(gomock.Any(), uint(4)).Return(models.Company{Model: gorm.Model{ID: 5}}, nil).Times(3)
+				b.EXPECT().AppendEvents(recorded("company.created company:5")).Times(3)
+				b.EXPECT().Rollback().Return(nil)
+			},
+			want: models.ImportReport{DryRun: true, Created: 3, Rows: []models.ImportRow{
+				{Line: 2, Status: models.ImportCreated},
+				{Line: 3, Status: models.ImportCreated},
+				{Line: 4, Status: models.ImportCreated},
+			}},
+		},
+		{
+			name: "unreadable upload keeps the committed chunks",
+			rows: &sliceSource[models.Company]{
+				rows: companies().rows,
+				err:  apperr.New(apperr.Validation, "could not read the import"),
+			},
+			opts: models.ImportOptions{ChunkSize: 2},
+			expect: func(r *repository.MockUserRepo, b *repository.MockBatch) {
+				r.EXPECT().BeginBatch(gomock.Any()).Return(b, nil).Times(2)
+				b.EXPECT().CompanyByName(gomock.Any()).Return(models.Company{}, false, nil).Times(3)
+				b.EXPECT().CreateCompany(models.Company{Name: "tek"}, uint(4)).Return(models.Company{Model: gorm.Model{ID: 1}}, nil)
+				b.EXPECT().CreateCompany(models.Company{Name: "acme"}, uint(4)).Return(models.Company{Model: gorm.Model{ID: 2}}, nil)
+				b.EXPECT().CreateCompany(models.Company{Name: "ibm"}, uint(4)).Return(models.Company{Model: gorm.Model{ID: 3}}, nil)
+				b.EXPECT().AppendEvents(gomock.Any()).Times(3)
+				b.EXPECT().Commit().Return(nil)
+				b.EXPECT().Rollback().Return(nil)
+			},
+			want: models.ImportReport{Created: 2, Failed: 1, Rows: []models.ImportRow{
+				{Line: 2, Status: models.ImportCreated, ID: 1},
+				{Line: 3, Status: models.ImportCreated, ID: 2},
+				{Line: 4, Status: models.ImportFailed, Reason: "not stored, the import stopped before its batch was committed"},
+			}, CommittedLine: 3, Error: "could not read the import"},
+			wantErr: true,
+		},
+		{
+			name: "nothing to write",
+			rows: &sliceSource[models.Company]{},
+			expect: func(r *repository.MockUserRepo, b *repository.MockBatch) {
+			},
+			want: models.ImportReport{Rows: []models.ImportRow{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			batch := repository.NewMockBatch(mc)
+			tt.expect(mockRepo, batch)
+
+			s, _ := NewService(mockRepo, &auth.Auth{})
+			got, err := s.ImportCompanies(context.Background(), 4, tt.rows, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Service.ImportCompanies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.ImportCompanies() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,14 +2,49 @@ package service
 
 import (
 	"context"
+	"errors"
 
+	"project/internal/apperr"
 	"project/internal/models"
+	"project/internal/repository"
+	"time"
 )
 
-func (s *Service) ViewJobById(ctx context.Context, jid uint64) (models.Jobs, error) {
+// ErrNotCompanyMember is returned when a user who does not recruit for the company changes its jobs
+var ErrNotCompanyMember = apperr.New(apperr.Forbidden, "only owners and recruiters of the company can change its jobs")
+
+// ViewJobById finds the job, drafts and closed jobs are only found by the owners
+// and recruiters of its company
+func (s *Service) ViewJobById(ctx context.Context, actorID uint, jid uint64) (models.Jobs, error) {
+	jobData, err := s.UserRepo.Jobbyjid(ctx, jid)
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	if jobData.Status != "" && jobData.Status != models.JobPublished {
+		err = s.canRecruit(ctx, actorID, uint64(jobData.Cid))
+		if errors.Is(err, ErrNotCompanyMember) {
+			return models.Jobs{}, apperr.New(apperr.NotFound, "could not find the job")
+		}
+		if err != nil {
+			return models.Jobs{}, err
+		}
+	}
+	return jobData, nil
+}
+
+// PublicJob is a published or closed job with its company, as anyone may see it.
+// Drafts are not found
+func (s *Service) PublicJob(ctx context.Context, jid uint64) (models.Jobs, error) {
 	jobData, err := s.UserRepo.Jobbyjid(ctx, jid)
 	if err != nil {
-		return models.Jobs{}, nil
+		return models.Jobs{}, err
+	}
+	if jobData.Status == models.JobDraft {
+		return models.Jobs{}, apperr.New(apperr.NotFound, "could not find the job")
+	}
+	jobData.Company, err = s.UserRepo.CompanyById(ctx, uint64(jobData.Cid))
+	if err != nil {
+		return models.Jobs{}, err
 	}
 	return jobData, nil
 }
@@ -23,12 +58,26 @@ func (s *Service) ViewAllJobs(ctx context.Context) ([]models.Jobs, error) {
 
 }
 
-func (s *Service) AddJobDetails(ctx context.Context, jobData models.Jobs, cid uint64) (models.Jobs, error) {
+// AddJobDetails posts a job at the company, only its owners and recruiters can
+func (s *Service) AddJobDetails(ctx context.Context, actorID uint, jobData models.Jobs, cid uint64) (models.Jobs, error) {
+	err := s.canRecruit(ctx, actorID, cid)
+	if err != nil {
+		return models.Jobs{}, err
+	}
 	jobData.Cid = uint(cid)
-	jobData, err := s.UserRepo.CreateUserJob(ctx, jobData)
+	jobData.ExternalID = ""
+	err = s.record(ctx, func(tx repository.UserRepo) ([]models.DomainEvent, error) {
+		var err error
+		jobData, err = tx.CreateUserJob(ctx, jobData)
+		if err != nil {
+			return nil, err
+		}
+		return []models.DomainEvent{models.JobCreated{Job: jobData}}, nil
+	})
 	if err != nil {
 		return models.Jobs{}, err
 	}
+	s.publishJob(models.EventJobCreated, models.Jobs{}, jobData)
 	return jobData, nil
 }
 
@@ -39,3 +88,104 @@ func (s *Service) ViewJob(ctx context.Context, cid uint64) ([]models.Jobs, error
 	}
 	return jobData, nil
 }
+
+// JobsByCompanyIds loads the jobs of all the companies with one query, keyed by company id
+func (s *Service) JobsByCompanyIds(ctx context.Context, cids []uint) (map[uint][]models.Jobs, error) {
+	jobDatas, err := s.UserRepo.JobsByCids(ctx, uniqueIds(cids))
+	if err != nil {
+		return nil, err
+	}
+	jobs := make(map[uint][]models.Jobs, len(cids))
+	for _, jobData := range jobDatas {
+		jobs[jobData.Cid] = append(jobs[jobData.Cid], jobData)
+	}
+	return jobs, nil
+}
+
+// UpdateJob replaces the job if ifMatch is its current ETag, only owners and
+// recruiters of its company can and the job stays with that company
+func (s *Service) UpdateJob(ctx context.Context, actorID uint, jid uint64, jobData models.Jobs, ifMatch string) (models.Jobs, error) {
+	return s.PatchJob(ctx, actorID, jid, func(models.Jobs) (models.Jobs, error) {
+		return jobData, nil
+	}, ifMatch)
+}
+
+// PatchJob stores what apply makes of the current job if ifMatch is its ETag,
+// apply returning an error leaves the job as it is
+func (s *Service) PatchJob(ctx context.Context, actorID uint, jid uint64, apply func(models.Jobs) (models.Jobs, error), ifMatch string) (models.Jobs, error) {
+	current, err := s.recruitedJob(ctx, actorID, jid, ifMatch)
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	jobData, err := apply(current)
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	jobData.Model = current.Model
+	jobData.Cid = current.Cid
+	jobData.ExternalID = current.ExternalID
+	if jobData.Status == "" {
+		jobData.Status = models.JobPublished
+	}
+	// a status set here is the portal's own, the sync leaves it alone from now on
+	jobData.SyncClosed = current.SyncClosed && jobData.Status == current.Status
+	var event models.DomainEvent
+	err = s.record(ctx, func(tx repository.UserRepo) ([]models.DomainEvent, error) {
+		var err error
+		jobData, err = tx.UpdateJob(ctx, jobData, current.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		event = models.JobUpdated{Job: jobData}
+		if jobData.Status == models.JobClosed && current.Status != models.JobClosed {
+			event = models.JobClosedEvent{Job: jobData, ClosedAt: jobData.UpdatedAt}
+		}
+		return []models.DomainEvent{event}, nil
+	})
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	s.publishJob(event.EventType(), current, jobData)
+	return jobData, nil
+}
+
+// DeleteJob deletes the job if ifMatch is its current ETag
+func (s *Service) DeleteJob(ctx context.Context, actorID uint, jid uint64, ifMatch string) error {
+	current, err := s.recruitedJob(ctx, actorID, jid, ifMatch)
+	if err != nil {
+		return err
+	}
+	err = s.record(ctx, func(tx repository.UserRepo) ([]models.DomainEvent, error) {
+		err := tx.DeleteJob(ctx, current.ID, current.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		return []models.DomainEvent{models.JobClosedEvent{Job: current, ClosedAt: time.Now().UTC()}}, nil
+	})
+	if err != nil {
+		return err
+	}
+	s.publishJob(models.EventJobClosed, current, models.Jobs{Model: current.Model, Status: models.JobClosed})
+	return nil
+}
+
+// recruitedJob loads the job a write targets once actorID is known to recruit for
+// its company and ifMatch to be its current ETag
+func (s *Service) recruitedJob(ctx context.Context, actorID uint, jid uint64, ifMatch string) (models.Jobs, error) {
+	current, err := s.UserRepo.Jobbyjid(ctx, jid)
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	m, err := s.membership(ctx, actorID, current.Cid)
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	if !m.CanRecruit() {
+		return models.Jobs{}, ErrNotCompanyMember
+	}
+	err = checkIfMatch(ifMatch, current.ETag())
+	if err != nil {
+		return models.Jobs{}, err
+	}
+	return current, nil
+}
@@ -3,13 +3,16 @@ package service
 import (
 	"context"
 	"errors"
+	"project/internal/apperr"
 	"project/internal/auth"
 	"project/internal/models"
 	"project/internal/repository"
 	"reflect"
 	"testing"
+	"time"
 
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestService_ViewJobById(t *testing.T) {
@@ -30,7 +33,7 @@ func TestService_ViewJobById(t *testing.T) {
 				ctx: context.Background(),
 			},
 			want:    models.Jobs{},
-			wantErr: false,
+			wantErr: true,
 			mockRepoResponse: func() (models.Jobs, error) {
 				return models.Jobs{}, errors.New("test error")
 			},
@@ -74,7 +77,7 @@ func TestService_ViewJobById(t *testing.T) {
 				jid: 5,
 			},
 			want:    models.Jobs{},
-			wantErr: false,
+			wantErr: true,
 			mockRepoResponse: func() (models.Jobs, error) {
 				return models.Jobs{}, errors.New("id not found")
 			},
@@ -88,7 +91,7 @@ func TestService_ViewJobById(t *testing.T) {
 				mockRepo.EXPECT().Jobbyjid(tt.args.ctx, tt.args.jid).Return(tt.mockRepoResponse()).AnyTimes()
 			}
 			s, _ := NewService(mockRepo, &auth.Auth{})
-			got, err := s.ViewJobById(tt.args.ctx, tt.args.jid)
+			got, err := s.ViewJobById(tt.args.ctx, 4, tt.args.jid)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.ViewJobById() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -100,6 +103,25 @@ func TestService_ViewJobById(t *testing.T) {
 	}
 }
 
+func TestService_ViewJobById_draft(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	draft := models.Jobs{Model: gorm.Model{ID: 5}, Cid: 7, Name: "developer", Status: models.JobDraft}
+	mockRepo.EXPECT().Jobbyjid(gomock.Any(), uint64(5)).Return(draft, nil).Times(2)
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(3)).Return([]models.Membership{}, nil)
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{{CompanyID: 7, UserID: 4, Role: models.RoleRecruiter}}, nil)
+
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	_, err := s.ViewJobById(context.Background(), 3, 5)
+	if apperr.KindOf(err) != apperr.NotFound {
+		t.Errorf("Service.ViewJobById() of a draft by a candidate error = %v, want not found", err)
+	}
+	got, err := s.ViewJobById(context.Background(), 4, 5)
+	if err != nil || !reflect.DeepEqual(got, draft) {
+		t.Errorf("Service.ViewJobById() of a draft by its recruiter = %v, %v", got, err)
+	}
+}
+
 func TestService_ViewAllJobs(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -180,6 +202,16 @@ func TestService_AddJobDetails(t *testing.T) {
 		wantErr          bool
 		mockRepoResponse func() (models.Jobs, error)
 	}{
+		{
+			name: "not a recruiter",
+			args: args{
+				ctx:     context.Background(),
+				jobData: models.Jobs{},
+				cid:     3,
+			},
+			want:    models.Jobs{},
+			wantErr: true,
+		},
 		{
 			name: "success",
 			args: args{
@@ -220,7 +252,7 @@ func TestService_AddJobDetails(t *testing.T) {
 			args: args{
 				ctx:     context.Background(),
 				jobData: models.Jobs{},
-				cid:     0,
+				cid:     2,
 			},
 			want:    models.Jobs{},
 			wantErr: true,
@@ -233,11 +265,17 @@ func TestService_AddJobDetails(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mc := gomock.NewController(t)
 			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).
+				Return([]models.Membership{{CompanyID: 2, UserID: 4, Role: models.RoleRecruiter}}, nil)
 			if tt.mockRepoResponse != nil {
+				inTransaction(mockRepo)
 				mockRepo.EXPECT().CreateUserJob(gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
 			}
+			if !tt.wantErr {
+				mockRepo.EXPECT().AppendEvents(gomock.Any(), recorded("job.created job:0")).Return(nil)
+			}
 			s, _ := NewService(mockRepo, &auth.Auth{})
-			got, err := s.AddJobDetails(tt.args.ctx, tt.args.jobData, tt.args.cid)
+			got, err := s.AddJobDetails(tt.args.ctx, 4, tt.args.jobData, tt.args.cid)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.AddJobDetails() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -318,3 +356,183 @@ func TestService_ViewJob(t *testing.T) {
 		})
 	}
 }
+
+func TestService_JobsByCompanyIds(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().JobsByCids(gomock.Any(), []uint{2, 7}).Return([]models.Jobs{
+		{Cid: 2, Name: "developer"},
+		{Cid: 2, Name: "tester"},
+	}, nil).Times(1)
+
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	got, err := s.JobsByCompanyIds(context.Background(), []uint{2, 7, 2})
+	if err != nil {
+		t.Fatalf("Service.JobsByCompanyIds() error = %v", err)
+	}
+	want := map[uint][]models.Jobs{
+		2: {{Cid: 2, Name: "developer"}, {Cid: 2, Name: "tester"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Service.JobsByCompanyIds() = %v, want %v", got, want)
+	}
+}
+
+func TestService_DeleteJob(t *testing.T) {
+	version := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	current := models.Jobs{Model: gorm.Model{ID: 3, UpdatedAt: version}, Cid: 7}
+	tests := []struct {
+		name        string
+		memberships []models.Membership
+		ifMatch     string
+		wantErr     error
+		delete      bool
+	}{
+		{
+			name:        "member of another company",
+			memberships: []models.Membership{{CompanyID: 8, UserID: 4, Role: models.RoleOwner}},
+			ifMatch:     current.ETag(),
+			wantErr:     ErrNotCompanyMember,
+		},
+		{
+			name:        "stale etag",
+			memberships: []models.Membership{{CompanyID: 7, UserID: 4, Role: models.RoleRecruiter}},
+			ifMatch:     `"stale"`,
+			wantErr:     ErrPreconditionFailed,
+		},
+		{
+			name:        "recruiter of the company",
+			memberships: []models.Membership{{CompanyID: 7, UserID: 4, Role: models.RoleRecruiter}},
+			ifMatch:     `"stale", ` + current.ETag(),
+			delete:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockRepo.EXPECT().Jobbyjid(gomock.Any(), uint64(3)).Return(current, nil)
+			mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return(tt.memberships, nil)
+			if tt.delete {
+				inTransaction(mockRepo)
+				mockRepo.EXPECT().DeleteJob(gomock.Any(), uint(3), version).Return(nil)
+				mockRepo.EXPECT().AppendEvents(gomock.Any(), recorded("job.closed job:3")).Return(nil)
+			}
+
+			s, _ := NewService(mockRepo, &auth.Auth{})
+			err := s.DeleteJob(context.Background(), 4, 3, tt.ifMatch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.DeleteJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_PatchJob(t *testing.T) {
+	version := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	current := models.Jobs{Model: gorm.Model{ID: 3, UpdatedAt: version}, Cid: 7, Name: "developer", Status: models.JobPublished}
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().Jobbyjid(gomock.Any(), uint64(3)).Return(current, nil).Times(2)
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{{CompanyID: 7, UserID: 4, Role: models.RoleOwner}}, nil).Times(2)
+	// the id, timestamps and company of the stored job win over what apply returns
+	mockRepo.EXPECT().UpdateJob(gomock.Any(), models.Jobs{Model: current.Model, Cid: 7, Name: "tester", Status: models.JobClosed}, version).
+		Return(models.Jobs{Model: current.Model, Cid: 7, Name: "tester", Status: models.JobClosed}, nil)
+	// closing a published job is recorded as job.closed rather than job.updated
+	inTransaction(mockRepo)
+	mockRepo.EXPECT().AppendEvents(gomock.Any(), recorded("job.closed job:3")).Return(nil)
+	s, _ := NewService(mockRepo, &auth.Auth{})
+
+	_, err := s.PatchJob(context.Background(), 4, 3, func(jobData models.Jobs) (models.Jobs, error) {
+		return models.Jobs{}, ErrPreconditionFailed
+	}, current.ETag())
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Service.PatchJob() error = %v, want the error of apply", err)
+	}
+
+	got, err := s.PatchJob(context.Background(), 4, 3, func(jobData models.Jobs) (models.Jobs, error) {
+		if !reflect.DeepEqual(jobData, current) {
+			t.Errorf("apply got %v, want %v", jobData, current)
+		}
+		return models.Jobs{Model: gorm.Model{ID: 9}, Cid: 8, Name: "tester", Status: models.JobClosed}, nil
+	}, current.ETag())
+	if err != nil || got.Name != "tester" {
+		t.Errorf("Service.PatchJob() = %v, %v", got, err)
+	}
+}
+
+// TestService_PatchJob_syncClosed checks that a status set in the portal takes
+// the job out of the hands of the sync, an edit keeping the status does not
+func TestService_PatchJob_syncClosed(t *testing.T) {
+	version := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	current := models.Jobs{Model: gorm.Model{ID: 3, UpdatedAt: version}, Cid: 7, Name: "developer", Status: models.JobClosed, SyncClosed: true, ExternalID: "lever:a"}
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	mockRepo.EXPECT().Jobbyjid(gomock.Any(), uint64(3)).Return(current, nil).Times(2)
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{{CompanyID: 7, UserID: 4, Role: models.RoleOwner}}, nil).Times(2)
+	inTransaction(mockRepo).Times(2)
+	mockRepo.EXPECT().AppendEvents(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	edited := current
+	edited.Name = "tester"
+	mockRepo.EXPECT().UpdateJob(gomock.Any(), edited, version).Return(edited, nil)
+	drafted := current
+	drafted.Status, drafted.SyncClosed = models.JobDraft, false
+	mockRepo.EXPECT().UpdateJob(gomock.Any(), drafted, version).Return(drafted, nil)
+	s, _ := NewService(mockRepo, &auth.Auth{})
+
+	for _, patch := range []models.Jobs{{Name: "tester", Status: models.JobClosed}, {Name: "developer", Status: models.JobDraft}} {
+		_, err := s.PatchJob(context.Background(), 4, 3, func(models.Jobs) (models.Jobs, error) {
+			return patch, nil
+		}, current.ETag())
+		if err != nil {
+			t.Errorf("Service.PatchJob() to %+v error = %v", patch, err)
+		}
+	}
+}
+
+func TestService_PublicJob(t *testing.T) {
+	tek := models.Company{Model: gorm.Model{ID: 7}, Name: "tek"}
+	tests := []struct {
+		name    string
+		setup   func(r *repository.MockUserRepo)
+		want    models.Jobs
+		wantErr bool
+	}{
+		{
+			name: "closed job with its company",
+			setup: func(r *repository.MockUserRepo) {
+				r.EXPECT().Jobbyjid(gomock.Any(), uint64(11)).Return(models.Jobs{Model: gorm.Model{ID: 11}, Cid: 7, Status: models.JobClosed}, nil)
+				r.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(tek, nil)
+			},
+			want: models.Jobs{Model: gorm.Model{ID: 11}, Company: tek, Cid: 7, Status: models.JobClosed},
+		},
+		{
+			name: "draft",
+			setup: func(r *repository.MockUserRepo) {
+				r.EXPECT().Jobbyjid(gomock.Any(), uint64(11)).Return(models.Jobs{Model: gorm.Model{ID: 11}, Cid: 7, Status: models.JobDraft}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown job",
+			setup: func(r *repository.MockUserRepo) {
+				r.EXPECT().Jobbyjid(gomock.Any(), uint64(11)).Return(models.Jobs{}, errors.New("not found"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+			tt.setup(mockRepo)
+			s, _ := NewService(mockRepo, &auth.Auth{})
+
+			got, err := s.PublicJob(context.Background(), 11)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Service.PublicJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.PublicJob() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project/internal/apperr"
+	"project/internal/inbox"
+	"project/internal/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (s *Service) Notifications(ctx context.Context, userID uint, unreadOnly bool) ([]models.Notification, error) {
+	notifications, err := s.UserRepo.Notifications(ctx, userID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+	return notifications, nil
+}
+
+// MarkNotifications marks notifications of the user read or unread and tells every
+// session of the user, ids of notifications of other users are ignored
+func (s *Service) MarkNotifications(ctx context.Context, userID uint, ids []uint, read bool) error {
+	var readAt *time.Time
+	msg := models.NotificationMessage{Type: models.MessageUnread, IDs: ids}
+	if read {
+		now := time.Now().UTC()
+		readAt = &now
+		msg.Type = models.MessageRead
+	}
+	err := s.UserRepo.MarkNotifications(ctx, userID, ids, readAt)
+	if err != nil {
+		return err
+	}
+	s.inbox.Publish(userID, msg)
+	return nil
+}
+
+// JoinNotifications opens a session receiving the notification messages of the
+// user and returns the unread notifications it starts from. A notification sent
+// while they load can be in both
+func (s *Service) JoinNotifications(ctx context.Context, userID uint) (*inbox.Session, []models.Notification, error) {
+	session, err := s.inbox.Join(userID)
+	if errors.Is(err, inbox.ErrClosed) {
+		return nil, nil, apperr.Wrap(apperr.Unavailable, err, "notifications are shutting down, reconnect later")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	unread, err := s.Notifications(ctx, userID, true)
+	if err != nil {
+		session.Close()
+		return nil, nil, err
+	}
+	return session, unread, nil
+}
+
+// sendNotification stores the notification and pushes it to the sessions of its
+// user. It reports a change already made, so a failure is logged rather than returned
+func (s *Service) sendNotification(ctx context.Context, n models.Notification) {
+	created, err := s.UserRepo.CreateNotification(ctx, n)
+	if err != nil {
+		log.Error().Err(err).Str("type", n.Type).Uint("user", n.UserID).Msg("notification not sent")
+		return
+	}
+	s.inbox.Publish(created.UserID, models.NotificationMessage{
+		Type:          models.MessageNotification,
+		Notifications: []models.Notification{created},
+	})
+}
+
+// companyName names the company in a notification, one that cannot be found is
+// left unnamed rather than failing what is being notified
+func (s *Service) companyName(ctx context.Context, cid uint) string {
+	company, err := s.UserRepo.CompanyById(ctx, uint64(cid))
+	if err != nil || company.Name == "" {
+		return fmt.Sprintf("company %d", cid)
+	}
+	return company.Name
+}
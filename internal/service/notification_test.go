@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/inbox"
+	"project/internal/models"
+	"project/internal/repository"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_MarkNotifications(t *testing.T) {
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	mockRepo.EXPECT().MarkNotifications(gomock.Any(), uint(4), []uint{3, 5}, gomock.Not(gomock.Nil())).Return(nil)
+	mockRepo.EXPECT().MarkNotifications(gomock.Any(), uint(4), []uint{3}, gomock.Nil()).Return(nil)
+	mockRepo.EXPECT().MarkNotifications(gomock.Any(), uint(4), []uint{9}, gomock.Any()).Return(errors.New("connection refused"))
+
+	hub := inbox.NewHub()
+	phone, _ := hub.Join(4)
+	laptop, _ := hub.Join(4)
+	s, _ := NewService(mockRepo, &auth.Auth{}, WithInbox(hub))
+
+	for _, step := range []struct {
+		ids  []uint
+		read bool
+		want string
+	}{
+		{[]uint{3, 5}, true, models.MessageRead},
+		{[]uint{3}, false, models.MessageUnread},
+	} {
+		err := s.MarkNotifications(context.Background(), 4, step.ids, step.read)
+		if err != nil {
+			t.Fatalf("Service.MarkNotifications() error = %v", err)
+		}
+		for name, session := range map[string]*inbox.Session{"phone": phone, "laptop": laptop} {
+			select {
+			case msg := <-session.Messages():
+				if msg.Type != step.want || len(msg.IDs) != len(step.ids) {
+					t.Errorf("the %s got %+v, want %s of %v", name, msg, step.want, step.ids)
+				}
+			default:
+				t.Errorf("the %s was not told about %v", name, step.ids)
+			}
+		}
+	}
+
+	// nothing is announced when storing failed
+	if err := s.MarkNotifications(context.Background(), 4, []uint{9}, true); err == nil {
+		t.Error("Service.MarkNotifications() error = nil, want the repository error")
+	}
+	select {
+	case msg := <-phone.Messages():
+		t.Errorf("the phone got %+v for a change that was not stored", msg)
+	default:
+	}
+}
+
+func TestService_JoinNotifications(t *testing.T) {
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	mockRepo.EXPECT().Notifications(gomock.Any(), uint(4), true).Return(nil, nil)
+
+	hub := inbox.NewHub()
+	s, _ := NewService(mockRepo, &auth.Auth{}, WithInbox(hub))
+	session, unread, err := s.JoinNotifications(context.Background(), 4)
+	if err != nil || unread == nil || len(unread) != 0 {
+		t.Fatalf("Service.JoinNotifications() = %v, %v, want no unread notifications", unread, err)
+	}
+	defer session.Close()
+	if hub.Sessions(4) != 1 {
+		t.Errorf("the user has %d sessions, want 1", hub.Sessions(4))
+	}
+
+	hub.Close()
+	_, _, err = s.JoinNotifications(context.Background(), 4)
+	if !errors.Is(err, apperr.ErrUnavailable) {
+		t.Errorf("Service.JoinNotifications() after the hub closed error = %v, want %v", err, apperr.ErrUnavailable)
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/apperr"
+	"project/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// recommendationPool is how many of the newest published jobs are scored for a candidate
+const recommendationPool = 1000
+
+func (s *Service) ViewProfile(ctx context.Context, userID uint) (models.Profile, error) {
+	profile, err := s.UserRepo.ProfileByUserID(ctx, userID)
+	if err != nil {
+		return models.Profile{}, err
+	}
+	return profile, nil
+}
+
+// SaveProfile creates the profile of the user, or replaces it if ifMatch is its
+// current ETag. A profile that does not exist yet matches no ETag
+func (s *Service) SaveProfile(ctx context.Context, userID uint, profile models.Profile, ifMatch string) (models.Profile, error) {
+	var version time.Time
+	current, err := s.UserRepo.ProfileByUserID(ctx, userID)
+	switch {
+	case errors.Is(err, apperr.ErrNotFound):
+		if ifMatch != "" {
+			return models.Profile{}, ErrPreconditionFailed
+		}
+	case err != nil:
+		return models.Profile{}, err
+	default:
+		err = checkIfMatch(ifMatch, current.ETag())
+		if err != nil {
+			return models.Profile{}, err
+		}
+		version = current.UpdatedAt
+	}
+
+	profile.Model = gorm.Model{}
+	profile.UserID = userID
+	profile, err = s.UserRepo.SaveProfile(ctx, profile, version)
+	if err != nil {
+		return models.Profile{}, err
+	}
+	return profile, nil
+}
+
+func (s *Service) Recommendations(ctx context.Context, userID uint, limit int) ([]models.Recommendation, error) {
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	profile, err := s.UserRepo.ProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	jobDatas, err := s.UserRepo.PublishedJobs(ctx, recommendationPool)
+	if err != nil {
+		return nil, err
+	}
+	recommendations, err := s.recommender.Recommend(ctx, profile, jobDatas)
+	if err != nil {
+		return nil, err
+	}
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/models"
+	"project/internal/recommend"
+	"project/internal/repository"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_SaveProfile(t *testing.T) {
+	saved := models.Profile{Model: gorm.Model{ID: 1}, UserID: 4, Skills: []string{"go"}}
+	version := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	current := models.Profile{Model: gorm.Model{ID: 1, UpdatedAt: version}, UserID: 4}
+	tests := []struct {
+		name      string
+		ifMatch   string
+		wantErr   error
+		setupMock func(r *repository.MockUserRepo)
+	}{
+		{
+			name: "create",
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().ProfileByUserID(gomock.Any(), uint(4)).Return(models.Profile{}, apperr.New(apperr.NotFound, "profile not found"))
+				r.EXPECT().SaveProfile(gomock.Any(), models.Profile{UserID: 4, Skills: []string{"go"}}, time.Time{}).Return(saved, nil)
+			},
+		},
+		{
+			name:    "create with an etag",
+			ifMatch: current.ETag(),
+			wantErr: ErrPreconditionFailed,
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().ProfileByUserID(gomock.Any(), uint(4)).Return(models.Profile{}, apperr.New(apperr.NotFound, "profile not found"))
+			},
+		},
+		{
+			name:    "replace without an etag",
+			wantErr: ErrPreconditionRequired,
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().ProfileByUserID(gomock.Any(), uint(4)).Return(current, nil)
+			},
+		},
+		{
+			name:    "replace with a stale etag",
+			ifMatch: `"stale"`,
+			wantErr: ErrPreconditionFailed,
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().ProfileByUserID(gomock.Any(), uint(4)).Return(current, nil)
+			},
+		},
+		{
+			name:    "replace",
+			ifMatch: current.ETag(),
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().ProfileByUserID(gomock.Any(), uint(4)).Return(current, nil)
+				r.EXPECT().SaveProfile(gomock.Any(), models.Profile{UserID: 4, Skills: []string{"go"}}, version).Return(saved, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			tt.setupMock(mockRepo)
+
+			s, _ := NewService(mockRepo, &auth.Auth{})
+			// the id and owner always come from the caller, never from the body
+			got, err := s.SaveProfile(context.Background(), 4, models.Profile{Model: gorm.Model{ID: 9}, UserID: 5, Skills: []string{"go"}}, tt.ifMatch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.SaveProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got.ID != 1 || got.UserID != 4) {
+				t.Errorf("Service.SaveProfile() = %v", got)
+			}
+		})
+	}
+}
+
+func TestService_Recommendations(t *testing.T) {
+	profile := models.Profile{UserID: 4, Skills: []string{"go"}}
+	jobs := make([]models.Jobs, 30)
+	ranked := make([]models.Recommendation, 30)
+	for i := range ranked {
+		ranked[i] = models.Recommendation{Job: models.Jobs{Model: gorm.Model{ID: uint(i + 1)}}, Score: 1}
+	}
+	tests := []struct {
+		name      string
+		limit     int
+		want      []models.Recommendation
+		wantErr   bool
+		setupMock func(r *repository.MockUserRepo, e *recommend.MockEngine)
+	}{
+		{
+			name:  "default limit",
+			limit: 0,
+			want:  ranked[:20],
+			setupMock: func(r *repository.MockUserRepo, e *recommend.MockEngine) {
+				r.EXPECT().ProfileByUserID(gomock.Any(), uint(4)).Return(profile, nil)
+				r.EXPECT().PublishedJobs(gomock.Any(), 1000).Return(jobs, nil)
+				e.EXPECT().Recommend(gomock.Any(), profile, jobs).Return(ranked, nil)
+			},
+		},
+		{
+			name:  "fewer than the limit",
+			limit: 50,
+			want:  ranked[:2],
+			setupMock: func(r *repository.MockUserRepo, e *recommend.MockEngine) {
+				r.EXPECT().ProfileByUserID(gomock.Any(), uint(4)).Return(profile, nil)
+				r.EXPECT().PublishedJobs(gomock.Any(), 1000).Return(jobs[:2], nil)
+				e.EXPECT().Recommend(gomock.Any(), profile, jobs[:2]).Return(ranked[:2], nil)
+			},
+		},
+		{
+			name:    "no profile",
+			limit:   5,
+			wantErr: true,
+			setupMock: func(r *repository.MockUserRepo, e *recommend.MockEngine) {
+				r.EXPECT().ProfileByUserID(gomock.Any(), uint(4)).Return(models.Profile{}, errors.New("profile not found"))
+			},
+		},
+		{
+			name:    "engine failure",
+			limit:   5,
+			wantErr: true,
+			setupMock: func(r *repository.MockUserRepo, e *recommend.MockEngine) {
+				r.EXPECT().ProfileByUserID(gomock.Any(), uint(4)).Return(profile, nil)
+				r.EXPECT().PublishedJobs(gomock.Any(), 1000).Return(jobs, nil)
+				e.EXPECT().Recommend(gomock.Any(), profile, jobs).Return(nil, errors.New("model unavailable"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			mockEngine := recommend.NewMockEngine(mc)
+			tt.setupMock(mockRepo, mockEngine)
+
+			s, _ := NewService(mockRepo, &auth.Auth{}, WithRecommender(mockEngine))
+			got, err := s.Recommendations(context.Background(), 4, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.Recommendations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.Recommendations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"project/internal/models"
+	"strings"
+)
+
+// paging limits of a job search
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+func (s *Service) SearchJobs(ctx context.Context, filter models.JobFilter) (models.JobSearchResult, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultPageSize
+	}
+	if filter.PageSize > maxPageSize {
+		filter.PageSize = maxPageSize
+	}
+	filter = s.matching(filter)
+
+	jobDatas, total, err := s.UserRepo.SearchJobs(ctx, filter)
+	if err != nil {
+		return models.JobSearchResult{}, err
+	}
+	facets, err := s.UserRepo.JobFacets(ctx, filter)
+	if err != nil {
+		return models.JobSearchResult{}, err
+	}
+	if jobDatas == nil {
+		jobDatas = []models.Jobs{}
+	}
+	result := models.JobSearchResult{
+		Jobs:     jobDatas,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Facets:   facets,
+	}
+
+	if filter.Query != "" && total < s.suggestBelow {
+		result.Suggestion, err = s.UserRepo.SuggestJobTerm(ctx, filter.Query, s.fuzzyThreshold)
+		if err != nil {
+			return models.JobSearchResult{}, err
+		}
+	}
+	return result, nil
+}
+
+// ExportJobs calls fn with every job the search for filter finds, not just one page
+func (s *Service) ExportJobs(ctx context.Context, filter models.JobFilter, fn func(models.Jobs) error) error {
+	return s.UserRepo.StreamJobs(ctx, s.matching(filter), fn)
+}
+
+// matching sets how closely names have to match the query of filter
+func (s *Service) matching(filter models.JobFilter) models.JobFilter {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Query != "" {
+		filter.Similarity = s.fuzzyThreshold
+	}
+	return filter
+}
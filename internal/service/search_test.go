@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/auth"
+	"project/internal/models"
+	"project/internal/repository"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_SearchJobs(t *testing.T) {
+	facets := models.JobFacets{
+		Company:      []models.FacetCount{{Value: "2", Label: "tcs", Count: 3}},
+		Location:     []models.FacetCount{{Value: "bang", Count: 2}, {Value: "mysore", Count: 1}},
+		SalaryBucket: []models.FacetCount{{Value: "300000-600000", Count: 1}},
+	}
+	tests := []struct {
+		name            string
+		filter          models.JobFilter
+		wantFilter      models.JobFilter
+		want            models.JobSearchResult
+		wantErr         bool
+		searchResponse  func() ([]models.Jobs, int64, error)
+		facetResponse   func() (models.JobFacets, error)
+		suggestResponse func() (string, error)
+	}{
+		{
+			name:       "default paging",
+			filter:     models.JobFilter{Location: []string{"bang"}},
+			wantFilter: models.JobFilter{Location: []string{"bang"}, Page: 1, PageSize: 20},
+			want: models.JobSearchResult{
+				Jobs:     []models.Jobs{{Cid: 2, Name: "developer", Location: "bang"}},
+				Total:    1,
+				Page:     1,
+				PageSize: 20,
+				Facets:   facets,
+			},
+			searchResponse: func() ([]models.Jobs, int64, error) {
+				return []models.Jobs{{Cid: 2, Name: "developer", Location: "bang"}}, 1, nil
+			},
+			facetResponse: func() (models.JobFacets, error) {
+				return facets, nil
+			},
+		},
+		{
+			name:       "page size capped and empty result",
+			filter:     models.JobFilter{Page: 3, PageSize: 500},
+			wantFilter: models.JobFilter{Page: 3, PageSize: 100},
+			want: models.JobSearchResult{
+				Jobs:     []models.Jobs{},
+				Total:    0,
+				Page:     3,
+				PageSize: 100,
+			},
+			searchResponse: func() ([]models.Jobs, int64, error) {
+				return nil, 0, nil
+			},
+			facetResponse: func() (models.JobFacets, error) {
+				return models.JobFacets{}, nil
+			},
+		},
+		{
+			name:       "typo with few results gets a suggestion",
+			filter:     models.JobFilter{Query: "  sofware enginer "},
+			wantFilter: models.JobFilter{Query: "sofware enginer", Similarity: 0.4, Page: 1, PageSize: 20},
+			want: models.JobSearchResult{
+				Jobs:       []models.Jobs{},
+				Page:       1,
+				PageSize:   20,
+				Suggestion: "Software Engineer",
+			},
+			searchResponse: func() ([]models.Jobs, int64, error) {
+				return []models.Jobs{}, 0, nil
+			},
+			facetResponse: func() (models.JobFacets, error) {
+				return models.JobFacets{}, nil
+			},
+			suggestResponse: func() (string, error) {
+				return "Software Engineer", nil
+			},
+		},
+		{
+			name:       "query with enough results has no suggestion",
+			filter:     models.JobFilter{Query: "developer"},
+			wantFilter: models.JobFilter{Query: "developer", Similarity: 0.4, Page: 1, PageSize: 20},
+			want: models.JobSearchResult{
+				Jobs:     []models.Jobs{{Name: "developer"}, {Name: "developer"}, {Name: "developer"}},
+				Total:    3,
+				Page:     1,
+				PageSize: 20,
+			},
+			searchResponse: func() ([]models.Jobs, int64, error) {
+				return []models.Jobs{{Name: "developer"}, {Name: "developer"}, {Name: "developer"}}, 3, nil
+			},
+			facetResponse: func() (models.JobFacets, error) {
+				return models.JobFacets{}, nil
+			},
+		},
+		{
+			name:       "suggestion failure",
+			filter:     models.JobFilter{Query: "devloper"},
+			wantFilter: models.JobFilter{Query: "devloper", Similarity: 0.4, Page: 1, PageSize: 20},
+			want:       models.JobSearchResult{},
+			wantErr:    true,
+			searchResponse: func() ([]models.Jobs, int64, error) {
+				return []models.Jobs{}, 0, nil
+			},
+			facetResponse: func() (models.JobFacets, error) {
+				return models.JobFacets{}, nil
+			},
+			suggestResponse: func() (string, error) {
+				return "", errors.New("could not suggest a search term")
+			},
+		},
+		{
+			name:       "search failure",
+			filter:     models.JobFilter{},
+			wantFilter: models.JobFilter{Page: 1, PageSize: 20},
+			want:       models.JobSearchResult{},
+			wantErr:    true,
+			searchResponse: func() ([]models.Jobs, int64, error) {
+				return nil, 0, errors.New("could not find the jobs")
+			},
+		},
+		{
+			name:       "facet failure",
+			filter:     models.JobFilter{},
+			wantFilter: models.JobFilter{Page: 1, PageSize: 20},
+			want:       models.JobSearchResult{},
+			wantErr:    true,
+			searchResponse: func() ([]models.Jobs, int64, error) {
+				return []models.Jobs{}, 0, nil
+			},
+			facetResponse: func() (models.JobFacets, error) {
+				return models.JobFacets{}, errors.New("could not count the company facet")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			ctx := context.Background()
+			mockRepo.EXPECT().SearchJobs(ctx, tt.wantFilter).Return(tt.searchResponse()).Times(1)
+			if tt.facetResponse != nil {
+				mockRepo.EXPECT().JobFacets(ctx, tt.wantFilter).Return(tt.facetResponse()).Times(1)
+			}
+			if tt.suggestResponse != nil {
+				mockRepo.EXPECT().SuggestJobTerm(ctx, tt.wantFilter.Query, 0.4).Return(tt.suggestResponse()).Times(1)
+			}
+			s, _ := NewService(mockRepo, &auth.Auth{})
+			got, err := s.SearchJobs(ctx, tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.SearchJobs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.SearchJobs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewService_FuzzyThreshold(t *testing.T) {
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	if _, err := NewService(mockRepo, &auth.Auth{}, WithFuzzyThreshold(1.5)); err == nil {
+		t.Errorf("NewService() with threshold 1.5 should fail")
+	}
+
+	s, err := NewService(mockRepo, &auth.Auth{}, WithFuzzyThreshold(0.7), WithSuggestionBelow(1))
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	filter := models.JobFilter{Query: "tcs", Similarity: 0.7, Page: 1, PageSize: 20}
+	mockRepo.EXPECT().SearchJobs(gomock.Any(), filter).Return([]models.Jobs{{Name: "developer"}}, int64(1), nil)
+	mockRepo.EXPECT().JobFacets(gomock.Any(), filter).Return(models.JobFacets{}, nil)
+	if _, err := s.SearchJobs(context.Background(), models.JobFilter{Query: "tcs"}); err != nil {
+		t.Errorf("Service.SearchJobs() error = %v", err)
+	}
+}
+
+func TestService_ExportJobs(t *testing.T) {
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	s, _ := NewService(mockRepo, &auth.Auth{}, WithFuzzyThreshold(0.6))
+	// the query matches as in a search, paging is left to the export
+	mockRepo.EXPECT().StreamJobs(gomock.Any(), models.JobFilter{Query: "tcs", Similarity: 0.6, Page: 2}, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, filter models.JobFilter, fn func(models.Jobs) error) error {
+			return fn(models.Jobs{Name: "developer"})
+		})
+
+	var got []models.Jobs
+	err := s.ExportJobs(context.Background(), models.JobFilter{Query: " tcs ", Page: 2}, func(jobData models.Jobs) error {
+		got = append(got, jobData)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Service.ExportJobs() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []models.Jobs{{Name: "developer"}}) {
+		t.Errorf("Service.ExportJobs() exported %v", got)
+	}
+}
@@ -2,39 +2,213 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"project/internal/auth"
+	"project/internal/email"
+	"project/internal/imports"
+	"project/internal/inbox"
+	"project/internal/jobstream"
 	"project/internal/models"
+	"project/internal/recommend"
 	"project/internal/repository"
 )
 
 type Service struct {
 	UserRepo repository.UserRepo
 	auth     auth.UserAuth
+
+	fuzzyThreshold float64
+	suggestBelow   int64
+	recommender    recommend.Engine
+	jobs           *jobstream.Broker
+	inbox          *inbox.Hub
+	emails         *email.Templates
+	tokens         *email.Tokens
+	links          email.Links
+	admins         map[uint]bool
+}
+
+// Option changes the default configuration of the service
+type Option func(*Service)
+
+// WithFuzzyThreshold sets the minimum similarity, from 0 to 1, a job or company
+// name needs to match a search query
+func WithFuzzyThreshold(threshold float64) Option {
+	return func(s *Service) {
+		s.fuzzyThreshold = threshold
+	}
+}
+
+// WithSuggestionBelow makes searches finding fewer than n jobs return a "did you mean" suggestion
+func WithSuggestionBelow(n int64) Option {
+	return func(s *Service) {
+		s.suggestBelow = n
+	}
 }
 
 //go:generate mockgen -source=ser.go -destination=mock-files/ser_mock.go -package=mock_files
 type UserService interface {
 	UserSignup(ctx context.Context, userData models.NewUser) (models.User, error)
 	UserLogin(ctx context.Context, userData models.NewUser) (string, error)
+	ViewUser(ctx context.Context, userID uint) (models.User, error)
 
-	AddCompanyDetails(ctx context.Context, companyData models.Company) (models.Company, error)
+	AddCompanyDetails(ctx context.Context, companyData models.Company, ownerID uint) (models.Company, error)
 	ViewAllCompanies(ctx context.Context) ([]models.Company, error)
 	ViewCompanyDetails(ctx context.Context, cid uint64) (models.Company, error)
+	CompaniesByIds(ctx context.Context, ids []uint) (map[uint]models.Company, error)
+	UpdateCompany(ctx context.Context, actorID uint, cid uint64, companyData models.Company, ifMatch string) (models.Company, error)
+	PatchCompany(ctx context.Context, actorID uint, cid uint64, apply func(models.Company) (models.Company, error), ifMatch string) (models.Company, error)
+	DeleteCompany(ctx context.Context, actorID uint, cid uint64, ifMatch string) error
 	ViewJob(ctx context.Context, cid uint64) ([]models.Jobs, error)
+	ExportCompanies(ctx context.Context, fn func(models.Company) error) error
 
-	AddJobDetails(ctx context.Context, jobData models.Jobs, cid uint64) (models.Jobs, error)
+	AddJobDetails(ctx context.Context, actorID uint, jobData models.Jobs, cid uint64) (models.Jobs, error)
 	ViewAllJobs(ctx context.Context) ([]models.Jobs, error)
-	ViewJobById(ctx context.Context, jid uint64) (models.Jobs, error)
+	ViewJobById(ctx context.Context, actorID uint, jid uint64) (models.Jobs, error)
+	PublicJob(ctx context.Context, jid uint64) (models.Jobs, error)
+	JobsByCompanyIds(ctx context.Context, cids []uint) (map[uint][]models.Jobs, error)
+	UpdateJob(ctx context.Context, actorID uint, jid uint64, jobData models.Jobs, ifMatch string) (models.Jobs, error)
+	PatchJob(ctx context.Context, actorID uint, jid uint64, apply func(models.Jobs) (models.Jobs, error), ifMatch string) (models.Jobs, error)
+	DeleteJob(ctx context.Context, actorID uint, jid uint64, ifMatch string) error
+	SearchJobs(ctx context.Context, filter models.JobFilter) (models.JobSearchResult, error)
+	ExportJobs(ctx context.Context, filter models.JobFilter, fn func(models.Jobs) error) error
+	JobFeed(ctx context.Context, filter models.JobFilter) (models.JobFeed, error)
+	CompanyJobFeed(ctx context.Context, cid uint64, filter models.JobFilter) (models.JobFeed, error)
+	ExportPublishedJobs(ctx context.Context, fn func(models.Jobs) error) error
+	Sitemap(ctx context.Context) (models.Sitemap, error)
+	SitemapPage(ctx context.Context, page int) (models.SitemapPage, error)
+	FollowJobs(ctx context.Context, filter models.JobFilter, lastEventID uint64) (*jobstream.Subscription, error)
+
+	ImportCompanies(ctx context.Context, actorID uint, rows imports.Source[models.Company], opts models.ImportOptions) (models.ImportReport, error)
+	ImportJobs(ctx context.Context, actorID uint, rows imports.Source[models.JobRow], opts models.ImportOptions) (models.ImportReport, error)
+
+	ViewProfile(ctx context.Context, userID uint) (models.Profile, error)
+	SaveProfile(ctx context.Context, userID uint, profile models.Profile, ifMatch string) (models.Profile, error)
+	Recommendations(ctx context.Context, userID uint, limit int) ([]models.Recommendation, error)
+
+	VerifyCompany(ctx context.Context, actorID uint, cid uint64) (models.CompanyVerification, error)
+	AddMember(ctx context.Context, actorID uint, membership models.Membership) (models.Membership, error)
+	SearchTalent(ctx context.Context, recruiterID uint, filter models.TalentFilter) (models.TalentSearchResult, error)
+	ViewCandidate(ctx context.Context, recruiterID uint, candidateID uint) (models.Candidate, error)
+	ProfileViews(ctx context.Context, userID uint) ([]models.ProfileView, error)
+
+	CreateWebhook(ctx context.Context, actorID uint, cid uint64, hook models.Webhook) (models.Webhook, error)
+	Webhooks(ctx context.Context, actorID uint, cid uint64) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, actorID uint, cid uint64, wid uint64) error
+	WebhookDeliveries(ctx context.Context, actorID uint, cid uint64, wid uint64) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, actorID uint, cid uint64, wid uint64, did uint64) (models.WebhookDelivery, error)
+
+	CreateConnector(ctx context.Context, actorID uint, cid uint64, connector models.ATSConnector) (models.ATSConnector, error)
+	Connectors(ctx context.Context, actorID uint, cid uint64) ([]models.ATSConnector, error)
+	DeleteConnector(ctx context.Context, actorID uint, cid uint64, id uint64) error
+	SyncATSJobs(ctx context.Context, connector models.ATSConnector, postings []models.Jobs) (models.ATSSync, error)
+	WebhookEvent(ctx context.Context, event models.DomainEvent) error
+
+	EmailEvent(ctx context.Context, event models.DomainEvent) error
+	PreviewEmail(ctx context.Context, actorID uint, template string, locale string) (models.RenderedEmail, error)
+	Unsubscribe(ctx context.Context, token string) (models.Unsubscribed, error)
+	VerifyUnsubscribe(ctx context.Context, token string) error
+
+	Notifications(ctx context.Context, userID uint, unreadOnly bool) ([]models.Notification, error)
+	MarkNotifications(ctx context.Context, userID uint, ids []uint, read bool) error
+	JoinNotifications(ctx context.Context, userID uint) (*inbox.Session, []models.Notification, error)
+}
+
+// WithJobStream publishes the job events on b, by default the service has a broker
+// of its own keeping jobstream.DefaultReplay events
+func WithJobStream(b *jobstream.Broker) Option {
+	return func(s *Service) {
+		s.jobs = b
+	}
+}
+
+// WithInbox pushes notifications to the sessions connected to h, by default the
+// service has a hub of its own
+func WithInbox(h *inbox.Hub) Option {
+	return func(s *Service) {
+		s.inbox = h
+	}
+}
+
+// WithEmail signs the unsubscribe links of emails with tokens and points the
+// links of emails at baseURL. By default tokens have a random key, so links sent
+// before a restart stop working, and emails point at http://localhost:8099
+func WithEmail(tokens *email.Tokens, baseURL string) Option {
+	return func(s *Service) {
+		s.tokens = tokens
+		s.links = email.Links{BaseURL: baseURL}
+	}
 }
 
-func NewService(userRepo repository.UserRepo, a auth.UserAuth) (
+// WithAdmins lets the users run the admin tools of the portal, there are none
+// by default
+func WithAdmins(userIDs ...uint) Option {
+	return func(s *Service) {
+		for _, id := range userIDs {
+			s.admins[id] = true
+		}
+	}
+}
+
+// WithRecommender replaces the engine ranking job recommendations, the default
+// is a recommend.Weighted engine with recommend.DefaultWeights
+func WithRecommender(e recommend.Engine) Option {
+	return func(s *Service) {
+		s.recommender = e
+	}
+}
+
+func NewService(userRepo repository.UserRepo, a auth.UserAuth, opts ...Option) (
 	UserService, error) {
 	if userRepo == nil {
 		return nil, errors.New("interface cannot be null")
 	}
-	return &Service{
-		UserRepo: userRepo,
-		auth:     a,
-	}, nil
+	s := &Service{
+		UserRepo:       userRepo,
+		auth:           a,
+		fuzzyThreshold: 0.4,
+		suggestBelow:   3,
+		links:          email.Links{BaseURL: "http://localhost:8099"},
+		admins:         make(map[uint]bool),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.recommender == nil {
+		e, err := recommend.NewWeighted(recommend.DefaultWeights())
+		if err != nil {
+			return nil, err
+		}
+		s.recommender = e
+	}
+	if s.jobs == nil {
+		b, err := jobstream.NewBroker(jobstream.DefaultReplay)
+		if err != nil {
+			return nil, err
+		}
+		s.jobs = b
+	}
+	if s.inbox == nil {
+		s.inbox = inbox.NewHub()
+	}
+	if s.emails == nil {
+		t, err := email.DefaultTemplates()
+		if err != nil {
+			return nil, err
+		}
+		s.emails = t
+	}
+	if s.tokens == nil {
+		key := make([]byte, 32)
+		_, err := rand.Read(key)
+		if err != nil {
+			return nil, err
+		}
+		s.tokens, _ = email.NewTokens(key)
+	}
+	if s.fuzzyThreshold < 0 || s.fuzzyThreshold > 1 {
+		return nil, errors.New("fuzzy threshold must be between 0 and 1")
+	}
+	return s, nil
 }
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"project/internal/apperr"
+	"project/internal/models"
+	"project/internal/sitemap"
+)
+
+// Sitemap is the state of the sitemap of the published jobs, split in pages of
+// sitemap.MaxURLs. There is always at least one page, empty when nothing is published
+func (s *Service) Sitemap(ctx context.Context) (models.Sitemap, error) {
+	count, modified, err := s.UserRepo.SitemapState(ctx)
+	if err != nil {
+		return models.Sitemap{}, err
+	}
+	pages := int((count + sitemap.MaxURLs - 1) / sitemap.MaxURLs)
+	if pages == 0 {
+		pages = 1
+	}
+	return models.Sitemap{Jobs: count, Pages: pages, Updated: modified}, nil
+}
+
+// SitemapPage lists the published jobs of one page of the sitemap, pages count from 1
+func (s *Service) SitemapPage(ctx context.Context, page int) (models.SitemapPage, error) {
+	state, err := s.Sitemap(ctx)
+	if err != nil {
+		return models.SitemapPage{}, err
+	}
+	if page < 1 || page > state.Pages {
+		return models.SitemapPage{}, apperr.New(apperr.NotFound, "could not find the sitemap page")
+	}
+	jobs, err := s.UserRepo.SitemapJobs(ctx, (page-1)*sitemap.MaxURLs, sitemap.MaxURLs)
+	if err != nil {
+		return models.SitemapPage{}, err
+	}
+	return models.SitemapPage{Sitemap: state, Page: page, Jobs: jobs}, nil
+}
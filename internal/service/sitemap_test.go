@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"project/internal/auth"
+	"project/internal/models"
+	"project/internal/repository"
+	"project/internal/sitemap"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_Sitemap(t *testing.T) {
+	updated := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name  string
+		count int64
+		want  int
+	}{
+		{name: "nothing published", count: 0, want: 1},
+		{name: "full page", count: sitemap.MaxURLs, want: 1},
+		{name: "split", count: sitemap.MaxURLs + 1, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+			mockRepo.EXPECT().SitemapState(gomock.Any()).Return(tt.count, updated, nil)
+			s, _ := NewService(mockRepo, &auth.Auth{})
+
+			got, err := s.Sitemap(context.Background())
+			if err != nil {
+				t.Fatalf("Service.Sitemap() error = %v", err)
+			}
+			want := models.Sitemap{Jobs: tt.count, Pages: tt.want, Updated: updated}
+			if got != want {
+				t.Errorf("Service.Sitemap() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestService_SitemapPage(t *testing.T) {
+	updated := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	jobs := []models.SitemapJob{{ID: 50012, UpdatedAt: updated}}
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	mockRepo.EXPECT().SitemapState(gomock.Any()).Return(int64(sitemap.MaxURLs+1), updated, nil).Times(3)
+	mockRepo.EXPECT().SitemapJobs(gomock.Any(), sitemap.MaxURLs, sitemap.MaxURLs).Return(jobs, nil)
+	s, _ := NewService(mockRepo, &auth.Auth{})
+
+	got, err := s.SitemapPage(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Service.SitemapPage() error = %v", err)
+	}
+	want := models.SitemapPage{Sitemap: models.Sitemap{Jobs: sitemap.MaxURLs + 1, Pages: 2, Updated: updated}, Page: 2, Jobs: jobs}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Service.SitemapPage() = %+v, want %+v", got, want)
+	}
+	for _, page := range []int{0, 3} {
+		_, err = s.SitemapPage(context.Background(), page)
+		if err == nil {
+			t.Errorf("Service.SitemapPage(%d) of a sitemap of 2 pages found it", page)
+		}
+	}
+}
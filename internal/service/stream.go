@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/apperr"
+	"project/internal/fuzzy"
+	"project/internal/jobstream"
+	"project/internal/models"
+	"slices"
+)
+
+// FollowJobs subscribes to the events of the jobs filter finds, the paging of the
+// filter is ignored. A lastEventID above 0 resumes after that event
+func (s *Service) FollowJobs(ctx context.Context, filter models.JobFilter, lastEventID uint64) (*jobstream.Subscription, error) {
+	sub, err := s.jobs.Subscribe(jobMatcher(s.matching(filter)), lastEventID)
+	if errors.Is(err, jobstream.ErrClosed) {
+		return nil, apperr.Wrap(apperr.Unavailable, err, "the job stream is shutting down, reconnect later")
+	}
+	return sub, err
+}
+
+// publishJob hands the change of a job from previous, the zero Jobs for a new
+// job, to the stream. Only published jobs are followed: a job published again is
+// new to the followers and a job leaving for draft or closed is sent as closed
+// with the fields of its last published version, what it became stays unseen.
+// Changes of jobs that were not published and still are not are not sent
+func (s *Service) publishJob(eventType string, previous, jobData models.Jobs) {
+	wasListed := previous.ID != 0 && listed(previous)
+	switch {
+	case listed(jobData) && previous.ID != 0 && !wasListed:
+		s.jobs.Publish(models.EventJobCreated, jobData)
+	case listed(jobData):
+		s.jobs.Publish(eventType, jobData)
+	case wasListed:
+		change := previous
+		change.Status = models.JobClosed
+		change.UpdatedAt = jobData.UpdatedAt
+		s.jobs.Publish(models.EventJobClosed, change)
+	}
+}
+
+// listed reports whether candidates see the job, jobs stored before they had a
+// status are published
+func listed(j models.Jobs) bool {
+	return j.Status == "" || j.Status == models.JobPublished
+}
+
+// jobMatcher checks one job against filter the way the search does in the database,
+// except that the query is only matched against the name of the job. Drafts never
+// match, closed jobs do so that the followers learn a job left
+func jobMatcher(filter models.JobFilter) func(models.Jobs) bool {
+	return func(j models.Jobs) bool {
+		if j.Status == models.JobDraft {
+			return false
+		}
+		if filter.Query != "" && fuzzy.WordSimilarity(filter.Query, j.Name) < filter.Similarity {
+			return false
+		}
+		if len(filter.Cid) > 0 && !slices.Contains(filter.Cid, j.Cid) {
+			return false
+		}
+		if len(filter.Location) > 0 && !slices.Contains(filter.Location, j.Location) {
+			return false
+		}
+		if len(filter.EmploymentType) > 0 && !slices.Contains(filter.EmploymentType, j.EmploymentType) {
+			return false
+		}
+		if len(filter.RemotePolicy) > 0 && !slices.Contains(filter.RemotePolicy, j.RemotePolicy) {
+			return false
+		}
+		if len(filter.SalaryBucket) > 0 {
+			for _, key := range filter.SalaryBucket {
+				b, ok := models.SalaryBucketByKey(key)
+				if ok && b.Contains(j.MinSalary) {
+					return true
+				}
+			}
+			return false
+		}
+		return true
+	}
+}
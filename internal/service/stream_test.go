@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"project/internal/auth"
+	"project/internal/models"
+	"project/internal/repository"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_FollowJobs(t *testing.T) {
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	inTransaction(mockRepo).Times(4)
+	mockRepo.EXPECT().AppendEvents(gomock.Any(), gomock.Any()).Return(nil).Times(4)
+	mockRepo.EXPECT().CreateUserJob(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, jobData models.Jobs) (models.Jobs, error) {
+		jobData.ID = uint(len(jobData.Name))
+		return jobData, nil
+	}).Times(4)
+
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{
+		{CompanyID: 7, UserID: 4, Role: models.RoleOwner}, {CompanyID: 8, UserID: 4, Role: models.RoleRecruiter}}, nil).Times(4)
+
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	filter := models.JobFilter{Query: "enginer", Cid: []uint{7}, SalaryBucket: []string{"600000-1200000"}}
+	sub, err := s.FollowJobs(context.Background(), filter, 0)
+	if err != nil {
+		t.Fatalf("Service.FollowJobs() error = %v", err)
+	}
+	defer sub.Close()
+
+	for _, job := range []struct {
+		models.Jobs
+		cid uint64
+	}{
+		{models.Jobs{Name: "software engineer", MinSalary: 800000}, 7},
+		{models.Jobs{Name: "software engineer", MinSalary: 800000}, 8},
+		{models.Jobs{Name: "accountant", MinSalary: 800000}, 7},
+		{models.Jobs{Name: "engineer", MinSalary: 200000}, 7},
+	} {
+		_, err := s.AddJobDetails(context.Background(), 4, job.Jobs, job.cid)
+		if err != nil {
+			t.Fatalf("Service.AddJobDetails() error = %v", err)
+		}
+	}
+
+	select {
+	case e := <-sub.Events():
+		want := models.Jobs{Model: gorm.Model{ID: 17}, Cid: 7, Name: "software engineer", MinSalary: 800000}
+		if e.Type != models.EventJobCreated || e.Job.ID != want.ID || e.Job.Cid != want.Cid {
+			t.Errorf("Service.FollowJobs() sent %s of %+v, want job.created of %+v", e.Type, e.Job, want)
+		}
+	default:
+		t.Fatal("Service.FollowJobs() sent nothing")
+	}
+	select {
+	case e := <-sub.Events():
+		t.Errorf("Service.FollowJobs() also sent %+v", e.Job)
+	default:
+	}
+}
+
+func TestService_FollowJobs_drafts(t *testing.T) {
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	inTransaction(mockRepo).Times(3)
+	mockRepo.EXPECT().AppendEvents(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{{CompanyID: 7, UserID: 4, Role: models.RoleOwner}}, nil).Times(3)
+	mockRepo.EXPECT().CreateUserJob(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, jobData models.Jobs) (models.Jobs, error) {
+		jobData.ID = 9
+		return jobData, nil
+	})
+	published := models.Jobs{Model: gorm.Model{ID: 5}, Cid: 7, Name: "engineer", Status: models.JobPublished}
+	mockRepo.EXPECT().Jobbyjid(gomock.Any(), uint64(5)).Return(published, nil)
+	draft := models.Jobs{Model: gorm.Model{ID: 9}, Cid: 7, Name: "secret plan", Status: models.JobDraft}
+	mockRepo.EXPECT().Jobbyjid(gomock.Any(), uint64(9)).Return(draft, nil)
+	mockRepo.EXPECT().UpdateJob(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, jobData models.Jobs, version time.Time) (models.Jobs, error) {
+		return jobData, nil
+	}).Times(2)
+
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	sub, err := s.FollowJobs(context.Background(), models.JobFilter{}, 0)
+	if err != nil {
+		t.Fatalf("Service.FollowJobs() error = %v", err)
+	}
+	defer sub.Close()
+
+	// a draft is neither sent when it is saved nor when it is changed
+	_, err = s.AddJobDetails(context.Background(), 4, models.Jobs{Name: "secret plan", Status: models.JobDraft}, 7)
+	if err != nil {
+		t.Fatalf("Service.AddJobDetails() error = %v", err)
+	}
+	_, err = s.UpdateJob(context.Background(), 4, 9, models.Jobs{Name: "secret plan v2", Status: models.JobDraft}, draft.ETag())
+	if err != nil {
+		t.Fatalf("Service.UpdateJob() of the draft error = %v", err)
+	}
+	// a published job taken back to draft leaves without its draft
+	_, err = s.UpdateJob(context.Background(), 4, 5, models.Jobs{Name: "rewritten", Status: models.JobDraft}, published.ETag())
+	if err != nil {
+		t.Fatalf("Service.UpdateJob() error = %v", err)
+	}
+
+	select {
+	case e := <-sub.Events():
+		if e.Type != models.EventJobClosed || e.Job.ID != 5 || e.Job.Name != "engineer" || e.Job.Status != models.JobClosed {
+			t.Errorf("Service.FollowJobs() sent %s of %+v, want job.closed of the published job", e.Type, e.Job)
+		}
+	default:
+		t.Fatal("Service.FollowJobs() sent nothing")
+	}
+	select {
+	case e := <-sub.Events():
+		t.Errorf("Service.FollowJobs() also sent %s of %+v", e.Type, e.Job)
+	default:
+	}
+}
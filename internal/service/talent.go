@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project/internal/apperr"
+	"project/internal/models"
+	"slices"
+)
+
+var (
+	// ErrNotRecruiter is returned when a user who recruits for no verified company searches candidates
+	ErrNotRecruiter = apperr.New(apperr.Forbidden, "only recruiters of verified companies can view candidates")
+	// ErrNotOwner is returned when a user who does not own the company changes its members
+	ErrNotOwner = apperr.New(apperr.Forbidden, "only company owners can add members")
+	// ErrCandidateNotFound hides whether a profile is missing or not discoverable
+	ErrCandidateNotFound = apperr.New(apperr.NotFound, "candidate not found")
+)
+
+func (s *Service) AddMember(ctx context.Context, actorID uint, membership models.Membership) (models.Membership, error) {
+	m, err := s.membership(ctx, actorID, membership.CompanyID)
+	if err != nil {
+		return models.Membership{}, err
+	}
+	if m.Role != models.RoleOwner {
+		return models.Membership{}, ErrNotOwner
+	}
+
+	membership.ID = 0
+	membership, err = s.UserRepo.CreateMembership(ctx, membership)
+	if err != nil {
+		return models.Membership{}, err
+	}
+	s.sendNotification(ctx, models.Notification{
+		UserID: membership.UserID,
+		Type:   models.NotificationMemberAdded,
+		Text:   fmt.Sprintf("You were added to %s as %s", s.companyName(ctx, membership.CompanyID), membership.Role),
+		Link:   fmt.Sprintf("/api/v1/companies/%d", membership.CompanyID),
+	})
+	return membership, nil
+}
+
+// membership finds the role of the user in the company, the zero Membership
+// grants nothing when they are not a member
+func (s *Service) membership(ctx context.Context, userID uint, companyID uint) (models.Membership, error) {
+	memberships, err := s.UserRepo.MembershipsByUser(ctx, userID)
+	if err != nil {
+		return models.Membership{}, err
+	}
+	for _, m := range memberships {
+		if m.CompanyID == companyID {
+			return m, nil
+		}
+	}
+	return models.Membership{}, nil
+}
+
+// VerifyCompany lets the members of the company search candidates, only admins
+// can verify companies
+func (s *Service) VerifyCompany(ctx context.Context, actorID uint, cid uint64) (models.CompanyVerification, error) {
+	if !s.admins[actorID] {
+		return models.CompanyVerification{}, ErrNotAdmin
+	}
+	_, err := s.UserRepo.CompanyById(ctx, cid)
+	if err != nil {
+		return models.CompanyVerification{}, err
+	}
+	return s.UserRepo.VerifyCompany(ctx, models.CompanyVerification{CompanyID: uint(cid), VerifiedBy: actorID})
+}
+
+// recruiterCompany returns the first verified company the user recruits for,
+// profile views are logged against it
+func (s *Service) recruiterCompany(ctx context.Context, recruiterID uint) (uint, error) {
+	memberships, err := s.UserRepo.MembershipsByUser(ctx, recruiterID)
+	if err != nil {
+		return 0, err
+	}
+	var companies []uint
+	for _, m := range memberships {
+		if m.CanRecruit() {
+			companies = append(companies, m.CompanyID)
+		}
+	}
+	if len(companies) == 0 {
+		return 0, ErrNotRecruiter
+	}
+	verified, err := s.UserRepo.VerifiedCompanies(ctx, companies)
+	if err != nil {
+		return 0, err
+	}
+	for _, cid := range companies {
+		if slices.Contains(verified, cid) {
+			return cid, nil
+		}
+	}
+	return 0, ErrNotRecruiter
+}
+
+func (s *Service) SearchTalent(ctx context.Context, recruiterID uint, filter models.TalentFilter) (models.TalentSearchResult, error) {
+	companyID, err := s.recruiterCompany(ctx, recruiterID)
+	if err != nil {
+		return models.TalentSearchResult{}, err
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultPageSize
+	}
+	if filter.PageSize > maxPageSize {
+		filter.PageSize = maxPageSize
+	}
+	profiles, total, err := s.UserRepo.SearchCandidates(ctx, filter)
+	if err != nil {
+		return models.TalentSearchResult{}, err
+	}
+
+	candidates := make([]models.Candidate, 0, len(profiles))
+	views := make([]models.ProfileView, 0, len(profiles))
+	for _, profile := range profiles {
+		candidates = append(candidates, profile.Candidate())
+		views = append(views, models.ProfileView{
+			ProfileUserID: profile.UserID,
+			ViewerID:      recruiterID,
+			CompanyID:     companyID,
+			Source:        models.ViewSourceSearch,
+		})
+	}
+	// a candidate must always be able to see who saw them, so nothing is shown unlogged
+	err = s.UserRepo.RecordProfileViews(ctx, views)
+	if err != nil {
+		return models.TalentSearchResult{}, err
+	}
+
+	return models.TalentSearchResult{
+		Candidates: candidates,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+	}, nil
+}
+
+func (s *Service) ViewCandidate(ctx context.Context, recruiterID uint, candidateID uint) (models.Candidate, error) {
+	companyID, err := s.recruiterCompany(ctx, recruiterID)
+	if err != nil {
+		return models.Candidate{}, err
+	}
+	profile, err := s.UserRepo.ProfileByUserID(ctx, candidateID)
+	if errors.Is(err, apperr.ErrNotFound) || (err == nil && !profile.Discoverable) {
+		return models.Candidate{}, ErrCandidateNotFound
+	}
+	if err != nil {
+		return models.Candidate{}, err
+	}
+
+	err = s.UserRepo.RecordProfileViews(ctx, []models.ProfileView{{
+		ProfileUserID: profile.UserID,
+		ViewerID:      recruiterID,
+		CompanyID:     companyID,
+		Source:        models.ViewSourceProfile,
+	}})
+	if err != nil {
+		return models.Candidate{}, err
+	}
+	s.sendNotification(ctx, models.Notification{
+		UserID: profile.UserID,
+		Type:   models.NotificationProfileViewed,
+		Text:   fmt.Sprintf("A recruiter of %s viewed your profile", s.companyName(ctx, companyID)),
+		Link:   "/api/v1/me/profile/views",
+	})
+	return profile.Candidate(), nil
+}
+
+func (s *Service) ProfileViews(ctx context.Context, userID uint) ([]models.ProfileView, error) {
+	views, err := s.UserRepo.ProfileViews(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return views, nil
+}
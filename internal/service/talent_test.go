@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/internal/apperr"
+	"project/internal/auth"
+	"project/internal/inbox"
+	"project/internal/models"
+	"project/internal/repository"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_SearchTalent(t *testing.T) {
+	profiles := []models.Profile{
+		{UserID: 11, Headline: "gopher", Skills: []string{"go"}, CurrentEmployer: "tcs", ExpectedSalary: 900000, HideCurrentEmployer: true, Discoverable: true},
+		{UserID: 12, Headline: "tester", CurrentEmployer: "ibm", ExpectedSalary: 500000, HideExpectedSalary: true, Discoverable: true},
+	}
+	tests := []struct {
+		name      string
+		want      models.TalentSearchResult
+		wantErr   error
+		setupMock func(r *repository.MockUserRepo)
+	}{
+		{
+			name:    "not a recruiter",
+			wantErr: ErrNotRecruiter,
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{}, nil)
+			},
+		},
+		{
+			name:    "owner of a company nobody verified",
+			wantErr: ErrNotRecruiter,
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{{CompanyID: 7, UserID: 4, Role: models.RoleOwner}}, nil)
+				r.EXPECT().VerifiedCompanies(gomock.Any(), []uint{7}).Return([]uint{}, nil)
+			},
+		},
+		{
+			name: "visibility applied and views logged",
+			want: models.TalentSearchResult{
+				Candidates: []models.Candidate{
+					{UserID: 11, Headline: "gopher", Skills: []string{"go"}, ExpectedSalary: 900000},
+					{UserID: 12, Headline: "tester", CurrentEmployer: "ibm"},
+				},
+				Total:    2,
+				Page:     1,
+				PageSize: 20,
+			},
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{
+					{CompanyID: 6, UserID: 4, Role: models.RoleOwner},
+					{CompanyID: 7, UserID: 4, Role: models.RoleRecruiter},
+				}, nil)
+				r.EXPECT().VerifiedCompanies(gomock.Any(), []uint{6, 7}).Return([]uint{7}, nil)
+				r.EXPECT().SearchCandidates(gomock.Any(), models.TalentFilter{Skills: []string{"go"}, Page: 1, PageSize: 20}).Return(profiles, int64(2), nil)
+				r.EXPECT().RecordProfileViews(gomock.Any(), []models.ProfileView{
+					{ProfileUserID: 11, ViewerID: 4, CompanyID: 7, Source: models.ViewSourceSearch},
+					{ProfileUserID: 12, ViewerID: 4, CompanyID: 7, Source: models.ViewSourceSearch},
+				}).Return(nil)
+			},
+		},
+		{
+			name:    "results are not shown when the views cannot be logged",
+			wantErr: errors.New("could not record the profile views"),
+			setupMock: func(r *repository.MockUserRepo) {
+				r.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{{CompanyID: 7, UserID: 4, Role: models.RoleOwner}}, nil)
+				r.EXPECT().VerifiedCompanies(gomock.Any(), []uint{7}).Return([]uint{7}, nil)
+				r.EXPECT().SearchCandidates(gomock.Any(), gomock.Any()).Return(profiles, int64(2), nil)
+				r.EXPECT().RecordProfileViews(gomock.Any(), gomock.Any()).Return(errors.New("could not record the profile views"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			mockRepo := repository.NewMockUserRepo(mc)
+			tt.setupMock(mockRepo)
+
+			s, _ := NewService(mockRepo, &auth.Auth{})
+			got, err := s.SearchTalent(context.Background(), 4, models.TalentFilter{Skills: []string{"go"}})
+			if (err != nil) != (tt.wantErr != nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("Service.SearchTalent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.SearchTalent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_ViewCandidate(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{{CompanyID: 7, UserID: 4, Role: models.RoleRecruiter}}, nil).Times(2)
+	mockRepo.EXPECT().VerifiedCompanies(gomock.Any(), []uint{7}).Return([]uint{7}, nil).Times(2)
+	mockRepo.EXPECT().ProfileByUserID(gomock.Any(), uint(11)).Return(models.Profile{UserID: 11, Discoverable: false}, nil)
+	mockRepo.EXPECT().ProfileByUserID(gomock.Any(), uint(12)).Return(models.Profile{UserID: 12, Headline: "tester", Discoverable: true}, nil)
+	mockRepo.EXPECT().RecordProfileViews(gomock.Any(), []models.ProfileView{
+		{ProfileUserID: 12, ViewerID: 4, CompanyID: 7, Source: models.ViewSourceProfile},
+	}).Return(nil)
+	// the candidate is told who saw them
+	mockRepo.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(models.Company{Model: gorm.Model{ID: 7}, Name: "tek"}, nil)
+	viewed := models.Notification{UserID: 12, Type: models.NotificationProfileViewed, Text: "A recruiter of tek viewed your profile", Link: "/api/v1/me/profile/views"}
+	mockRepo.EXPECT().CreateNotification(gomock.Any(), viewed).DoAndReturn(func(ctx context.Context, n models.Notification) (models.Notification, error) {
+		n.ID = 3
+		return n, nil
+	})
+
+	hub := inbox.NewHub()
+	session, _ := hub.Join(12)
+	s, _ := NewService(mockRepo, &auth.Auth{}, WithInbox(hub))
+	_, err := s.ViewCandidate(context.Background(), 4, 11)
+	if !errors.Is(err, ErrCandidateNotFound) {
+		t.Errorf("Service.ViewCandidate() of a hidden profile error = %v, want %v", err, ErrCandidateNotFound)
+	}
+	got, err := s.ViewCandidate(context.Background(), 4, 12)
+	if err != nil || got.Headline != "tester" {
+		t.Errorf("Service.ViewCandidate() = %v, %v", got, err)
+	}
+	select {
+	case msg := <-session.Messages():
+		if msg.Type != models.MessageNotification || len(msg.Notifications) != 1 || msg.Notifications[0].ID != 3 {
+			t.Errorf("the candidate got %+v, want notification 3", msg)
+		}
+	default:
+		t.Error("the candidate got no notification")
+	}
+}
+
+func TestService_AddMember(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{
+		{CompanyID: 7, UserID: 4, Role: models.RoleOwner},
+		{CompanyID: 8, UserID: 4, Role: models.RoleRecruiter},
+	}, nil).Times(2)
+	mockRepo.EXPECT().CreateMembership(gomock.Any(), models.Membership{CompanyID: 7, UserID: 5, Role: models.RoleRecruiter}).
+		Return(models.Membership{CompanyID: 7, UserID: 5, Role: models.RoleRecruiter}, nil)
+	// a company that cannot be found is named by its id
+	mockRepo.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(models.Company{}, apperr.New(apperr.NotFound, "company not found"))
+	mockRepo.EXPECT().CreateNotification(gomock.Any(), models.Notification{
+		UserID: 5, Type: models.NotificationMemberAdded, Text: "You were added to company 7 as recruiter", Link: "/api/v1/companies/7",
+	}).Return(models.Notification{}, nil)
+
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	_, err := s.AddMember(context.Background(), 4, models.Membership{CompanyID: 8, UserID: 5, Role: models.RoleRecruiter})
+	if !errors.Is(err, ErrNotOwner) {
+		t.Errorf("Service.AddMember() as a recruiter error = %v, want %v", err, ErrNotOwner)
+	}
+	_, err = s.AddMember(context.Background(), 4, models.Membership{CompanyID: 7, UserID: 5, Role: models.RoleRecruiter})
+	if err != nil {
+		t.Errorf("Service.AddMember() as the owner error = %v", err)
+	}
+}
+
+func TestService_VerifyCompany(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().CompanyById(gomock.Any(), uint64(7)).Return(models.Company{Model: gorm.Model{ID: 7}, Name: "tek"}, nil)
+	mockRepo.EXPECT().VerifyCompany(gomock.Any(), models.CompanyVerification{CompanyID: 7, VerifiedBy: 1}).
+		Return(models.CompanyVerification{CompanyID: 7, VerifiedBy: 1}, nil)
+
+	s, _ := NewService(mockRepo, &auth.Auth{}, WithAdmins(1))
+	_, err := s.VerifyCompany(context.Background(), 4, 7)
+	if !errors.Is(err, ErrNotAdmin) {
+		t.Errorf("Service.VerifyCompany() by the owner error = %v, want %v", err, ErrNotAdmin)
+	}
+	got, err := s.VerifyCompany(context.Background(), 1, 7)
+	if err != nil || got.CompanyID != 7 {
+		t.Errorf("Service.VerifyCompany() by an admin = %+v, %v", got, err)
+	}
+}
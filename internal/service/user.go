@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"project/internal/apperr"
 	"project/internal/database"
 	"project/internal/models"
+	"project/internal/repository"
 	"strconv"
 	"time"
 
@@ -16,6 +18,9 @@ func (s Service) UserLogin(ctx context.Context, userData models.NewUser) (string
 	// checcking the email in the db
 	var userDetails models.User
 	userDetails, err := s.UserRepo.Userbyemail(ctx, userData.Email)
+	if errors.Is(err, apperr.ErrNotFound) {
+		return "", apperr.Wrap(apperr.Unauthorized, err, "email not found")
+	}
 	if err != nil {
 		return "", err
 	}
@@ -24,7 +29,7 @@ func (s Service) UserLogin(ctx context.Context, userData models.NewUser) (string
 	err = database.HashedPassword(userData.Password, userDetails.PasswordHash)
 	if err != nil {
 		log.Info().Err(err).Send()
-		return "", errors.New("entered password is not wrong")
+		return "", apperr.Wrap(apperr.Unauthorized, err, "entered password is wrong")
 	}
 
 	// setting up the claims
@@ -54,8 +59,24 @@ func (s Service) UserSignup(ctx context.Context, userData models.NewUser) (model
 		Username:     userData.Username,
 		Email:        userData.Email,
 		PasswordHash: hashedPass,
+		Locale:       userData.Locale,
+	}
+	err = s.record(ctx, func(tx repository.UserRepo) ([]models.DomainEvent, error) {
+		userDetails, err = tx.CreateUser(ctx, userDetails)
+		if err != nil {
+			return nil, err
+		}
+		return []models.DomainEvent{models.UserSignedUp{User: userDetails}}, nil
+	})
+	if err != nil {
+		return models.User{}, err
 	}
-	userDetails, err = s.UserRepo.CreateUser(ctx, userDetails)
+	return userDetails, nil
+}
+
+// ViewUser loads the account of the user, the password hash is never sent back
+func (s Service) ViewUser(ctx context.Context, userID uint) (models.User, error) {
+	userDetails, err := s.UserRepo.UserByID(ctx, userID)
 	if err != nil {
 		return models.User{}, err
 	}
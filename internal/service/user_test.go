@@ -197,8 +197,12 @@ func TestService_UserSignup(t *testing.T) {
 			mc := gomock.NewController(t)
 			mockRepo := repository.NewMockUserRepo(mc)
 			if tt.mockRepoResponse != nil {
+				inTransaction(mockRepo)
 				mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
 			}
+			if !tt.wantErr {
+				mockRepo.EXPECT().AppendEvents(gomock.Any(), recorded("user.signed_up user:0")).Return(nil)
+			}
 			s, _ := NewService(mockRepo, &auth.Auth{})
 			got, err := s.UserSignup(tt.args.ctx, tt.args.userData)
 			if (err != nil) != tt.wantErr {
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"project/internal/apperr"
+	"project/internal/egress"
+	"project/internal/models"
+	"time"
+)
+
+// CreateWebhook subscribes hook to the job events of the company, only those
+// who recruit for it can. The secret is not sent back and the url must be public
+func (s *Service) CreateWebhook(ctx context.Context, actorID uint, cid uint64, hook models.Webhook) (models.Webhook, error) {
+	err := s.canRecruit(ctx, actorID, cid)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	err = egress.CheckURL(hook.URL)
+	if err != nil {
+		return models.Webhook{}, apperr.Wrap(apperr.Validation, err, "the webhook url must be on the public internet")
+	}
+	hook.ID = 0
+	hook.Cid = uint(cid)
+	hook, err = s.UserRepo.CreateWebhook(ctx, hook)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	hook.Secret = ""
+	return hook, nil
+}
+
+func (s *Service) Webhooks(ctx context.Context, actorID uint, cid uint64) ([]models.Webhook, error) {
+	err := s.canRecruit(ctx, actorID, cid)
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := s.UserRepo.WebhooksByCompany(ctx, uint(cid))
+	if err != nil {
+		return nil, err
+	}
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	return hooks, nil
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, actorID uint, cid uint64, wid uint64) error {
+	err := s.canRecruit(ctx, actorID, cid)
+	if err != nil {
+		return err
+	}
+	return s.UserRepo.DeleteWebhook(ctx, uint(cid), uint(wid))
+}
+
+// WebhookDeliveries is the delivery log of the webhook, newest first
+func (s *Service) WebhookDeliveries(ctx context.Context, actorID uint, cid uint64, wid uint64) ([]models.WebhookDelivery, error) {
+	hook, err := s.companyWebhook(ctx, actorID, cid, wid)
+	if err != nil {
+		return nil, err
+	}
+	return s.UserRepo.WebhookDeliveries(ctx, hook.ID)
+}
+
+// Redeliver sends a delivery of the webhook again as soon as the dispatcher gets
+// to it, with all of its attempts
+func (s *Service) Redeliver(ctx context.Context, actorID uint, cid uint64, wid uint64, did uint64) (models.WebhookDelivery, error) {
+	hook, err := s.companyWebhook(ctx, actorID, cid, wid)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	return s.UserRepo.Redeliver(ctx, hook.ID, uint(did))
+}
+
+func (s *Service) companyWebhook(ctx context.Context, actorID uint, cid uint64, wid uint64) (models.Webhook, error) {
+	err := s.canRecruit(ctx, actorID, cid)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	return s.UserRepo.WebhookByID(ctx, uint(cid), uint(wid))
+}
+
+func (s *Service) canRecruit(ctx context.Context, actorID uint, cid uint64) error {
+	m, err := s.membership(ctx, actorID, uint(cid))
+	if err != nil {
+		return err
+	}
+	if !m.CanRecruit() {
+		return ErrNotCompanyMember
+	}
+	return nil
+}
+
+// WebhookEvent queues the job events for the webhooks of the company of the job,
+// the outbox dispatcher hands it the events recorded with the changes. An event
+// handed over again queues no delivery twice
+func (s *Service) WebhookEvent(ctx context.Context, event models.DomainEvent) error {
+	jobData, occurredAt, ok := jobEvent(event)
+	if !ok {
+		return nil
+	}
+	payload, err := json.Marshal(models.WebhookEvent{Event: event.EventType(), OccurredAt: occurredAt, Job: jobData})
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s %s at:%d", event.EventType(), event.Aggregate(), occurredAt.UnixNano())
+	return s.UserRepo.EnqueueDeliveries(ctx, jobData.Cid, event.EventType(), key, string(payload))
+}
+
+// jobEvent is the job a job event is about and when it happened, ok is false
+// for the events of other aggregates
+func jobEvent(event models.DomainEvent) (jobData models.Jobs, occurredAt time.Time, ok bool) {
+	switch e := event.(type) {
+	case models.JobCreated:
+		jobData, occurredAt = e.Job, e.Job.CreatedAt
+	case models.JobUpdated:
+		jobData, occurredAt = e.Job, e.Job.UpdatedAt
+	case models.JobClosedEvent:
+		jobData, occurredAt = e.Job, e.ClosedAt
+	default:
+		return models.Jobs{}, time.Time{}, false
+	}
+	return jobData, occurredAt.UTC(), true
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"project/internal/auth"
+	"project/internal/egress"
+	"project/internal/models"
+	"project/internal/repository"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_CreateWebhook(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{
+		{CompanyID: 7, UserID: 4, Role: models.RoleRecruiter},
+	}, nil).Times(3)
+	// the hook is always created at the company of the path
+	mockRepo.EXPECT().CreateWebhook(gomock.Any(), models.Webhook{Cid: 7, URL: "https://partner.example/hooks", Secret: "0123456789abcdef"}).
+		Return(models.Webhook{Model: gorm.Model{ID: 3}, Cid: 7, URL: "https://partner.example/hooks", Secret: "0123456789abcdef"}, nil)
+
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	hook := models.Webhook{Model: gorm.Model{ID: 9}, Cid: 8, URL: "https://partner.example/hooks", Secret: "0123456789abcdef"}
+	_, err := s.CreateWebhook(context.Background(), 4, 8, hook)
+	if !errors.Is(err, ErrNotCompanyMember) {
+		t.Errorf("Service.CreateWebhook() at another company error = %v, want %v", err, ErrNotCompanyMember)
+	}
+	_, err = s.CreateWebhook(context.Background(), 4, 7, models.Webhook{URL: "http://169.254.169.254/latest", Secret: "0123456789abcdef"})
+	if !errors.Is(err, egress.ErrForbiddenAddress) {
+		t.Errorf("Service.CreateWebhook() to a link-local url error = %v, want %v", err, egress.ErrForbiddenAddress)
+	}
+	got, err := s.CreateWebhook(context.Background(), 4, 7, hook)
+	if err != nil {
+		t.Fatalf("Service.CreateWebhook() error = %v", err)
+	}
+	if got.ID != 3 || got.Secret != "" {
+		t.Errorf("Service.CreateWebhook() = %+v, want webhook 3 without its secret", got)
+	}
+}
+
+func TestService_Redeliver(t *testing.T) {
+	mc := gomock.NewController(t)
+	mockRepo := repository.NewMockUserRepo(mc)
+	mockRepo.EXPECT().MembershipsByUser(gomock.Any(), uint(4)).Return([]models.Membership{
+		{CompanyID: 7, UserID: 4, Role: models.RoleOwner},
+	}, nil)
+	mockRepo.EXPECT().WebhookByID(gomock.Any(), uint(7), uint(3)).Return(models.Webhook{Model: gorm.Model{ID: 3}, Cid: 7}, nil)
+	mockRepo.EXPECT().Redeliver(gomock.Any(), uint(3), uint(8)).
+		Return(models.WebhookDelivery{Model: gorm.Model{ID: 8}, WebhookID: 3, Status: models.DeliveryPending}, nil)
+
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	got, err := s.Redeliver(context.Background(), 4, 7, 3, 8)
+	if err != nil || got.Status != models.DeliveryPending {
+		t.Errorf("Service.Redeliver() = %+v, %v, want the pending delivery 8", got, err)
+	}
+}
+
+func TestService_WebhookEvent(t *testing.T) {
+	closedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	job := models.Jobs{Model: gorm.Model{ID: 11}, Cid: 7, Name: "developer", Status: models.JobClosed}
+	mockRepo := repository.NewMockUserRepo(gomock.NewController(t))
+	var payload string
+	// the key stays the same when the outbox hands the event over again
+	mockRepo.EXPECT().EnqueueDeliveries(gomock.Any(), uint(7), models.EventJobClosed, "job.closed job:11 at:1704164645000000000", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, cid uint, event, key, p string) error {
+			payload = p
+			return nil
+		}).Times(2)
+
+	s, _ := NewService(mockRepo, &auth.Auth{})
+	for i := 0; i < 2; i++ {
+		err := s.WebhookEvent(context.Background(), models.JobClosedEvent{Job: job, ClosedAt: closedAt})
+		if err != nil {
+			t.Fatalf("Service.WebhookEvent() error = %v", err)
+		}
+	}
+	var got models.WebhookEvent
+	_ = json.Unmarshal([]byte(payload), &got)
+	if got.Event != models.EventJobClosed || !got.OccurredAt.Equal(closedAt) || got.Job.ID != 11 {
+		t.Errorf("Service.WebhookEvent() queued %s", payload)
+	}
+
+	// the events of other aggregates are not for webhooks
+	err := s.WebhookEvent(context.Background(), models.UserSignedUp{User: models.User{Model: gorm.Model{ID: 4}}})
+	if err != nil {
+		t.Errorf("Service.WebhookEvent() of a signup error = %v", err)
+	}
+}
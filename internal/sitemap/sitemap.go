@@ -0,0 +1,84 @@
+// Package sitemap writes sitemaps and sitemap indexes of the sitemaps.org protocol
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MediaType is the media type of sitemaps and sitemap indexes
+const MediaType = "application/xml"
+
+// MaxURLs is how many urls one sitemap may list, more are split over several
+// sitemaps listed by an index
+const MaxURLs = 50000
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL is an entry of a sitemap, or a sitemap listed by an index. LastMod is
+// left out when zero
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlset struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []entry  `xml:"url"`
+}
+
+type index struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Sitemaps []entry  `xml:"sitemap"`
+}
+
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// WriteURLSet writes a sitemap of urls, at most MaxURLs of them
+func WriteURLSet(w io.Writer, urls []URL) error {
+	if len(urls) > MaxURLs {
+		return fmt.Errorf("a sitemap lists at most %d urls, got %d", MaxURLs, len(urls))
+	}
+	return write(w, urlset{XMLNS: namespace, URLs: entries(urls)})
+}
+
+// WriteIndex writes a sitemap index of sitemaps, at most MaxURLs of them
+func WriteIndex(w io.Writer, sitemaps []URL) error {
+	if len(sitemaps) > MaxURLs {
+		return fmt.Errorf("a sitemap index lists at most %d sitemaps, got %d", MaxURLs, len(sitemaps))
+	}
+	return write(w, index{XMLNS: namespace, Sitemaps: entries(sitemaps)})
+}
+
+func entries(urls []URL) []entry {
+	out := make([]entry, 0, len(urls))
+	for _, u := range urls {
+		e := entry{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			e.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func write(w io.Writer, doc interface{}) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+	return enc.Close()
+}
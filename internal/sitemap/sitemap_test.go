@@ -0,0 +1,64 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteURLSet(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteURLSet(&b, []URL{
+		{Loc: "https://portal.example/api/v1/jobs/11?a=1&b=2", LastMod: time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("IST", 19800))},
+		{Loc: "https://portal.example/api/v1/jobs/12"},
+	})
+	if err != nil {
+		t.Fatalf("WriteURLSet() error = %v", err)
+	}
+	var got struct {
+		XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+		URLs    []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"url"`
+	}
+	err = xml.Unmarshal(b.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("the sitemap is not well formed: %v\n%s", err, b.String())
+	}
+	if len(got.URLs) != 2 || got.URLs[0].Loc != "https://portal.example/api/v1/jobs/11?a=1&b=2" ||
+		got.URLs[0].LastMod != "2024-01-01T21:34:05Z" || got.URLs[1].LastMod != "" {
+		t.Errorf("WriteURLSet() wrote\n%s", b.String())
+	}
+	if strings.Contains(b.String(), "<lastmod></lastmod>") {
+		t.Errorf("WriteURLSet() writes empty dates\n%s", b.String())
+	}
+
+	err = WriteURLSet(&bytes.Buffer{}, make([]URL, MaxURLs+1))
+	if err == nil {
+		t.Errorf("WriteURLSet() accepts more than %d urls", MaxURLs)
+	}
+}
+
+func TestWriteIndex(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteIndex(&b, []URL{{Loc: "https://portal.example/api/v1/sitemaps/1"}, {Loc: "https://portal.example/api/v1/sitemaps/2"}})
+	if err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+	var got struct {
+		XMLName  xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+		Sitemaps []struct {
+			Loc string `xml:"loc"`
+		} `xml:"sitemap"`
+	}
+	err = xml.Unmarshal(b.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("the index is not well formed: %v\n%s", err, b.String())
+	}
+	if len(got.Sitemaps) != 2 || got.Sitemaps[1].Loc != "https://portal.example/api/v1/sitemaps/2" {
+		t.Errorf("WriteIndex() wrote\n%s", b.String())
+	}
+}
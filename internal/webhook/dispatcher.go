@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"project/internal/egress"
+	"project/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Queue is where deliveries wait, the repository keeps them in the database so
+// none are lost when the api restarts
+type Queue interface {
+	ClaimDeliveries(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.DueDelivery, error)
+	SaveAttempt(ctx context.Context, delivery models.WebhookDelivery) error
+}
+
+// Dispatcher sends the deliveries that come due. Several can work the same queue,
+// a claimed delivery is left alone by the others until its lease ends
+type Dispatcher struct {
+	queue       Queue
+	client      *http.Client
+	backoff     Backoff
+	maxAttempts int
+	batch       int
+	interval    time.Duration
+	now         func() time.Time
+}
+
+// Option changes the default configuration of the dispatcher
+type Option func(*Dispatcher)
+
+// WithClient sends deliveries with c, the default client gives up after 10s and
+// only reaches public addresses, see egress.NewClient
+func WithClient(c *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = c
+	}
+}
+
+// WithBackoff replaces DefaultBackoff
+func WithBackoff(b Backoff) Option {
+	return func(d *Dispatcher) {
+		d.backoff = b
+	}
+}
+
+// WithMaxAttempts sets how many failed attempts mark a delivery failed, 10 by default
+func WithMaxAttempts(n int) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = n
+	}
+}
+
+// WithInterval sets how often Run looks for due deliveries, every 5s by default
+func WithInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.interval = interval
+	}
+}
+
+func NewDispatcher(q Queue, opts ...Option) (*Dispatcher, error) {
+	if q == nil {
+		return nil, errors.New("queue cannot be null")
+	}
+	d := &Dispatcher{
+		queue:       q,
+		client:      egress.NewClient(10 * time.Second),
+		backoff:     DefaultBackoff(),
+		maxAttempts: 10,
+		batch:       20,
+		interval:    5 * time.Second,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.maxAttempts < 1 || d.interval <= 0 || d.backoff.Base <= 0 {
+		return nil, errors.New("webhook attempts, interval and backoff must be positive")
+	}
+	return d, nil
+}
+
+// Run sends due deliveries until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := d.DeliverDue(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("webhook deliveries stopped")
+			}
+			// a full batch means more may be waiting
+			if err != nil || n < d.batch {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue sends one batch of due deliveries and returns how many it tried
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	now := d.now()
+	// the lease outlasts every attempt of the batch, a dispatcher that dies
+	// midway leaves the rest to be claimed again once it ends
+	lease := now.Add(time.Duration(d.batch+1) * d.client.Timeout)
+	if d.client.Timeout == 0 {
+		lease = now.Add(time.Hour)
+	}
+	due, err := d.queue.ClaimDeliveries(ctx, now, lease, d.batch)
+	if err != nil {
+		return 0, err
+	}
+	for _, delivery := range due {
+		attempt := d.send(ctx, delivery)
+		err = d.queue.SaveAttempt(ctx, attempt)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(due), nil
+}
+
+// send posts the delivery and returns it updated with the outcome
+func (d *Dispatcher) send(ctx context.Context, due models.DueDelivery) models.WebhookDelivery {
+	delivery := due.WebhookDelivery
+	delivery.Attempts++
+	status, err := d.post(ctx, due)
+	delivery.LastStatusCode = status
+	if err == nil {
+		now := d.now()
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return delivery
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Status = models.DeliveryFailed
+		return delivery
+	}
+	delivery.Status = models.DeliveryPending
+	delivery.NextAttemptAt = d.now().Add(d.backoff.Delay(delivery.Attempts))
+	return delivery
+}
+
+// post sends the payload, any status outside 2xx is a failure
+func (d *Dispatcher) post(ctx context.Context, due models.DueDelivery) (int, error) {
+	body := []byte(due.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, due.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, due.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(due.ID), 10))
+	now := d.now()
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(due.Secret, now, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("the receiver answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,85 @@
+// Package webhook signs job events and posts them to the webhooks partners
+// subscribed, retrying with exponential backoff until they are taken
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// signaturePrefix names the scheme of the signature header
+const signaturePrefix = "sha256="
+
+// Sign is the signature header of body sent at timestamp, an HMAC-SHA256 with
+// secret of the unix timestamp, a dot and the body. Signing the timestamp lets
+// receivers refuse old deliveries replayed to them
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+var (
+	ErrNoSignature  = errors.New("the delivery is not signed")
+	ErrBadSignature = errors.New("the signature does not match")
+	ErrTooOld       = errors.New("the delivery is too old")
+)
+
+// Verify checks the signature of a delivery the way a receiver would, body is
+// the request body as it was read. Deliveries signed more than tolerance from
+// now are refused
+func Verify(secret string, header http.Header, body []byte, now time.Time, tolerance time.Duration) error {
+	sig, ts := header.Get(HeaderSignature), header.Get(HeaderTimestamp)
+	if sig == "" || ts == "" {
+		return ErrNoSignature
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrNoSignature
+	}
+	timestamp := time.Unix(unix, 0)
+	if now.Sub(timestamp) > tolerance || timestamp.Sub(now) > tolerance {
+		return ErrTooOld
+	}
+	if !hmac.Equal([]byte(sig), []byte(Sign(secret, timestamp, body))) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// Backoff is how long to wait before the next attempt after attempts failed
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// DefaultBackoff waits 30s after the first failure and doubles up to 6h
+func DefaultBackoff() Backoff {
+	return Backoff{Base: 30 * time.Second, Max: 6 * time.Hour}
+}
+
+func (b Backoff) Delay(attempts int) time.Duration {
+	d := b.Base
+	for i := 1; i < attempts && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	return d
+}
@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/models"
+	"strconv"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"job.created"}`)
+	signed := func(secret string, at time.Time) http.Header {
+		h := http.Header{}
+		h.Set(HeaderTimestamp, strconv.FormatInt(at.Unix(), 10))
+		h.Set(HeaderSignature, Sign(secret, at, body))
+		return h
+	}
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		want   error
+	}{
+		{name: "signed", header: signed("0123456789abcdef", now), body: body},
+		{name: "not signed", header: http.Header{}, body: body, want: ErrNoSignature},
+		{name: "another secret", header: signed("fedcba9876543210", now), body: body, want: ErrBadSignature},
+		{name: "changed body", header: signed("0123456789abcdef", now), body: []byte(`{"event":"job.closed"}`), want: ErrBadSignature},
+		{name: "replayed", header: signed("0123456789abcdef", now.Add(-10*time.Minute)), body: body, want: ErrTooOld},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify("0123456789abcdef", tt.header, tt.body, now, 5*time.Minute)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	b := Backoff{Base: time.Second, Max: 10 * time.Second}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{30, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := b.Delay(tt.attempts); got != tt.want {
+			t.Errorf("Delay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// fakeQueue hands out its deliveries once and keeps the attempts saved
+type fakeQueue struct {
+	due   []models.DueDelivery
+	saved []models.WebhookDelivery
+}
+
+func (q *fakeQueue) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Time, limit int) ([]models.DueDelivery, error) {
+	due := q.due
+	q.due = nil
+	return due, nil
+}
+
+func (q *fakeQueue) SaveAttempt(ctx context.Context, delivery models.WebhookDelivery) error {
+	q.saved = append(q.saved, delivery)
+	return nil
+}
+
+func TestDispatcher_DeliverDue(t *testing.T) {
+	const secret = "0123456789abcdef"
+	now := time.Now().Truncate(time.Second)
+	tests := []struct {
+		name         string
+		answer       int
+		attempts     int
+		wantStatus   string
+		wantAttempts int
+		wantNext     time.Time
+	}{
+		{name: "taken", answer: http.StatusNoContent, wantStatus: models.DeliveryDelivered, wantAttempts: 1},
+		{name: "refused", answer: http.StatusInternalServerError, attempts: 2, wantStatus: models.DeliveryPending, wantAttempts: 3, wantNext: now.Add(4 * time.Second)},
+		{name: "refused for the last time", answer: http.StatusGone, attempts: 4, wantStatus: models.DeliveryFailed, wantAttempts: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				err := Verify(secret, r.Header, body, time.Now(), time.Minute)
+				if err != nil {
+					t.Errorf("the receiver could not verify the delivery: %v", err)
+				}
+				got = r
+				w.WriteHeader(tt.answer)
+			}))
+			defer receiver.Close()
+
+			q := &fakeQueue{due: []models.DueDelivery{{
+				WebhookDelivery: models.WebhookDelivery{Model: gorm.Model{ID: 8}, WebhookID: 3, Event: models.EventJobCreated,
+					Payload: `{"event":"job.created"}`, Status: models.DeliveryPending, Attempts: tt.attempts},
+				URL:    receiver.URL,
+				Secret: secret,
+			}}}
+			d, err := NewDispatcher(q, WithClient(receiver.Client()), WithBackoff(Backoff{Base: time.Second, Max: time.Minute}), WithMaxAttempts(5))
+			if err != nil {
+				t.Fatalf("NewDispatcher() error = %v", err)
+			}
+			d.now = func() time.Time { return now }
+
+			n, err := d.DeliverDue(context.Background())
+			if err != nil || n != 1 {
+				t.Fatalf("DeliverDue() = %d, %v, want 1 delivery", n, err)
+			}
+			if got == nil || got.Header.Get(HeaderEvent) != models.EventJobCreated || got.Header.Get(HeaderDelivery) != "8" {
+				t.Fatalf("the receiver got %v, want the job.created delivery 8", got)
+			}
+			if len(q.saved) != 1 {
+				t.Fatalf("%d attempts saved, want 1", len(q.saved))
+			}
+			saved := q.saved[0]
+			if saved.Status != tt.wantStatus || saved.Attempts != tt.wantAttempts || saved.LastStatusCode != tt.answer {
+				t.Errorf("saved %s after %d attempts with %d, want %s after %d with %d",
+					saved.Status, saved.Attempts, saved.LastStatusCode, tt.wantStatus, tt.wantAttempts, tt.answer)
+			}
+			if !tt.wantNext.IsZero() && !saved.NextAttemptAt.Equal(tt.wantNext) {
+				t.Errorf("next attempt at %v, want %v", saved.NextAttemptAt, tt.wantNext)
+			}
+			if (saved.DeliveredAt != nil) != (tt.wantStatus == models.DeliveryDelivered) {
+				t.Errorf("delivered at %v with status %s", saved.DeliveredAt, saved.Status)
+			}
+		})
+	}
+}
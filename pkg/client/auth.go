@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrNoCredentials is returned for a call that needs a token when the client has
+// none and no credentials to get one
+var ErrNoCredentials = errors.New("the client has no token and no credentials to sign in with")
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type tokenResponse struct {
+	Token string `json:"token"`
+}
+
+// Login signs in with the credentials, the client then authenticates with the
+// token it gets and signs in the same way again when the token runs out
+func (c *Client) Login(ctx context.Context, email, password string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.email, c.password = email, password
+	return c.login(ctx)
+}
+
+// Token is the token the client authenticates with, it signs in first when it
+// has none or its token is about to expire
+func (c *Client) Token(ctx context.Context) (string, error) {
+	return c.validToken(ctx)
+}
+
+// validToken is the current token, renewed when it expires within refreshGap.
+// Concurrent calls wait for one sign in
+func (c *Client) validToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && (c.expires.IsZero() || c.now().Add(c.refreshGap).Before(c.expires)) {
+		return c.token, nil
+	}
+	if c.email == "" {
+		if c.token != "" {
+			// an expiring token of the caller is still worth sending
+			return c.token, nil
+		}
+		return "", ErrNoCredentials
+	}
+	return c.login(ctx)
+}
+
+// login signs in with the credentials, c.mu is held
+func (c *Client) login(ctx context.Context) (string, error) {
+	r, err := jsonCall(http.MethodPost, APIPrefix+"/sessions", loginRequest{Email: c.email, Password: c.password})
+	if err != nil {
+		return "", err
+	}
+	r.public = true
+	var t tokenResponse
+	err = c.do(ctx, r, &t)
+	if err != nil {
+		return "", err
+	}
+	c.token, c.expires = t.Token, expiry(t.Token)
+	return c.token, nil
+}
+
+func (c *Client) canLogin() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.email != ""
+}
+
+// forget drops the token the server refused, unless another call already
+// replaced it
+func (c *Client) forget(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == token {
+		c.token, c.expires = "", time.Time{}
+	}
+}
+
+// expiry reads when the token expires without verifying it, only the server can.
+// It is zero for a token that does not say
+func expiry(token string) time.Time {
+	var claims jwt.RegisteredClaims
+	_, _, err := jwt.NewParser().ParseUnverified(token, &claims)
+	if err != nil || claims.ExpiresAt == nil {
+		return time.Time{}
+	}
+	return claims.ExpiresAt.Time
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// TalentQuery filters a candidate search, only recruiters of a verified company
+// can search. A candidate has all the skills and any of the availabilities
+type TalentQuery struct {
+	Skills        []string
+	Location      string
+	MinExperience int
+	MaxExperience int
+	Availability  []string
+	PageSize      int
+}
+
+func (q TalentQuery) values() url.Values {
+	v := url.Values{}
+	for _, skill := range q.Skills {
+		v.Add("skill", skill)
+	}
+	if q.Location != "" {
+		v.Set("location", q.Location)
+	}
+	if q.MinExperience > 0 {
+		v.Set("min_experience", strconv.Itoa(q.MinExperience))
+	}
+	if q.MaxExperience > 0 {
+		v.Set("max_experience", strconv.Itoa(q.MaxExperience))
+	}
+	for _, a := range q.Availability {
+		v.Add("availability", a)
+	}
+	if q.PageSize > 0 {
+		v.Set("page_size", strconv.Itoa(q.PageSize))
+	}
+	return v
+}
+
+// SearchCandidates walks every candidate the query finds, page after page
+func (c *Client) SearchCandidates(ctx context.Context, q TalentQuery) *Iterator[Candidate] {
+	return newIterator(ctx, func(ctx context.Context, page int) ([]Candidate, int64, error) {
+		result, err := c.SearchCandidatesPage(ctx, q, page)
+		return result.Candidates, result.Total, err
+	})
+}
+
+// SearchCandidatesPage is one page of the search, pages count from 1
+func (c *Client) SearchCandidatesPage(ctx context.Context, q TalentQuery, page int) (TalentResult, error) {
+	query := q.values()
+	query.Set("page", strconv.Itoa(page))
+	return get[TalentResult](ctx, c, APIPrefix+"/candidates", query)
+}
+
+// Candidate is the profile of the candidate, the view is recorded for them to see
+func (c *Client) Candidate(ctx context.Context, userID uint) (Candidate, error) {
+	return get[Candidate](ctx, c, APIPrefix+"/candidates/"+id(userID), nil)
+}
@@ -0,0 +1,376 @@
+// Package client is the Go client of the portal API. A Client signs in with the
+// credentials it was given and signs in again before its token expires, retries
+// the calls that are safe to repeat and turns error responses into an *Error.
+//
+//	c, err := client.New("https://portal.example", client.WithCredentials(email, password))
+//	...
+//	jobs := c.SearchJobs(ctx, client.JobQuery{Query: "golang"})
+//	for jobs.Next() {
+//		fmt.Println(jobs.Value().Name)
+//	}
+//	if err := jobs.Err(); err != nil {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIPrefix is where the versioned api is served under the base url
+const APIPrefix = "/api/v1"
+
+// IdempotencyKeyHeader makes a retried POST creating a resource safe, the server
+// answers a repeated key with the first response
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Client calls the portal API, it is safe for concurrent use
+type Client struct {
+	base       *url.URL
+	httpClient *http.Client
+	userAgent  string
+
+	retries    int
+	retryBase  time.Duration
+	retryMax   time.Duration
+	refreshGap time.Duration
+
+	mu       sync.Mutex
+	email    string
+	password string
+	token    string
+	expires  time.Time
+
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+}
+
+// Option changes the default configuration of the client
+type Option func(*Client)
+
+// WithCredentials signs the client in as the user, it signs in again whenever
+// the token is about to expire or is refused
+func WithCredentials(email, password string) Option {
+	return func(c *Client) {
+		c.email, c.password = email, password
+	}
+}
+
+// WithToken authenticates with a token the caller got elsewhere, the client
+// cannot renew it unless it also has credentials
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+		c.expires = expiry(token)
+	}
+}
+
+// WithHTTPClient replaces http.DefaultClient
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithRetries sets how often a call that is safe to repeat is retried, 3 times
+// by default, waiting base after the first failure and doubling up to max
+func WithRetries(n int, base, max time.Duration) Option {
+	return func(c *Client) {
+		c.retries, c.retryBase, c.retryMax = n, base, max
+	}
+}
+
+// WithUserAgent names the application in the User-Agent of every request
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// New is a client of the api served at baseURL, the scheme and host the api is
+// reached at
+func New(baseURL string, opts ...Option) (*Client, error) {
+	base, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, errors.New("the base url must be http or https")
+	}
+	c := &Client{
+		base:       base,
+		httpClient: http.DefaultClient,
+		userAgent:  "portal-go-client",
+		retries:    3,
+		retryBase:  200 * time.Millisecond,
+		retryMax:   5 * time.Second,
+		refreshGap: time.Minute,
+		sleep:      sleep,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.httpClient == nil {
+		return nil, errors.New("http client cannot be null")
+	}
+	if c.retries < 0 || c.retryBase <= 0 || c.retryMax < c.retryBase {
+		return nil, errors.New("retries cannot be negative and the backoff must be positive")
+	}
+	return c, nil
+}
+
+// call is one api request
+type call struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	// body is sent as is, with contentType
+	body        []byte
+	contentType string
+	// public calls are sent without a token
+	public bool
+}
+
+// jsonCall is a call sending v as its json body
+func jsonCall(method, path string, v interface{}) (call, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return call{}, err
+	}
+	return call{method: method, path: path, body: b, contentType: "application/json"}, nil
+}
+
+// retryable reports whether repeating the call cannot apply it twice. A POST is
+// when it carries an idempotency key, a PATCH never is as it may be a JSON Patch
+func (r call) retryable() bool {
+	switch r.method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return r.header.Get(IdempotencyKeyHeader) != ""
+	}
+	return false
+}
+
+// send writes body to path and decodes the record the server answers with. A
+// PATCH is sent as a JSON Merge Patch, a POST creates the record and carries an
+// idempotency key
+func send[T any](ctx context.Context, c *Client, method, path string, body interface{}, ifMatch string) (T, error) {
+	var record T
+	r, err := jsonCall(method, path, body)
+	if err != nil {
+		return record, err
+	}
+	if method == http.MethodPatch {
+		r.contentType = MergePatchType
+	}
+	r.header = ifMatchHeader(ifMatch)
+	if method == http.MethodPost {
+		if r.header == nil {
+			r.header = make(http.Header)
+		}
+		r.header.Set(IdempotencyKeyHeader, newKey())
+	}
+	err = c.do(ctx, r, &record)
+	return record, err
+}
+
+func ifMatchHeader(ifMatch string) http.Header {
+	if ifMatch == "" {
+		return nil
+	}
+	return http.Header{"If-Match": {ifMatch}}
+}
+
+// get decodes the json the server answers the GET of path with
+func get[T any](ctx context.Context, c *Client, path string, query url.Values) (T, error) {
+	var record T
+	err := c.do(ctx, call{method: http.MethodGet, path: path, query: query}, &record)
+	return record, err
+}
+
+// do sends the call and decodes its json response into out, when out is not nil
+func (c *Client) do(ctx context.Context, r call, out interface{}) error {
+	resp, err := c.send(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("%s %s: decoding the response: %w", r.method, r.path, err)
+	}
+	return nil
+}
+
+// raw sends the call and returns its body as is
+func (c *Client) raw(ctx context.Context, r call) ([]byte, error) {
+	resp, err := c.send(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// send sends the call, signing in first when needed and retrying what is safe to
+// retry. A response other than 2xx is returned as an *Error, a 2xx response is
+// left for the caller to read and close
+func (c *Client) send(ctx context.Context, r call) (*http.Response, error) {
+	relogged := false
+	for attempt := 1; ; attempt++ {
+		var token string
+		if !r.public {
+			var err error
+			token, err = c.validToken(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resp, err := c.attempt(ctx, r, token)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			return resp, nil
+		}
+
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && !r.public && !relogged && c.canLogin() {
+			// the token may have been revoked or signed with a rotated key,
+			// signing in again is worth one more try
+			drain(resp)
+			relogged = true
+			c.forget(token)
+			attempt--
+			continue
+		}
+
+		var delay time.Duration
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			err = fmt.Errorf("%s %s: %w", r.method, r.path, err)
+		} else {
+			delay = retryAfter(resp.Header.Get("Retry-After"))
+			err = errorOf(resp)
+		}
+		if !r.retryable() || attempt > c.retries || !temporary(err) {
+			return nil, err
+		}
+		if delay == 0 {
+			delay = c.backoff(attempt)
+		}
+		if serr := c.sleep(ctx, delay); serr != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, r call, token string) (*http.Response, error) {
+	u := *c.base
+	u.Path = c.base.Path + r.path
+	u.RawQuery = r.query.Encode()
+
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// temporary reports whether a failed call may succeed when repeated, after a
+// network error, a rate limit or a server error
+func temporary(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return true
+	}
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff is how long to wait after the failed attempt
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.retryBase
+	for i := 1; i < attempt && d < c.retryMax; i++ {
+		d *= 2
+	}
+	if d > c.retryMax {
+		d = c.retryMax
+	}
+	return d
+}
+
+// retryAfter reads a Retry-After of seconds, the server sends no dates
+func retryAfter(v string) time.Duration {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return time.Duration(n) * time.Second
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// newKey is a random idempotency key, the same for every attempt of a call
+func newKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func id(n uint) string {
+	return strconv.FormatUint(uint64(n), 10)
+}
@@ -0,0 +1,314 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"project/internal/apperr"
+	"project/internal/auth"
+	handler "project/internal/handlers"
+	mock_files "project/internal/mock-files"
+	"project/internal/models"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+// portal is the api served by handler.API, with real tokens and a mocked service
+type portal struct {
+	*httptest.Server
+	svc  *mock_files.MockUserService
+	auth auth.UserAuth
+}
+
+func newPortal(t *testing.T, opts ...handler.Option) *portal {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	a, err := auth.NewAuth(key, &key.PublicKey)
+	if err != nil {
+		t.Fatalf("NewAuth() error = %v", err)
+	}
+	svc := mock_files.NewMockUserService(gomock.NewController(t))
+	srv := httptest.NewServer(handler.API(a, svc, opts...))
+	t.Cleanup(srv.Close)
+	return &portal{Server: srv, svc: svc, auth: a}
+}
+
+// token is a token of the user the portal accepts until ttl passed
+func (p *portal) token(t *testing.T, uid uint, ttl time.Duration) string {
+	t.Helper()
+	tkn, err := p.auth.GenerateToken(jwt.RegisteredClaims{
+		Subject:   strconv.FormatUint(uint64(uid), 10),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+	})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	return tkn
+}
+
+// expectLogin answers the sign ins of the user with the tokens, in order
+func (p *portal) expectLogin(tokens ...string) {
+	calls := make([]any, 0, len(tokens))
+	for _, tkn := range tokens {
+		calls = append(calls, p.svc.EXPECT().UserLogin(gomock.Any(), models.NewUser{Email: "asha@example.com", Password: "secret"}).Return(tkn, nil))
+	}
+	gomock.InOrder(calls...)
+}
+
+func (p *portal) client(t *testing.T, opts ...Option) *Client {
+	t.Helper()
+	c, err := New(p.URL, append([]Option{WithCredentials("asha@example.com", "secret")}, opts...)...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	c.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	return c
+}
+
+// flaky loses the responses to the first fails requests after the server
+// handled them, as a connection dropped on the way back would
+type flaky struct {
+	mu       sync.Mutex
+	fails    int
+	requests []*http.Request
+}
+
+func (f *flaky) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req)
+	if err != nil || f.fails == 0 {
+		return resp, err
+	}
+	f.fails--
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return nil, errors.New("connection reset by peer")
+}
+
+func company(id uint, name string) models.Company {
+	return models.Company{Model: gorm.Model{ID: id, UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Name: name, Location: "Pune", Field: "software"}
+}
+
+func job(id uint, name string) models.Jobs {
+	return models.Jobs{Model: gorm.Model{ID: id, UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Cid: 7, Name: name, Location: "Pune", Status: models.JobPublished}
+}
+
+func TestNew(t *testing.T) {
+	for _, base := range []string{"portal.example", "ftp://portal.example", "://"} {
+		_, err := New(base)
+		if err == nil {
+			t.Errorf("New(%q) error = nil, want an invalid base url", base)
+		}
+	}
+	_, err := New("https://portal.example", WithRetries(-1, time.Second, time.Minute))
+	if err == nil {
+		t.Error("New() with negative retries error = nil")
+	}
+}
+
+func TestClient_signIn(t *testing.T) {
+	p := newPortal(t, handler.WithValidation())
+	p.expectLogin(p.token(t, 4, time.Hour))
+	p.svc.EXPECT().ViewAllCompanies(gomock.Any()).Return([]models.Company{company(7, "Tek Solutions")}, nil).Times(2)
+	c := p.client(t)
+
+	for i := 0; i < 2; i++ {
+		companies, err := c.Companies(context.Background())
+		if err != nil {
+			t.Fatalf("Companies() error = %v", err)
+		}
+		assert.Equal(t, []Company{company(7, "Tek Solutions")}, companies)
+	}
+}
+
+func TestClient_refresh(t *testing.T) {
+	p := newPortal(t, handler.WithValidation())
+	p.expectLogin(p.token(t, 4, time.Hour), p.token(t, 4, 2*time.Hour))
+	p.svc.EXPECT().ViewAllJobs(gomock.Any()).Return([]models.Jobs{}, nil).Times(3)
+	c := p.client(t)
+
+	_, err := c.Jobs(context.Background())
+	if err != nil {
+		t.Fatalf("Jobs() error = %v", err)
+	}
+	// with the token about to expire the client signs in again, once
+	now := time.Now()
+	c.now = func() time.Time { return now.Add(time.Hour - 30*time.Second) }
+	for i := 0; i < 2; i++ {
+		_, err = c.Jobs(context.Background())
+		if err != nil {
+			t.Fatalf("Jobs() error = %v", err)
+		}
+	}
+}
+
+func TestClient_refused(t *testing.T) {
+	p := newPortal(t, handler.WithValidation())
+	other := newPortal(t, handler.WithValidation())
+	// a token the portal did not sign is refused, the client signs in instead
+	p.expectLogin(p.token(t, 4, time.Hour))
+	p.svc.EXPECT().ViewJobById(gomock.Any(), uint(4), uint64(11)).Return(job(11, "Backend developer"), nil)
+	c := p.client(t, WithToken(other.token(t, 4, time.Hour)))
+
+	got, err := c.Job(context.Background(), 11)
+	if err != nil {
+		t.Fatalf("Job() error = %v", err)
+	}
+	assert.Equal(t, job(11, "Backend developer"), got)
+
+	// without credentials the refusal is the error
+	c, err = New(p.URL, WithToken(other.token(t, 4, time.Hour)))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_, err = c.Job(context.Background(), 11)
+	assert.Equal(t, true, errors.Is(err, ErrUnauthorized))
+
+	c, err = New(p.URL)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_, err = c.Job(context.Background(), 11)
+	assert.Equal(t, ErrNoCredentials, err)
+}
+
+func TestClient_errors(t *testing.T) {
+	p := newPortal(t, handler.WithValidation())
+	p.expectLogin(p.token(t, 4, time.Hour))
+	p.svc.EXPECT().ViewCompanyDetails(gomock.Any(), uint64(9)).Return(models.Company{}, apperr.New(apperr.NotFound, "company not found"))
+	p.svc.EXPECT().UpdateJob(gomock.Any(), uint(4), uint64(11), gomock.Any(), `"stale"`).Return(models.Jobs{}, apperr.ErrPreconditionFailed)
+	c := p.client(t)
+	ctx := context.Background()
+
+	_, err := c.Company(ctx, 9)
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("Company() error = %v, want an *Error", err)
+	}
+	assert.Equal(t, http.StatusNotFound, e.StatusCode)
+	assert.Equal(t, "company not found", e.Detail)
+	assert.Equal(t, true, e.TraceID != "")
+	assert.Equal(t, true, errors.Is(err, ErrNotFound))
+	assert.Equal(t, false, errors.Is(err, ErrConflict))
+
+	_, err = c.UpdateJob(ctx, 11, job(11, "Backend developer"), `"stale"`)
+	assert.Equal(t, true, errors.Is(err, ErrPreconditionFailed))
+
+	// the api spec refuses the filter before any handler runs
+	it := c.SearchJobs(ctx, JobQuery{EmploymentType: []string{"forever"}})
+	assert.Equal(t, false, it.Next())
+	assert.Equal(t, true, errors.Is(it.Err(), ErrValidation))
+}
+
+func TestClient_retries(t *testing.T) {
+	p := newPortal(t, handler.WithValidation())
+	p.expectLogin(p.token(t, 4, time.Hour))
+	gomock.InOrder(
+		p.svc.EXPECT().ViewAllCompanies(gomock.Any()).Return(nil, apperr.New(apperr.Unavailable, "try again")),
+		p.svc.EXPECT().ViewAllCompanies(gomock.Any()).Return([]models.Company{company(7, "Tek Solutions")}, nil),
+	)
+	// the retried POST is answered from the first response, the company is added once
+	p.svc.EXPECT().AddCompanyDetails(gomock.Any(), gomock.Any(), uint(4)).Return(company(8, "Acme"), nil)
+	p.svc.EXPECT().PatchJob(gomock.Any(), uint(4), uint64(11), gomock.Any(), `"v1"`).Return(job(11, "Go developer"), nil)
+
+	transport := &flaky{}
+	c := p.client(t, WithHTTPClient(&http.Client{Transport: transport}), WithRetries(3, time.Second, 4*time.Second))
+	var delays []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	ctx := context.Background()
+
+	companies, err := c.Companies(ctx)
+	if err != nil {
+		t.Fatalf("Companies() error = %v", err)
+	}
+	assert.Equal(t, []Company{company(7, "Tek Solutions")}, companies)
+	assert.Equal(t, []time.Duration{time.Second}, delays)
+
+	transport.fails = 2
+	created, err := c.CreateCompany(ctx, Company{Name: "Acme", Location: "Pune", Field: "software"})
+	if err != nil {
+		t.Fatalf("CreateCompany() error = %v", err)
+	}
+	assert.Equal(t, company(8, "Acme"), created)
+	assert.Equal(t, []time.Duration{time.Second, time.Second, 2 * time.Second}, delays)
+	posts := transport.requests[len(transport.requests)-3:]
+	key := posts[0].Header.Get(IdempotencyKeyHeader)
+	assert.Equal(t, true, key != "")
+	for _, req := range posts {
+		assert.Equal(t, key, req.Header.Get(IdempotencyKeyHeader))
+	}
+
+	// a patch may not be safe to repeat, it is never retried
+	transport.fails = 1
+	sent := len(transport.requests)
+	_, err = c.PatchJob(ctx, 11, map[string]string{"name": "Go developer"}, `"v1"`)
+	assert.Equal(t, true, err != nil)
+	assert.Equal(t, sent+1, len(transport.requests))
+}
+
+func TestClient_SearchJobs(t *testing.T) {
+	p := newPortal(t, handler.WithValidation())
+	p.expectLogin(p.token(t, 4, time.Hour))
+	filter := func(page int) models.JobFilter {
+		return models.JobFilter{Query: "go", Location: []string{"pune"}, Page: page, PageSize: 2}
+	}
+	p.svc.EXPECT().SearchJobs(gomock.Any(), filter(1)).
+		Return(models.JobSearchResult{Jobs: []models.Jobs{job(1, "a"), job(2, "b")}, Total: 5, Page: 1, PageSize: 2}, nil)
+	p.svc.EXPECT().SearchJobs(gomock.Any(), filter(2)).
+		Return(models.JobSearchResult{Jobs: []models.Jobs{job(3, "c"), job(4, "d")}, Total: 5, Page: 2, PageSize: 2}, nil)
+	p.svc.EXPECT().SearchJobs(gomock.Any(), filter(3)).
+		Return(models.JobSearchResult{Jobs: []models.Jobs{job(5, "e")}, Total: 5, Page: 3, PageSize: 2}, nil)
+	c := p.client(t)
+
+	it := c.SearchJobs(context.Background(), JobQuery{Query: "go", Locations: []string{"pune"}, PageSize: 2})
+	var names []string
+	for it.Next() {
+		names = append(names, it.Value().Name)
+	}
+	if it.Err() != nil {
+		t.Fatalf("SearchJobs() error = %v", it.Err())
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names)
+	assert.Equal(t, int64(5), it.Total())
+}
+
+func TestClient_SearchCandidates(t *testing.T) {
+	p := newPortal(t, handler.WithValidation())
+	p.expectLogin(p.token(t, 4, time.Hour))
+	p.svc.EXPECT().SearchTalent(gomock.Any(), uint(4), models.TalentFilter{Skills: []string{"go"}, MinExperience: 3, Page: 1}).
+		Return(models.TalentSearchResult{Candidates: []models.Candidate{{UserID: 2, Skills: []string{"go"}}}, Total: 1, Page: 1, PageSize: 20}, nil)
+	c := p.client(t)
+
+	it := c.SearchCandidates(context.Background(), TalentQuery{Skills: []string{"go"}, MinExperience: 3})
+	var ids []uint
+	for it.Next() {
+		ids = append(ids, it.Value().UserID)
+	}
+	if it.Err() != nil {
+		t.Fatalf("SearchCandidates() error = %v", it.Err())
+	}
+	assert.Equal(t, []uint{2}, ids)
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// media types of the bodies the client sends besides json
+const (
+	MergePatchType = "application/merge-patch+json"
+	NDJSONType     = "application/x-ndjson"
+)
+
+// SignUp creates the account of a user, it needs no token
+func (c *Client) SignUp(ctx context.Context, u NewUser) (User, error) {
+	r, err := jsonCall(http.MethodPost, APIPrefix+"/users", u)
+	if err != nil {
+		return User{}, err
+	}
+	r.public = true
+	var created User
+	err = c.do(ctx, r, &created)
+	return created, err
+}
+
+// Companies lists every company
+func (c *Client) Companies(ctx context.Context) ([]Company, error) {
+	return get[[]Company](ctx, c, APIPrefix+"/companies", nil)
+}
+
+// Company is the company of the id, its ETag() is what UpdateCompany,
+// PatchCompany and DeleteCompany take in ifMatch
+func (c *Client) Company(ctx context.Context, cid uint) (Company, error) {
+	return get[Company](ctx, c, APIPrefix+"/companies/"+id(cid), nil)
+}
+
+// CreateCompany adds a company owned by the signed in user
+func (c *Client) CreateCompany(ctx context.Context, company Company) (Company, error) {
+	return send[Company](ctx, c, http.MethodPost, APIPrefix+"/companies", company, "")
+}
+
+// UpdateCompany replaces the company, ifMatch is the ETag of the version it was
+// read at and ErrPreconditionFailed answers when it changed since
+func (c *Client) UpdateCompany(ctx context.Context, cid uint, company Company, ifMatch string) (Company, error) {
+	return send[Company](ctx, c, http.MethodPut, APIPrefix+"/companies/"+id(cid), company, ifMatch)
+}
+
+// PatchCompany changes the fields of the company patch sets, as a JSON Merge Patch
+func (c *Client) PatchCompany(ctx context.Context, cid uint, patch interface{}, ifMatch string) (Company, error) {
+	return send[Company](ctx, c, http.MethodPatch, APIPrefix+"/companies/"+id(cid), patch, ifMatch)
+}
+
+// DeleteCompany deletes the company at the version of ifMatch
+func (c *Client) DeleteCompany(ctx context.Context, cid uint, ifMatch string) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: APIPrefix + "/companies/" + id(cid), header: ifMatchHeader(ifMatch)}, nil)
+}
+
+// ImportCompanies adds the companies in one bulk import
+func (c *Client) ImportCompanies(ctx context.Context, companies []Company, opts ImportOptions) (ImportReport, error) {
+	return importRows(ctx, c, APIPrefix+"/companies/import", companies, opts)
+}
+
+// CompanyJobs lists the jobs of the company
+func (c *Client) CompanyJobs(ctx context.Context, cid uint) ([]Job, error) {
+	return get[[]Job](ctx, c, APIPrefix+"/companies/"+id(cid)+"/jobs", nil)
+}
+
+// CreateJob posts a job at the company
+func (c *Client) CreateJob(ctx context.Context, cid uint, job Job) (Job, error) {
+	return send[Job](ctx, c, http.MethodPost, APIPrefix+"/companies/"+id(cid)+"/jobs", job, "")
+}
+
+// AddMember makes a user a member of the company
+func (c *Client) AddMember(ctx context.Context, cid uint, m Membership) (Membership, error) {
+	return send[Membership](ctx, c, http.MethodPost, APIPrefix+"/companies/"+id(cid)+"/members", m, "")
+}
+
+// VerifyCompany lets the members of the company search candidates, only admins can
+func (c *Client) VerifyCompany(ctx context.Context, cid uint) (Verification, error) {
+	var verification Verification
+	err := c.do(ctx, call{method: http.MethodPut, path: APIPrefix + "/companies/" + id(cid) + "/verification"}, &verification)
+	return verification, err
+}
+
+// Webhooks lists the webhooks of the company
+func (c *Client) Webhooks(ctx context.Context, cid uint) ([]Webhook, error) {
+	return get[[]Webhook](ctx, c, APIPrefix+"/companies/"+id(cid)+"/webhooks", nil)
+}
+
+// CreateWebhook subscribes the url of hook to the events of the company
+func (c *Client) CreateWebhook(ctx context.Context, cid uint, hook Webhook) (Webhook, error) {
+	return send[Webhook](ctx, c, http.MethodPost, APIPrefix+"/companies/"+id(cid)+"/webhooks", hook, "")
+}
+
+// DeleteWebhook deletes the webhook of the company
+func (c *Client) DeleteWebhook(ctx context.Context, cid, wid uint) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: APIPrefix + "/companies/" + id(cid) + "/webhooks/" + id(wid)}, nil)
+}
+
+// WebhookDeliveries lists the deliveries of the webhook
+func (c *Client) WebhookDeliveries(ctx context.Context, cid, wid uint) ([]WebhookDelivery, error) {
+	return get[[]WebhookDelivery](ctx, c, APIPrefix+"/companies/"+id(cid)+"/webhooks/"+id(wid)+"/deliveries", nil)
+}
+
+// Redeliver sends the delivery of the webhook again
+func (c *Client) Redeliver(ctx context.Context, cid, wid, did uint) (WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	err := c.do(ctx, call{method: http.MethodPost,
+		path: APIPrefix + "/companies/" + id(cid) + "/webhooks/" + id(wid) + "/deliveries/" + id(did) + "/redeliver"}, &delivery)
+	return delivery, err
+}
+
+// Connectors lists the applicant tracking systems the jobs of the company are pulled from
+func (c *Client) Connectors(ctx context.Context, cid uint) ([]ATSConnector, error) {
+	return get[[]ATSConnector](ctx, c, APIPrefix+"/companies/"+id(cid)+"/connectors", nil)
+}
+
+// CreateConnector pulls the jobs of the company from the board of connector
+func (c *Client) CreateConnector(ctx context.Context, cid uint, connector ATSConnector) (ATSConnector, error) {
+	return send[ATSConnector](ctx, c, http.MethodPost, APIPrefix+"/companies/"+id(cid)+"/connectors", connector, "")
+}
+
+// DeleteConnector stops pulling jobs from the connector
+func (c *Client) DeleteConnector(ctx context.Context, cid, connectorID uint) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: APIPrefix + "/companies/" + id(cid) + "/connectors/" + id(connectorID)}, nil)
+}
+
+// importRows sends rows as NDJSON to the import at path. An import that stopped
+// after committing some rows answers without an error, the Error of its report
+// tells why the rows past CommittedLine were not stored
+func importRows[T any](ctx context.Context, c *Client, path string, rows []T, opts ImportOptions) (ImportReport, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	for _, row := range rows {
+		err := enc.Encode(row)
+		if err != nil {
+			return ImportReport{}, err
+		}
+	}
+	query := url.Values{}
+	if opts.DryRun {
+		query.Set("dry_run", "true")
+	}
+	if opts.ChunkSize > 0 {
+		query.Set("chunk_size", strconv.Itoa(opts.ChunkSize))
+	}
+	var report ImportReport
+	err := c.do(ctx, call{method: http.MethodPost, path: path, query: query, body: b.Bytes(), contentType: NDJSONType}, &report)
+	return report, err
+}
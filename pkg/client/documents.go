@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// formats of the job feeds
+const (
+	FeedRSS  = "rss"
+	FeedAtom = "atom"
+)
+
+// JobsFeed is the RSS or Atom feed of the newest published jobs the query
+// finds, as the server writes it. Feeds need no token, the page size of the
+// query is left out
+func (c *Client) JobsFeed(ctx context.Context, format string, q JobQuery) ([]byte, error) {
+	return c.feed(ctx, APIPrefix+"/jobs."+format, q)
+}
+
+// CompanyJobsFeed is JobsFeed of the jobs of one company
+func (c *Client) CompanyJobsFeed(ctx context.Context, cid uint, format string, q JobQuery) ([]byte, error) {
+	return c.feed(ctx, APIPrefix+"/companies/"+id(cid)+"/jobs."+format, q)
+}
+
+func (c *Client) feed(ctx context.Context, path string, q JobQuery) ([]byte, error) {
+	q.PageSize = 0
+	return c.raw(ctx, call{method: http.MethodGet, path: path, query: q.values(), public: true,
+		header: http.Header{"Accept": {"application/rss+xml, application/atom+xml"}}})
+}
+
+// AggregatorFeed is the XML feed of the published jobs in the format of a job
+// aggregator, the server picks its generic format when format is empty
+func (c *Client) AggregatorFeed(ctx context.Context, format string) ([]byte, error) {
+	return c.raw(ctx, call{method: http.MethodGet, path: APIPrefix + "/aggregator/jobs.xml", query: formatQuery(format), public: true,
+		header: http.Header{"Accept": {"application/xml"}}})
+}
+
+// AggregatorReport tells which published jobs the aggregator feed of format
+// leaves out and why
+func (c *Client) AggregatorReport(ctx context.Context, format string) (AggregatorReport, error) {
+	return get[AggregatorReport](ctx, c, APIPrefix+"/aggregator/report", formatQuery(format))
+}
+
+func formatQuery(format string) url.Values {
+	if format == "" {
+		return nil
+	}
+	return url.Values{"format": {format}}
+}
+
+// Sitemap is the sitemap of the published jobs, or the index of its pages once
+// there are too many for one
+func (c *Client) Sitemap(ctx context.Context) ([]byte, error) {
+	return c.raw(ctx, call{method: http.MethodGet, path: APIPrefix + "/sitemap.xml", public: true,
+		header: http.Header{"Accept": {"application/xml"}}})
+}
+
+// SitemapPage is one page of a sitemap split by an index, pages count from 1
+func (c *Client) SitemapPage(ctx context.Context, page uint) ([]byte, error) {
+	return c.raw(ctx, call{method: http.MethodGet, path: APIPrefix + "/sitemaps/" + id(page), public: true,
+		header: http.Header{"Accept": {"application/xml"}}})
+}
+
+// PreviewEmail renders an email template in locale from made up data, only
+// admins can
+func (c *Client) PreviewEmail(ctx context.Context, template, locale string) (RenderedEmail, error) {
+	query := url.Values{"template": {template}}
+	if locale != "" {
+		query.Set("locale", locale)
+	}
+	return get[RenderedEmail](ctx, c, APIPrefix+"/emails/preview", query)
+}
+
+// Unsubscribe stops the email notifications of the user the token of an
+// unsubscribe link was signed for, it needs no other token
+func (c *Client) Unsubscribe(ctx context.Context, token string) (Unsubscribed, error) {
+	var unsubscribed Unsubscribed
+	err := c.do(ctx, call{method: http.MethodPost, path: APIPrefix + "/emails/unsubscribe",
+		query: url.Values{"token": {token}}, public: true}, &unsubscribed)
+	return unsubscribed, err
+}
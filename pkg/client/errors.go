@@ -0,0 +1,73 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Error is an error response of the api, the server describes it as a problem
+// of RFC 7807
+type Error struct {
+	StatusCode int    `json:"status"`
+	Title      string `json:"title"`
+	Detail     string `json:"detail,omitempty"`
+	Instance   string `json:"instance,omitempty"`
+	// TraceID finds the request in the logs of the server
+	TraceID string `json:"trace_id,omitempty"`
+}
+
+func (e *Error) Error() string {
+	msg := e.Title
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.TraceID != "" {
+		msg += " (trace id " + e.TraceID + ")"
+	}
+	return msg
+}
+
+// Is makes every error match the sentinel of its status, errors.Is(err,
+// ErrNotFound) holds for any 404
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Title == "" && t.Detail == "" && t.StatusCode == e.StatusCode
+}
+
+// sentinels to test the status of an error with errors.Is
+var (
+	ErrValidation           = &Error{StatusCode: http.StatusBadRequest}
+	ErrUnauthorized         = &Error{StatusCode: http.StatusUnauthorized}
+	ErrForbidden            = &Error{StatusCode: http.StatusForbidden}
+	ErrNotFound             = &Error{StatusCode: http.StatusNotFound}
+	ErrConflict             = &Error{StatusCode: http.StatusConflict}
+	ErrPreconditionFailed   = &Error{StatusCode: http.StatusPreconditionFailed}
+	ErrUnsupportedMediaType = &Error{StatusCode: http.StatusUnsupportedMediaType}
+	ErrUnprocessable        = &Error{StatusCode: http.StatusUnprocessableEntity}
+	ErrPreconditionRequired = &Error{StatusCode: http.StatusPreconditionRequired}
+	ErrTooManyRequests      = &Error{StatusCode: http.StatusTooManyRequests}
+	ErrInternal             = &Error{StatusCode: http.StatusInternalServerError}
+	ErrUnavailable          = &Error{StatusCode: http.StatusServiceUnavailable}
+)
+
+// errorOf reads the error response and closes it. A body that is no problem,
+// such as the page of a proxy, ends up in Detail
+func errorOf(resp *http.Response) error {
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	e := &Error{}
+	if json.Unmarshal(b, e) != nil || e.Title == "" {
+		e = &Error{Detail: strings.TrimSpace(string(b))}
+	}
+	e.StatusCode = resp.StatusCode
+	if e.Title == "" {
+		e.Title = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// GraphQLErrors are the errors a GraphQL query met, the data it resolved around
+// them is still decoded
+type GraphQLErrors []gqlerrors.FormattedError
+
+func (e GraphQLErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Message)
+	}
+	return "graphql: " + strings.Join(msgs, "; ")
+}
+
+// GraphQL runs the query and decodes what it resolved to into data. The errors
+// the query met are returned as GraphQLErrors
+func (c *Client) GraphQL(ctx context.Context, req GraphQLRequest, data interface{}) error {
+	r, err := jsonCall(http.MethodPost, APIPrefix+"/graphql", req)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+	err = c.do(ctx, r, &resp)
+	if err != nil {
+		return err
+	}
+	if data != nil && len(resp.Data) > 0 && string(resp.Data) != "null" {
+		err = json.Unmarshal(resp.Data, data)
+		if err != nil {
+			return err
+		}
+	}
+	if len(resp.Errors) > 0 {
+		return resp.Errors
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package client
+
+import "context"
+
+// Iterator walks the results of a paginated search, fetching the next page when
+// the current one runs out
+//
+//	for it.Next() {
+//		use(it.Value())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, page int) (items []T, total int64, err error)
+
+	page  int
+	items []T
+	i     int
+	seen  int64
+	total int64
+	done  bool
+	err   error
+}
+
+func newIterator[T any](ctx context.Context, fetch func(ctx context.Context, page int) ([]T, int64, error)) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch, i: -1}
+}
+
+// Next moves to the next result, it is false once there are no more or a page
+// could not be fetched
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.i+1 < len(it.items) {
+		it.i++
+		it.seen++
+		return true
+	}
+	if it.done {
+		return false
+	}
+
+	it.page++
+	items, total, err := it.fetch(it.ctx, it.page)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.items, it.i, it.total = items, -1, total
+	// an empty page is the last, so is the one reaching the total
+	if len(items) == 0 || it.seen+int64(len(items)) >= total {
+		it.done = true
+	}
+	return it.Next()
+}
+
+// Value is the current result
+func (it *Iterator[T]) Value() T {
+	if it.i < 0 || it.i >= len(it.items) {
+		var zero T
+		return zero
+	}
+	return it.items[it.i]
+}
+
+// Total is how many results the search found, known once Next was called
+func (it *Iterator[T]) Total() int64 {
+	return it.total
+}
+
+// Err is the error that stopped the iteration, nil when it ran out of results
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// JobQuery filters a job search, every list matches any of its values. PageSize
+// is how many jobs a page holds, the server picks when it is zero
+type JobQuery struct {
+	Query          string
+	Companies      []uint
+	Locations      []string
+	EmploymentType []string
+	RemotePolicy   []string
+	Salary         []string
+	PageSize       int
+}
+
+func (q JobQuery) values() url.Values {
+	v := url.Values{}
+	if q.Query != "" {
+		v.Set("q", q.Query)
+	}
+	for _, cid := range q.Companies {
+		v.Add("company", id(cid))
+	}
+	for key, list := range map[string][]string{
+		"location":        q.Locations,
+		"employment_type": q.EmploymentType,
+		"remote_policy":   q.RemotePolicy,
+		"salary":          q.Salary,
+	} {
+		for _, item := range list {
+			v.Add(key, item)
+		}
+	}
+	if q.PageSize > 0 {
+		v.Set("page_size", strconv.Itoa(q.PageSize))
+	}
+	return v
+}
+
+// Jobs lists every job
+func (c *Client) Jobs(ctx context.Context) ([]Job, error) {
+	return get[[]Job](ctx, c, APIPrefix+"/jobs", nil)
+}
+
+// Job is the job of the id, its ETag() is what UpdateJob, PatchJob and
+// DeleteJob take in ifMatch
+func (c *Client) Job(ctx context.Context, jid uint) (Job, error) {
+	return get[Job](ctx, c, APIPrefix+"/jobs/"+id(jid), nil)
+}
+
+// SearchJobs walks every job the query finds, page after page
+func (c *Client) SearchJobs(ctx context.Context, q JobQuery) *Iterator[Job] {
+	return newIterator(ctx, func(ctx context.Context, page int) ([]Job, int64, error) {
+		result, err := c.SearchJobsPage(ctx, q, page)
+		return result.Jobs, result.Total, err
+	})
+}
+
+// SearchJobsPage is one page of the search, with the facets of every job the
+// query finds. Pages count from 1
+func (c *Client) SearchJobsPage(ctx context.Context, q JobQuery, page int) (JobSearchResult, error) {
+	query := q.values()
+	query.Set("page", strconv.Itoa(page))
+	return get[JobSearchResult](ctx, c, APIPrefix+"/jobs/search", query)
+}
+
+// UpdateJob replaces the job, ifMatch is the ETag of the version it was read at
+// and ErrPreconditionFailed answers when it changed since
+func (c *Client) UpdateJob(ctx context.Context, jid uint, job Job, ifMatch string) (Job, error) {
+	return send[Job](ctx, c, http.MethodPut, APIPrefix+"/jobs/"+id(jid), job, ifMatch)
+}
+
+// PatchJob changes the fields of the job patch sets, as a JSON Merge Patch
+func (c *Client) PatchJob(ctx context.Context, jid uint, patch interface{}, ifMatch string) (Job, error) {
+	return send[Job](ctx, c, http.MethodPatch, APIPrefix+"/jobs/"+id(jid), patch, ifMatch)
+}
+
+// DeleteJob deletes the job at the version of ifMatch
+func (c *Client) DeleteJob(ctx context.Context, jid uint, ifMatch string) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: APIPrefix + "/jobs/" + id(jid), header: ifMatchHeader(ifMatch)}, nil)
+}
+
+// ImportJobs posts the jobs in one bulk import, each row names its company
+func (c *Client) ImportJobs(ctx context.Context, rows []JobRow, opts ImportOptions) (ImportReport, error) {
+	return importRows(ctx, c, APIPrefix+"/jobs/import", rows, opts)
+}
+
+// JobPosting is the schema.org JobPosting of a published job, it needs no token
+func (c *Client) JobPosting(ctx context.Context, jid uint) (JobPosting, error) {
+	var posting JobPosting
+	err := c.do(ctx, call{method: http.MethodGet, path: APIPrefix + "/jobs/" + id(jid) + "/jsonld", public: true,
+		header: http.Header{"Accept": {"application/ld+json"}}}, &posting)
+	return posting, err
+}
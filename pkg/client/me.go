@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Profile is the profile of the signed in user, its ETag() is what SaveProfile
+// takes in ifMatch
+func (c *Client) Profile(ctx context.Context) (Profile, error) {
+	return get[Profile](ctx, c, APIPrefix+"/me/profile", nil)
+}
+
+// SaveProfile replaces the profile of the signed in user, ifMatch is empty for
+// the first one
+func (c *Client) SaveProfile(ctx context.Context, profile Profile, ifMatch string) (Profile, error) {
+	return send[Profile](ctx, c, http.MethodPut, APIPrefix+"/me/profile", profile, ifMatch)
+}
+
+// ProfileViews lists the recruiters who saw the profile of the signed in user
+func (c *Client) ProfileViews(ctx context.Context) ([]ProfileView, error) {
+	return get[[]ProfileView](ctx, c, APIPrefix+"/me/profile/views", nil)
+}
+
+// Recommendations are the jobs matching the profile of the signed in user best,
+// the server picks how many when limit is zero
+func (c *Client) Recommendations(ctx context.Context, limit int) ([]Recommendation, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	return get[[]Recommendation](ctx, c, APIPrefix+"/me/recommendations", query)
+}
+
+// Notifications lists the notifications of the signed in user, only the unread
+// ones when unreadOnly
+func (c *Client) Notifications(ctx context.Context, unreadOnly bool) ([]Notification, error) {
+	query := url.Values{}
+	if unreadOnly {
+		query.Set("unread", "true")
+	}
+	return get[[]Notification](ctx, c, APIPrefix+"/me/notifications", query)
+}
+
+// MarkNotifications marks the notifications read, or unread again
+func (c *Client) MarkNotifications(ctx context.Context, ids []uint, read bool) error {
+	path := APIPrefix + "/me/notifications/read"
+	if !read {
+		path = APIPrefix + "/me/notifications/unread"
+	}
+	r, err := jsonCall(http.MethodPost, path, struct {
+		IDs []uint `json:"ids"`
+	}{ids})
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, r, nil)
+}
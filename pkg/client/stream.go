@@ -0,0 +1,173 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventReset tells a stream that resumed too late that the events it missed are
+// gone, the listing it keeps has to be loaded again
+const EventReset = "reset"
+
+// JobEvent is an event of the job stream, Type is job.created, job.updated,
+// job.closed or EventReset
+type JobEvent struct {
+	ID   uint64
+	Type string
+	Job  Job
+}
+
+// JobStream follows the events of the jobs matching a query, it reconnects when
+// the server drops it and resumes after the last event it got
+//
+//	for stream.Next() {
+//		use(stream.Event())
+//	}
+//	err := stream.Err()
+type JobStream struct {
+	c      *Client
+	ctx    context.Context
+	query  JobQuery
+	lastID uint64
+	retry  time.Duration
+
+	body   io.ReadCloser
+	lines  *bufio.Scanner
+	event  JobEvent
+	err    error
+	closed bool
+}
+
+// FollowJobs streams the events of the jobs the query matches, after the event
+// lastEventID when it is not zero. The stream ends with ctx or Close
+func (c *Client) FollowJobs(ctx context.Context, q JobQuery, lastEventID uint64) *JobStream {
+	q.PageSize = 0
+	return &JobStream{c: c, ctx: ctx, query: q, lastID: lastEventID, retry: 3 * time.Second}
+}
+
+// Next waits for the next event, it is false once the stream ended
+func (s *JobStream) Next() bool {
+	for s.err == nil && !s.closed {
+		if s.body == nil {
+			s.err = s.connect()
+			continue
+		}
+		event, ok := s.read()
+		if ok {
+			s.event = event
+			return true
+		}
+		// the server dropped the stream, it is resumed after a while
+		s.disconnect()
+		if s.ctx.Err() != nil {
+			s.err = s.ctx.Err()
+		} else if err := s.c.sleep(s.ctx, s.retry); err != nil {
+			s.err = err
+		}
+	}
+	return false
+}
+
+// Event is the current event
+func (s *JobStream) Event() JobEvent {
+	return s.event
+}
+
+// LastEventID is the id of the last event, FollowJobs resumes after it
+func (s *JobStream) LastEventID() uint64 {
+	return s.lastID
+}
+
+// Err is the error that ended the stream, nil when Close did
+func (s *JobStream) Err() error {
+	return s.err
+}
+
+// Close ends the stream, from the goroutine calling Next. Cancelling the ctx of
+// the stream stops a Next that is waiting
+func (s *JobStream) Close() error {
+	s.closed = true
+	s.disconnect()
+	return nil
+}
+
+func (s *JobStream) connect() error {
+	r := call{method: http.MethodGet, path: APIPrefix + "/jobs/stream", query: s.query.values(),
+		header: http.Header{"Accept": {"text/event-stream"}}}
+	if s.lastID > 0 {
+		r.header.Set("Last-Event-ID", strconv.FormatUint(s.lastID, 10))
+	}
+	resp, err := s.c.send(s.ctx, r)
+	if err != nil {
+		return err
+	}
+	s.body = resp.Body
+	s.lines = bufio.NewScanner(resp.Body)
+	s.lines.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	return nil
+}
+
+func (s *JobStream) disconnect() {
+	if s.body != nil {
+		s.body.Close()
+		s.body, s.lines = nil, nil
+	}
+}
+
+// read reads up to the next event that carries data, it is false when the
+// stream ended
+func (s *JobStream) read() (JobEvent, bool) {
+	var (
+		event JobEvent
+		data  strings.Builder
+	)
+	for s.lines.Scan() {
+		line := s.lines.Text()
+		if line == "" {
+			if data.Len() == 0 {
+				event = JobEvent{}
+				continue
+			}
+			if event.Type == "" {
+				event.Type = "message"
+			}
+			if json.Unmarshal([]byte(data.String()), &event.Job) != nil {
+				event = JobEvent{}
+				data.Reset()
+				continue
+			}
+			if event.ID > 0 {
+				s.lastID = event.ID
+			}
+			return event, true
+		}
+		if strings.HasPrefix(line, ":") {
+			// a heartbeat
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			event.ID, _ = strconv.ParseUint(value, 10, 64)
+		case "event":
+			event.Type = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	return JobEvent{}, false
+}
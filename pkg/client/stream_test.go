@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"project/internal/jobstream"
+	"project/internal/models"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func broker(t *testing.T, events int) *jobstream.Broker {
+	t.Helper()
+	b, err := jobstream.NewBroker(jobstream.DefaultReplay)
+	if err != nil {
+		t.Fatalf("NewBroker() error = %v", err)
+	}
+	for i := 1; i <= events; i++ {
+		b.Publish(models.EventJobCreated, models.Jobs{Model: gorm.Model{ID: uint(i)}, Cid: 7, Name: "developer"})
+	}
+	return b
+}
+
+func TestClient_FollowJobs(t *testing.T) {
+	p := newPortal(t)
+	p.expectLogin(p.token(t, 4, time.Hour))
+	first, second := broker(t, 3), broker(t, 5)
+	filter := models.JobFilter{Cid: []uint{7}}
+	gomock.InOrder(
+		p.svc.EXPECT().FollowJobs(gomock.Any(), filter, uint64(1)).
+			DoAndReturn(func(ctx context.Context, filter models.JobFilter, lastEventID uint64) (*jobstream.Subscription, error) {
+				return first.Subscribe(nil, lastEventID)
+			}),
+		// the stream the server dropped resumes after the last event it sent
+		p.svc.EXPECT().FollowJobs(gomock.Any(), filter, uint64(4)).
+			DoAndReturn(func(ctx context.Context, filter models.JobFilter, lastEventID uint64) (*jobstream.Subscription, error) {
+				return second.Subscribe(nil, lastEventID)
+			}),
+	)
+	c := p.client(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream := c.FollowJobs(ctx, JobQuery{Companies: []uint{7}}, 1)
+	defer stream.Close()
+
+	var ids []uint64
+	for len(ids) < 4 && stream.Next() {
+		e := stream.Event()
+		assert.Equal(t, models.EventJobCreated, e.Type)
+		assert.Equal(t, e.ID, uint64(e.Job.ID))
+		ids = append(ids, e.ID)
+		if len(ids) == 2 {
+			first.Publish(models.EventJobCreated, models.Jobs{Model: gorm.Model{ID: 4}, Cid: 7, Name: "developer"})
+		}
+		if len(ids) == 3 {
+			first.Close()
+		}
+	}
+	if stream.Err() != nil {
+		t.Fatalf("FollowJobs() error = %v", stream.Err())
+	}
+	assert.Equal(t, []uint64{2, 3, 4, 5}, ids)
+	assert.Equal(t, uint64(5), stream.LastEventID())
+}
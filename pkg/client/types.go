@@ -0,0 +1,36 @@
+package client
+
+import (
+	"project/internal/graph"
+	"project/internal/models"
+	"project/internal/schemaorg"
+)
+
+// the records of the api are the ones the server sends, so the client cannot
+// drift from it
+type (
+	NewUser          = models.NewUser
+	User             = models.User
+	Company          = models.Company
+	Job              = models.Jobs
+	JobRow           = models.JobRow
+	JobSearchResult  = models.JobSearchResult
+	JobPosting       = schemaorg.JobPosting
+	ImportOptions    = models.ImportOptions
+	ImportReport     = models.ImportReport
+	Membership       = models.Membership
+	Verification     = models.CompanyVerification
+	Webhook          = models.Webhook
+	WebhookDelivery  = models.WebhookDelivery
+	ATSConnector     = models.ATSConnector
+	Profile          = models.Profile
+	ProfileView      = models.ProfileView
+	Recommendation   = models.Recommendation
+	Notification     = models.Notification
+	Candidate        = models.Candidate
+	TalentResult     = models.TalentSearchResult
+	RenderedEmail    = models.RenderedEmail
+	Unsubscribed     = models.Unsubscribed
+	AggregatorReport = models.AggregatorReport
+	GraphQLRequest   = graph.Request
+)